@@ -0,0 +1,18 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCmd(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	// The cleanup integration specs build and drive the plugin binary against a
+	// kubeconfig, so they stay out of the unit suite as they were before it existed
+	suiteConfig, reporterConfig := GinkgoConfiguration()
+	suiteConfig.SkipFiles = append(suiteConfig.SkipFiles, "cleanup_test.go")
+	RunSpecs(t, "Cmd Suite", suiteConfig, reporterConfig)
+}
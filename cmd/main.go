@@ -1,27 +1,58 @@
 package main
 
 import (
+	"bufio"
+	"cmp"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
 
+	"github.com/jdambly/kubectl-csi-scan/pkg/baseline"
+	"github.com/jdambly/kubectl-csi-scan/pkg/cache"
 	"github.com/jdambly/kubectl-csi-scan/pkg/cleanup"
 	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+	"github.com/jdambly/kubectl-csi-scan/pkg/config"
 	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/export"
+	"github.com/jdambly/kubectl-csi-scan/pkg/multicluster"
+	"github.com/jdambly/kubectl-csi-scan/pkg/notify"
+	"github.com/jdambly/kubectl-csi-scan/pkg/parse"
+	"github.com/jdambly/kubectl-csi-scan/pkg/remediate"
+	"github.com/jdambly/kubectl-csi-scan/pkg/report"
+	"github.com/jdambly/kubectl-csi-scan/pkg/schema"
+	"github.com/jdambly/kubectl-csi-scan/pkg/serve"
 	"github.com/jdambly/kubectl-csi-scan/pkg/types"
 )
 
 var (
 	configFlags = genericclioptions.NewConfigFlags(true)
+
+	// serverClock follows the API server's clock through the clients buildKubernetesClient builds
+	serverClock = client.NewServerClock()
 )
 
 func main() {
@@ -38,11 +69,35 @@ func main() {
 	if err := root.Execute(); err != nil {
 		// CLI error messages to stderr are appropriate
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
+// exitCodeError makes the process exit with a specific code, so scripts can tell the
+// outcomes of a run apart
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
 func newRootCmd() *cobra.Command {
+	var (
+		logLevel   string
+		redactLogs bool
+	)
+
 	cmd := &cobra.Command{
 		Use:   "kubectl-csi_mount_detective",
 		Short: "Detect and analyze CSI mount cleanup issues in Kubernetes clusters",
@@ -51,31 +106,103 @@ func newRootCmd() *cobra.Command {
 - Cross-node PVC usage analysis for multi-attach issues  
 - Kubernetes events monitoring for mount failures
 - Prometheus metrics queries for operation failures
+- StorageClass checks for binding and reclaim settings that cause problems
 
 This tool was developed to address production issues where CSI volumes get stuck
 in attached state, preventing proper pod scheduling and volume cleanup.`,
 		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			setLogRedaction(redactLogs)
+			return setLogLevel(logLevel)
+		},
 	}
 
 	// Add global flags
 	configFlags.AddFlags(cmd.PersistentFlags())
+	cmd.PersistentFlags().StringVar(&logLevel, "log-level", "info",
+		"Log level (debug,info,warn,error); debug adds progress lines during long scans")
+	cmd.PersistentFlags().BoolVar(&redactLogs, "redact-logs", false,
+		"Shorten volume handles in logged errors so log aggregation does not capture full identifiers")
 
 	// Add subcommands
 	cmd.AddCommand(newDetectCmd())
 	cmd.AddCommand(newAnalyzeCmd())
 	cmd.AddCommand(newMetricsCmd())
 	cmd.AddCommand(newCleanupCmd())
+	cmd.AddCommand(newAnnotateCmd())
+	cmd.AddCommand(newServeCmd())
+	cmd.AddCommand(newValidateConfigCmd())
+	cmd.AddCommand(newSchemaCmd())
 
 	return cmd
 }
 
+// detectFlags holds the flag values of the detect command
+type detectFlags struct {
+	methods             []string
+	targetDriver        string
+	outputFormat        string
+	recommendCleanup    bool
+	minSeverity         string
+	csiOnly             bool
+	withOwners          bool
+	strictDriverMatch   bool
+	webhookURL          string
+	pushGateway         string
+	notifyOn            string
+	deviceBusyPatterns  []string
+	stuckThreshold      time.Duration
+	deletionThreshold   time.Duration
+	driverThresholds    map[string]string
+	offline             bool
+	cacheFile           string
+	cacheTTL            time.Duration
+	omitEmpty           bool
+	groupBy             string
+	splitByNamespace    bool
+	outputDir           string
+	fullMessage         bool
+	noHeaders           bool
+	suppressions        []types.SuppressionRule
+	contexts            []string
+	clusterConcurrency  int
+	clusterTimeout      time.Duration
+	probe               bool
+	probeMounts         bool
+	probeNamespace      string
+	nodePluginSelectors map[string]string
+	severityOverrides   map[types.IssueType]types.IssueSeverity
+	checkClaims         bool
+	enrichErrorLimit    int
+	nodePVCWarn         int
+	flapThreshold       int
+	eventsLookback      time.Duration
+	baselineConfigMap   string
+	pvc                 string
+	storageClass        string
+	prometheusURL       string
+	prometheusCAFile    string
+	prometheusInsecure  bool
+	summaryLine         bool
+	criticalWhen        map[string]string
+	degradedWhen        map[string]string
+	exitZeroOnEmpty     bool
+	scanFailureExitCode int
+	watch               bool
+	watchInterval       time.Duration
+	savePath            string
+	timeout             time.Duration
+	noDedup             bool
+	bestEffort          bool
+	failOn              string
+}
+
 func newDetectCmd() *cobra.Command {
 	var (
-		methods         []string
-		targetDriver    string
-		outputFormat    string
-		recommendCleanup bool
-		minSeverity     string
+		flags       detectFlags
+		listMethods  bool
+		configPath   string
+		suppressPath string
 	)
 
 	cmd := &cobra.Command{
@@ -88,6 +215,9 @@ Available methods:
 - cross-node-pvc: Analyze PVC usage across multiple nodes  
 - events: Monitor Kubernetes events for mount failures
 - metrics: Query Prometheus metrics for operation failures
+- storageclass: Report StorageClass settings that commonly cause mount problems
+- node-conditions: Report affected nodes that are NotReady or under disk or PID pressure
+- driver-topology: Report nodes whose CSINode does not register a driver their pods' volumes need
 
 Examples:
   # Detect all issues using all methods
@@ -99,55 +229,351 @@ Examples:
   # Target specific CSI driver
   kubectl csi-mount-detective detect --driver=cinder.csi.openstack.org
 
+  # Only report items definitively attributed to the driver
+  kubectl csi-mount-detective detect --driver=cinder.csi.openstack.org --strict-driver-match
+
+  # Include non-CSI PVCs (NFS, hostPath) in cross-node analysis for a driver
+  kubectl csi-mount-detective detect --driver=cinder.csi.openstack.org --csi-only=false
+
   # Get cleanup recommendations
   kubectl csi-mount-detective detect --recommend-cleanup
 
+  # Include the most affected workloads in recommendations
+  kubectl csi-mount-detective detect --recommend-cleanup --with-owners
+
+  # Recommend only on-cluster steps in an air-gapped cluster
+  kubectl csi-mount-detective detect --recommend-cleanup --offline
+
   # Filter by severity level
-  kubectl csi-mount-detective detect --min-severity=high`,
+  kubectl csi-mount-detective detect --min-severity=high
+
+  # Only list pods and events in one namespace
+  kubectl csi-mount-detective detect --method=cross-node-pvc,events -n shop
+
+  # Allow slow backends longer to attach before reporting them as stuck
+  kubectl csi-mount-detective detect --stuck-threshold=5m --driver-stuck-threshold=cinder.csi.openstack.org=1h
+
+  # Look back a full day of events instead of the last hour
+  kubectl csi-mount-detective detect --method=events --events-lookback=24h
+
+  # Find nodes whose CSINode is missing the driver their pods' volumes need
+  kubectl csi-mount-detective detect --method=driver-topology --driver=cinder.csi.openstack.org
+
+  # Show severity, driver, detection method and age for every issue
+  kubectl csi-mount-detective detect --output=wide
+
+  # See what each method found on its own, without merging issues about the same volume
+  kubectl csi-mount-detective detect --output=wide --no-dedup
+
+  # Report what the permitted methods find even if others are forbidden by RBAC
+  kubectl csi-mount-detective detect --best-effort
+
+  # One CSV row per issue for spreadsheets and tickets
+  kubectl csi-mount-detective detect --output=csv > issues.csv
+
+  # One JSON object per issue and line, for jq -c and log processors
+  kubectl csi-mount-detective detect --output=jsonl | jq -c 'select(.severity == "critical")'
+
+  # Minimal JSON for dashboards, without empty summary fields
+  kubectl csi-mount-detective detect --output=json --omit-empty
+
+  # Fail a CI pipeline when any high or critical issue is found
+  kubectl csi-mount-detective detect --fail-on=high
+
+  # One key=value summary line on stderr for shell scripts, whatever the output format
+  kubectl csi-mount-detective detect --output=json --summary-line 2>summary.txt
+
+  # One JSON report per namespace for multi-team clusters
+  kubectl csi-mount-detective detect --split-by-namespace --output-dir=reports/
+
+  # Only inspect volumes of the StorageClass your team owns
+  kubectl csi-mount-detective detect --method=volumeattachments,cross-node-pvc --storage-class=fast-ssd
+
+  # Query Prometheus for CSI operation failures
+  kubectl csi-mount-detective detect --method=metrics --prometheus-url=http://prometheus.monitoring:9090
+
+  # Query a Prometheus served with a certificate from an internal CA
+  kubectl csi-mount-detective detect --method=metrics --prometheus-url=https://prometheus.internal --prometheus-ca-file=ca.pem
+
+  # Allow a scan of a very large cluster more time
+  kubectl csi-mount-detective detect --timeout=10m
+
+  # Rescan every 30 seconds during an incident, showing what appeared and resolved
+  kubectl csi-mount-detective detect --watch --interval=30s
+
+  # JSON keyed by node for node-centric tooling
+  kubectl csi-mount-detective detect --output=json --group-by=node
+
+  # Exit 3 when no detection method could run, to catch missing RBAC permissions in CI
+  kubectl csi-mount-detective detect --scan-failure-exit-code=3
+
+  # Write a markdown incident report for a postmortem
+  kubectl csi-mount-detective detect --recommend-cleanup --output=report > incident.md
+
+  # Self-contained HTML page to share with stakeholders during an incident review
+  kubectl csi-mount-detective detect --recommend-cleanup --output=html > incident.html
+
+  # Issues grouped by node, most severe first, to decide which nodes to cordon
+  kubectl csi-mount-detective detect --output=by-node
+
+  # Notify Slack when high or critical issues are found
+  kubectl csi-mount-detective detect --webhook-url=https://hooks.slack.com/services/... --notify-on=high
+
+  # Track issue counts over time by pushing them to a Prometheus Pushgateway
+  kubectl csi-mount-detective detect --push-gateway=http://pushgateway.monitoring:9091
+
+  # Save the full result to share during an incident, then analyze it without cluster access
+  kubectl csi-mount-detective detect --save=scan.json
+  kubectl csi-mount-detective analyze --from-file=scan.json
+
+  # Reuse a result from the last 5 minutes when re-running with another output format
+  kubectl csi-mount-detective detect --cache-file=/tmp/csi-scan.json
+  kubectl csi-mount-detective detect --cache-file=/tmp/csi-scan.json --output=report
+
+  # Scan several clusters, two at a time
+  kubectl csi-mount-detective detect --contexts=prod-east,prod-west,staging --cluster-concurrency=2
+
+  # Find volumes still attached after their PVC was deleted
+  kubectl csi-mount-detective detect --method=volumeattachments --check-claims
+
+  # Check the CSI node plugin pods on nodes with issues
+  kubectl csi-mount-detective detect --driver=cinder.csi.openstack.org --probe
+
+  # Probe a driver whose node plugin pods use a custom label
+  kubectl csi-mount-detective detect --driver=nfs.csi.k8s.io --probe --node-plugin-selector=nfs.csi.k8s.io=app=csi-nfs-node
+
+  # List the CSI mounts actually left on nodes with suspected stuck mount references
+  kubectl csi-mount-detective detect --method=cross-node-pvc,events --probe-mounts
+
+  # Leave known and accepted issues out of the results
+  kubectl csi-mount-detective detect --suppress=suppressions.yaml
+
+  # Load thresholds, drivers and patterns from a config file (flags still take precedence)
+  kubectl csi-mount-detective detect --config=csi-scan.yaml
+
+  # Or keep it at ~/.kube/csi-scan.yaml, which is read whenever --config is omitted
+  kubectl csi-mount-detective detect
+
+  # Show what each method does and the RBAC it needs
+  kubectl csi-mount-detective detect --list-methods`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDetect(methods, targetDriver, outputFormat, recommendCleanup, minSeverity)
+			if listMethods {
+				return printMethods(os.Stdout, detect.AvailableMethods())
+			}
+			if path := resolveConfigPath(configPath); path != "" {
+				cfg, err := loadValidConfig(path)
+				if err != nil {
+					return err
+				}
+				if err := applyConfig(cmd, &flags, cfg); err != nil {
+					return err
+				}
+			}
+			if suppressPath != "" {
+				rules, err := config.LoadSuppressions(suppressPath)
+				if err != nil {
+					return err
+				}
+				flags.suppressions = rules
+			}
+			// Limit cross-node analysis to CSI volumes when targeting a driver, unless told otherwise
+			if !cmd.Flags().Changed("csi-only") {
+				flags.csiOnly = flags.targetDriver != ""
+			}
+			return runDetect(flags)
 		},
 	}
 
-	cmd.Flags().StringSliceVar(&methods, "method", []string{"volumeattachments", "cross-node-pvc", "events"}, 
-		"Detection methods to use (volumeattachments,cross-node-pvc,events,metrics)")
-	cmd.Flags().StringVar(&targetDriver, "driver", "", 
+	cmd.Flags().StringSliceVar(&flags.methods, "method", []string{"volumeattachments", "cross-node-pvc", "events"}, 
+		"Detection methods to use ("+strings.Join(methodNames(), ",")+")")
+	cmd.Flags().StringVar(&flags.targetDriver, "driver", "", 
 		"Target CSI driver to analyze (e.g., cinder.csi.openstack.org)")
-	cmd.Flags().StringVar(&outputFormat, "output", "table", 
-		"Output format (table,json,yaml,detailed)")
-	cmd.Flags().BoolVar(&recommendCleanup, "recommend-cleanup", false, 
+	cmd.Flags().StringVar(&flags.outputFormat, "output", "table", 
+		"Output format (table,wide,json,yaml,csv,jsonl,detailed,report,html,by-node)")
+	cmd.Flags().BoolVar(&flags.recommendCleanup, "recommend-cleanup", false, 
 		"Generate cleanup recommendations")
-	cmd.Flags().StringVar(&minSeverity, "min-severity", "", 
+	cmd.Flags().StringVar(&flags.minSeverity, "min-severity", "", 
 		"Minimum severity level to report (low,medium,high,critical)")
+	cmd.Flags().BoolVar(&listMethods, "list-methods", false,
+		"List available detection methods and the permissions they require, then exit")
+	cmd.Flags().BoolVar(&flags.fullMessage, "full-message", false,
+		"Show event messages in full in table output instead of truncating them to 200 characters")
+	cmd.Flags().BoolVar(&flags.noHeaders, "no-headers", false,
+		"Print only issue rows in table output, without the summary, section headers or next-step hints")
+	cmd.Flags().BoolVar(&flags.omitEmpty, "omit-empty", false,
+		"Drop empty and zero-valued summary fields and empty issue metadata from JSON and YAML output")
+	cmd.Flags().StringVar(&flags.groupBy, "group-by", "",
+		"Write JSON output as issues keyed by this field instead of the full result (node; issues without a node go under "+unassignedGroup+")")
+	cmd.Flags().BoolVar(&flags.splitByNamespace, "split-by-namespace", false,
+		"Write one JSON report per namespace to --output-dir instead of printing results, with issues without a namespace in "+clusterReport+".json")
+	cmd.Flags().StringVar(&flags.outputDir, "output-dir", "",
+		"Directory for --split-by-namespace reports, created if missing")
+	cmd.Flags().BoolVar(&flags.offline, "offline", false,
+		"Only recommend on-cluster remediation steps, omitting anything that needs external connectivity (air-gapped clusters)")
+	cmd.Flags().BoolVar(&flags.withOwners, "with-owners", false,
+		"Add the workloads (Deployment/StatefulSet) consuming affected PVCs to cleanup recommendations")
+	cmd.Flags().BoolVar(&flags.strictDriverMatch, "strict-driver-match", false,
+		"With --driver, exclude PVCs and events whose driver cannot be determined instead of including them")
+	cmd.Flags().StringVar(&flags.webhookURL, "webhook-url", "",
+		"Post a summary to this Slack-compatible incoming webhook when issues are found (alias --slack-webhook)")
+	cmd.Flags().StringVar(&flags.pushGateway, "push-gateway", "",
+		"Push issue counts by severity and type, affected nodes and scan duration to this Prometheus Pushgateway")
+	cmd.Flags().StringVar(&flags.notifyOn, "notify-on", "low",
+		"Minimum issue severity that triggers a webhook notification (low,medium,high,critical) (alias --notify-min-severity)")
+	cmd.Flags().SetNormalizeFunc(detectFlagAliases)
+	cmd.Flags().StringSliceVar(&flags.deviceBusyPatterns, "device-busy-patterns", detect.DefaultDeviceBusyPatterns,
+		"Event message substrings (case-insensitive) reported as high-severity device-busy issues")
+	cmd.Flags().DurationVar(&flags.stuckThreshold, "stuck-threshold", detect.DefaultStuckThreshold,
+		"How long a VolumeAttachment may stay unattached before it is reported as stuck")
+	cmd.Flags().StringToStringVar(&flags.driverThresholds, "driver-stuck-threshold", nil,
+		"Per-driver stuck thresholds overriding --stuck-threshold (e.g. cinder.csi.openstack.org=1h,local.csi.example.com=2m)")
+	cmd.Flags().DurationVar(&flags.deletionThreshold, "deletion-threshold", detect.DefaultDeletionThreshold,
+		"How long a deleted VolumeAttachment may keep its finalizers before it is reported as stuck detaching")
+	cmd.Flags().BoolVar(&flags.csiOnly, "csi-only", false,
+		"Only analyze PVCs backed by CSI volumes in cross-node detection (default true when --driver is set)")
+	cmd.Flags().StringVar(&flags.cacheFile, "cache-file", "",
+		"Write the detection result to this file and reuse it on later runs with the same options while it is newer than --cache-ttl")
+	cmd.Flags().DurationVar(&flags.cacheTTL, "cache-ttl", 5*time.Minute,
+		"How long a result in --cache-file is reused before a fresh scan runs")
+	cmd.Flags().StringVar(&flags.savePath, "save", "",
+		"Also write the full detection result as JSON to this file, for sharing or for analyze --from-file")
+	cmd.Flags().StringVar(&configPath, "config", "",
+		"Config file with detect settings; flags given on the command line override it (check it with validate-config). Defaults to ~/.kube/csi-scan.yaml when that file exists")
+	cmd.Flags().StringSliceVar(&flags.contexts, "contexts", nil,
+		"Scan the clusters of these kubeconfig contexts instead of the current one")
+	cmd.Flags().IntVar(&flags.clusterConcurrency, "cluster-concurrency", 4,
+		"How many clusters from --contexts to scan at once")
+	cmd.Flags().DurationVar(&flags.clusterTimeout, "cluster-timeout", multicluster.DefaultClusterTimeout,
+		"How long detection may run against each cluster from --contexts")
+	cmd.Flags().StringVar(&suppressPath, "suppress", "",
+		"File listing suppression rules (type, driver, node or PVC glob) for known and accepted issues; replaces the config file's suppressions")
+	cmd.Flags().BoolVar(&flags.checkClaims, "check-claims", false,
+		"Look up the claim of every attached PV and report volumes still attached after their PVC was deleted")
+	cmd.Flags().IntVar(&flags.enrichErrorLimit, "enrichment-error-limit", detect.DefaultEnrichmentErrorLimit,
+		"Stop enrichment lookups such as --check-claims and PVC driver lookups for the rest of the run after this many identical errors in a row")
+	cmd.Flags().StringToStringVar(&flags.criticalWhen, "critical-when", nil,
+		"Issue counts per severity that make the overall status critical (default critical=1)")
+	cmd.Flags().StringToStringVar(&flags.degradedWhen, "degraded-when", nil,
+		"Issue counts per severity that make the overall status degraded (default high=5)")
+	cmd.Flags().BoolVar(&flags.summaryLine, "summary-line", false,
+		"Print one key=value summary line to stderr after the results, e.g. issues=7 critical=1 high=2 medium=3 low=1 nodes=3 status=critical")
+	cmd.Flags().BoolVar(&flags.exitZeroOnEmpty, "exit-zero-on-empty", false,
+		"Exit 0 when a scan that stopped early found no issues in the methods that completed")
+	cmd.Flags().IntVar(&flags.scanFailureExitCode, "scan-failure-exit-code", 1,
+		"Exit code when no detection method could complete, e.g. because of missing RBAC permissions")
+	cmd.Flags().StringVar(&flags.failOn, "fail-on", "",
+		"Exit 1 when any issue at or above this severity is found (low, medium, high, critical), e.g. to fail a CI pipeline")
+	cmd.Flags().StringVar(&flags.storageClass, "storage-class", "",
+		"Only inspect PVCs and VolumeAttachments of PVs in this StorageClass (cross-node-pvc and volumeattachments methods)")
+	cmd.Flags().BoolVar(&flags.watch, "watch", false,
+		"Rescan on every --interval until interrupted, showing issues that appeared or resolved since the previous scan")
+	cmd.Flags().DurationVar(&flags.watchInterval, "interval", 30*time.Second,
+		"Time between scans with --watch")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", 2*time.Minute,
+		"How long detection may run before it is stopped (each scan with --watch)")
+	cmd.Flags().StringVar(&flags.prometheusURL, "prometheus-url", "",
+		"Prometheus the metrics method queries for CSI operation failures, e.g. http://prometheus.monitoring:9090")
+	cmd.Flags().StringVar(&flags.prometheusCAFile, "prometheus-ca-file", "",
+		"PEM bundle of CAs trusted to verify the --prometheus-url server certificate, instead of the system cert pool")
+	cmd.Flags().BoolVar(&flags.prometheusInsecure, "prometheus-insecure-skip-verify", false,
+		"Do not verify the --prometheus-url server certificate (insecure)")
+	cmd.Flags().StringVar(&flags.pvc, "pvc", "",
+		"Only report issues about this PVC (namespace/name), including VolumeAttachments of its bound PV")
+	cmd.Flags().StringVar(&flags.baselineConfigMap, "baseline-configmap", "",
+		"Show only issues that are new or resolved since the baseline in this ConfigMap in --namespace, then store this scan as the new baseline")
+	cmd.Flags().IntVar(&flags.nodePVCWarn, "node-pvc-warn", 0,
+		"Report nodes holding more than this many PVC references in total as informational issues (0 disables; needs the cross-node-pvc method)")
+	cmd.Flags().IntVar(&flags.flapThreshold, "flap-threshold", detect.DefaultFlapThreshold,
+		"Report volumes with more than this many attach and detach events within the events lookback as flapping")
+	cmd.Flags().DurationVar(&flags.eventsLookback, "events-lookback", detect.DefaultEventsLookback,
+		"How far back the events method looks for mount and attach failures, e.g. 24h to cover an incident earlier in the day")
+	cmd.Flags().BoolVar(&flags.probe, "probe", false,
+		"Report CSI node plugin pods that are not Running and Ready on nodes with issues")
+	cmd.Flags().BoolVar(&flags.probeMounts, "probe-mounts", false,
+		"Run read-only jobs on nodes with suspected stuck mount references to list the CSI mounts still mounted there")
+	cmd.Flags().StringVar(&flags.probeNamespace, "probe-namespace", "default",
+		"Namespace to create --probe-mounts jobs in")
+	cmd.Flags().BoolVar(&flags.noDedup, "no-dedup", false,
+		"Report every method's issues separately instead of merging those about the same volume on the same node")
+	cmd.Flags().BoolVar(&flags.bestEffort, "best-effort", false,
+		"Keep running the other methods when one fails, e.g. because RBAC forbids what it reads, and report the failures with the issues found")
+	cmd.Flags().StringToStringVar(&flags.nodePluginSelectors, "node-plugin-selector", nil,
+		"Per-driver label selectors of node plugin pods for --probe, overriding the built-in ones (e.g. nfs.csi.k8s.io=app=csi-nfs-node)")
 
 	return cmd
 }
 
+// analyzeFlags holds the flag values of the analyze command
+type analyzeFlags struct {
+	methods      []string
+	targetDriver string
+	outputFormat string
+	fromFile     string
+	topPVCs      int
+}
+
 func newAnalyzeCmd() *cobra.Command {
+	var flags analyzeFlags
+
 	cmd := &cobra.Command{
 		Use:   "analyze",
 		Short: "Perform detailed analysis of cluster state",
 		Long: `Perform detailed analysis of cluster state including:
 - VolumeAttachment statistics
-- Node PVC usage patterns
+- Node PVC usage patterns and the most referenced PVCs
 - Recent relevant events
 - Recommended Prometheus queries
 
-This provides deeper insights for troubleshooting and monitoring setup.`,
+This provides deeper insights for troubleshooting and monitoring setup.
+
+Examples:
+  # Analyze everything in the cluster
+  kubectl csi-mount-detective analyze
+
+  # Focus the analysis on a single CSI driver
+  kubectl csi-mount-detective analyze --driver=cinder.csi.openstack.org
+
+  # Only gather VolumeAttachment statistics and recent events
+  kubectl csi-mount-detective analyze --method=volumeattachments,events
+
+  # Analyze a result saved with detect --save, without cluster access
+  kubectl csi-mount-detective analyze --from-file=scan.json
+
+  # Rank the 20 PVCs with the most pod references across nodes
+  kubectl csi-mount-detective analyze --method=cross-node-pvc --top-pvcs=20`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runAnalyze()
+			return runAnalyze(flags)
 		},
 	}
 
+	cmd.Flags().StringSliceVar(&flags.methods, "method", analysisMethodNames(),
+		"Analyses to run ("+strings.Join(analysisMethodNames(), ",")+")")
+	cmd.Flags().StringVar(&flags.targetDriver, "driver", "",
+		"Target CSI driver to analyze (e.g., cinder.csi.openstack.org)")
+	cmd.Flags().IntVar(&flags.topPVCs, "top-pvcs", detect.DefaultTopPVCs,
+		"How many PVCs to rank by pod references across all nodes")
+	cmd.Flags().StringVar(&flags.outputFormat, "output", "json",
+		"Output format (json,yaml)")
+	cmd.Flags().StringVar(&flags.fromFile, "from-file", "",
+		"Analyze a detection result saved with detect --save or --cache-file instead of the cluster")
+
 	return cmd
 }
 
+// metricsFlags holds the flag values for the metrics command
+type metricsFlags struct {
+	generateAlerts    bool
+	generateDashboard bool
+	outputFile        string
+	outputFormat      string
+	targetDriver      string
+	compareDriver     string
+	driverLabel       string
+	toConfigMap       string
+}
+
 func newMetricsCmd() *cobra.Command {
-	var (
-		generateAlerts    bool
-		generateDashboard bool
-		outputFile        string
-	)
+	flags := &metricsFlags{}
 
 	cmd := &cobra.Command{
 		Use:   "metrics",
@@ -155,33 +581,235 @@ func newMetricsCmd() *cobra.Command {
 		Long: `Generate Prometheus queries, alerting rules, and Grafana dashboards
 for monitoring CSI mount issues.
 
-This helps set up proactive monitoring to detect issues before they impact applications.`,
+This helps set up proactive monitoring to detect issues before they impact applications.
+
+Use --output=yaml to emit a single structured document instead of annotated text.
+
+Use --compare-driver with --driver to compare two drivers side by side, for example
+while migrating between them: driver-specific queries are generated for both, and
+dashboard panels show one series per driver.
+
+Examples:
+  # Queries for a single driver
+  kubectl csi-mount-detective metrics --driver cinder.csi.openstack.org
+
+  # Dashboard comparing two drivers
+  kubectl csi-mount-detective metrics --generate-dashboard --driver cinder.csi.openstack.org --compare-driver ebs.csi.aws.com
+
+  # Queries for a Prometheus that relabels driver_name to csi_driver
+  kubectl csi-mount-detective metrics --driver cinder.csi.openstack.org --driver-label csi_driver
+
+  # Install the dashboard for the Grafana sidecar to load
+  kubectl csi-mount-detective metrics --generate-dashboard --driver cinder.csi.openstack.org --to-configmap csi-dashboard -n monitoring`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runMetrics(generateAlerts, generateDashboard, outputFile)
+			return runMetrics(flags)
 		},
 	}
 
-	cmd.Flags().BoolVar(&generateAlerts, "generate-alerts", false, 
+	cmd.Flags().BoolVar(&flags.generateAlerts, "generate-alerts", false, 
 		"Generate Prometheus alerting rules")
-	cmd.Flags().BoolVar(&generateDashboard, "generate-dashboard", false, 
+	cmd.Flags().BoolVar(&flags.generateDashboard, "generate-dashboard", false, 
 		"Generate Grafana dashboard JSON")
-	cmd.Flags().StringVar(&outputFile, "output-file", "", 
+	cmd.Flags().StringVar(&flags.outputFile, "output-file", "", 
 		"Write output to file instead of stdout")
+	cmd.Flags().StringVar(&flags.outputFormat, "output", "text",
+		"Output format (text,yaml)")
+	cmd.Flags().StringVar(&flags.targetDriver, "driver", "",
+		"CSI driver name to generate driver-specific queries for")
+	cmd.Flags().StringVar(&flags.compareDriver, "compare-driver", "",
+		"Second CSI driver to compare against --driver in queries and dashboard panels")
+	cmd.Flags().StringVar(&flags.driverLabel, "driver-label", detect.DefaultDriverLabel,
+		"Prometheus label holding the driver name on CSI operation metrics, if relabelled (e.g. driver, csi_driver)")
+	cmd.Flags().StringVar(&flags.toConfigMap, "to-configmap", "",
+		"Write the Grafana dashboard to this ConfigMap in --namespace, labelled for the Grafana sidecar, instead of printing it")
+
+	return cmd
+}
+
+func newValidateConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate-config <file>",
+		Short: "Validate a detect config file and show the effective settings",
+		Long: `Load and validate a config file for detect --config without contacting the cluster.
+
+Unknown keys, invalid durations, unknown severities, methods or issue types, and
+malformed suppression globs are each reported. A valid file prints OK followed
+by the effective settings, with defaults filled in for anything the file omits.
+
+Examples:
+  # Check a config file before a scan
+  kubectl csi-mount-detective validate-config csi-scan.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runValidateConfig(os.Stdout, os.Stderr, args[0])
+		},
+	}
+
+	return cmd
+}
+
+func newSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the detect JSON output",
+		Long: `Print a JSON Schema (draft 2020-12) describing the output of detect --output=json,
+generated from the result types of this build. The schema's $id carries the output
+schemaVersion, so consumers can validate results and generate code against it.
+
+Output written with --omit-empty leaves out required fields and does not validate.
+
+Examples:
+  # Save the schema for downstream validation
+  kubectl csi-mount-detective schema > detection-result.schema.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSchema(os.Stdout)
+		},
+	}
 
 	return cmd
 }
 
+// runSchema writes the JSON Schema of the detection result to out
+func runSchema(out io.Writer) error {
+	data, err := schema.Marshal(schema.DetectionResult())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+// runValidateConfig validates a config file, printing OK and the effective settings to out
+// or every validation error to errOut
+func runValidateConfig(out, errOut io.Writer, path string) error {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+
+	if errs := cfg.Validate(); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintf(errOut, "❌ %v\n", err)
+		}
+		return fmt.Errorf("config file %s has %d error(s)", path, len(errs))
+	}
+
+	data, err := yaml.Marshal(cfg.Effective())
+	if err != nil {
+		return fmt.Errorf("failed to marshal effective settings: %w", err)
+	}
+	fmt.Fprintf(out, "OK\n\n# Effective settings\n%s", data)
+	return nil
+}
+
+// selectedNamespace returns the namespace given with --namespace, or "" when the flag is
+// not set. Unlike the kubeconfig loader it does not fall back to the context's namespace,
+// so scans cover all namespaces unless one is asked for.
+func selectedNamespace() string {
+	if configFlags.Namespace == nil {
+		return ""
+	}
+	return *configFlags.Namespace
+}
+
+// resolveConfigPath returns the config file to load: the --config value when given,
+// otherwise the default location if a file exists there, or "" for none
+func resolveConfigPath(configPath string) string {
+	if configPath != "" {
+		return configPath
+	}
+	path := config.DefaultPath()
+	if path == "" {
+		return ""
+	}
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// loadValidConfig loads a config file and fails on the first validation error
+func loadValidConfig(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if errs := cfg.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config file %s (run validate-config for details): %w", path, errs[0])
+	}
+	return cfg, nil
+}
+
+// applyConfig copies config file settings into flags that were not set on the command line
+func applyConfig(cmd *cobra.Command, flags *detectFlags, cfg *config.Config) error {
+	changed := cmd.Flags().Changed
+
+	if len(cfg.Methods) > 0 && !changed("method") {
+		flags.methods = cfg.Methods
+	}
+	if cfg.Driver != "" && !changed("driver") {
+		flags.targetDriver = cfg.Driver
+	}
+	if cfg.MinSeverity != "" && !changed("min-severity") {
+		flags.minSeverity = cfg.MinSeverity
+	}
+	if cfg.NotifyOn != "" && !changed("notify-on") {
+		flags.notifyOn = cfg.NotifyOn
+	}
+	if cfg.StuckThreshold != "" && !changed("stuck-threshold") {
+		threshold, err := cfg.StuckThresholdDuration()
+		if err != nil {
+			return err
+		}
+		flags.stuckThreshold = threshold
+	}
+	if len(cfg.DriverStuckThresholds) > 0 && !changed("driver-stuck-threshold") {
+		flags.driverThresholds = cfg.DriverStuckThresholds
+	}
+	if len(cfg.DeviceBusyPatterns) > 0 && !changed("device-busy-patterns") {
+		flags.deviceBusyPatterns = cfg.DeviceBusyPatterns
+	}
+	flags.suppressions = cfg.Suppressions
+	flags.severityOverrides = cfg.SeverityOverrideValues()
+
+	return nil
+}
+
+// defaultCleanupImage is the cleanup job image when neither --image nor the
+// CSI_SCAN_CLEANUP_IMAGE environment variable is set
+const defaultCleanupImage = "kubectl-csi-scan:latest"
+
+// cleanupImageEnv overrides the default cleanup image, for example to point at a mirror in
+// an air-gapped registry
+const cleanupImageEnv = "CSI_SCAN_CLEANUP_IMAGE"
+
+// cleanupFlags holds the flag values of the cleanup command
+type cleanupFlags struct {
+	targetNodes      []string
+	dryRun           bool
+	verbose          bool
+	recreate         bool
+	cooldown         time.Duration
+	force            bool
+	readOnly         bool
+	image            string
+	imagePullPolicy  string
+	mountPropagation string
+	namespace        string
+	serviceAccount   string
+	createRBAC       bool
+	timeout          time.Duration
+	pollInterval     time.Duration
+	maxPollInterval  time.Duration
+	maxWait          time.Duration
+	fromDetect       string
+	since            time.Duration
+	includeUntimed   bool
+}
+
 func newCleanupCmd() *cobra.Command {
-	var (
-		targetNodes      []string
-		dryRun          bool
-		verbose         bool
-		image           string
-		imagePullPolicy string
-		namespace       string
-		serviceAccount  string
-		timeout         time.Duration
-	)
+	var flags cleanupFlags
 
 	cmd := &cobra.Command{
 		Use:   "cleanup",
@@ -194,7 +822,7 @@ The cleanup jobs run privileged containers on target nodes to safely unmount
 stuck CSI mount references that prevent proper volume detachment.
 
 Examples:
-  # Dry run cleanup on specific nodes
+  # Dry run cleanup on specific nodes, ending with a per-node plan of what would change
   kubectl csi-mount-detective cleanup --nodes=knode57,knode55 --dry-run
 
   # Perform actual cleanup on nodes with issues
@@ -203,120 +831,303 @@ Examples:
   # Cleanup with custom container image
   kubectl csi-mount-detective cleanup --nodes=knode57 --image=myregistry/csi-cleanup:latest
 
+  # Use a mirrored image for every run, e.g. in an air-gapped cluster
+  export CSI_SCAN_CLEANUP_IMAGE=registry.internal/csi-cleanup:latest
+  kubectl csi-mount-detective cleanup --nodes=knode57
+
+  # Run under a PodSecurity policy that forbids Bidirectional mount propagation
+  kubectl csi-mount-detective cleanup --nodes=knode57 --mount-propagation=HostToContainer
+
+  # Only report stuck mounts where privileged containers are forbidden
+  kubectl csi-mount-detective cleanup --nodes=knode57 --read-only
+
   # Cleanup with verbose logging
   kubectl csi-mount-detective cleanup --nodes=knode57 --verbose
 
+  # Replace a finished cleanup job left over from a previous run
+  kubectl csi-mount-detective cleanup --nodes=knode57 --recreate
+
+  # Check job status less often as the wait drags on, and stop waiting after 5 minutes
+  kubectl csi-mount-detective cleanup --nodes=knode57,knode55 --poll-interval=2s --max-poll-interval=30s --max-wait=5m
+
+  # Also create the cleanup ClusterRole and bind it to the service account
+  kubectl csi-mount-detective cleanup --nodes=knode57 --create-rbac
+
+  # Re-run cleanup on a node that was cleaned up a few minutes ago
+  kubectl csi-mount-detective cleanup --nodes=knode57 --force
+
+  # Clean up the nodes with issues in a saved detection run
+  kubectl csi-mount-detective cleanup --from-detect=scan.json --dry-run
+
+  # Only clean up nodes whose issues were detected in the last hour
+  kubectl csi-mount-detective cleanup --from-detect=scan.json --since=1h
+
+  # Delete the stuck VolumeAttachments found by a saved detection run
+  kubectl csi-mount-detective cleanup volumeattachments --from-detect=scan.json
+
 Security Notes:
 - Cleanup jobs run with privileged security context
 - Jobs have access to host filesystem mount points
+- With --read-only, jobs run unprivileged and only report stuck mounts
 - Use --dry-run first to verify what would be cleaned up`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCleanup(targetNodes, dryRun, verbose, image, imagePullPolicy, namespace, serviceAccount, timeout)
+			return runCleanup(flags)
 		},
 	}
 
-	cmd.Flags().StringSliceVar(&targetNodes, "nodes", []string{}, 
-		"Target nodes for cleanup (required)")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, 
+	cmd.Flags().StringSliceVar(&flags.targetNodes, "nodes", []string{}, 
+		"Target nodes for cleanup (required unless --from-detect is set)")
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, 
 		"Show what would be cleaned up without making changes")
-	cmd.Flags().BoolVar(&verbose, "verbose", false, 
+	cmd.Flags().BoolVar(&flags.verbose, "verbose", false, 
 		"Enable verbose logging in cleanup jobs")
-	cmd.Flags().StringVar(&image, "image", "kubectl-csi-scan:latest", 
-		"Container image for cleanup jobs")
-	cmd.Flags().StringVar(&imagePullPolicy, "image-pull-policy", "IfNotPresent", 
+	cmd.Flags().BoolVar(&flags.recreate, "recreate", false,
+		"Delete and recreate a finished cleanup job for a node instead of creating one with a suffixed name")
+	cmd.Flags().DurationVar(&flags.cooldown, "cooldown", cleanup.DefaultCooldown,
+		"Skip nodes whose cleanup job finished within this window")
+	cmd.Flags().BoolVar(&flags.force, "force", false,
+		"Run cleanup even on nodes cleaned up within the --cooldown window")
+	cmd.Flags().StringVar(&flags.image, "image", cleanupImage(),
+		"Container image for cleanup jobs (defaults to $"+cleanupImageEnv+" when set)")
+	cmd.Flags().StringVar(&flags.imagePullPolicy, "image-pull-policy", "IfNotPresent", 
 		"Image pull policy for cleanup jobs")
-	cmd.Flags().StringVar(&namespace, "namespace", "default", 
+	cmd.Flags().BoolVar(&flags.readOnly, "read-only", false,
+		"Run unprivileged jobs with read-only mounts that only report stuck mounts, for clusters that forbid privileged containers")
+	cmd.Flags().StringVar(&flags.mountPropagation, "mount-propagation", "",
+		"Propagation of the kubelet-dir mount (Bidirectional, HostToContainer, None); modes other than Bidirectional suit stricter PodSecurity policies but cannot unmount on the host (default Bidirectional, or HostToContainer with --read-only)")
+	cmd.Flags().StringVar(&flags.namespace, "namespace", "default", 
 		"Namespace to create cleanup jobs in")
-	cmd.Flags().StringVar(&serviceAccount, "service-account", "kubectl-csi-scan-cleanup", 
+	cmd.Flags().StringVar(&flags.serviceAccount, "service-account", "kubectl-csi-scan-cleanup", 
 		"Service account for cleanup jobs")
-	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute, 
+	cmd.Flags().BoolVar(&flags.createRBAC, "create-rbac", false,
+		"Create the cleanup ClusterRole and bind it to the service account if they do not exist")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", 10*time.Minute, 
 		"Timeout for cleanup job completion")
+	cmd.Flags().DurationVar(&flags.pollInterval, "poll-interval", cleanup.DefaultPollInterval,
+		"How often to check cleanup job status while waiting")
+	cmd.Flags().DurationVar(&flags.maxPollInterval, "max-poll-interval", 0,
+		"Double the poll interval after each check up to this value (0 keeps it fixed)")
+	cmd.Flags().DurationVar(&flags.maxWait, "max-wait", 0,
+		"Stop waiting for cleanup jobs after this long, leaving them running (0 waits until --timeout)")
+	cmd.Flags().StringVar(&flags.fromDetect, "from-detect", "",
+		"Also clean up the nodes with issues in this result saved with detect --save or --cache-file")
+	cmd.Flags().DurationVar(&flags.since, "since", 0,
+		"With --from-detect, only clean up nodes whose issues were detected within this window (0 takes every node)")
+	cmd.Flags().BoolVar(&flags.includeUntimed, "include-untimed", false,
+		"With --since, also clean up nodes whose issues have no detection time")
+
+	cmd.AddCommand(newCleanupVolumeAttachmentsCmd())
+
+	return cmd
+}
+
+// volumeAttachmentCleanupFlags holds the flag values of the cleanup volumeattachments command
+type volumeAttachmentCleanupFlags struct {
+	names      []string
+	fromDetect string
+	dryRun     bool
+	force      bool
+	timeout    time.Duration
+}
+
+func newCleanupVolumeAttachmentsCmd() *cobra.Command {
+	var flags volumeAttachmentCleanupFlags
+
+	cmd := &cobra.Command{
+		Use:   "volumeattachments [NAME...]",
+		Short: "Delete stuck VolumeAttachment objects",
+		Long: `Delete stuck VolumeAttachment objects so the external-attacher can retry the
+attach or release the volume. Attachments are named as arguments, taken from the
+stuck attach and detach issues of a result saved with detect --save, or both.
+
+An attachment is never deleted while a running pod on its node uses the volume,
+as that would detach the volume from under the pod. Attachments of inline volumes
+and attachments that no longer exist are skipped as well.
+
+Examples:
+  # Show which attachments from a saved detection run would be deleted
+  kubectl csi-mount-detective cleanup volumeattachments --from-detect=scan.json --dry-run
+
+  # Delete specific attachments without a confirmation prompt
+  kubectl csi-mount-detective cleanup volumeattachments csi-0a1b2c csi-3d4e5f --force`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags.names = args
+			return runCleanupVolumeAttachments(flags, os.Stdin, os.Stderr)
+		},
+	}
 
-	cmd.MarkFlagRequired("nodes")
+	cmd.Flags().StringVar(&flags.fromDetect, "from-detect", "",
+		"Also delete the VolumeAttachments of stuck attach and detach issues in this result saved with detect --save or --cache-file")
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false,
+		"Show which attachments would be deleted without deleting them")
+	cmd.Flags().BoolVar(&flags.force, "force", false,
+		"Delete without asking for confirmation")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", 2*time.Minute,
+		"Timeout for looking up and deleting the attachments")
 
 	return cmd
 }
 
-func runCleanup(targetNodes []string, dryRun, verbose bool, image, imagePullPolicy, namespace, serviceAccount string, timeout time.Duration) error {
-	if len(targetNodes) == 0 {
-		return fmt.Errorf("no target nodes specified - use --nodes flag")
+func runCleanupVolumeAttachments(flags volumeAttachmentCleanupFlags, in io.Reader, out io.Writer) error {
+	names := slices.Clone(flags.names)
+	if flags.fromDetect != "" {
+		result, err := cache.LoadResult(flags.fromDetect)
+		if err != nil {
+			return err
+		}
+		names = append(names, cleanup.StuckVolumeAttachments(result)...)
+	}
+	if len(names) == 0 && flags.fromDetect == "" {
+		return fmt.Errorf("no VolumeAttachments specified - name them as arguments or use --from-detect")
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+	if len(names) == 0 {
+		fmt.Fprintf(out, "✅ No stuck VolumeAttachments to delete\n")
+		return nil
 	}
 
 	log.Info().
-		Strs("nodes", targetNodes).
-		Bool("dry_run", dryRun).
-		Bool("verbose", verbose).
-		Str("image", image).
-		Str("namespace", namespace).
-		Dur("timeout", timeout).
-		Msg("starting cleanup job creation")
+		Strs("volume_attachments", names).
+		Str("from_detect", flags.fromDetect).
+		Bool("dry_run", flags.dryRun).
+		Bool("force", flags.force).
+		Msg("starting VolumeAttachment cleanup")
 
-	// Build Kubernetes client
 	kubeClient, err := buildKubernetesClient()
 	if err != nil {
 		log.Error().Err(err).Msg("failed to build Kubernetes client")
 		return newClientError(err)
 	}
 
-	// Create cleanup job manager
-	jobManager := cleanup.NewCleanupJobManager(kubeClient, namespace)
-
-	// Progress feedback
-	fmt.Fprintf(os.Stderr, "Creating cleanup jobs for %d node(s)...\n", len(targetNodes))
-
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
-	var createdJobs []string
-	var failed []string
+	return deleteVolumeAttachments(context.Background(), cleanup.NewVolumeAttachmentCleaner(kubeClient), names, flags, in, out)
+}
 
-	for _, node := range targetNodes {
-		jobConfig := cleanup.CleanupJobConfig{
-			NodeName:        node,
-			DryRun:          dryRun,
-			Verbose:         verbose,
-			Image:           image,
-			ImagePullPolicy: imagePullPolicy,
-			Namespace:       namespace,
-			ServiceAccount:  serviceAccount,
-		}
+// deleteVolumeAttachments plans the deletion of the named attachments, reports the ones
+// skipped and, unless this is a dry run or the user declines, deletes the rest. Looking
+// up and deleting each get the full timeout, so time spent at the prompt counts towards
+// neither.
+func deleteVolumeAttachments(ctx context.Context, cleaner *cleanup.VolumeAttachmentCleaner, names []string, flags volumeAttachmentCleanupFlags, in io.Reader, out io.Writer) error {
+	planCtx, cancel := context.WithTimeout(ctx, flags.timeout)
+	targets, err := cleaner.Plan(planCtx, names)
+	cancel()
+	if err != nil {
+		return err
+	}
 
-		jobName, err := jobManager.CreateCleanupJob(ctx, jobConfig)
-		if err != nil {
-			log.Error().Err(err).Str("node", node).Msg("failed to create cleanup job")
-			failed = append(failed, node)
+	eligible := 0
+	for _, target := range targets {
+		if !target.Eligible() {
+			fmt.Fprintf(out, "⏭️  Skipping VolumeAttachment %s: %s\n", target.Name, target.Skip)
 			continue
 		}
+		eligible++
+		verb := "Will delete"
+		if flags.dryRun {
+			verb = "Would delete"
+		}
+		fmt.Fprintf(out, "🗑️  %s VolumeAttachment %s (PV %s on node %s)\n", verb, target.Name, target.PV, valueOrDash(target.Node))
+	}
+	if eligible == 0 || flags.dryRun {
+		return nil
+	}
 
-		createdJobs = append(createdJobs, jobName)
-		fmt.Fprintf(os.Stderr, "✅ Created job %s for node %s\n", jobName, node)
+	if !flags.force && !confirm(in, out, fmt.Sprintf("Delete %d VolumeAttachment(s)?", eligible)) {
+		fmt.Fprintf(out, "Aborted, nothing was deleted\n")
+		return nil
 	}
 
-	if len(failed) > 0 {
-		fmt.Fprintf(os.Stderr, "❌ Failed to create jobs for nodes: %v\n", failed)
+	deleteCtx, cancel := context.WithTimeout(ctx, flags.timeout)
+	defer cancel()
+	deleted, err := cleaner.Delete(deleteCtx, targets)
+	for _, name := range deleted {
+		fmt.Fprintf(out, "✅ Deleted VolumeAttachment %s\n", name)
 	}
+	return err
+}
 
-	if len(createdJobs) > 0 {
-		fmt.Fprintf(os.Stderr, "\nMonitoring job progress...\n")
-		return jobManager.WaitForJobs(ctx, createdJobs)
+// confirm asks a yes/no question on out and reports whether the answer read from in is yes
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// cleanupImage returns the default for --image: the CSI_SCAN_CLEANUP_IMAGE environment
+// variable if set, otherwise the built-in image
+func cleanupImage() string {
+	if image := os.Getenv(cleanupImageEnv); image != "" {
+		return image
 	}
+	return defaultCleanupImage
+}
 
-	return fmt.Errorf("no cleanup jobs were created successfully")
+// newMountProber creates the prober of detect --probe-mounts, which runs the cleanup image
+// read-only with the cleanup service account
+func newMountProber(kubeClient kubernetes.Interface, namespace string) *cleanup.MountProber {
+	return cleanup.NewMountProber(cleanup.NewCleanupJobManager(kubeClient, namespace), cleanup.CleanupJobConfig{
+		Image:           cleanupImage(),
+		ImagePullPolicy: "IfNotPresent",
+		Namespace:       namespace,
+		ServiceAccount:  "kubectl-csi-scan-cleanup",
+	})
 }
 
-func runDetect(methods []string, targetDriver, outputFormat string, recommendCleanup bool, minSeverity string) error {
-	// Validate input parameters
-	if err := validateDetectFlags(methods, outputFormat, minSeverity); err != nil {
+func runCleanup(flags cleanupFlags) error {
+	if flags.since < 0 {
+		return fmt.Errorf("invalid since %s: must not be negative", flags.since)
+	}
+	if flags.since > 0 && flags.fromDetect == "" {
+		return fmt.Errorf("--since requires --from-detect")
+	}
+	if flags.includeUntimed && flags.since == 0 {
+		return fmt.Errorf("--include-untimed requires --since")
+	}
+	targetNodes, err := cleanupTargetNodes(flags, time.Now())
+	if err != nil {
 		return err
 	}
+	if len(targetNodes) == 0 {
+		if flags.fromDetect == "" {
+			return fmt.Errorf("no target nodes specified - use --nodes or --from-detect")
+		}
+		fmt.Fprintf(os.Stderr, "✅ No nodes to clean up in %s\n", flags.fromDetect)
+		return nil
+	}
+	flags.targetNodes = targetNodes
+	if flags.pollInterval <= 0 {
+		return fmt.Errorf("invalid poll interval %s: must be positive", flags.pollInterval)
+	}
+	if flags.maxPollInterval < 0 || flags.maxWait < 0 {
+		return fmt.Errorf("--max-poll-interval and --max-wait must not be negative")
+	}
+	var mountPropagation corev1.MountPropagationMode
+	if flags.mountPropagation != "" {
+		var err error
+		if mountPropagation, err = cleanup.ParseMountPropagation(flags.mountPropagation); err != nil {
+			return err
+		}
+		if flags.readOnly && mountPropagation == corev1.MountPropagationBidirectional {
+			return fmt.Errorf("--mount-propagation=%s requires privileged jobs and cannot be combined with --read-only", mountPropagation)
+		}
+	}
 
 	log.Info().
-		Strs("methods", methods).
-		Str("driver", targetDriver).
-		Str("format", outputFormat).
-		Bool("recommend_cleanup", recommendCleanup).
-		Str("min_severity", minSeverity).
-		Msg("starting detection process")
+		Strs("nodes", flags.targetNodes).
+		Str("from_detect", flags.fromDetect).
+		Dur("since", flags.since).
+		Bool("dry_run", flags.dryRun).
+		Bool("verbose", flags.verbose).
+		Bool("recreate", flags.recreate).
+		Dur("cooldown", flags.cooldown).
+		Bool("force", flags.force).
+		Bool("read_only", flags.readOnly).
+		Str("image", flags.image).
+		Str("mount_propagation", flags.mountPropagation).
+		Str("namespace", flags.namespace).
+		Dur("timeout", flags.timeout).
+		Msg("starting cleanup job creation")
 
 	// Build Kubernetes client
 	kubeClient, err := buildKubernetesClient()
@@ -325,96 +1136,1171 @@ func runDetect(methods []string, targetDriver, outputFormat string, recommendCle
 		return newClientError(err)
 	}
 
-	// Parse detection methods
-	var detectionMethods []types.DetectionMethod
-	for _, method := range methods {
-		switch method {
-		case "volumeattachments":
-			detectionMethods = append(detectionMethods, types.VolumeAttachmentMethod)
-		case "cross-node-pvc":
-			detectionMethods = append(detectionMethods, types.CrossNodePVCMethod)
-		case "events":
-			detectionMethods = append(detectionMethods, types.EventsMethod)
-		case "metrics":
-			detectionMethods = append(detectionMethods, types.MetricsMethod)
-		default:
-			return fmt.Errorf("unknown detection method: %s", method)
-		}
+	// Create cleanup job manager
+	jobManager := cleanup.NewCleanupJobManager(kubeClient, flags.namespace)
+	statusTable := newJobStatusTable(os.Stderr)
+	jobManager.SetWaitOptions(cleanup.WaitOptions{
+		PollInterval:    flags.pollInterval,
+		MaxPollInterval: flags.maxPollInterval,
+		MaxWait:         flags.maxWait,
+		OnStatus:        statusTable.update,
+	})
+
+	// Progress feedback
+	fmt.Fprintf(os.Stderr, "Creating cleanup jobs for %d node(s)...\n", len(flags.targetNodes))
+
+	ctx, cancel := context.WithTimeout(context.Background(), flags.timeout)
+	defer cancel()
+
+	var createdJobs []string
+	var created []cleanup.CleanupJobResult
+	var failed []string
+	var skipped []string
+
+	cooldown := flags.cooldown
+	if flags.force {
+		cooldown = 0
 	}
 
-	// Parse minimum severity
-	var minSev types.IssueSeverity
-	if minSeverity != "" {
-		switch strings.ToLower(minSeverity) {
-		case "low":
-			minSev = types.SeverityLow
-		case "medium":
-			minSev = types.SeverityMedium
-		case "high":
-			minSev = types.SeverityHigh
-		case "critical":
-			minSev = types.SeverityCritical
-		default:
-			return fmt.Errorf("unknown severity level: %s", minSeverity)
-		}
+	jobConfig := cleanup.CleanupJobConfig{
+		DryRun:           flags.dryRun,
+		Verbose:          flags.verbose,
+		Image:            flags.image,
+		ImagePullPolicy:  flags.imagePullPolicy,
+		MountPropagation: mountPropagation,
+		ReadOnly:         flags.readOnly,
+		Namespace:        flags.namespace,
+		ServiceAccount:   flags.serviceAccount,
+		CreateRBAC:       flags.createRBAC,
+		Recreate:         flags.recreate,
+		Cooldown:         cooldown,
+	}
+
+	results, err := jobManager.CreateCleanupJobs(ctx, flags.targetNodes, jobConfig)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to prepare cleanup jobs")
+		return err
+	}
+
+	for _, result := range results {
+		node := result.NodeName
+		if errors.Is(result.Err, cleanup.ErrJobRunning) {
+			fmt.Fprintf(os.Stderr, "⏭️  Skipping node %s: %v\n", node, result.Err)
+			skipped = append(skipped, node)
+			continue
+		}
+		if errors.Is(result.Err, cleanup.ErrRecentlyCleaned) {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping node %s: %v (use --force to run anyway)\n", node, result.Err)
+			skipped = append(skipped, node)
+			continue
+		}
+		if result.Err != nil {
+			log.Error().Err(result.Err).Str("node", node).Msg("failed to create cleanup job")
+			failed = append(failed, node)
+			continue
+		}
+
+		createdJobs = append(createdJobs, result.JobName)
+		created = append(created, result)
+		fmt.Fprintf(os.Stderr, "✅ Created job %s for node %s\n", result.JobName, node)
+	}
+
+	if len(failed) > 0 {
+		fmt.Fprintf(os.Stderr, "❌ Failed to create jobs for nodes: %v\n", failed)
+	}
+
+	if len(createdJobs) > 0 {
+		fmt.Fprintf(os.Stderr, "\nMonitoring job progress...\n")
+		logCtx, stopLogs := context.WithCancel(ctx)
+		waitLogs := streamJobLogs(logCtx, jobManager, created, statusTable)
+		if err := jobManager.WaitForJobs(ctx, createdJobs); err != nil {
+			// Jobs still running are left behind; stop following them
+			stopLogs()
+			waitLogs()
+			return err
+		}
+		// The pods have exited, so the streams end once their last lines are written
+		waitLogs()
+		stopLogs()
+		if flags.dryRun {
+			writeDryRunPreview(os.Stdout, collectDryRunPreview(ctx, jobManager, created))
+		}
+		return nil
+	}
+
+	// Every node already has a cleanup job in progress or was cleaned up recently
+	if len(failed) == 0 && len(skipped) > 0 {
+		return nil
+	}
+
+	return fmt.Errorf("no cleanup jobs were created successfully")
+}
+
+// cleanupTargetNodes returns the --nodes values together with the nodes of the issues in
+// the --from-detect result, limited to those detected within --since of now, each once
+func cleanupTargetNodes(flags cleanupFlags, now time.Time) ([]string, error) {
+	nodes := slices.Clone(flags.targetNodes)
+	if flags.fromDetect != "" {
+		result, err := cache.LoadResult(flags.fromDetect)
+		if err != nil {
+			return nil, err
+		}
+		times := cleanup.NodeDetectionTimes(result)
+		nodes = append(nodes, cleanup.RecentNodes(times, flags.since, now, flags.includeUntimed)...)
+	}
+	slices.Sort(nodes)
+	return slices.Compact(nodes), nil
+}
+
+// collectDryRunPreview gathers the changes each dry-run job reported, by node. Nodes whose
+// plan cannot be read are left out with a warning.
+func collectDryRunPreview(ctx context.Context, jobManager *cleanup.CleanupJobManager, jobs []cleanup.CleanupJobResult) map[string][]cleanup.PlannedChange {
+	preview := make(map[string][]cleanup.PlannedChange, len(jobs))
+	for _, job := range jobs {
+		changes, err := jobManager.PlannedChanges(ctx, job.JobName)
+		if err != nil {
+			log.Warn().Err(err).Str("node", job.NodeName).Msg("failed to read dry-run plan")
+			fmt.Fprintf(os.Stderr, "⚠️  Could not read the dry-run plan for node %s: %v\n", job.NodeName, err)
+			continue
+		}
+		preview[job.NodeName] = changes
+	}
+	return preview
+}
+
+// writeDryRunPreview renders the changes dry-run cleanup jobs would make as one table,
+// grouped by node
+func writeDryRunPreview(w io.Writer, preview map[string][]cleanup.PlannedChange) {
+	fmt.Fprintf(w, "\nDRY-RUN PLAN:\n")
+	fmt.Fprintf(w, "%-30s %-30s %s\n", "NODE", "ACTION", "PATH")
+	for _, node := range slices.Sorted(maps.Keys(preview)) {
+		if len(preview[node]) == 0 {
+			fmt.Fprintf(w, "%-30s %-30s %s\n", node, "(nothing to clean up)", "-")
+			continue
+		}
+		for _, change := range preview[node] {
+			fmt.Fprintf(w, "%-30s %-30s %s\n", node, change.Action, change.Path)
+		}
+	}
+}
+
+// jobStatusTable shows cleanup job states while waiting. On a terminal the table is
+// redrawn in place on every poll; otherwise only state changes are printed, so logs stay
+// readable.
+type jobStatusTable struct {
+	mu       sync.Mutex // serializes redraws with job log lines written by logLine
+	w        io.Writer
+	inPlace  bool
+	lines    int
+	last     map[string]cleanup.JobState
+	statuses []cleanup.JobStatus
+}
+
+// newJobStatusTable creates a table writing to f, redrawing in place if f is a terminal
+func newJobStatusTable(f *os.File) *jobStatusTable {
+	info, err := f.Stat()
+	return &jobStatusTable{
+		w:       f,
+		inPlace: err == nil && info.Mode()&os.ModeCharDevice != 0,
+		last:    make(map[string]cleanup.JobState),
+	}
+}
+
+// update renders the job states of one poll
+func (t *jobStatusTable) update(statuses []cleanup.JobStatus) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.inPlace {
+		for _, status := range statuses {
+			if t.last[status.JobName] != status.State {
+				fmt.Fprintf(t.w, "%s %s (%s): %s\n", jobStateEmoji(status.State), status.NodeName, status.JobName, status.State)
+				t.last[status.JobName] = status.State
+			}
+		}
+		return
+	}
+
+	t.clear()
+	t.statuses = statuses
+	t.draw()
+}
+
+// logLine writes a line of a node's job log. On a terminal the table is cleared first and
+// redrawn below the line, so the log scrolls above it.
+func (t *jobStatusTable) logLine(node string, line []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.inPlace {
+		t.clear()
+	}
+	fmt.Fprintf(t.w, "[%s] %s", node, line)
+	if t.inPlace {
+		t.draw()
+	}
+}
+
+// clear moves the cursor back to the top of the drawn table and clears it
+func (t *jobStatusTable) clear() {
+	if t.lines > 0 {
+		fmt.Fprintf(t.w, "\033[%dA\033[J", t.lines)
+		t.lines = 0
+	}
+}
+
+// draw renders the states of the last poll, if any
+func (t *jobStatusTable) draw() {
+	if t.statuses == nil {
+		return
+	}
+	fmt.Fprintf(t.w, "%-30s %-40s %s\n", "NODE", "JOB", "STATE")
+	for _, status := range t.statuses {
+		fmt.Fprintf(t.w, "%-30s %-40s %s %s\n", status.NodeName, status.JobName, jobStateEmoji(status.State), status.State)
+	}
+	t.lines = len(t.statuses) + 1
+}
+
+// jobLogWriter writes a job's log lines to the status table, prefixed with its node
+type jobLogWriter struct {
+	table *jobStatusTable
+	node  string
+}
+
+func (w jobLogWriter) Write(p []byte) (int, error) {
+	w.table.logLine(w.node, p)
+	return len(p), nil
+}
+
+// streamJobLogs follows the logs of the jobs in the background until their pods exit or
+// ctx is done. The returned function waits for the streams to end.
+func streamJobLogs(ctx context.Context, jobManager *cleanup.CleanupJobManager, jobs []cleanup.CleanupJobResult, table *jobStatusTable) func() {
+	var streams sync.WaitGroup
+	for _, job := range jobs {
+		streams.Add(1)
+		go func() {
+			defer streams.Done()
+			err := jobManager.StreamJobLogs(ctx, job.JobName, jobLogWriter{table: table, node: job.NodeName})
+			if err != nil && ctx.Err() == nil {
+				log.Warn().Err(err).Str("job", job.JobName).Msg("failed to stream cleanup job logs")
+			}
+		}()
+	}
+	return streams.Wait
+}
+
+// jobStateEmoji returns the marker shown before a job state
+func jobStateEmoji(state cleanup.JobState) string {
+	switch state {
+	case cleanup.JobSucceeded:
+		return "✅"
+	case cleanup.JobFailed:
+		return "❌"
+	}
+	return "⏳"
+}
+
+// setLogLevel sets the global log level from the --log-level flag
+func setLogLevel(value string) error {
+	valid := []string{"debug", "info", "warn", "error"}
+	if !slices.Contains(valid, strings.ToLower(value)) {
+		return newValidationError("log level", value, valid)
+	}
+	level, err := zerolog.ParseLevel(strings.ToLower(value))
+	if err != nil {
+		return err
+	}
+	zerolog.SetGlobalLevel(level)
+	return nil
+}
+
+// setLogRedaction makes every error attached to a log line pass through the volume handle
+// redaction when enabled. Errors carry the full CSI messages, which is where handles end up.
+func setLogRedaction(enabled bool) {
+	if !enabled {
+		zerolog.ErrorMarshalFunc = func(err error) interface{} { return err }
+		return
+	}
+	zerolog.ErrorMarshalFunc = func(err error) interface{} {
+		return parse.Redact(err.Error())
+	}
+}
+
+// annotateFlags holds the flag values of the annotate command
+type annotateFlags struct {
+	pvcs          []string
+	fromDetection bool
+	targetDriver  string
+	annotation    string
+	dryRun        bool
+	timeout       time.Duration
+}
+
+func newAnnotateCmd() *cobra.Command {
+	var flags annotateFlags
+
+	cmd := &cobra.Command{
+		Use:   "annotate",
+		Short: "Annotate PVCs with detected issues to trigger a reconcile or mark them for follow-up",
+		Long: `Apply an annotation to PersistentVolumeClaims, either named explicitly or taken
+from the issues a detection run finds. Changing a PVC's annotations triggers a
+reconcile in controllers watching it, and the annotation marks stuck claims for
+follow-up. Other annotations on the PVC are left untouched.
+
+Examples:
+  # Preview annotating the PVCs named in detected issues
+  kubectl csi-mount-detective annotate --from-detection --dry-run
+
+  # Mark the PVCs with issues for one driver for follow-up
+  kubectl csi-mount-detective annotate --from-detection --driver=cinder.csi.openstack.org
+
+  # Apply a custom annotation to specific PVCs
+  kubectl csi-mount-detective annotate --pvc=default/data-web-0 --annotation=example.com/reconcile=now`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAnnotate(flags)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&flags.pvcs, "pvc", []string{},
+		"PVCs to annotate as namespace/name")
+	cmd.Flags().BoolVar(&flags.fromDetection, "from-detection", false,
+		"Also annotate every PVC named in issues found by a detection run")
+	cmd.Flags().StringVar(&flags.targetDriver, "driver", "",
+		"With --from-detection, only consider issues for this CSI driver")
+	cmd.Flags().StringVar(&flags.annotation, "annotation", remediate.DefaultAnnotation,
+		"Annotation to apply as key=value")
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false,
+		"Validate the annotation server-side without changing any PVC")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", 2*time.Minute,
+		"Timeout for detection and annotation")
+
+	return cmd
+}
+
+func runAnnotate(flags annotateFlags) error {
+	key, value, err := remediate.ParseAnnotation(flags.annotation)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range flags.pvcs {
+		if _, _, err := remediate.SplitPVC(pvc); err != nil {
+			return err
+		}
+	}
+	if len(flags.pvcs) == 0 && !flags.fromDetection {
+		return fmt.Errorf("no PVCs specified - use --pvc or --from-detection")
+	}
+	if flags.targetDriver != "" && !flags.fromDetection {
+		return fmt.Errorf("--driver requires --from-detection")
+	}
+
+	log.Info().
+		Strs("pvcs", flags.pvcs).
+		Bool("from_detection", flags.fromDetection).
+		Str("driver", flags.targetDriver).
+		Str("annotation", flags.annotation).
+		Bool("dry_run", flags.dryRun).
+		Msg("starting PVC annotation")
+
+	// Build Kubernetes client
+	kubeClient, err := buildKubernetesClient()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build Kubernetes client")
+		return newClientError(err)
+	}
+	csiClient := client.NewClient(kubeClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), flags.timeout)
+	defer cancel()
+
+	pvcs := slices.Clone(flags.pvcs)
+	if flags.fromDetection {
+		fmt.Fprintf(os.Stderr, "Detecting PVCs with issues...\n")
+		detector := detect.NewDetector(csiClient, types.DetectionOptions{
+			Methods:      []types.DetectionMethod{types.VolumeAttachmentMethod, types.CrossNodePVCMethod, types.EventsMethod},
+			TargetDriver: flags.targetDriver,
+			CSIOnly:      flags.targetDriver != "",
+		})
+		result, err := detector.DetectAll(ctx)
+		if err != nil {
+			return fmt.Errorf("detection failed: %w", err)
+		}
+		pvcs = append(pvcs, annotatablePVCs(result.Issues)...)
+	}
+	slices.Sort(pvcs)
+	pvcs = slices.Compact(pvcs)
+
+	if len(pvcs) == 0 {
+		fmt.Fprintf(os.Stderr, "✅ No PVCs with issues to annotate\n")
+		return nil
+	}
+
+	annotator := remediate.NewPVCAnnotator(csiClient, flags.dryRun)
+	var failed []string
+	for _, pvc := range pvcs {
+		if err := annotator.Annotate(ctx, pvc, key, value); err != nil {
+			log.Error().Err(err).Str("pvc", pvc).Msg("failed to annotate PVC")
+			failed = append(failed, pvc)
+			continue
+		}
+		if flags.dryRun {
+			fmt.Fprintf(os.Stderr, "🔍 Would annotate PVC %s with %s=%s\n", pvc, key, value)
+		} else {
+			fmt.Fprintf(os.Stderr, "✅ Annotated PVC %s with %s=%s\n", pvc, key, value)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to annotate %d of %d PVC(s): %v", len(failed), len(pvcs), failed)
+	}
+	return nil
+}
+
+// annotatablePVCs returns the PVCs named in issues that can be annotated. Missing PVC
+// issues name claims that do not exist, so they are left out.
+func annotatablePVCs(issues []types.CSIMountIssue) []string {
+	existing := slices.DeleteFunc(slices.Clone(issues), func(issue types.CSIMountIssue) bool {
+		return issue.Type == types.MissingPVC
+	})
+	return detect.AffectedPVCs(existing)
+}
+
+// serveFlags holds the flag values of the serve command
+type serveFlags struct {
+	listen        string
+	interval      time.Duration
+	scanTimeout   time.Duration
+	shutdownGrace time.Duration
+	methods       []string
+	targetDriver  string
+}
+
+func newServeCmd() *cobra.Command {
+	var flags serveFlags
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run detection on an interval with a health endpoint, for running in-cluster",
+		Long: `Run detection repeatedly and log the results, serving /healthz so the tool can be
+deployed as a Deployment.
+
+/healthz answers 503 until the first scan completes and 200 afterwards. /results serves
+the latest successful scan result as JSON, or 503 until there is one. On SIGTERM or
+Ctrl-C the scan in progress is cancelled and the HTTP server is given --shutdown-grace
+to finish in-flight requests.
+
+Examples:
+  # Scan every 5 minutes, serving /healthz on :8080
+  kubectl csi-mount-detective serve
+
+  # Scan one driver every minute
+  kubectl csi-mount-detective serve --driver=cinder.csi.openstack.org --interval=1m`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.listen, "listen", ":8080",
+		"Address to serve /healthz and /results on")
+	cmd.Flags().DurationVar(&flags.interval, "interval", 5*time.Minute,
+		"Time between scans")
+	cmd.Flags().DurationVar(&flags.scanTimeout, "scan-timeout", 2*time.Minute,
+		"How long each scan may run")
+	cmd.Flags().DurationVar(&flags.shutdownGrace, "shutdown-grace", serve.DefaultShutdownGrace,
+		"How long in-flight requests get to finish on shutdown")
+	cmd.Flags().StringSliceVar(&flags.methods, "method", []string{"volumeattachments", "cross-node-pvc", "events"},
+		"Detection methods to use ("+strings.Join(methodNames(), ",")+")")
+	cmd.Flags().StringVar(&flags.targetDriver, "driver", "",
+		"Target CSI driver to scan (e.g., cinder.csi.openstack.org)")
+
+	return cmd
+}
+
+func runServe(flags serveFlags) error {
+	if flags.interval <= 0 {
+		return fmt.Errorf("invalid interval %s: must be a positive duration", flags.interval)
+	}
+	if flags.scanTimeout <= 0 {
+		return fmt.Errorf("invalid scan timeout %s: must be a positive duration", flags.scanTimeout)
+	}
+	methods, err := parseMethods(flags.methods)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := buildKubernetesClient()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build Kubernetes client")
+		return newClientError(err)
+	}
+	detector := detect.NewDetector(client.NewClient(kubeClient), types.DetectionOptions{
+		Methods:      methods,
+		TargetDriver: flags.targetDriver,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := serve.New(flags.listen, flags.interval, func(ctx context.Context) (*types.DetectionResult, error) {
+		ctx, cancel := context.WithTimeout(ctx, flags.scanTimeout)
+		defer cancel()
+		return detector.DetectAll(ctx)
+	})
+	return server.Run(ctx, flags.shutdownGrace)
+}
+
+func runDetect(flags detectFlags) error {
+	// Validate input parameters
+	if err := validateDetectFlags(flags.methods, flags.outputFormat, flags.minSeverity); err != nil {
+		return err
+	}
+	if flags.withOwners && !flags.recommendCleanup {
+		return fmt.Errorf("--with-owners requires --recommend-cleanup")
+	}
+	if flags.offline && !flags.recommendCleanup {
+		return fmt.Errorf("--offline requires --recommend-cleanup")
+	}
+	if flags.groupBy != "" {
+		if flags.groupBy != "node" {
+			return newValidationError("group-by field", flags.groupBy, []string{"node"})
+		}
+		if flags.outputFormat != "json" {
+			return fmt.Errorf("--group-by requires --output=json")
+		}
+		if flags.omitEmpty {
+			return fmt.Errorf("--group-by cannot be used with --omit-empty")
+		}
+	}
+	if flags.omitEmpty && flags.outputFormat != "json" && flags.outputFormat != "yaml" {
+		return fmt.Errorf("--omit-empty requires --output=json or --output=yaml")
+	}
+	if flags.timeout <= 0 {
+		return fmt.Errorf("invalid timeout %s: must be a positive duration", flags.timeout)
+	}
+	if flags.splitByNamespace != (flags.outputDir != "") {
+		return fmt.Errorf("--split-by-namespace and --output-dir must be used together")
+	}
+	if flags.splitByNamespace && (flags.groupBy != "" || flags.omitEmpty) {
+		return fmt.Errorf("--split-by-namespace cannot be used with --group-by or --omit-empty")
+	}
+	if flags.watch {
+		if flags.watchInterval <= 0 {
+			return fmt.Errorf("invalid watch interval %s: must be a positive duration", flags.watchInterval)
+		}
+		if flags.outputFormat != "table" {
+			return fmt.Errorf("--watch requires --output=table")
+		}
+		if len(flags.contexts) > 0 || flags.splitByNamespace || flags.baselineConfigMap != "" || flags.cacheFile != "" || flags.webhookURL != "" || flags.pushGateway != "" || flags.savePath != "" || flags.failOn != "" {
+			return fmt.Errorf("--watch cannot be used with --contexts, --split-by-namespace, --baseline-configmap, --cache-file, --webhook-url, --push-gateway, --save or --fail-on")
+		}
+	}
+	if flags.probeMounts && (flags.watch || len(flags.contexts) > 0) {
+		return fmt.Errorf("--probe-mounts cannot be used with --watch or --contexts")
+	}
+	notifyOn, err := parseSeverity(flags.notifyOn)
+	if flags.webhookURL != "" && err != nil {
+		return newValidationError("notify-on severity", flags.notifyOn, []string{"low", "medium", "high", "critical"})
+	}
+	var failOn types.IssueSeverity
+	if flags.failOn != "" {
+		if failOn, err = parseSeverity(flags.failOn); err != nil {
+			return newValidationError("fail-on severity", flags.failOn, []string{"low", "medium", "high", "critical"})
+		}
+	}
+	if flags.stuckThreshold <= 0 {
+		return fmt.Errorf("invalid stuck threshold %s: must be a positive duration", flags.stuckThreshold)
+	}
+	if flags.deletionThreshold <= 0 {
+		return fmt.Errorf("invalid deletion threshold %s: must be a positive duration", flags.deletionThreshold)
+	}
+	driverThresholds, err := parseDriverThresholds(flags.driverThresholds)
+	if err != nil {
+		return err
+	}
+	if flags.cacheFile != "" && flags.cacheTTL <= 0 {
+		return fmt.Errorf("invalid cache TTL %s: must be a positive duration", flags.cacheTTL)
+	}
+	if flags.enrichErrorLimit < 1 {
+		return fmt.Errorf("invalid enrichment error limit %d: must be at least 1", flags.enrichErrorLimit)
+	}
+	criticalThresholds, err := parseStatusThresholds("critical-when", flags.criticalWhen)
+	if err != nil {
+		return err
+	}
+	degradedThresholds, err := parseStatusThresholds("degraded-when", flags.degradedWhen)
+	if err != nil {
+		return err
+	}
+	if flags.pvc != "" {
+		if _, _, err := remediate.SplitPVC(flags.pvc); err != nil {
+			return err
+		}
+	}
+	if flags.baselineConfigMap != "" && len(flags.contexts) > 0 {
+		return fmt.Errorf("--baseline-configmap cannot be used with --contexts")
+	}
+	if flags.nodePVCWarn < 0 {
+		return fmt.Errorf("invalid node PVC warning threshold %d: must not be negative", flags.nodePVCWarn)
+	}
+	if flags.nodePVCWarn > 0 && !slices.Contains(flags.methods, "cross-node-pvc") {
+		return fmt.Errorf("--node-pvc-warn requires the cross-node-pvc method")
+	}
+	if flags.scanFailureExitCode < 1 || flags.scanFailureExitCode > 125 {
+		return fmt.Errorf("invalid scan failure exit code %d: must be between 1 and 125", flags.scanFailureExitCode)
+	}
+	if flags.flapThreshold < 1 {
+		return fmt.Errorf("invalid flap threshold %d: must be at least 1", flags.flapThreshold)
+	}
+	if flags.eventsLookback <= 0 {
+		return fmt.Errorf("invalid events lookback %s: must be a positive duration", flags.eventsLookback)
+	}
+	if len(flags.nodePluginSelectors) > 0 && !flags.probe {
+		return fmt.Errorf("--node-plugin-selector requires --probe")
+	}
+	if flags.probe && flags.targetDriver != "" {
+		_, known := detect.DefaultNodePluginSelectors[flags.targetDriver]
+		if _, ok := flags.nodePluginSelectors[flags.targetDriver]; !known && !ok {
+			return fmt.Errorf("no node plugin selector is known for driver %s: set one with --node-plugin-selector=%s=<selector>", flags.targetDriver, flags.targetDriver)
+		}
+	}
+	if len(flags.contexts) > 0 {
+		if flags.clusterConcurrency < 1 {
+			return fmt.Errorf("invalid cluster concurrency %d: must be at least 1", flags.clusterConcurrency)
+		}
+		if flags.clusterTimeout <= 0 {
+			return fmt.Errorf("invalid cluster timeout %s: must be a positive duration", flags.clusterTimeout)
+		}
+		if flags.cacheFile != "" || flags.webhookURL != "" || flags.pushGateway != "" || flags.savePath != "" {
+			return fmt.Errorf("--contexts cannot be combined with --cache-file, --webhook-url, --push-gateway or --save")
+		}
+	}
+
+	log.Info().
+		Strs("methods", flags.methods).
+		Str("driver", flags.targetDriver).
+		Str("format", flags.outputFormat).
+		Bool("recommend_cleanup", flags.recommendCleanup).
+		Str("min_severity", flags.minSeverity).
+		Bool("csi_only", flags.csiOnly).
+		Bool("with_owners", flags.withOwners).
+		Bool("strict_driver_match", flags.strictDriverMatch).
+		Msg("starting detection process")
+
+	// Parse detection methods
+	detectionMethods, err := parseMethods(flags.methods)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(detectionMethods, types.MetricsMethod) && flags.prometheusURL == "" {
+		fmt.Fprintln(os.Stderr, "⚠️  No --prometheus-url given - metrics detection will be skipped")
+	}
+
+	// Parse minimum severity
+	var minSev types.IssueSeverity
+	if flags.minSeverity != "" {
+		minSev, err = parseSeverity(flags.minSeverity)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Create detector
 	options := types.DetectionOptions{
-		Methods:          detectionMethods,
-		TargetDriver:     targetDriver,
-		OutputFormat:     outputFormat,
-		RecommendCleanup: recommendCleanup,
-		MinSeverity:      minSev,
+		Methods:               detectionMethods,
+		TargetDriver:          flags.targetDriver,
+		OutputFormat:          flags.outputFormat,
+		RecommendCleanup:      flags.recommendCleanup,
+		MinSeverity:           minSev,
+		CSIOnly:               flags.csiOnly,
+		WithOwners:            flags.withOwners,
+		StrictDriverMatch:     flags.strictDriverMatch,
+		DeviceBusyPatterns:    flags.deviceBusyPatterns,
+		StuckThreshold:        flags.stuckThreshold,
+		DriverStuckThresholds: driverThresholds,
+		DeletionThreshold:     flags.deletionThreshold,
+		Offline:               flags.offline,
+		Suppressions:          flags.suppressions,
+		Probe:                 flags.probe,
+		ProbeMounts:           flags.probeMounts,
+		NodePluginSelectors:   flags.nodePluginSelectors,
+		SeverityOverrides:     flags.severityOverrides,
+		CheckClaims:           flags.checkClaims,
+		EnrichmentErrorLimit:  flags.enrichErrorLimit,
+		NodePVCWarn:           flags.nodePVCWarn,
+		FlapThreshold:         flags.flapThreshold,
+		EventsLookback:        flags.eventsLookback,
+		PVC:                   flags.pvc,
+		StorageClass:          flags.storageClass,
+		PrometheusURL:         flags.prometheusURL,
+		CriticalThresholds:    criticalThresholds,
+		DegradedThresholds:    degradedThresholds,
+		NoDedup:               flags.noDedup,
+		Namespace:             selectedNamespace(),
+		BestEffort:            flags.bestEffort,
 	}
 
-	detector := detect.NewDetector(client.NewClient(kubeClient), options)
+	if len(flags.contexts) > 0 {
+		return runMultiClusterDetect(flags, options, failOn)
+	}
+
+	// Build Kubernetes client
+	kubeClient, err := buildKubernetesClient()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build Kubernetes client")
+		return scanOutcome(nil, newClientError(err), flags)
+	}
+
+	csiClient := client.NewClient(kubeClient)
+	detector := detect.NewDetector(csiClient, options)
+	if err := detector.SetPrometheusTLS(flags.prometheusCAFile, flags.prometheusInsecure); err != nil {
+		return err
+	}
+	detector.SetServerClock(serverClock)
+	if flags.probeMounts {
+		detector.SetMountProber(newMountProber(kubeClient, flags.probeNamespace))
+	}
+
+	if flags.watch {
+		return runWatch(detector, flags)
+	}
 
 	// Add progress feedback
 	fmt.Fprintf(os.Stderr, "Analyzing cluster state using %d detection methods...\n", len(detectionMethods))
 	
-	// Run detection with improved context handling
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	// Run detection with improved context handling. Ctrl-C cancels detection so the
+	// issues found so far can still be shown.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, flags.timeout)
 	defer cancel()
 
-	result, err := detector.DetectAll(ctx)
+	started := time.Now()
+	var result *types.DetectionResult
+	if flags.cacheFile != "" {
+		var cached bool
+		result, cached, err = cache.New(flags.cacheFile, flags.cacheTTL).GetOrDetect(ctx, cacheKey(options), detector.DetectAll)
+		if cached {
+			fmt.Fprintf(os.Stderr, "♻️  Reusing detection result cached at %s\n", result.GeneratedAt.Format(time.RFC3339))
+		}
+	} else {
+		result, err = detector.DetectAll(ctx)
+	}
+	// Let a second Ctrl-C terminate immediately while output is written
+	stop()
+
+	if err != nil && result != nil && result.Partial {
+		fmt.Fprintf(os.Stderr, "⚠️  Detection stopped early - showing %d issues from %d of %d methods\n",
+			len(result.Issues), len(result.Summary.MethodsUsed), len(detectionMethods))
+		if outErr := outputResult(result, flags); outErr != nil {
+			return outErr
+		}
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("detection process failed")
-		if ctx.Err() == context.DeadlineExceeded {
-			return fmt.Errorf("detection timed out after 2 minutes - try reducing scope with --driver flag or --method selection")
-		}
-		return newDetectionError("general", err)
+		return scanOutcome(result, detectionFailure(ctx, err, flags.timeout), flags)
 	}
 
 	log.Info().
 		Int("issues_found", len(result.Issues)).
 		Msg("detection completed successfully")
 
+	// Save before comparing with a baseline so the file holds every issue found
+	if flags.savePath != "" {
+		if err := cache.SaveResult(flags.savePath, result); err != nil {
+			return fmt.Errorf("failed to save detection result: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "💾 Saved detection result to %s\n", flags.savePath)
+	}
+
+	// Push before comparing with a baseline so the gauges count every issue found
+	if flags.pushGateway != "" {
+		exporter := export.NewPushgatewayExporter(flags.pushGateway, export.JobName(currentContextName()))
+		if err := exporter.Push(context.Background(), result, time.Since(started)); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "📈 Pushed detection metrics to %s\n", flags.pushGateway)
+	}
+
+	if flags.baselineConfigMap != "" {
+		result, err = compareWithBaseline(csiClient, flags.baselineConfigMap, result)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Add success feedback
 	if len(result.Issues) == 0 {
 		fmt.Fprintf(os.Stderr, "✅ No CSI mount issues detected\n")
 	} else {
 		fmt.Fprintf(os.Stderr, "⚠️  Found %d issues\n", len(result.Issues))
 	}
+	if result.Summary.Suppressed > 0 {
+		fmt.Fprintf(os.Stderr, "🔇 Suppressed %d known issue(s)\n", result.Summary.Suppressed)
+	}
+	writeMethodErrors(os.Stderr, result.Errors)
+	fmt.Fprintf(os.Stderr, "%s Status: %s\n", statusEmoji(result.Summary.Status), strings.ToUpper(string(result.Summary.Status)))
 
 	// Output results
-	return outputResult(result, outputFormat)
+	if flags.splitByNamespace {
+		count, err := writeNamespaceReports(flags.outputDir, result, options)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "📁 Wrote %d namespace reports and %s.json to %s\n", count, clusterReport, flags.outputDir)
+	} else if err := outputResult(result, flags); err != nil {
+		return err
+	}
+	if flags.summaryLine {
+		fmt.Fprintln(os.Stderr, summaryLine(result))
+	}
+
+	if flags.webhookURL != "" {
+		sent, err := notify.NewWebhookNotifier(flags.webhookURL).Notify(context.Background(), result, notifyOn)
+		if err != nil {
+			return fmt.Errorf("failed to send webhook notification: %w", err)
+		}
+		if sent {
+			fmt.Fprintf(os.Stderr, "📣 Posted detection summary to webhook\n")
+		}
+	}
+
+	return failOnOutcome(result.Issues, failOn)
 }
 
-func runAnalyze() error {
-	kubeClient, err := buildKubernetesClient()
+// runWatch rescans on every interval until interrupted. Each scan replaces the previous
+// one on a terminal, or follows it after a separator otherwise, and is followed by the
+// issues that appeared or resolved since the previous scan. Ctrl-C stops watching cleanly.
+func runWatch(detector *detect.Detector, flags detectFlags) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	info, err := os.Stdout.Stat()
+	inPlace := err == nil && info.Mode()&os.ModeCharDevice != 0
+
+	ticker := time.NewTicker(flags.watchInterval)
+	defer ticker.Stop()
+
+	var previous *types.DetectionResult
+	for {
+		scanCtx, cancel := context.WithTimeout(ctx, flags.timeout)
+		result, err := detector.DetectAll(scanCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if inPlace {
+			// Move the cursor home and clear the screen
+			fmt.Fprint(os.Stdout, "\033[H\033[2J")
+		} else if previous != nil {
+			fmt.Fprintln(os.Stdout, strings.Repeat("-", 80))
+		}
+		fmt.Fprintf(os.Stdout, "Every %s: %s (Ctrl-C to stop)\n\n", flags.watchInterval, time.Now().Format(time.RFC3339))
+
+		if err != nil && (result == nil || !result.Partial) {
+			fmt.Fprintf(os.Stdout, "❌ Scan failed: %v\n", err)
+		} else {
+			if err := writeResult(os.Stdout, result, flags); err != nil {
+				return err
+			}
+			previous = writeWatchDiff(os.Stdout, previous, result)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// detectionFailure explains why detection run under ctx failed, naming the timeout when
+// its deadline passed
+func detectionFailure(ctx context.Context, err error, timeout time.Duration) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("detection timed out after %s - try reducing scope with --driver flag or --method selection, or allow longer with --timeout", timeout)
+	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return fmt.Errorf("detection interrupted, results are partial: %w", err)
+	}
+	return newDetectionError("general", err)
+}
+
+// watchChanges returns the issues that appeared in current and those of previous that
+// are gone, matching issues on their type, node, volume and PVC
+func watchChanges(previous, current *types.DetectionResult) baseline.Delta {
+	return baseline.Diff(previous.Issues, current.Issues)
+}
+
+// writeWatchDiff writes the changes between the previous watch scan and result, returning
+// the scan the next one should be compared against. An incomplete result is not compared,
+// since the issues of the methods that did not finish would show as resolved, and previous
+// is kept.
+func writeWatchDiff(w io.Writer, previous, result *types.DetectionResult) *types.DetectionResult {
+	if incompleteResult(result) {
+		if previous != nil {
+			fmt.Fprintln(w, "\nCHANGES SINCE LAST SCAN: not shown, this scan did not complete every method")
+		}
+		return previous
+	}
+	if previous != nil {
+		writeWatchChanges(w, watchChanges(previous, result))
+	}
+	return result
+}
+
+// incompleteResult reports whether a detection was interrupted or had methods fail, so its
+// issues cannot be compared against a complete scan
+func incompleteResult(result *types.DetectionResult) bool {
+	return result.Partial || len(result.Errors) > 0
+}
+
+// writeWatchChanges prints the issues that appeared (+) and resolved (-) between two scans
+func writeWatchChanges(w io.Writer, delta baseline.Delta) {
+	fmt.Fprintf(w, "\nCHANGES SINCE LAST SCAN: %d new, %d resolved\n", len(delta.Added), len(delta.Resolved))
+	for _, change := range []struct {
+		marker string
+		issues []types.CSIMountIssue
+	}{{"+", delta.Added}, {"-", delta.Resolved}} {
+		for _, issue := range change.issues {
+			fmt.Fprintf(w, "  %s %-10s %-30s %-20s %-30s %s\n", change.marker, issue.Severity, issue.Type,
+				valueOrDash(issue.Node), valueOrDash(issue.PVC), valueOrDash(detect.DisplayVolume(issue)))
+		}
+	}
+}
+
+// summaryLine returns a one-line key=value summary of a result for shell scripts. The keys
+// and their order are stable; new keys are only ever appended.
+func summaryLine(result *types.DetectionResult) string {
+	bySeverity := result.Summary.IssuesBySeverity
+	return fmt.Sprintf("issues=%d critical=%d high=%d medium=%d low=%d nodes=%d status=%s",
+		len(result.Issues),
+		bySeverity[types.SeverityCritical],
+		bySeverity[types.SeverityHigh],
+		bySeverity[types.SeverityMedium],
+		bySeverity[types.SeverityLow],
+		len(result.Summary.AffectedNodes),
+		result.Summary.Status)
+}
+
+// scanOutcome returns the error that sets the exit code of a failed scan. A scan in which
+// no detection method completed exits with --scan-failure-exit-code. A scan that stopped
+// early fails too, unless --exit-zero-on-empty is set and the methods that completed found
+// no issues.
+func scanOutcome(result *types.DetectionResult, scanErr error, flags detectFlags) error {
+	if result == nil || len(result.Summary.MethodsUsed) == 0 {
+		return &exitCodeError{code: flags.scanFailureExitCode, err: scanErr}
+	}
+	if flags.exitZeroOnEmpty && len(result.Issues) == 0 {
+		log.Warn().Err(scanErr).Msg("detection stopped early without finding issues; exiting 0 for --exit-zero-on-empty")
+		return nil
+	}
+	return scanErr
+}
+
+// severityGateError reports that a scan found issues at or above the --fail-on severity.
+// It exits with the generic failure code, like any other error of a run.
+type severityGateError struct {
+	severity types.IssueSeverity
+	count    int
+}
+
+func (e *severityGateError) Error() string {
+	return fmt.Sprintf("found %d issue(s) at or above %s severity (--fail-on=%s)", e.count, e.severity, e.severity)
+}
+
+// failOnOutcome returns a severityGateError when any of the issues reaches the --fail-on
+// severity, ranking severities as --min-severity does. Without --fail-on it returns nil.
+func failOnOutcome(issues []types.CSIMountIssue, failOn types.IssueSeverity) error {
+	if failOn == "" {
+		return nil
+	}
+	if count := len(detect.FilterBySeverity(issues, failOn)); count > 0 {
+		return &severityGateError{severity: failOn, count: count}
+	}
+	return nil
+}
+
+// compareWithBaseline returns the issues that changed since the baseline stored in the named
+// ConfigMap, then stores result as the new baseline. Without a stored baseline the full
+// result is returned.
+func compareWithBaseline(csiClient client.KubernetesClient, name string, result *types.DetectionResult) (*types.DetectionResult, error) {
+	namespace, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
 	if err != nil {
-		return fmt.Errorf("failed to build Kubernetes client: %w", err)
+		return nil, fmt.Errorf("failed to determine namespace: %w", err)
 	}
 
-	// Create detector with all methods
-	options := types.DetectionOptions{
-		Methods: []types.DetectionMethod{
-			types.VolumeAttachmentMethod,
-			types.CrossNodePVCMethod,
-			types.EventsMethod,
-			types.MetricsMethod,
-		},
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	store := baseline.NewStore(csiClient, namespace, name)
+	previous, err := store.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// A scan with failed methods is missing their issues, which would all show as new
+	// again once the next complete scan is compared against it
+	if incompleteResult(result) {
+		fmt.Fprintf(os.Stderr, "📌 Not storing this scan as the baseline in ConfigMap %s/%s: %d detection methods failed\n",
+			namespace, name, len(result.Errors))
+	} else if err := store.Save(ctx, result); err != nil {
+		return nil, fmt.Errorf("failed to update baseline: %w", err)
+	}
+
+	if previous == nil {
+		if !incompleteResult(result) {
+			fmt.Fprintf(os.Stderr, "📌 No baseline in ConfigMap %s/%s yet - stored this scan as the baseline\n", namespace, name)
+		}
+		return result, nil
+	}
+
+	changed := baseline.OnlyChanged(result, previous)
+	fmt.Fprintf(os.Stderr, "📌 Compared with the baseline from %s: %d new, %d resolved\n",
+		previous.GeneratedAt.Format(time.RFC3339), len(changed.Issues), len(changed.Resolved))
+	return changed, nil
+}
+
+// cacheKey identifies the detection options a cached result was produced with, so a cache
+// is only reused for the same scan. The output format does not affect detection.
+func cacheKey(options types.DetectionOptions) string {
+	options.OutputFormat = ""
+	data, _ := json.Marshal(options)
+	return string(data)
+}
+
+// parseMethods converts method flag values to detection methods. Methods given more than
+// once, whether repeated in one --method value or across several flags, are kept once in
+// the order they first appear.
+func parseMethods(methods []string) ([]types.DetectionMethod, error) {
+	var detectionMethods []types.DetectionMethod
+	seen := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		if seen[method] {
+			log.Debug().Str("method", method).Msg("ignoring duplicate detection method")
+			continue
+		}
+		seen[method] = true
+
+		if !slices.Contains(methodNames(), method) {
+			return nil, fmt.Errorf("unknown detection method: %s", method)
+		}
+		detectionMethods = append(detectionMethods, types.DetectionMethod(method))
+	}
+	return detectionMethods, nil
+}
+
+// methodNames returns the names of every detection method, in the order
+// detect.AvailableMethods lists them
+func methodNames() []string {
+	var names []string
+	for _, method := range detect.AvailableMethods() {
+		names = append(names, string(method.Method))
+	}
+	return names
+}
+
+// analysisMethodNames returns the names of the methods with a detailed analysis, in the
+// order detect.AvailableMethods lists them
+func analysisMethodNames() []string {
+	var names []string
+	for _, method := range detect.AvailableMethods() {
+		if method.Analysis {
+			names = append(names, string(method.Method))
+		}
+	}
+	return names
+}
+
+// parseSeverity converts a severity flag value to an IssueSeverity
+func parseSeverity(value string) (types.IssueSeverity, error) {
+	switch strings.ToLower(value) {
+	case "low":
+		return types.SeverityLow, nil
+	case "medium":
+		return types.SeverityMedium, nil
+	case "high":
+		return types.SeverityHigh, nil
+	case "critical":
+		return types.SeverityCritical, nil
+	}
+	return "", fmt.Errorf("unknown severity level: %s", value)
+}
+
+// parseStatusThresholds converts severity=count flag values to status thresholds, returning
+// nil when none are given so the defaults apply
+func parseStatusThresholds(flag string, values map[string]string) (map[types.IssueSeverity]int, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	thresholds := make(map[types.IssueSeverity]int, len(values))
+	for value, countValue := range values {
+		severity, err := parseSeverity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s severity %q: must be one of low, medium, high, critical", flag, value)
+		}
+		count, err := strconv.Atoi(countValue)
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf("invalid --%s count %q for %s: must be a positive integer", flag, countValue, severity)
+		}
+		thresholds[severity] = count
+	}
+	return thresholds, nil
+}
+
+// writeMethodErrors lists the methods that failed in best-effort mode and why, as their
+// issues are missing from the result
+func writeMethodErrors(w io.Writer, methodErrors []types.MethodError) {
+	if len(methodErrors) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "⚠️  %d detection method(s) failed - results are incomplete:\n", len(methodErrors))
+	for _, methodErr := range methodErrors {
+		fmt.Fprintf(w, "   %s: %s\n", methodErr.Method, methodErr.Error)
+	}
+}
+
+// statusEmoji returns the marker printed before the overall status
+func statusEmoji(status types.HealthStatus) string {
+	switch status {
+	case types.StatusCritical:
+		return "🔴"
+	case types.StatusDegraded:
+		return "🟡"
+	}
+	return "🟢"
+}
+
+// parseDriverThresholds converts driver=duration flag values into per-driver stuck thresholds
+func parseDriverThresholds(values map[string]string) (map[string]time.Duration, error) {
+	thresholds := make(map[string]time.Duration, len(values))
+	for driver, value := range values {
+		threshold, err := time.ParseDuration(value)
+		if err != nil || threshold <= 0 {
+			return nil, fmt.Errorf("invalid stuck threshold %q for driver %s: must be a positive duration such as 10m", value, driver)
+		}
+		thresholds[driver] = threshold
+	}
+	return thresholds, nil
+}
+
+func runAnalyze(flags analyzeFlags) error {
+	options, err := analyzeOptions(flags)
+	if err != nil {
+		return err
+	}
+
+	if flags.fromFile != "" {
+		result, err := cache.LoadResult(flags.fromFile)
+		if err != nil {
+			return err
+		}
+		return outputAnalysis(os.Stdout, detect.AnalyzeResult(result, options), flags.outputFormat)
+	}
+
+	kubeClient, err := buildKubernetesClient()
+	if err != nil {
+		return fmt.Errorf("failed to build Kubernetes client: %w", err)
 	}
 
 	detector := detect.NewDetector(client.NewClient(kubeClient), options)
@@ -427,56 +2313,211 @@ func runAnalyze() error {
 		return fmt.Errorf("analysis failed: %w", err)
 	}
 
-	// Output detailed analysis
-	data, err := json.MarshalIndent(analysis, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal analysis: %w", err)
+	return outputAnalysis(os.Stdout, analysis, flags.outputFormat)
+}
+
+// analyzeOptions validates the analyze flags and builds the detection options for them
+func analyzeOptions(flags analyzeFlags) (types.DetectionOptions, error) {
+	if flags.outputFormat != "json" && flags.outputFormat != "yaml" {
+		return types.DetectionOptions{}, newValidationError("output format", flags.outputFormat, []string{"json", "yaml"})
+	}
+
+	validMethods := analysisMethodNames()
+	for _, method := range flags.methods {
+		if !slices.Contains(validMethods, method) {
+			return types.DetectionOptions{}, newValidationError("analysis method", method, validMethods)
+		}
+	}
+	methods, err := parseMethods(flags.methods)
+	if err != nil {
+		return types.DetectionOptions{}, err
+	}
+	if flags.topPVCs < 0 {
+		return types.DetectionOptions{}, fmt.Errorf("invalid top PVC count %d: must not be negative", flags.topPVCs)
+	}
+
+	return types.DetectionOptions{
+		Methods:      methods,
+		TargetDriver: flags.targetDriver,
+		TopPVCs:      flags.topPVCs,
+	}, nil
+}
+
+// outputAnalysis writes the detailed analysis in the requested format
+func outputAnalysis(w io.Writer, analysis *detect.DetailedAnalysis, format string) error {
+	var (
+		data []byte
+		err  error
+	)
+	if format == "yaml" {
+		data, err = yaml.Marshal(analysis)
+	} else {
+		data, err = json.MarshalIndent(analysis, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+
+	fmt.Fprintln(w, strings.TrimSuffix(string(data), "\n"))
+	return nil
+}
+
+func runMetrics(flags *metricsFlags) error {
+	generateAlerts, generateDashboard := flags.generateAlerts, flags.generateDashboard
+	outputFile, outputFormat := flags.outputFile, flags.outputFormat
+
+	if outputFormat != "text" && outputFormat != "yaml" {
+		return newValidationError("output format", outputFormat, []string{"text", "yaml"})
+	}
+	if flags.compareDriver != "" {
+		if flags.targetDriver == "" {
+			return fmt.Errorf("--compare-driver requires --driver")
+		}
+		if flags.compareDriver == flags.targetDriver {
+			return fmt.Errorf("--compare-driver must differ from --driver (both are %s)", flags.targetDriver)
+		}
+	}
+
+	if flags.toConfigMap != "" && !generateDashboard {
+		return fmt.Errorf("--to-configmap requires --generate-dashboard")
+	}
+
+	metricsDetector := detect.NewMetricsDetector("", flags.targetDriver)
+	metricsDetector.SetCompareDriver(flags.compareDriver)
+	metricsDetector.SetDriverLabel(flags.driverLabel)
+
+	if flags.toConfigMap != "" {
+		if err := writeDashboardConfigMap(metricsDetector, flags.toConfigMap); err != nil {
+			return err
+		}
+		// The dashboard now lives in the cluster; only print the alerts if also requested
+		if !generateAlerts {
+			return nil
+		}
+		generateDashboard = false
+	}
+
+	if outputFormat == "yaml" {
+		doc, err := buildMetricsDocument(metricsDetector, generateAlerts, generateDashboard)
+		if err != nil {
+			return err
+		}
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metrics output: %w", err)
+		}
+		return writeMetricsOutput(string(data), outputFile)
+	}
+
+	var output strings.Builder
+
+	if generateAlerts {
+		output.WriteString("# Prometheus Alerting Rules for CSI Mount Issues\n")
+		output.WriteString("groups:\n")
+		output.WriteString("- name: csi-mount-detective\n")
+		output.WriteString("  rules:\n")
+		
+		alerts := metricsDetector.GetRecommendedAlerts()
+		for _, alert := range alerts {
+			output.WriteString(alert)
+			output.WriteString("\n")
+		}
+	}
+
+	if generateDashboard {
+		if generateAlerts {
+			output.WriteString("\n---\n\n")
+		}
+		output.WriteString("# Grafana Dashboard JSON\n")
+		output.WriteString(metricsDetector.GenerateGrafanaDashboard())
+		output.WriteString("\n")
+	}
+
+	if !generateAlerts && !generateDashboard {
+		// Default: show metric queries
+		output.WriteString("# Prometheus Queries for CSI Mount Detection\n\n")
+		queries := metricsDetector.GetMetricQueries()
+		for _, query := range queries {
+			output.WriteString(fmt.Sprintf("## %s\n", query.Name))
+			output.WriteString(fmt.Sprintf("# %s\n", query.Description))
+			output.WriteString(fmt.Sprintf("%s\n\n", query.Query))
+		}
+	}
+
+	return writeMetricsOutput(output.String(), outputFile)
+}
+
+// writeDashboardConfigMap creates or updates the named dashboard ConfigMap in the namespace
+// selected by --namespace or the current kubeconfig context
+func writeDashboardConfigMap(metricsDetector *detect.MetricsDetector, name string) error {
+	namespace, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return fmt.Errorf("failed to determine namespace: %w", err)
+	}
+
+	kubeClient, err := buildKubernetesClient()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build Kubernetes client")
+		return newClientError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	configMap := metricsDetector.DashboardConfigMap(namespace, name)
+	if err := detect.ApplyConfigMap(ctx, client.NewClient(kubeClient), configMap); err != nil {
+		return err
 	}
 
-	fmt.Println(string(data))
+	fmt.Fprintf(os.Stderr, "✅ Grafana dashboard written to ConfigMap %s/%s\n", namespace, name)
 	return nil
 }
 
-func runMetrics(generateAlerts, generateDashboard bool, outputFile string) error {
-	metricsDetector := detect.NewMetricsDetector("", "")
+// metricsDocument is the structured form of the metrics command output.
+// Groups follows the Prometheus rule file layout so it can be loaded directly.
+type metricsDocument struct {
+	Queries   []types.MetricQuery    `json:"queries,omitempty"`
+	Groups    []alertRuleGroup       `json:"groups,omitempty"`
+	Dashboard map[string]interface{} `json:"dashboard,omitempty"`
+}
 
-	var output strings.Builder
+// alertRuleGroup is a Prometheus alerting rule group
+type alertRuleGroup struct {
+	Name  string                   `json:"name"`
+	Rules []map[string]interface{} `json:"rules"`
+}
+
+// buildMetricsDocument assembles the structured metrics output for the selected sections
+func buildMetricsDocument(metricsDetector *detect.MetricsDetector, generateAlerts, generateDashboard bool) (*metricsDocument, error) {
+	doc := &metricsDocument{}
 
 	if generateAlerts {
-		output.WriteString("# Prometheus Alerting Rules for CSI Mount Issues\n")
-		output.WriteString("groups:\n")
-		output.WriteString("- name: csi-mount-detective\n")
-		output.WriteString("  rules:\n")
-		
-		alerts := metricsDetector.GetRecommendedAlerts()
-		for _, alert := range alerts {
-			output.WriteString(alert)
-			output.WriteString("\n")
+		group := alertRuleGroup{Name: "csi-mount-detective"}
+		for _, alert := range metricsDetector.GetRecommendedAlerts() {
+			var rule map[string]interface{}
+			if err := yaml.Unmarshal([]byte(alert), &rule); err != nil {
+				return nil, fmt.Errorf("failed to parse alerting rule: %w", err)
+			}
+			group.Rules = append(group.Rules, rule)
 		}
+		doc.Groups = append(doc.Groups, group)
 	}
 
 	if generateDashboard {
-		if generateAlerts {
-			output.WriteString("\n---\n\n")
+		if err := json.Unmarshal([]byte(metricsDetector.GenerateGrafanaDashboard()), &doc.Dashboard); err != nil {
+			return nil, fmt.Errorf("failed to parse Grafana dashboard: %w", err)
 		}
-		output.WriteString("# Grafana Dashboard JSON\n")
-		output.WriteString(metricsDetector.GenerateGrafanaDashboard())
-		output.WriteString("\n")
 	}
 
 	if !generateAlerts && !generateDashboard {
-		// Default: show metric queries
-		output.WriteString("# Prometheus Queries for CSI Mount Detection\n\n")
-		queries := metricsDetector.GetMetricQueries()
-		for _, query := range queries {
-			output.WriteString(fmt.Sprintf("## %s\n", query.Name))
-			output.WriteString(fmt.Sprintf("# %s\n", query.Description))
-			output.WriteString(fmt.Sprintf("%s\n\n", query.Query))
-		}
+		doc.Queries = metricsDetector.GetMetricQueries()
 	}
 
-	result := output.String()
+	return doc, nil
+}
 
+// writeMetricsOutput writes the metrics output to a file, or stdout if no file is given
+func writeMetricsOutput(result, outputFile string) error {
 	if outputFile != "" {
 		return os.WriteFile(outputFile, []byte(result), 0644)
 	}
@@ -485,12 +2526,112 @@ func runMetrics(generateAlerts, generateDashboard bool, outputFile string) error
 	return nil
 }
 
+// runMultiClusterDetect runs detection against the cluster of every --contexts entry and
+// outputs the results tagged by context. Failing clusters are reported without stopping
+// the others, and make the command fail once all clusters are done.
+func runMultiClusterDetect(flags detectFlags, options types.DetectionOptions, failOn types.IssueSeverity) error {
+	fmt.Fprintf(os.Stderr, "Scanning %d clusters, up to %d at a time...\n", len(flags.contexts), flags.clusterConcurrency)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results := multicluster.Scan(ctx, flags.contexts, multicluster.Options{
+		Concurrency: flags.clusterConcurrency,
+		Timeout:     flags.clusterTimeout,
+		OnResult: func(result multicluster.ClusterResult) {
+			if result.Err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s: %v\n", result.Context, result.Err)
+				return
+			}
+			fmt.Fprintf(os.Stderr, "✅ %s: %d issues\n", result.Context, len(result.Result.Issues))
+		},
+	}, func(ctx context.Context, kubeContext string) (*types.DetectionResult, error) {
+		clock := client.NewServerClock()
+		kubeClient, err := buildKubernetesClientForContext(kubeContext, clock)
+		if err != nil {
+			return nil, newClientError(err)
+		}
+		detector := detect.NewDetector(client.NewClient(kubeClient), options)
+		detector.SetServerClock(clock)
+		if err := detector.SetPrometheusTLS(flags.prometheusCAFile, flags.prometheusInsecure); err != nil {
+			return nil, err
+		}
+		return detector.DetectAll(ctx)
+	})
+	stop()
+
+	if err := outputClusterResults(results, flags); err != nil {
+		return err
+	}
+
+	var (
+		failed []string
+		issues []types.CSIMountIssue
+	)
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result.Context)
+			continue
+		}
+		issues = append(issues, result.Result.Issues...)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("detection failed for %d of %d clusters: %s", len(failed), len(results), strings.Join(failed, ", "))
+	}
+	return failOnOutcome(issues, failOn)
+}
+
+// outputClusterResults writes multi-cluster results: a JSON array of tagged results, or
+// each cluster's result under a heading in the other formats
+func outputClusterResults(results []multicluster.ClusterResult, flags detectFlags) error {
+	if flags.outputFormat == "json" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, result := range results {
+		fmt.Printf("=== CLUSTER: %s ===\n", result.Context)
+		if result.Result == nil {
+			fmt.Printf("Detection failed: %s\n\n", result.Error)
+			continue
+		}
+		if err := outputResult(result.Result, flags); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// buildKubernetesClientForContext builds a client for a named kubeconfig context, honouring
+// --kubeconfig but not the current-context
+func buildKubernetesClientForContext(kubeContext string, clock *client.ServerClock) (kubernetes.Interface, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if configFlags.KubeConfig != nil && *configFlags.KubeConfig != "" {
+		loadingRules.ExplicitPath = *configFlags.KubeConfig
+	}
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig context %s: %w", kubeContext, err)
+	}
+
+	config.Wrap(clock.Wrap)
+	return kubernetes.NewForConfig(config)
+}
+
 func buildKubernetesClient() (kubernetes.Interface, error) {
 	config, err := configFlags.ToRESTConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	config.Wrap(serverClock.Wrap)
 	client, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
@@ -499,44 +2640,346 @@ func buildKubernetesClient() (kubernetes.Interface, error) {
 	return client, nil
 }
 
-func outputResult(result *types.DetectionResult, format string) error {
-	switch format {
-	case "json":
-		data, err := json.MarshalIndent(result, "", "  ")
+// currentContextName returns the kubeconfig context detect runs against, or "" when none
+// is known, for example in-cluster
+func currentContextName() string {
+	if configFlags.Context != nil && *configFlags.Context != "" {
+		return *configFlags.Context
+	}
+	rawConfig, err := configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return ""
+	}
+	return rawConfig.CurrentContext
+}
+
+// outputResult writes a detection result to stdout
+func outputResult(result *types.DetectionResult, flags detectFlags) error {
+	return writeResult(os.Stdout, result, flags)
+}
+
+// writeResult writes a detection result to w in the output format of flags
+func writeResult(w io.Writer, result *types.DetectionResult, flags detectFlags) error {
+	switch flags.outputFormat {
+	case "json", "yaml":
+		var value interface{} = result
+		if flags.groupBy == "node" {
+			value = groupIssuesByNode(result.Issues)
+		}
+		if flags.omitEmpty {
+			compact, err := compactResult(result)
+			if err != nil {
+				return err
+			}
+			value = compact
+		}
+		if flags.outputFormat == "yaml" {
+			// sigs.k8s.io/yaml goes through the json tags, so both formats share field names
+			data, err := yaml.Marshal(value)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		}
+		data, err := json.MarshalIndent(value, "", "  ")
 		if err != nil {
 			return err
 		}
-		fmt.Println(string(data))
+		fmt.Fprintln(w, string(data))
 
 	case "table":
-		return outputTable(result)
+		return outputTable(w, result, tableOptions{fullMessage: flags.fullMessage, noHeaders: flags.noHeaders})
+
+	case "wide":
+		return outputTable(w, result, tableOptions{wide: true, fullMessage: flags.fullMessage, noHeaders: flags.noHeaders})
 
 	case "detailed":
-		return outputDetailed(result)
+		return outputDetailed(w, result)
+
+	case "by-node":
+		return outputByNode(w, result)
+
+	case "csv":
+		return outputCSV(w, result)
+
+	case "jsonl":
+		return outputJSONLines(w, result)
+
+	case "report":
+		return report.WriteIncidentReport(w, result)
+
+	case "html":
+		return report.WriteHTMLReport(w, result)
 
 	default:
-		return fmt.Errorf("unknown output format: %s", format)
+		return fmt.Errorf("unknown output format: %s", flags.outputFormat)
 	}
 
 	return nil
 }
 
-func outputTable(result *types.DetectionResult) error {
-	// Simple output with full names - no truncation
+// outputByNode prints each affected node followed by its issues, most severe first, so
+// nodes can be triaged one at a time. Nodes are sorted by name, with issues not tied to a
+// node last.
+func outputByNode(w io.Writer, result *types.DetectionResult) error {
 	if len(result.Issues) == 0 {
-		fmt.Printf("No CSI mount issues detected\n")
+		fmt.Fprintf(w, "No CSI mount issues detected\n")
+		return nil
+	}
+
+	byNode := result.IssuesByNode()
+	nodes := slices.Sorted(maps.Keys(byNode))
+	if i := slices.Index(nodes, types.ClusterWideNode); i >= 0 {
+		nodes = append(slices.Delete(nodes, i, i+1), types.ClusterWideNode)
+	}
+
+	for _, node := range nodes {
+		issues := byNode[node]
+		fmt.Fprintf(w, "%s (%d issues)\n", node, len(issues))
+		for _, issue := range issues {
+			fmt.Fprintf(w, "  %-10s %-30s %-30s %-30s %s\n", issue.Severity, issue.Type,
+				valueOrDash(issue.PVC), valueOrDash(detect.DisplayVolume(issue)), issue.Description)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// csvHeader lists the columns of CSV output, one row per issue
+var csvHeader = []string{"type", "severity", "node", "volume", "pvc", "namespace", "driver", "detectedBy", "detectedAt", "description"}
+
+// outputCSV writes one row per issue for spreadsheets and ticketing systems. Fields
+// holding commas, quotes or newlines, as event messages often do, are quoted.
+func outputCSV(w io.Writer, result *types.DetectionResult) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, issue := range result.Issues {
+		detectedAt := ""
+		if !issue.DetectedAt.IsZero() {
+			detectedAt = issue.DetectedAt.Format(time.RFC3339)
+		}
+		row := []string{
+			string(issue.Type), string(issue.Severity), issue.Node, issue.Volume, issue.PVC,
+			issue.Namespace, issue.Driver, string(issue.DetectedBy), detectedAt, issue.Description,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// outputJSONLines writes each issue as a JSON object on a line of its own, for jq and log
+// processors that read issues as they arrive. A result without issues writes nothing.
+func outputJSONLines(w io.Writer, result *types.DetectionResult) error {
+	encoder := json.NewEncoder(w)
+	for _, issue := range result.Issues {
+		if err := encoder.Encode(issue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clusterReport names the --split-by-namespace report of issues without a namespace, such
+// as VolumeAttachment and node issues
+const clusterReport = "_cluster"
+
+// writeNamespaceReports writes the result split by namespace to dir as <namespace>.json,
+// plus clusterReport.json, which is always written. It returns the number of namespace
+// reports written.
+func writeNamespaceReports(dir string, result *types.DetectionResult, options types.DetectionOptions) (int, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	reports := splitByNamespace(result, options)
+	for name, report := range reports {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return 0, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), append(data, '\n'), 0o644); err != nil {
+			return 0, fmt.Errorf("failed to write %s report: %w", name, err)
+		}
+	}
+	return len(reports) - 1, nil
+}
+
+// splitByNamespace divides a result into one result per namespace, plus one under
+// clusterReport for issues without a namespace. Each has a summary and status of its own
+// issues; recommendations cover the whole cluster and are left out.
+func splitByNamespace(result *types.DetectionResult, options types.DetectionOptions) map[string]*types.DetectionResult {
+	issues := map[string][]types.CSIMountIssue{clusterReport: {}}
+	resolved := make(map[string][]types.CSIMountIssue)
+	for _, issue := range result.Issues {
+		name := cmp.Or(issueNamespace(issue), clusterReport)
+		issues[name] = append(issues[name], issue)
+	}
+	for _, issue := range result.Resolved {
+		name := cmp.Or(issueNamespace(issue), clusterReport)
+		resolved[name] = append(resolved[name], issue)
+	}
+
+	reports := make(map[string]*types.DetectionResult, len(issues))
+	for name, scoped := range issues {
+		report := *result
+		report.Issues = scoped
+		report.Resolved = resolved[name]
+		report.Recommendations = nil
+		report.Summary = detect.Summarize(scoped, result.Summary.MethodsUsed)
+		report.Summary.SnapshotTime = result.Summary.SnapshotTime
+		report.Summary.Status = detect.HealthVerdict(report.Summary.IssuesBySeverity, options.CriticalThresholds, options.DegradedThresholds)
+		reports[name] = &report
+	}
+	return reports
+}
+
+// issueNamespace returns the namespace of an issue, from its PVC key when the namespace is
+// not set on its own
+func issueNamespace(issue types.CSIMountIssue) string {
+	if issue.Namespace != "" {
+		return issue.Namespace
+	}
+	if namespace, _, ok := strings.Cut(issue.PVC, "/"); ok {
+		return namespace
+	}
+	return ""
+}
+
+// unassignedGroup holds issues without a node in --group-by=node output
+const unassignedGroup = "_unassigned"
+
+// issueGroup is one bucket of --group-by JSON output
+type issueGroup struct {
+	Issues []types.CSIMountIssue `json:"issues"`
+}
+
+// groupIssuesByNode buckets issues under their node, keeping their order, with issues
+// without a node under unassignedGroup
+func groupIssuesByNode(issues []types.CSIMountIssue) map[string]*issueGroup {
+	groups := make(map[string]*issueGroup)
+	for _, issue := range issues {
+		node := issue.Node
+		if node == "" {
+			node = unassignedGroup
+		}
+		if groups[node] == nil {
+			groups[node] = &issueGroup{}
+		}
+		groups[node].Issues = append(groups[node].Issues, issue)
+	}
+	return groups
+}
+
+// compactResult returns the generic form of a result with empty and zero-valued summary
+// fields, empty issue metadata and an empty issue list removed, for --omit-empty
+func compactResult(result *types.DetectionResult) (map[string]interface{}, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	var compact map[string]interface{}
+	if err := json.Unmarshal(data, &compact); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+
+	if summary, ok := compact["summary"].(map[string]interface{}); ok {
+		for key, value := range summary {
+			if isEmptyValue(value) {
+				delete(summary, key)
+			}
+		}
+	}
+	if issues, ok := compact["issues"].([]interface{}); ok {
+		for _, issue := range issues {
+			if fields, ok := issue.(map[string]interface{}); ok && isEmptyValue(fields["metadata"]) {
+				delete(fields, "metadata")
+			}
+		}
+	}
+	for key, value := range compact {
+		if isEmptyValue(value) {
+			delete(compact, key)
+		}
+	}
+
+	return compact, nil
+}
+
+// isEmptyValue reports whether a decoded JSON value is null, zero, empty, or a zero timestamp
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == "" || v == zeroTimeJSON
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// zeroTimeJSON is how an unset time.Time is encoded in JSON
+var zeroTimeJSON = time.Time{}.Format(time.RFC3339Nano)
+
+// tableColumn is a column of the table output
+type tableColumn struct {
+	header string
+	width  int // padding width, unused for the last column of a section
+	value  func(issue types.CSIMountIssue) string
+}
+
+// wideColumns are the extra columns shown by --output=wide. They are inserted before the
+// last column of each section, which is left unpadded so long values are not truncated.
+var wideColumns = []tableColumn{
+	{header: "SEVERITY", width: 10, value: func(issue types.CSIMountIssue) string { return string(issue.Severity) }},
+	{header: "DRIVER", width: 30, value: func(issue types.CSIMountIssue) string { return valueOrDash(issue.Driver) }},
+	{header: "DETECTED-BY", width: 18, value: func(issue types.CSIMountIssue) string { return string(issue.DetectedBy) }},
+	{header: "AGE", width: 6, value: issueAge},
+}
+
+// tableMessageLimit is how many characters of an event message the table shows unless
+// --full-message is given
+const tableMessageLimit = 200
+
+// tableOptions controls the table output
+type tableOptions struct {
+	wide        bool // add the wideColumns to every section
+	fullMessage bool // show event messages in full instead of truncating them
+	noHeaders   bool // print only issue rows, without summary, titles, headers or hints
+}
+
+func outputTable(w io.Writer, result *types.DetectionResult, opts tableOptions) error {
+	// Simple output with full names; only long event messages are truncated
+	if len(result.Issues) == 0 && len(result.Resolved) == 0 {
+		if !opts.noHeaders {
+			fmt.Fprintf(w, "No CSI mount issues detected\n")
+		}
 		return nil
 	}
 
-	fmt.Printf("Total Issues: %d\n\n", result.Summary.TotalIssues)
+	if !opts.noHeaders {
+		fmt.Fprintf(w, "Total Issues: %d\n\n", result.Summary.TotalIssues)
+	}
 
 	// Affected Nodes
-	if len(result.Summary.AffectedNodes) > 0 {
-		fmt.Printf("AFFECTED NODES:\n")
+	if len(result.Summary.AffectedNodes) > 0 && !opts.noHeaders {
+		fmt.Fprintf(w, "AFFECTED NODES:\n")
 		for _, node := range result.Summary.AffectedNodes {
-			fmt.Printf("  %s\n", node)
+			fmt.Fprintf(w, "  %s\n", node)
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(w, "\n")
 	}
 
 	// Group issues by detection method for clearer output
@@ -559,200 +3002,332 @@ func outputTable(result *types.DetectionResult) error {
 	}
 
 	// Volume Attachment Issues (show Node and Volume)
-	if len(volumeAttachmentIssues) > 0 {
-		fmt.Printf("VOLUME ATTACHMENT ISSUES:\n")
-		fmt.Printf("%-20s %s\n", "NODE", "VOLUME")
-		fmt.Printf("%-20s %s\n", "----", "------")
-		for _, issue := range volumeAttachmentIssues {
-			node := issue.Node
-			if node == "" {
-				node = "-"
-			}
-			volume := issue.Volume
-			if volume == "" {
-				volume = "-"
-			}
-			fmt.Printf("%-20s %s\n", node, volume)
-		}
-		fmt.Printf("\n")
-	}
+	writeIssueSection(w, "VOLUME ATTACHMENT ISSUES", []tableColumn{
+		{header: "NODE", width: 20, value: func(issue types.CSIMountIssue) string { return valueOrDash(issue.Node) }},
+		{header: "VOLUME", value: func(issue types.CSIMountIssue) string { return valueOrDash(detect.DisplayVolume(issue)) }},
+	}, volumeAttachmentIssues, opts)
 
 	// Cross-Node PVC Issues (show PVC and affected nodes from metadata)
-	if len(crossNodePVCIssues) > 0 {
-		fmt.Printf("CROSS-NODE PVC ISSUES:\n")
-		fmt.Printf("%-50s %s\n", "PVC", "AFFECTED NODES")
-		fmt.Printf("%-50s %s\n", "---", "--------------")
-		for _, issue := range crossNodePVCIssues {
-			pvc := issue.PVC
-			if pvc == "" {
-				pvc = "-"
-			}
-			
+	writeIssueSection(w, "CROSS-NODE PVC ISSUES", []tableColumn{
+		{header: "PVC", width: 50, value: func(issue types.CSIMountIssue) string { return valueOrDash(issue.PVC) }},
+		{header: "AFFECTED NODES", value: func(issue types.CSIMountIssue) string {
 			// Extract nodes from metadata if available
-			nodes := "-"
-			if nodesStr, exists := issue.Metadata["nodes"]; exists {
-				nodes = nodesStr
-			} else if issue.Node != "" {
-				nodes = issue.Node
+			if nodes, exists := issue.Metadata["nodes"]; exists {
+				return nodes
 			}
-			
-			fmt.Printf("%-50s %s\n", pvc, nodes)
-		}
-		fmt.Printf("\n")
-	}
+			return valueOrDash(issue.Node)
+		}},
+	}, crossNodePVCIssues, opts)
 
 	// Event-based Issues
-	if len(eventIssues) > 0 {
-		fmt.Printf("EVENT-BASED ISSUES:\n")
-		fmt.Printf("%-15s %-40s %-15s %-35s %s\n", "NAMESPACE", "OBJECT", "NODE", "VOLUME", "MESSAGE")
-		fmt.Printf("%-15s %-40s %-15s %-35s %s\n", "---------", "------", "----", "------", "-------")
-		for _, issue := range eventIssues {
-			// Extract namespace
-			namespace := issue.Namespace
-			if namespace == "" {
-				namespace = "-"
-			}
-			
+	writeIssueSection(w, "EVENT-BASED ISSUES", []tableColumn{
+		{header: "NAMESPACE", width: 15, value: func(issue types.CSIMountIssue) string { return valueOrDash(issue.Namespace) }},
+		{header: "OBJECT", width: 40, value: func(issue types.CSIMountIssue) string {
 			// Extract involved object from metadata
-			object := "-"
-			if involvedObject, exists := issue.Metadata["involved_object"]; exists {
-				object = involvedObject
+			return valueOrDash(issue.Metadata["involved_object"])
+		}},
+		{header: "NODE", width: 15, value: func(issue types.CSIMountIssue) string { return valueOrDash(issue.Node) }},
+		{header: "VOLUME", width: 35, value: func(issue types.CSIMountIssue) string { return valueOrDash(detect.DisplayVolume(issue)) }},
+		{header: "MESSAGE", value: func(issue types.CSIMountIssue) string {
+			// Extract the full event message from metadata
+			message, exists := issue.Metadata["full_event_message"]
+			if !exists {
+				message = issue.Description
 			}
-			
-			// Extract node
-			node := issue.Node
-			if node == "" {
-				node = "-"
-			}
-			
-			// Extract volume
-			volume := issue.Volume
-			if volume == "" {
-				volume = "-"
+			if opts.fullMessage {
+				return message
 			}
-			
-			// Extract the full event message from metadata - no truncation
-			message := issue.Description
-			if fullMessage, exists := issue.Metadata["full_event_message"]; exists {
-				message = fullMessage
+			return truncateMessage(message, tableMessageLimit)
+		}},
+	}, eventIssues, opts)
+
+	// Other Issues
+	writeIssueSection(w, "OTHER ISSUES", []tableColumn{
+		{header: "NODE", width: 20, value: func(issue types.CSIMountIssue) string { return valueOrDash(issue.Node) }},
+		{header: "PVC", width: 30, value: func(issue types.CSIMountIssue) string { return valueOrDash(issue.PVC) }},
+		{header: "VOLUME", value: func(issue types.CSIMountIssue) string { return valueOrDash(issue.Volume) }},
+	}, otherIssues, opts)
+
+	// Issues in a --baseline-configmap baseline that were not detected again
+	writeIssueSection(w, "RESOLVED SINCE BASELINE", []tableColumn{
+		{header: "TYPE", width: 30, value: func(issue types.CSIMountIssue) string { return string(issue.Type) }},
+		{header: "NODE", width: 20, value: func(issue types.CSIMountIssue) string { return valueOrDash(issue.Node) }},
+		{header: "PVC", width: 30, value: func(issue types.CSIMountIssue) string { return valueOrDash(issue.PVC) }},
+		{header: "VOLUME", value: func(issue types.CSIMountIssue) string { return valueOrDash(issue.Volume) }},
+	}, result.Resolved, opts)
+
+	if !opts.noHeaders && len(result.Issues) > 0 {
+		writeNextSteps(w, result)
+	}
+
+	return nil
+}
+
+// writeNextSteps prints hints for following up on the issues in a table
+func writeNextSteps(w io.Writer, result *types.DetectionResult) {
+	fmt.Fprintf(w, "NEXT STEPS:\n")
+	fmt.Fprintf(w, "  kubectl csi-mount-detective detect --recommend-cleanup   # get remediation guidance\n")
+	fmt.Fprintf(w, "  kubectl csi-mount-detective detect --output=detailed     # show full descriptions and metadata\n")
+	if len(result.Summary.AffectedNodes) > 0 {
+		fmt.Fprintf(w, "  kubectl csi-mount-detective cleanup --nodes=%s --dry-run   # preview stuck mount cleanup on the affected nodes\n",
+			strings.Join(result.Summary.AffectedNodes, ","))
+	}
+}
+
+// writeIssueSection writes one titled table of issues, adding the wide columns if requested.
+// With noHeaders only the issue rows are written.
+func writeIssueSection(w io.Writer, title string, columns []tableColumn, issues []types.CSIMountIssue, opts tableOptions) {
+	if len(issues) == 0 {
+		return
+	}
+
+	// Order rows by node then volume so output does not depend on collection order
+	issues = sortedIssues(issues)
+
+	if opts.wide {
+		last := len(columns) - 1
+		columns = append(append(append([]tableColumn{}, columns[:last]...), wideColumns...), columns[last])
+	}
+
+	writeRow := func(value func(column tableColumn) string) {
+		for i, column := range columns {
+			if i == len(columns)-1 {
+				fmt.Fprintf(w, "%s\n", value(column))
+				continue
 			}
-			
-			fmt.Printf("%-15s %-40s %-15s %-35s %s\n", namespace, object, node, volume, message)
+			fmt.Fprintf(w, "%-*s ", column.width, value(column))
 		}
-		fmt.Printf("\n")
 	}
 
-	// Other Issues
-	if len(otherIssues) > 0 {
-		fmt.Printf("OTHER ISSUES:\n")
-		fmt.Printf("%-20s %-30s %s\n", "NODE", "PVC", "VOLUME")
-		fmt.Printf("%-20s %-30s %s\n", "----", "---", "------")
-		for _, issue := range otherIssues {
-			node := issue.Node
-			if node == "" {
-				node = "-"
-			}
-			pvc := issue.PVC
-			if pvc == "" {
-				pvc = "-"
-			}
-			volume := issue.Volume
-			if volume == "" {
-				volume = "-"
-			}
-			fmt.Printf("%-20s %-30s %s\n", node, pvc, volume)
+	if opts.noHeaders {
+		for _, issue := range issues {
+			writeRow(func(column tableColumn) string { return column.value(issue) })
 		}
+		return
 	}
 
-	return nil
+	fmt.Fprintf(w, "%s:\n", title)
+	writeRow(func(column tableColumn) string { return column.header })
+	writeRow(func(column tableColumn) string { return strings.Repeat("-", len(column.header)) })
+	for _, issue := range issues {
+		writeRow(func(column tableColumn) string { return column.value(issue) })
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// sortedIssues returns a copy of issues ordered by node and volume, breaking ties on the
+// remaining identifying fields so that any input order yields the same rows
+func sortedIssues(issues []types.CSIMountIssue) []types.CSIMountIssue {
+	sorted := slices.Clone(issues)
+	slices.SortStableFunc(sorted, func(a, b types.CSIMountIssue) int {
+		return cmp.Or(
+			cmp.Compare(a.Node, b.Node),
+			cmp.Compare(a.Volume, b.Volume),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.PVC, b.PVC),
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.Description, b.Description),
+		)
+	})
+	return sorted
+}
+
+// issueAge returns how long ago an issue occurred, falling back to when it was detected
+func issueAge(issue types.CSIMountIssue) string {
+	when := issue.OccurredAt
+	if when.IsZero() {
+		when = issue.DetectedAt
+	}
+	if when.IsZero() {
+		return "-"
+	}
+	return duration.HumanDuration(time.Since(when))
+}
+
+// truncateMessage shortens a message to at most limit characters, ending it with an
+// ellipsis when anything was cut
+func truncateMessage(message string, limit int) string {
+	runes := []rune(message)
+	if len(runes) <= limit {
+		return message
+	}
+	return string(runes[:limit-1]) + "…"
 }
 
-func outputDetailed(result *types.DetectionResult) error {
-	fmt.Printf("# CSI Mount Detective - Detailed Report\n\n")
-	fmt.Printf("**Generated:** %s\n\n", result.GeneratedAt.Format(time.RFC3339))
+// valueOrDash returns a table cell value, using "-" for empty values
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func outputDetailed(w io.Writer, result *types.DetectionResult) error {
+	fmt.Fprintf(w, "# CSI Mount Detective - Detailed Report\n\n")
+	fmt.Fprintf(w, "**Generated:** %s\n\n", result.GeneratedAt.Format(time.RFC3339))
 
 	// Summary section
-	fmt.Printf("## Summary\n\n")
-	fmt.Printf("- **Total Issues:** %d\n", result.Summary.TotalIssues)
-	fmt.Printf("- **Methods Used:** %v\n", result.Summary.MethodsUsed)
+	fmt.Fprintf(w, "## Summary\n\n")
+	if result.Summary.Status != "" {
+		fmt.Fprintf(w, "- **Status:** %s\n", result.Summary.Status)
+	}
+	fmt.Fprintf(w, "- **Total Issues:** %d\n", result.Summary.TotalIssues)
+	fmt.Fprintf(w, "- **Methods Used:** %v\n", result.Summary.MethodsUsed)
+	if len(result.Errors) > 0 {
+		fmt.Fprintf(w, "- **Failed Methods:**\n")
+		for _, methodErr := range result.Errors {
+			fmt.Fprintf(w, "  - %s: %s\n", methodErr.Method, methodErr.Error)
+		}
+	}
+	if !result.Summary.SnapshotTime.IsZero() {
+		fmt.Fprintf(w, "- **Cluster Snapshot:** %s (approximately consistent across methods)\n", result.Summary.SnapshotTime.Format(time.RFC3339))
+	}
 	
 	if len(result.Summary.IssuesBySeverity) > 0 {
-		fmt.Printf("- **Issues by Severity:**\n")
+		fmt.Fprintf(w, "- **Issues by Severity:**\n")
 		for severity, count := range result.Summary.IssuesBySeverity {
-			fmt.Printf("  - %s: %d\n", severity, count)
+			fmt.Fprintf(w, "  - %s: %d\n", severity, count)
 		}
 	}
 
 	if len(result.Summary.AffectedNodes) > 0 {
-		fmt.Printf("- **Affected Nodes:** %v\n", result.Summary.AffectedNodes)
+		fmt.Fprintf(w, "- **Affected Nodes:** %v\n", result.Summary.AffectedNodes)
 	}
 
 	if len(result.Summary.AffectedDrivers) > 0 {
-		fmt.Printf("- **Affected Drivers:** %v\n", result.Summary.AffectedDrivers)
+		fmt.Fprintf(w, "- **Affected Drivers:** %v\n", result.Summary.AffectedDrivers)
 	}
 
+	writeDriverSummary(w, result.Summary)
+
 	// Detailed issues
 	if len(result.Issues) > 0 {
-		fmt.Printf("\n## Detailed Issues\n\n")
+		fmt.Fprintf(w, "\n## Detailed Issues\n\n")
 		
 		for i, issue := range result.Issues {
-			fmt.Printf("### Issue %d: %s\n\n", i+1, issue.Type)
-			fmt.Printf("- **Severity:** %s\n", issue.Severity)
-			fmt.Printf("- **Description:** %s\n", issue.Description)
-			fmt.Printf("- **Detected By:** %s\n", issue.DetectedBy)
-			fmt.Printf("- **Detected At:** %s\n", issue.DetectedAt.Format(time.RFC3339))
+			fmt.Fprintf(w, "### Issue %d: %s\n\n", i+1, issue.Type)
+			fmt.Fprintf(w, "- **Severity:** %s\n", issue.Severity)
+			fmt.Fprintf(w, "- **Description:** %s\n", issue.Description)
+			fmt.Fprintf(w, "- **Detected By:** %s\n", issue.DetectedBy)
+			fmt.Fprintf(w, "- **Detected At:** %s\n", issue.DetectedAt.Format(time.RFC3339))
+			if !issue.OccurredAt.IsZero() {
+				fmt.Fprintf(w, "- **Occurred At:** %s\n", issue.OccurredAt.Format(time.RFC3339))
+			}
 			
 			if issue.Node != "" {
-				fmt.Printf("- **Node:** %s\n", issue.Node)
+				fmt.Fprintf(w, "- **Node:** %s\n", issue.Node)
 			}
 			if issue.Volume != "" {
-				fmt.Printf("- **Volume:** %s\n", issue.Volume)
+				fmt.Fprintf(w, "- **Volume:** %s\n", detect.DisplayVolume(issue))
 			}
 			if issue.PVC != "" {
-				fmt.Printf("- **PVC:** %s\n", issue.PVC)
+				fmt.Fprintf(w, "- **PVC:** %s\n", issue.PVC)
 			}
 			if issue.Driver != "" {
-				fmt.Printf("- **Driver:** %s\n", issue.Driver)
+				fmt.Fprintf(w, "- **Driver:** %s\n", issue.Driver)
+			}
+			if len(issue.Sources) > 0 {
+				fmt.Fprintf(w, "- **Sources:**\n")
+				for _, source := range issue.Sources {
+					fmt.Fprintf(w, "  - %s\n", source)
+				}
 			}
 			
 			if len(issue.Metadata) > 0 {
-				fmt.Printf("- **Metadata:**\n")
+				fmt.Fprintf(w, "- **Metadata:**\n")
 				for key, value := range issue.Metadata {
-					fmt.Printf("  - %s: %s\n", key, value)
+					fmt.Fprintf(w, "  - %s: %s\n", key, value)
 				}
 			}
-			fmt.Printf("\n")
+			fmt.Fprintf(w, "\n")
 		}
 	}
 
 	// Recommendations
 	if len(result.Recommendations) > 0 {
-		fmt.Printf("## Recommendations\n\n")
+		fmt.Fprintf(w, "## Recommendations\n\n")
 		for _, rec := range result.Recommendations {
-			fmt.Printf("%s\n", rec)
+			fmt.Fprintf(w, "%s\n", rec)
+		}
+	}
+
+	return nil
+}
+
+// writeDriverSummary writes each affected driver's issue counts by severity and type, to
+// tell apart several drivers misbehaving at once
+func writeDriverSummary(w io.Writer, summary types.DetectionSummary) {
+	if len(summary.IssuesByDriver) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n## Driver Summary\n\n")
+	for _, driver := range slices.Sorted(maps.Keys(summary.IssuesByDriver)) {
+		counts := summary.IssuesByDriver[driver]
+		fmt.Fprintf(w, "### %s\n\n", driver)
+		fmt.Fprintf(w, "- **Total Issues:** %d\n", counts.Total)
+
+		var severities []string
+		for _, severity := range types.Severities {
+			if count := counts.BySeverity[severity]; count > 0 {
+				severities = append(severities, fmt.Sprintf("%s: %d", severity, count))
+			}
+		}
+		fmt.Fprintf(w, "- **By Severity:** %s\n", strings.Join(severities, ", "))
+
+		fmt.Fprintf(w, "- **By Type:**\n")
+		for _, issueType := range slices.Sorted(maps.Keys(counts.ByType)) {
+			fmt.Fprintf(w, "  - %s: %d\n", issueType, counts.ByType[issueType])
 		}
+		fmt.Fprintf(w, "\n")
 	}
+}
 
+// printMethods writes a description of each detection method and the RBAC it needs
+func printMethods(w io.Writer, methods []types.MethodInfo) error {
+	for _, method := range methods {
+		fmt.Fprintf(w, "%s\n", method.Method)
+		fmt.Fprintf(w, "  Description: %s\n", method.Description)
+		fmt.Fprintf(w, "  Reads:       %s\n", strings.Join(method.Reads, ", "))
+		if len(method.Permissions) == 0 {
+			fmt.Fprintf(w, "  Permissions: none (no Kubernetes API access)\n")
+		} else {
+			fmt.Fprintf(w, "  Permissions:\n")
+			for _, perm := range method.Permissions {
+				fmt.Fprintf(w, "    - %s: %s\n", perm.Resource, strings.Join(perm.Verbs, ","))
+			}
+		}
+		fmt.Fprintf(w, "\n")
+	}
 	return nil
 }
 
+// detectFlagAliases maps the alternative names of detect flags to the flags they stand for
+func detectFlagAliases(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+	switch name {
+	case "slack-webhook":
+		name = "webhook-url"
+	case "notify-min-severity":
+		name = "notify-on"
+	}
+	return pflag.NormalizedName(name)
+}
+
 // validateDetectFlags validates input parameters for the detect command
 func validateDetectFlags(methods []string, outputFormat, minSeverity string) error {
 	// Validate output format
 	validFormats := map[string]bool{
-		"table": true, "json": true, "yaml": true, "detailed": true,
+		"table": true, "wide": true, "json": true, "yaml": true, "csv": true, "jsonl": true, "detailed": true, "report": true, "html": true, "by-node": true,
 	}
 	if !validFormats[outputFormat] {
-		return newValidationError("output format", outputFormat, []string{"table", "json", "yaml", "detailed"})
+		return newValidationError("output format", outputFormat, []string{"table", "wide", "json", "yaml", "csv", "jsonl", "detailed", "report", "html", "by-node"})
 	}
 	
 	// Validate methods
-	validMethods := map[string]bool{
-		"volumeattachments": true, "cross-node-pvc": true, "events": true, "metrics": true,
-	}
+	validMethods := methodNames()
 	for _, method := range methods {
-		if !validMethods[method] {
-			return newValidationError("detection method", method, []string{"volumeattachments", "cross-node-pvc", "events", "metrics"})
+		if !slices.Contains(validMethods, method) {
+			return newValidationError("detection method", method, validMethods)
 		}
 	}
 	
@@ -0,0 +1,1270 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/cache"
+	"github.com/jdambly/kubectl-csi-scan/pkg/cleanup"
+	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+	"github.com/jdambly/kubectl-csi-scan/pkg/config"
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("Output Formats", func() {
+	Describe("outputAnalysis", func() {
+		var analysis *detect.DetailedAnalysis
+
+		BeforeEach(func() {
+			analysis = &detect.DetailedAnalysis{
+				VolumeAttachmentCount: 3,
+				AttachedVolumeCount:   2,
+				NodePVCUsage: []types.NodePVCUsage{
+					{Node: "node-1", PVCCounts: map[string]int{"default/data": 2}, Total: 2},
+				},
+				MetricQueries: detect.NewMetricsDetector("", "").GetMetricQueries(),
+			}
+		})
+
+		It("should emit valid YAML", func() {
+			var buf bytes.Buffer
+			Expect(outputAnalysis(&buf, analysis, "yaml")).To(Succeed())
+
+			var decoded detect.DetailedAnalysis
+			Expect(yaml.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+			Expect(decoded.VolumeAttachmentCount).To(Equal(3))
+			Expect(decoded.AttachedVolumeCount).To(Equal(2))
+			Expect(decoded.NodePVCUsage).To(HaveLen(1))
+			Expect(decoded.MetricQueries).To(HaveLen(len(analysis.MetricQueries)))
+			Expect(buf.String()).To(ContainSubstring("volumeAttachmentCount: 3"))
+		})
+
+		It("should keep JSON as the default format", func() {
+			var buf bytes.Buffer
+			Expect(outputAnalysis(&buf, analysis, "json")).To(Succeed())
+			Expect(buf.String()).To(HavePrefix("{"))
+			Expect(buf.String()).To(ContainSubstring(`"volumeAttachmentCount": 3`))
+		})
+	})
+
+	Describe("runMetrics", func() {
+		var outputFile string
+
+		BeforeEach(func() {
+			outputFile = filepath.Join(GinkgoT().TempDir(), "metrics.yaml")
+		})
+
+		readDocument := func() metricsDocument {
+			data, err := os.ReadFile(outputFile)
+			Expect(err).NotTo(HaveOccurred())
+
+			var doc metricsDocument
+			Expect(yaml.Unmarshal(data, &doc)).To(Succeed())
+			return doc
+		}
+
+		It("should emit queries as valid YAML", func() {
+			Expect(runMetrics(&metricsFlags{outputFile: outputFile, outputFormat: "yaml"})).To(Succeed())
+
+			doc := readDocument()
+			Expect(doc.Queries).NotTo(BeEmpty())
+			Expect(doc.Queries[0].Name).NotTo(BeEmpty())
+			Expect(doc.Queries[0].Query).NotTo(BeEmpty())
+			Expect(doc.Groups).To(BeEmpty())
+			Expect(doc.Dashboard).To(BeNil())
+		})
+
+		It("should emit alerts as a Prometheus rule group", func() {
+			Expect(runMetrics(&metricsFlags{generateAlerts: true, outputFile: outputFile, outputFormat: "yaml"})).To(Succeed())
+
+			doc := readDocument()
+			Expect(doc.Groups).To(HaveLen(1))
+			Expect(doc.Groups[0].Name).To(Equal("csi-mount-detective"))
+			Expect(doc.Groups[0].Rules).NotTo(BeEmpty())
+			for _, rule := range doc.Groups[0].Rules {
+				Expect(rule).To(HaveKey("alert"))
+				Expect(rule).To(HaveKey("expr"))
+			}
+			Expect(doc.Queries).To(BeEmpty())
+		})
+
+		It("should embed the dashboard as structured data", func() {
+			Expect(runMetrics(&metricsFlags{generateDashboard: true, outputFile: outputFile, outputFormat: "yaml"})).To(Succeed())
+
+			doc := readDocument()
+			Expect(doc.Dashboard).NotTo(BeEmpty())
+		})
+
+		It("should compare two drivers in the dashboard", func() {
+			Expect(runMetrics(&metricsFlags{
+				generateDashboard: true,
+				outputFile:        outputFile,
+				outputFormat:      "yaml",
+				targetDriver:      "cinder.csi.openstack.org",
+				compareDriver:     "ebs.csi.aws.com",
+			})).To(Succeed())
+
+			data, err := os.ReadFile(outputFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`driver_name="cinder.csi.openstack.org"`))
+			Expect(string(data)).To(ContainSubstring(`driver_name="ebs.csi.aws.com"`))
+		})
+
+		It("should require --driver with --compare-driver", func() {
+			err := runMetrics(&metricsFlags{outputFormat: "text", compareDriver: "ebs.csi.aws.com"})
+			Expect(err).To(MatchError("--compare-driver requires --driver"))
+
+			err = runMetrics(&metricsFlags{outputFormat: "text", targetDriver: "ebs.csi.aws.com", compareDriver: "ebs.csi.aws.com"})
+			Expect(err).To(MatchError(ContainSubstring("must differ from --driver")))
+		})
+
+		It("should reject unknown output formats", func() {
+			err := runMetrics(&metricsFlags{outputFile: outputFile, outputFormat: "xml"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid output format 'xml'"))
+		})
+	})
+
+	Describe("setLogRedaction", func() {
+		AfterEach(func() {
+			setLogRedaction(false)
+		})
+
+		It("should shorten volume handles in logged errors but keep the context", func() {
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf)
+			err := errors.New(`NodeStage failed for volumeHandle "vol-0abc1234def56789": rpc error: code = Internal`)
+
+			setLogRedaction(true)
+			logger.Error().Err(err).Str("node", "node-1").Msg("failed to stage volume")
+
+			Expect(buf.String()).NotTo(ContainSubstring("vol-0abc1234def56789"))
+			Expect(buf.String()).To(ContainSubstring(`vol-0abc...`))
+			Expect(buf.String()).To(ContainSubstring("NodeStage failed for volumeHandle"))
+			Expect(buf.String()).To(ContainSubstring(`"node":"node-1"`))
+			Expect(buf.String()).To(ContainSubstring("failed to stage volume"))
+		})
+
+		It("should log errors unchanged when disabled", func() {
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf)
+
+			setLogRedaction(false)
+			logger.Error().Err(errors.New(`volume "pvc-0a1b2c3d-4e5f" not found`)).Msg("lookup failed")
+
+			Expect(buf.String()).To(ContainSubstring("pvc-0a1b2c3d-4e5f"))
+		})
+	})
+
+	Describe("parseMethods", func() {
+		It("should keep each method once in first-seen order", func() {
+			methods, err := parseMethods([]string{"events", "volumeattachments", "events", "volumeattachments"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(methods).To(Equal([]types.DetectionMethod{types.EventsMethod, types.VolumeAttachmentMethod}))
+		})
+
+		It("should reject unknown methods", func() {
+			_, err := parseMethods([]string{"events", "inotify"})
+			Expect(err).To(MatchError("unknown detection method: inotify"))
+		})
+		It("should accept every available method, and so should the detect flag validation", func() {
+			for _, info := range detect.AvailableMethods() {
+				methods, err := parseMethods([]string{string(info.Method)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(methods).To(Equal([]types.DetectionMethod{info.Method}))
+				Expect(validateDetectFlags([]string{string(info.Method)}, "table", "")).To(Succeed())
+			}
+			Expect(validateDetectFlags([]string{"inotify"}, "table", "")).To(MatchError(ContainSubstring("driver-topology")))
+		})
+	})
+
+	Describe("parseDriverThresholds", func() {
+		It("should parse driver durations", func() {
+			thresholds, err := parseDriverThresholds(map[string]string{
+				"cinder.csi.openstack.org": "1h",
+				"local.csi.example.com":    "90s",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(thresholds).To(HaveKeyWithValue("cinder.csi.openstack.org", time.Hour))
+			Expect(thresholds).To(HaveKeyWithValue("local.csi.example.com", 90*time.Second))
+		})
+
+		It("should reject invalid or non-positive durations", func() {
+			_, err := parseDriverThresholds(map[string]string{"cinder.csi.openstack.org": "soon"})
+			Expect(err).To(MatchError(ContainSubstring("cinder.csi.openstack.org")))
+
+			_, err = parseDriverThresholds(map[string]string{"cinder.csi.openstack.org": "0s"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("outputTable", func() {
+		var result *types.DetectionResult
+
+		BeforeEach(func() {
+			result = &types.DetectionResult{
+				Summary: types.DetectionSummary{TotalIssues: 2, AffectedNodes: []string{"node-1"}},
+				Issues: []types.CSIMountIssue{
+					{
+						Type:       types.StuckVolumeAttachment,
+						Severity:   types.SeverityHigh,
+						Node:       "node-1",
+						Volume:     "pvc-123",
+						Driver:     "cinder.csi.openstack.org",
+						DetectedBy: types.VolumeAttachmentMethod,
+						OccurredAt: time.Now().Add(-90 * time.Minute),
+					},
+					{
+						Type:       types.CSIOperationFailure,
+						Severity:   types.SeverityMedium,
+						Namespace:  "default",
+						Driver:     "ebs.csi.aws.com",
+						DetectedBy: types.EventsMethod,
+						Metadata:   map[string]string{"full_event_message": "MountVolume.SetUp failed"},
+					},
+				},
+			}
+		})
+
+		It("should keep the plain table compact", func() {
+			var buf bytes.Buffer
+			Expect(outputTable(&buf, result, tableOptions{})).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("NODE                 VOLUME\n"))
+			Expect(buf.String()).NotTo(ContainSubstring("SEVERITY"))
+			Expect(buf.String()).NotTo(ContainSubstring("DRIVER"))
+			Expect(buf.String()).NotTo(ContainSubstring("cinder.csi.openstack.org"))
+		})
+
+		It("should add severity, driver, detected-by and age columns in wide mode", func() {
+			var buf bytes.Buffer
+			Expect(outputTable(&buf, result, tableOptions{wide: true})).To(Succeed())
+			output := buf.String()
+			Expect(output).To(ContainSubstring("SEVERITY"))
+			Expect(output).To(ContainSubstring("DRIVER"))
+			Expect(output).To(ContainSubstring("DETECTED-BY"))
+			Expect(output).To(ContainSubstring("AGE"))
+			Expect(output).To(MatchRegexp(`node-1\s+high\s+cinder\.csi\.openstack\.org\s+volumeattachments\s+90m\s+pvc-123`))
+			Expect(output).To(MatchRegexp(`medium\s+ebs\.csi\.aws\.com\s+events\s+\S+\s+MountVolume\.SetUp failed`))
+		})
+
+		It("should end with next-step hints naming the cleanup command for affected nodes", func() {
+			result.Summary.AffectedNodes = []string{"node-1", "node-2"}
+
+			var buf bytes.Buffer
+			Expect(outputTable(&buf, result, tableOptions{})).To(Succeed())
+			output := buf.String()
+			Expect(output).To(ContainSubstring("NEXT STEPS:"))
+			Expect(output).To(ContainSubstring("--recommend-cleanup"))
+			Expect(output).To(ContainSubstring("--output=detailed"))
+			Expect(output).To(ContainSubstring("kubectl csi-mount-detective cleanup --nodes=node-1,node-2 --dry-run"))
+		})
+
+		It("should print only issue rows with no headers", func() {
+			var buf bytes.Buffer
+			Expect(outputTable(&buf, result, tableOptions{noHeaders: true})).To(Succeed())
+			output := buf.String()
+			Expect(output).NotTo(ContainSubstring("Total Issues"))
+			Expect(output).NotTo(ContainSubstring("VOLUME ATTACHMENT ISSUES"))
+			Expect(output).NotTo(ContainSubstring("NEXT STEPS"))
+			Expect(strings.Split(strings.TrimSpace(output), "\n")).To(HaveLen(2))
+		})
+
+		It("should not print hints when there are no issues", func() {
+			var buf bytes.Buffer
+			Expect(outputTable(&buf, &types.DetectionResult{}, tableOptions{})).To(Succeed())
+			Expect(buf.String()).To(Equal("No CSI mount issues detected\n"))
+		})
+
+		It("should truncate long event messages unless the full message is requested", func() {
+			message := "MountVolume.SetUp failed: rpc error: " + strings.Repeat("x", 500)
+			result.Issues[1].Metadata["full_event_message"] = message
+
+			var buf bytes.Buffer
+			Expect(outputTable(&buf, result, tableOptions{})).To(Succeed())
+			Expect(buf.String()).NotTo(ContainSubstring(message))
+			Expect(buf.String()).To(ContainSubstring(message[:199] + "…\n"))
+
+			buf.Reset()
+			Expect(outputTable(&buf, result, tableOptions{fullMessage: true})).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring(message + "\n"))
+		})
+
+		It("should order VolumeAttachment rows by node then volume regardless of input order", func() {
+			attachment := func(node, volume string) types.CSIMountIssue {
+				return types.CSIMountIssue{Type: types.StuckVolumeAttachment, Node: node, Volume: volume, DetectedBy: types.VolumeAttachmentMethod}
+			}
+			result.Issues = []types.CSIMountIssue{
+				attachment("node-2", "pvc-b"),
+				attachment("node-1", "pvc-c"),
+				attachment("node-2", "pvc-a"),
+				attachment("node-1", "pvc-a"),
+			}
+
+			var buf bytes.Buffer
+			Expect(outputTable(&buf, result, tableOptions{noHeaders: true})).To(Succeed())
+			rows := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			Expect(rows).To(HaveLen(4))
+			Expect(rows[0]).To(MatchRegexp(`^node-1\s+pvc-a$`))
+			Expect(rows[1]).To(MatchRegexp(`^node-1\s+pvc-c$`))
+			Expect(rows[2]).To(MatchRegexp(`^node-2\s+pvc-a$`))
+			Expect(rows[3]).To(MatchRegexp(`^node-2\s+pvc-b$`))
+
+			// Reversing the input yields identical output
+			slices.Reverse(result.Issues)
+			var reversed bytes.Buffer
+			Expect(outputTable(&reversed, result, tableOptions{noHeaders: true})).To(Succeed())
+			Expect(reversed.String()).To(Equal(buf.String()))
+		})
+
+		It("should show VolumeAttachment issues by claim and PV when the claim is known", func() {
+			result.Issues = []types.CSIMountIssue{{
+				Type:       types.FailedAttachVolume,
+				Node:       "node-1",
+				Volume:     "vol-0abc123",
+				PVC:        "data-web-0",
+				Namespace:  "shop",
+				DetectedBy: types.VolumeAttachmentMethod,
+				Sources: []types.SourceRef{
+					{Kind: "VolumeAttachment", Name: "csi-123"},
+					{Kind: "PersistentVolume", Name: "pvc-3c9d8e7f"},
+				},
+			}}
+
+			var buf bytes.Buffer
+			Expect(outputTable(&buf, result, tableOptions{})).To(Succeed())
+			output := buf.String()
+			Expect(output).To(ContainSubstring("VOLUME ATTACHMENT ISSUES"))
+			Expect(output).To(MatchRegexp(`node-1\s+shop/data-web-0 \(pvc-3c9d8e7f\)\n`))
+			Expect(output).NotTo(ContainSubstring("vol-0abc123"))
+		})
+	})
+
+	Describe("outputDetailed", func() {
+		It("should break down the issues of each affected driver by severity and type", func() {
+			issues := []types.CSIMountIssue{
+				{Type: types.StuckVolumeAttachment, Severity: types.SeverityHigh, Driver: "cinder.csi.openstack.org"},
+				{Type: types.StuckVolumeAttachment, Severity: types.SeverityCritical, Driver: "cinder.csi.openstack.org"},
+				{Type: types.FailedAttachVolume, Severity: types.SeverityHigh, Driver: "cinder.csi.openstack.org"},
+				{Type: types.DeviceBusy, Severity: types.SeverityMedium, Driver: "ebs.csi.aws.com"},
+				{Type: types.MultipleAttachments, Severity: types.SeverityLow},
+			}
+			result := &types.DetectionResult{Summary: detect.Summarize(issues, nil), Issues: issues}
+
+			var buf bytes.Buffer
+			Expect(outputDetailed(&buf, result)).To(Succeed())
+			Expect(buf.String()).To(ContainSubstring("## Driver Summary\n\n" +
+				"### cinder.csi.openstack.org\n\n" +
+				"- **Total Issues:** 3\n" +
+				"- **By Severity:** critical: 1, high: 2\n" +
+				"- **By Type:**\n" +
+				"  - failed-attach-volume: 1\n" +
+				"  - stuck-volume-attachment: 2\n\n" +
+				"### ebs.csi.aws.com\n\n" +
+				"- **Total Issues:** 1\n" +
+				"- **By Severity:** medium: 1\n" +
+				"- **By Type:**\n" +
+				"  - device-busy: 1\n"))
+		})
+	})
+
+	Describe("jobStatusTable", func() {
+		It("should print only state changes when not on a terminal", func() {
+			var out bytes.Buffer
+			table := &jobStatusTable{w: &out, last: map[string]cleanup.JobState{}}
+
+			table.update([]cleanup.JobStatus{{JobName: "csi-mount-cleanup-n1", NodeName: "n1", State: cleanup.JobRunning}})
+			table.update([]cleanup.JobStatus{{JobName: "csi-mount-cleanup-n1", NodeName: "n1", State: cleanup.JobRunning}})
+			table.update([]cleanup.JobStatus{{JobName: "csi-mount-cleanup-n1", NodeName: "n1", State: cleanup.JobSucceeded}})
+
+			Expect(out.String()).To(Equal("⏳ n1 (csi-mount-cleanup-n1): Running\n✅ n1 (csi-mount-cleanup-n1): Succeeded\n"))
+		})
+
+		It("should write job log lines above the table on a terminal", func() {
+			var out bytes.Buffer
+			table := &jobStatusTable{w: &out, inPlace: true, last: map[string]cleanup.JobState{}}
+			statuses := []cleanup.JobStatus{{JobName: "csi-mount-cleanup-n1", NodeName: "n1", State: cleanup.JobRunning}}
+
+			table.update(statuses)
+			out.Reset()
+			jobLogWriter{table: table, node: "n1"}.Write([]byte("[csi-mount-cleanup] Processing mount: /var/lib/kubelet/pods/x\n"))
+
+			lines := strings.Split(out.String(), "\n")
+			Expect(lines[0]).To(Equal("\033[2A\033[J[n1] [csi-mount-cleanup] Processing mount: /var/lib/kubelet/pods/x"))
+			Expect(strings.Fields(lines[1])).To(Equal([]string{"NODE", "JOB", "STATE"}))
+			Expect(lines[2]).To(HavePrefix("n1"))
+			Expect(table.lines).To(Equal(2))
+		})
+	})
+
+	Describe("writeDryRunPreview", func() {
+		It("should render the planned changes of every node as one table", func() {
+			changes, err := cleanup.ParseDryRunLog(strings.NewReader(
+				"[csi-mount-cleanup] Processing mount: /var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-1/globalmount\n" +
+					"[csi-mount-cleanup] DRY RUN: Would unmount /var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-1/globalmount\n" +
+					"[csi-mount-cleanup] DRY RUN: Would remove empty PV directory /var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-1\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			var out bytes.Buffer
+			writeDryRunPreview(&out, map[string][]cleanup.PlannedChange{
+				"node-2": changes,
+				"node-1": nil,
+			})
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			Expect(lines).To(HaveLen(5))
+			Expect(lines[0]).To(Equal("DRY-RUN PLAN:"))
+			Expect(strings.Fields(lines[1])).To(Equal([]string{"NODE", "ACTION", "PATH"}))
+			Expect(lines[2]).To(HavePrefix("node-1"))
+			Expect(lines[2]).To(ContainSubstring("(nothing to clean up)"))
+			Expect(strings.Fields(lines[3])).To(Equal([]string{"node-2", "unmount", "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-1/globalmount"}))
+			Expect(lines[4]).To(HavePrefix("node-2"))
+			Expect(lines[4]).To(ContainSubstring("remove empty PV directory"))
+		})
+	})
+
+	Describe("groupIssuesByNode", func() {
+		It("should bucket issues under their node and node-less issues as unassigned", func() {
+			issues := []types.CSIMountIssue{
+				{Type: types.StuckVolumeAttachment, Node: "node-1", Volume: "pvc-a"},
+				{Type: types.MissingPVC, PVC: "orphan"},
+				{Type: types.DeviceBusy, Node: "node-2", Volume: "pvc-b"},
+				{Type: types.MultiAttachError, Node: "node-1", Volume: "pvc-c"},
+			}
+
+			groups := groupIssuesByNode(issues)
+			Expect(groups).To(HaveLen(3))
+			Expect(groups["node-1"].Issues).To(Equal([]types.CSIMountIssue{issues[0], issues[3]}))
+			Expect(groups["node-2"].Issues).To(Equal([]types.CSIMountIssue{issues[2]}))
+			Expect(groups[unassignedGroup].Issues).To(Equal([]types.CSIMountIssue{issues[1]}))
+
+			data, err := json.Marshal(groups)
+			Expect(err).NotTo(HaveOccurred())
+			var decoded map[string]map[string][]interface{}
+			Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+			Expect(decoded["_unassigned"]["issues"]).To(HaveLen(1))
+		})
+	})
+
+	Describe("summaryLine", func() {
+		It("should report each summary field as a key=value pair", func() {
+			issues := []types.CSIMountIssue{
+				{Type: types.MultipleAttachments, Severity: types.SeverityCritical, Node: "node-1"},
+				{Type: types.DeviceBusy, Severity: types.SeverityHigh, Node: "node-2"},
+				{Type: types.DeviceBusy, Severity: types.SeverityHigh, Node: "node-2"},
+				{Type: types.MissingPVC, Severity: types.SeverityLow},
+			}
+			summary := detect.Summarize(issues, []types.DetectionMethod{types.EventsMethod})
+			summary.Status = types.StatusCritical
+			result := &types.DetectionResult{Summary: summary, Issues: issues}
+
+			fields := make(map[string]string)
+			for _, pair := range strings.Fields(summaryLine(result)) {
+				key, value, ok := strings.Cut(pair, "=")
+				Expect(ok).To(BeTrue())
+				fields[key] = value
+			}
+			Expect(fields).To(Equal(map[string]string{
+				"issues":   "4",
+				"critical": "1",
+				"high":     "2",
+				"medium":   "0",
+				"low":      "1",
+				"nodes":    "2",
+				"status":   "critical",
+			}))
+			Expect(summaryLine(result)).To(HavePrefix("issues=4 critical=1 high=2 medium=0 low=1 nodes=2"))
+		})
+	})
+
+	Describe("watchChanges", func() {
+		It("should identify issues that appeared and resolved between two scans", func() {
+			stuck := types.CSIMountIssue{Type: types.StuckVolumeAttachment, Severity: types.SeverityMedium, Node: "node-1", Volume: "pv-1"}
+			busy := types.CSIMountIssue{Type: types.DeviceBusy, Severity: types.SeverityHigh, Node: "node-2", PVC: "data"}
+			multi := types.CSIMountIssue{Type: types.MultipleAttachments, Severity: types.SeverityCritical, Volume: "pv-2"}
+
+			// The same issue with a new severity and description is not a change
+			escalated := stuck
+			escalated.Severity = types.SeverityHigh
+			escalated.Description = "stuck for longer"
+
+			delta := watchChanges(
+				&types.DetectionResult{Issues: []types.CSIMountIssue{stuck, busy}},
+				&types.DetectionResult{Issues: []types.CSIMountIssue{escalated, multi}},
+			)
+			Expect(delta.Added).To(Equal([]types.CSIMountIssue{multi}))
+			Expect(delta.Resolved).To(Equal([]types.CSIMountIssue{busy}))
+		})
+
+		It("should tell issues on another node apart", func() {
+			issue := types.CSIMountIssue{Type: types.DeviceBusy, Node: "node-1", PVC: "data"}
+			moved := issue
+			moved.Node = "node-2"
+
+			delta := watchChanges(
+				&types.DetectionResult{Issues: []types.CSIMountIssue{issue}},
+				&types.DetectionResult{Issues: []types.CSIMountIssue{moved}},
+			)
+			Expect(delta.Added).To(Equal([]types.CSIMountIssue{moved}))
+			Expect(delta.Resolved).To(Equal([]types.CSIMountIssue{issue}))
+		})
+
+		It("should print added and resolved issues with their markers", func() {
+			delta := watchChanges(
+				&types.DetectionResult{Issues: []types.CSIMountIssue{{Type: types.DeviceBusy, Severity: types.SeverityHigh, Node: "node-2", PVC: "data"}}},
+				&types.DetectionResult{Issues: []types.CSIMountIssue{{Type: types.MultipleAttachments, Severity: types.SeverityCritical, Volume: "pv-2"}}},
+			)
+
+			var out bytes.Buffer
+			writeWatchChanges(&out, delta)
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(Equal("CHANGES SINCE LAST SCAN: 1 new, 1 resolved"))
+			Expect(strings.Fields(lines[1])).To(Equal([]string{"+", "critical", string(types.MultipleAttachments), "-", "-", "pv-2"}))
+			Expect(strings.Fields(lines[2])).To(Equal([]string{"-", "high", string(types.DeviceBusy), "node-2", "data", "-"}))
+		})
+	})
+
+	Describe("writeWatchDiff", func() {
+		busy := types.CSIMountIssue{Type: types.DeviceBusy, Severity: types.SeverityHigh, Node: "node-2", PVC: "data"}
+		previous := &types.DetectionResult{Issues: []types.CSIMountIssue{busy}}
+
+		It("should compare a complete scan and keep it for the next one", func() {
+			current := &types.DetectionResult{}
+
+			var out bytes.Buffer
+			Expect(writeWatchDiff(&out, previous, current)).To(BeIdenticalTo(current))
+			Expect(out.String()).To(ContainSubstring("CHANGES SINCE LAST SCAN: 0 new, 1 resolved"))
+		})
+
+		It("should not compare a scan with failed methods and keep the previous one", func() {
+			current := &types.DetectionResult{Errors: []types.MethodError{{Method: types.EventsMethod, Error: "forbidden"}}}
+
+			var out bytes.Buffer
+			Expect(writeWatchDiff(&out, previous, current)).To(BeIdenticalTo(previous))
+			Expect(out.String()).To(ContainSubstring("not shown"))
+			Expect(out.String()).NotTo(ContainSubstring("resolved"))
+		})
+
+		It("should not compare an interrupted scan and keep the previous one", func() {
+			var out bytes.Buffer
+			Expect(writeWatchDiff(&out, previous, &types.DetectionResult{Partial: true})).To(BeIdenticalTo(previous))
+			Expect(out.String()).NotTo(ContainSubstring("resolved"))
+		})
+
+		It("should not use an incomplete first scan as the one to compare against", func() {
+			var out bytes.Buffer
+			Expect(writeWatchDiff(&out, nil, &types.DetectionResult{Partial: true})).To(BeNil())
+			Expect(out.String()).To(BeEmpty())
+		})
+	})
+
+	Describe("scanOutcome", func() {
+		scanErr := errors.New("detection failed")
+		partial := func(issues ...types.CSIMountIssue) *types.DetectionResult {
+			return &types.DetectionResult{
+				Summary: types.DetectionSummary{MethodsUsed: []types.DetectionMethod{types.EventsMethod}},
+				Issues:  issues,
+				Partial: true,
+			}
+		}
+
+		It("should exit with the scan failure code when no method completed", func() {
+			for _, result := range []*types.DetectionResult{nil, {Partial: true}} {
+				err := scanOutcome(result, scanErr, detectFlags{scanFailureExitCode: 3, exitZeroOnEmpty: true})
+				var exitErr *exitCodeError
+				Expect(errors.As(err, &exitErr)).To(BeTrue())
+				Expect(exitErr.code).To(Equal(3))
+				Expect(err).To(MatchError(scanErr))
+			}
+		})
+
+		It("should fail a partial scan with the default exit code", func() {
+			err := scanOutcome(partial(), scanErr, detectFlags{scanFailureExitCode: 3})
+			Expect(err).To(Equal(scanErr))
+
+			var exitErr *exitCodeError
+			Expect(errors.As(err, &exitErr)).To(BeFalse())
+		})
+
+		It("should succeed for a partial scan without issues with --exit-zero-on-empty", func() {
+			Expect(scanOutcome(partial(), scanErr, detectFlags{exitZeroOnEmpty: true})).To(Succeed())
+		})
+
+		It("should still fail a partial scan that found issues with --exit-zero-on-empty", func() {
+			result := partial(types.CSIMountIssue{Type: types.DeviceBusy, Node: "node-1"})
+			Expect(scanOutcome(result, scanErr, detectFlags{exitZeroOnEmpty: true})).To(Equal(scanErr))
+		})
+	})
+
+	Describe("failOnOutcome", func() {
+		issues := []types.CSIMountIssue{
+			{Type: types.DeviceBusy, Severity: types.SeverityMedium, Node: "node-1"},
+			{Type: types.MultiAttachError, Severity: types.SeverityCritical, Node: "node-2"},
+			{Type: types.MissingPVC, Severity: types.SeverityLow},
+		}
+
+		It("should fail with the generic exit code when issues reach the severity", func() {
+			err := failOnOutcome(issues, types.SeverityMedium)
+			Expect(err).To(MatchError("found 2 issue(s) at or above medium severity (--fail-on=medium)"))
+
+			var exitErr *exitCodeError
+			Expect(errors.As(err, &exitErr)).To(BeFalse())
+		})
+
+		It("should succeed when no issue reaches the severity", func() {
+			Expect(failOnOutcome(issues[:1], types.SeverityHigh)).To(Succeed())
+			Expect(failOnOutcome(nil, types.SeverityLow)).To(Succeed())
+		})
+
+		It("should succeed without --fail-on", func() {
+			Expect(failOnOutcome(issues, "")).To(Succeed())
+		})
+	})
+
+	Describe("detectionFailure", func() {
+		It("should name the configured timeout when the deadline passes", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+			defer cancel()
+			<-ctx.Done()
+
+			err := detectionFailure(ctx, ctx.Err(), 1500*time.Millisecond)
+			Expect(err).To(MatchError(ContainSubstring("detection timed out after 1.5s")))
+			Expect(err).To(MatchError(ContainSubstring("try reducing scope")))
+		})
+
+		It("should report an interrupted scan as partial", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := detectionFailure(ctx, context.Canceled, time.Minute)
+			Expect(err).To(MatchError(ContainSubstring("detection interrupted, results are partial")))
+		})
+	})
+
+	Describe("compareWithBaseline", func() {
+		It("should not store a scan with failed methods as the baseline", func() {
+			clientset := fake.NewSimpleClientset()
+			stuck := types.CSIMountIssue{Type: types.StuckVolumeAttachment, Node: "node-1", Volume: "pv-1"}
+			failed := &types.DetectionResult{
+				Issues: []types.CSIMountIssue{stuck},
+				Errors: []types.MethodError{{Method: types.EventsMethod, Error: "forbidden"}},
+			}
+
+			result, err := compareWithBaseline(client.NewClient(clientset), "csi-baseline", failed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeIdenticalTo(failed))
+			configMaps, err := clientset.CoreV1().ConfigMaps("").List(context.Background(), metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configMaps.Items).To(BeEmpty())
+
+			_, err = compareWithBaseline(client.NewClient(clientset), "csi-baseline", &types.DetectionResult{Issues: []types.CSIMountIssue{stuck}})
+			Expect(err).NotTo(HaveOccurred())
+			configMaps, err = clientset.CoreV1().ConfigMaps("").List(context.Background(), metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(configMaps.Items).To(HaveLen(1))
+		})
+	})
+
+	Describe("deleteVolumeAttachments", func() {
+		var (
+			ctx       context.Context
+			clientset *fake.Clientset
+			cleaner   *cleanup.VolumeAttachmentCleaner
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			pv := "pv-gone"
+			clientset = fake.NewSimpleClientset(&storagev1.VolumeAttachment{
+				ObjectMeta: metav1.ObjectMeta{Name: "csi-stale"},
+				Spec: storagev1.VolumeAttachmentSpec{
+					NodeName: "node-1",
+					Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pv},
+				},
+			})
+			cleaner = cleanup.NewVolumeAttachmentCleaner(clientset)
+		})
+
+		exists := func() bool {
+			_, err := clientset.StorageV1().VolumeAttachments().Get(ctx, "csi-stale", metav1.GetOptions{})
+			return err == nil
+		}
+
+		It("should keep the attachments when the prompt is declined", func() {
+			var out bytes.Buffer
+			err := deleteVolumeAttachments(ctx, cleaner, []string{"csi-stale"}, volumeAttachmentCleanupFlags{timeout: time.Minute}, strings.NewReader("n\n"), &out)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.String()).To(ContainSubstring("Delete 1 VolumeAttachment(s)? [y/N]"))
+			Expect(out.String()).To(ContainSubstring("nothing was deleted"))
+			Expect(exists()).To(BeTrue())
+		})
+
+		It("should delete after the prompt is confirmed", func() {
+			var out bytes.Buffer
+			err := deleteVolumeAttachments(ctx, cleaner, []string{"csi-stale"}, volumeAttachmentCleanupFlags{timeout: time.Minute}, strings.NewReader("yes\n"), &out)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.String()).To(ContainSubstring("Deleted VolumeAttachment csi-stale"))
+			Expect(exists()).To(BeFalse())
+		})
+
+		It("should only list the attachments in a dry run", func() {
+			var out bytes.Buffer
+			err := deleteVolumeAttachments(ctx, cleaner, []string{"csi-stale"}, volumeAttachmentCleanupFlags{dryRun: true, force: true, timeout: time.Minute}, strings.NewReader(""), &out)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out.String()).To(ContainSubstring("Would delete VolumeAttachment csi-stale (PV pv-gone on node node-1)"))
+			Expect(exists()).To(BeTrue())
+		})
+	})
+
+	Describe("writeNamespaceReports", func() {
+		It("should write a report per namespace plus the cluster report", func() {
+			issues := []types.CSIMountIssue{
+				{Type: types.MultipleAttachments, Severity: types.SeverityCritical, PVC: "shop/data-web-0", Namespace: "shop"},
+				{Type: types.MissingPVC, Severity: types.SeverityHigh, PVC: "billing/ledger"},
+				{Type: types.DeviceBusy, Severity: types.SeverityHigh, Node: "node-1", PVC: "data-web-1", Namespace: "shop"},
+				{Type: types.StuckVolumeAttachment, Severity: types.SeverityMedium, Node: "node-2", Volume: "pv-1"},
+			}
+			methods := []types.DetectionMethod{types.VolumeAttachmentMethod, types.CrossNodePVCMethod}
+			result := &types.DetectionResult{
+				Summary:         detect.Summarize(issues, methods),
+				Issues:          issues,
+				Recommendations: []string{"## Immediate Actions"},
+			}
+
+			dir := filepath.Join(GinkgoT().TempDir(), "reports")
+			count, err := writeNamespaceReports(dir, result, types.DetectionOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+
+			entries, err := os.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			Expect(names).To(ConsistOf("shop.json", "billing.json", "_cluster.json"))
+
+			read := func(name string) types.DetectionResult {
+				data, err := os.ReadFile(filepath.Join(dir, name))
+				Expect(err).NotTo(HaveOccurred())
+				var report types.DetectionResult
+				Expect(json.Unmarshal(data, &report)).To(Succeed())
+				return report
+			}
+			shop := read("shop.json")
+			Expect(shop.Issues).To(Equal([]types.CSIMountIssue{issues[0], issues[2]}))
+			Expect(shop.Summary.TotalIssues).To(Equal(2))
+			Expect(shop.Summary.Status).To(Equal(types.StatusCritical))
+			Expect(shop.Summary.MethodsUsed).To(Equal(methods))
+			Expect(shop.Recommendations).To(BeEmpty())
+
+			billing := read("billing.json")
+			Expect(billing.Issues).To(Equal([]types.CSIMountIssue{issues[1]}))
+			Expect(billing.Summary.Status).To(Equal(types.StatusHealthy))
+
+			cluster := read("_cluster.json")
+			Expect(cluster.Issues).To(Equal([]types.CSIMountIssue{issues[3]}))
+			Expect(cluster.Summary.AffectedNodes).To(Equal([]string{"node-2"}))
+		})
+	})
+
+	Describe("writeResult", func() {
+		It("should write a populated result as YAML that reads back unchanged", func() {
+			snapshot := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			issues := []types.CSIMountIssue{{
+				Type:        types.StuckVolumeAttachment,
+				Severity:    types.SeverityHigh,
+				Node:        "node-1",
+				Volume:      "pvc-3c9d8e7f",
+				Driver:      "cinder.csi.openstack.org",
+				Description: "Volume stuck in attaching state for 2h0m0s",
+				DetectedBy:  types.VolumeAttachmentMethod,
+				DetectedAt:  snapshot,
+				OccurredAt:  snapshot.Add(-2 * time.Hour),
+				Metadata:    map[string]string{"volume_attachment_name": "csi-0a1b2c"},
+			}}
+			result := &types.DetectionResult{
+				SchemaVersion: types.SchemaVersion,
+				Summary:       detect.Summarize(issues, []types.DetectionMethod{types.VolumeAttachmentMethod}),
+				Issues:        issues,
+				GeneratedAt:   snapshot,
+			}
+			result.Summary.SnapshotTime = snapshot
+			result.Summary.Status = types.StatusDegraded
+
+			var out bytes.Buffer
+			Expect(writeResult(&out, result, detectFlags{outputFormat: "yaml"})).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("generatedAt: \"2024-05-01T12:00:00Z\""))
+			Expect(out.String()).To(ContainSubstring("issuesBySeverity:\n    high: 1"))
+
+			var decoded types.DetectionResult
+			Expect(yaml.Unmarshal(out.Bytes(), &decoded)).To(Succeed())
+			Expect(decoded.Issues).To(HaveLen(1))
+			Expect(decoded.Issues[0].OccurredAt).To(BeTemporally("==", issues[0].OccurredAt))
+			Expect(decoded.Issues[0].Metadata).To(Equal(issues[0].Metadata))
+			Expect(decoded.Summary.IssuesBySeverity).To(Equal(map[types.IssueSeverity]int{types.SeverityHigh: 1}))
+			Expect(decoded.Summary.Status).To(Equal(types.StatusDegraded))
+			Expect(decoded.GeneratedAt).To(BeTemporally("==", snapshot))
+		})
+
+		It("should write one CSV row per issue that parses back to the issue fields", func() {
+			detectedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			result := &types.DetectionResult{Issues: []types.CSIMountIssue{
+				{
+					Type: types.MultiAttachError, Severity: types.SeverityCritical, Node: "node-a", Volume: "pv-1",
+					PVC: "data", Namespace: "default", Driver: "cinder.csi.openstack.org", DetectedBy: types.EventsMethod,
+					DetectedAt: detectedAt, Description: "Multi-Attach error for volume \"pv-1\", already used by pod(s) web-0, web-1\nretrying",
+				},
+				{Type: types.MissingPVC, Severity: types.SeverityLow, PVC: "gone", Namespace: "default", Description: "PVC not found"},
+			}}
+
+			var out bytes.Buffer
+			Expect(writeResult(&out, result, detectFlags{outputFormat: "csv"})).To(Succeed())
+
+			records, err := csv.NewReader(&out).ReadAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(3))
+			Expect(records[0]).To(Equal([]string{"type", "severity", "node", "volume", "pvc", "namespace", "driver", "detectedBy", "detectedAt", "description"}))
+			Expect(records[1]).To(Equal([]string{
+				string(types.MultiAttachError), "critical", "node-a", "pv-1", "data", "default",
+				"cinder.csi.openstack.org", string(types.EventsMethod), "2024-05-01T12:00:00Z", result.Issues[0].Description,
+			}))
+			Expect(records[2][0]).To(Equal(string(types.MissingPVC)))
+			Expect(records[2][2]).To(BeEmpty())
+			Expect(records[2][8]).To(BeEmpty())
+		})
+
+		It("should write one JSON object per issue and line", func() {
+			detectedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			result := &types.DetectionResult{Issues: []types.CSIMountIssue{
+				{
+					Type: types.MultiAttachError, Severity: types.SeverityCritical, Node: "node-a", Volume: "pv-1",
+					DetectedBy: types.EventsMethod, DetectedAt: detectedAt, Description: "Multi-Attach error\nretrying",
+					Metadata: map[string]string{"event_reason": "FailedAttachVolume"},
+				},
+				{Type: types.MissingPVC, Severity: types.SeverityLow, PVC: "gone", Namespace: "default", DetectedAt: detectedAt, Description: "PVC not found"},
+			}}
+
+			var out bytes.Buffer
+			Expect(writeResult(&out, result, detectFlags{outputFormat: "jsonl"})).To(Succeed())
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			Expect(lines).To(HaveLen(2))
+			for i, line := range lines {
+				var fields map[string]interface{}
+				Expect(json.Unmarshal([]byte(line), &fields)).To(Succeed())
+				Expect(fields["detectedAt"]).To(Equal("2024-05-01T12:00:00Z"))
+
+				var issue types.CSIMountIssue
+				Expect(json.Unmarshal([]byte(line), &issue)).To(Succeed())
+				Expect(issue).To(Equal(result.Issues[i]))
+			}
+		})
+
+		It("should write no lines for a result without issues", func() {
+			var out bytes.Buffer
+			Expect(writeResult(&out, &types.DetectionResult{}, detectFlags{outputFormat: "jsonl"})).To(Succeed())
+			Expect(out.String()).To(BeEmpty())
+		})
+
+		It("should list each node with its issues and cluster-wide issues last", func() {
+			result := &types.DetectionResult{Issues: []types.CSIMountIssue{
+				{Type: types.MissingPVC, Severity: types.SeverityLow, PVC: "default/gone", Description: "PVC not found"},
+				{Type: types.StuckVolumeAttachment, Severity: types.SeverityMedium, Node: "node-b", Volume: "pv-1", Description: "stuck"},
+				{Type: types.MultiAttachError, Severity: types.SeverityCritical, Node: "node-b", Volume: "pv-2", Description: "multi-attach"},
+				{Type: types.DeviceBusy, Severity: types.SeverityHigh, Node: "node-a", PVC: "default/data", Description: "busy"},
+			}}
+
+			var out bytes.Buffer
+			Expect(writeResult(&out, result, detectFlags{outputFormat: "by-node"})).To(Succeed())
+
+			var headers, rows []string
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				switch {
+				case line == "":
+				case strings.HasPrefix(line, "  "):
+					rows = append(rows, strings.Fields(line)[1])
+				default:
+					headers = append(headers, line)
+				}
+			}
+			Expect(headers).To(Equal([]string{"node-a (1 issues)", "node-b (2 issues)", types.ClusterWideNode + " (1 issues)"}))
+			Expect(rows).To(Equal([]string{
+				string(types.DeviceBusy), string(types.MultiAttachError), string(types.StuckVolumeAttachment), string(types.MissingPVC),
+			}))
+		})
+	})
+
+	Describe("compactResult", func() {
+		It("should reduce an all-clear result to a minimal object", func() {
+			generatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			result := &types.DetectionResult{
+				Summary: types.DetectionSummary{
+					IssuesBySeverity: map[types.IssueSeverity]int{},
+					IssuesByType:     map[types.IssueType]int{},
+				},
+				Issues:      []types.CSIMountIssue{},
+				GeneratedAt: generatedAt,
+			}
+
+			compact, err := compactResult(result)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(compact).To(Equal(map[string]interface{}{
+				"generatedAt": "2024-05-01T12:00:00Z",
+			}))
+		})
+
+		It("should keep populated fields and drop empty issue metadata", func() {
+			result := &types.DetectionResult{
+				Summary: types.DetectionSummary{
+					TotalIssues:   1,
+					AffectedNodes: []string{"node-1"},
+					MethodsUsed:   []types.DetectionMethod{types.EventsMethod},
+				},
+				Issues: []types.CSIMountIssue{{Node: "node-1", Metadata: map[string]string{}}},
+			}
+
+			compact, err := compactResult(result)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(compact["summary"]).To(Equal(map[string]interface{}{
+				"totalIssues":   float64(1),
+				"affectedNodes": []interface{}{"node-1"},
+				"methodsUsed":   []interface{}{"events"},
+			}))
+			Expect(compact["issues"]).To(HaveLen(1))
+			Expect(compact["issues"].([]interface{})[0]).NotTo(HaveKey("metadata"))
+		})
+
+		It("should drop empty fields from YAML output too", func() {
+			result := &types.DetectionResult{
+				Summary: types.DetectionSummary{TotalIssues: 1},
+				Issues:  []types.CSIMountIssue{{Node: "node-1", Metadata: map[string]string{}}},
+			}
+
+			var out bytes.Buffer
+			Expect(writeResult(&out, result, detectFlags{outputFormat: "yaml", omitEmpty: true})).To(Succeed())
+
+			var decoded map[string]interface{}
+			Expect(yaml.Unmarshal(out.Bytes(), &decoded)).To(Succeed())
+			Expect(decoded["summary"]).To(Equal(map[string]interface{}{"totalIssues": float64(1)}))
+			Expect(decoded).NotTo(HaveKey("generatedAt"))
+			Expect(decoded["issues"].([]interface{})[0]).NotTo(HaveKey("metadata"))
+		})
+	})
+
+	Describe("cacheKey", func() {
+		It("should ignore the output format but not detection settings", func() {
+			options := types.DetectionOptions{
+				Methods:      []types.DetectionMethod{types.VolumeAttachmentMethod},
+				TargetDriver: "cinder.csi.openstack.org",
+				OutputFormat: "table",
+			}
+			asReport := options
+			asReport.OutputFormat = "report"
+			otherDriver := options
+			otherDriver.TargetDriver = "ebs.csi.aws.com"
+
+			Expect(cacheKey(asReport)).To(Equal(cacheKey(options)))
+			Expect(cacheKey(otherDriver)).NotTo(Equal(cacheKey(options)))
+		})
+	})
+
+	Describe("analyzeOptions", func() {
+		It("should pass the driver and methods to the detection options", func() {
+			options, err := analyzeOptions(analyzeFlags{
+				methods:      []string{"volumeattachments", "events"},
+				targetDriver: "cinder.csi.openstack.org",
+				outputFormat: "yaml",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options.TargetDriver).To(Equal("cinder.csi.openstack.org"))
+			Expect(options.Methods).To(Equal([]types.DetectionMethod{types.VolumeAttachmentMethod, types.EventsMethod}))
+		})
+
+		It("should pass the top PVC count and reject negative counts", func() {
+			options, err := analyzeOptions(analyzeFlags{outputFormat: "json", topPVCs: 20})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(options.TopPVCs).To(Equal(20))
+
+			_, err = analyzeOptions(analyzeFlags{outputFormat: "json", topPVCs: -1})
+			Expect(err).To(MatchError(ContainSubstring("invalid top PVC count -1")))
+		})
+
+		It("should accept exactly the methods that have an analysis", func() {
+			for _, method := range detect.AvailableMethods() {
+				_, err := analyzeOptions(analyzeFlags{methods: []string{string(method.Method)}, outputFormat: "json"})
+				if method.Analysis {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(MatchError(ContainSubstring("invalid analysis method")))
+				}
+			}
+		})
+
+		It("should reject methods that have no analysis", func() {
+			_, err := analyzeOptions(analyzeFlags{methods: []string{"storageclass"}, outputFormat: "json"})
+			Expect(err).To(MatchError(ContainSubstring("invalid analysis method 'storageclass'")))
+		})
+
+		It("should reject unknown output formats", func() {
+			_, err := analyzeOptions(analyzeFlags{outputFormat: "table"})
+			Expect(err).To(MatchError(ContainSubstring("invalid output format 'table'")))
+		})
+	})
+
+	Describe("annotatablePVCs", func() {
+		It("should leave out PVCs from missing PVC issues", func() {
+			issues := []types.CSIMountIssue{
+				{Type: types.StuckMountReference, PVC: "data-web-0", Namespace: "shop"},
+				{Type: types.MissingPVC, PVC: "deleted-claim", Namespace: "shop"},
+				{Type: types.FailedAttachVolume, PVC: "shop/data-web-1"},
+			}
+			Expect(annotatablePVCs(issues)).To(Equal([]string{"shop/data-web-0", "shop/data-web-1"}))
+		})
+	})
+
+	Describe("printMethods", func() {
+		It("should describe each method with the RBAC it needs", func() {
+			var out bytes.Buffer
+			Expect(printMethods(&out, []types.MethodInfo{
+				{
+					Method:      types.VolumeAttachmentMethod,
+					Description: "Check VolumeAttachment objects",
+					Reads:       []string{"VolumeAttachments"},
+					Permissions: []types.Permission{{Resource: "volumeattachments.storage.k8s.io", Verbs: []string{"get", "list"}}},
+				},
+				{
+					Method:      types.MetricsMethod,
+					Description: "Query Prometheus",
+					Reads:       []string{"Prometheus"},
+				},
+			})).To(Succeed())
+
+			Expect(out.String()).To(Equal("volumeattachments\n" +
+				"  Description: Check VolumeAttachment objects\n" +
+				"  Reads:       VolumeAttachments\n" +
+				"  Permissions:\n" +
+				"    - volumeattachments.storage.k8s.io: get,list\n" +
+				"\n" +
+				"metrics\n" +
+				"  Description: Query Prometheus\n" +
+				"  Reads:       Prometheus\n" +
+				"  Permissions: none (no Kubernetes API access)\n" +
+				"\n"))
+		})
+
+		It("should list the name and description of every available method", func() {
+			var out bytes.Buffer
+			Expect(printMethods(&out, detect.AvailableMethods())).To(Succeed())
+
+			for _, method := range detect.AvailableMethods() {
+				Expect(out.String()).To(ContainSubstring(string(method.Method) + "\n  Description: " + method.Description + "\n"))
+			}
+		})
+	})
+
+	Describe("runValidateConfig", func() {
+		var (
+			path   string
+			out    *bytes.Buffer
+			errOut *bytes.Buffer
+		)
+
+		BeforeEach(func() {
+			path = filepath.Join(GinkgoT().TempDir(), "config.yaml")
+			out = &bytes.Buffer{}
+			errOut = &bytes.Buffer{}
+		})
+
+		It("should print OK and the effective settings for a valid config", func() {
+			Expect(os.WriteFile(path, []byte("driver: ebs.csi.aws.com\nstuckThreshold: 5m\n"), 0o600)).To(Succeed())
+
+			Expect(runValidateConfig(out, errOut, path)).To(Succeed())
+			Expect(out.String()).To(HavePrefix("OK\n"))
+			Expect(out.String()).To(ContainSubstring("driver: ebs.csi.aws.com"))
+			Expect(out.String()).To(ContainSubstring("stuckThreshold: 5m"))
+			Expect(out.String()).To(ContainSubstring("notifyOn: low"))
+			Expect(errOut.String()).To(BeEmpty())
+		})
+
+		It("should list every validation error", func() {
+			Expect(os.WriteFile(path, []byte("minSeverity: urgent\nstuckThreshold: soon\n"), 0o600)).To(Succeed())
+
+			err := runValidateConfig(out, errOut, path)
+			Expect(err).To(MatchError(ContainSubstring("has 2 error(s)")))
+			Expect(errOut.String()).To(ContainSubstring("minSeverity"))
+			Expect(errOut.String()).To(ContainSubstring("stuckThreshold"))
+			Expect(out.String()).To(BeEmpty())
+		})
+
+		It("should reject unknown keys", func() {
+			Expect(os.WriteFile(path, []byte("severity: high\n"), 0o600)).To(Succeed())
+
+			err := runValidateConfig(out, errOut, path)
+			Expect(err).To(MatchError(ContainSubstring("severity")))
+		})
+	})
+
+	Describe("cleanup --image", func() {
+		imageFlag := func(args ...string) string {
+			cmd := newCleanupCmd()
+			Expect(cmd.Flags().Parse(args)).To(Succeed())
+			return cmd.Flags().Lookup("image").Value.String()
+		}
+
+		It("should use the built-in image when neither the flag nor the env var is set", func() {
+			Expect(os.Unsetenv(cleanupImageEnv)).To(Succeed())
+			Expect(imageFlag()).To(Equal(defaultCleanupImage))
+		})
+
+		It("should use the env var when the flag is absent", func() {
+			Expect(os.Setenv(cleanupImageEnv, "registry.internal/csi-cleanup:1.0")).To(Succeed())
+			DeferCleanup(os.Unsetenv, cleanupImageEnv)
+			Expect(imageFlag()).To(Equal("registry.internal/csi-cleanup:1.0"))
+		})
+
+		It("should prefer the flag over the env var", func() {
+			Expect(os.Setenv(cleanupImageEnv, "registry.internal/csi-cleanup:1.0")).To(Succeed())
+			DeferCleanup(os.Unsetenv, cleanupImageEnv)
+			Expect(imageFlag("--image=myregistry/csi-cleanup:2.0")).To(Equal("myregistry/csi-cleanup:2.0"))
+		})
+	})
+
+	Describe("cleanupTargetNodes", func() {
+		now := time.Now()
+		var path string
+
+		BeforeEach(func() {
+			path = filepath.Join(GinkgoT().TempDir(), "scan.json")
+			Expect(cache.SaveResult(path, &types.DetectionResult{
+				Issues: []types.CSIMountIssue{
+					{Node: "node-a", Severity: types.SeverityHigh, DetectedAt: now.Add(-5 * time.Minute)},
+					{Node: "node-b", Severity: types.SeverityHigh, DetectedAt: now.Add(-2 * time.Hour)},
+					{Node: "node-c", Severity: types.SeverityLow},
+				},
+			})).To(Succeed())
+		})
+
+		It("should add the nodes of a saved result to --nodes, each once", func() {
+			nodes, err := cleanupTargetNodes(cleanupFlags{targetNodes: []string{"node-b", "node-z"}, fromDetect: path}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodes).To(Equal([]string{"node-a", "node-b", "node-c", "node-z"}))
+		})
+
+		It("should only take nodes detected within --since", func() {
+			nodes, err := cleanupTargetNodes(cleanupFlags{fromDetect: path, since: time.Hour}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodes).To(Equal([]string{"node-a"}))
+
+			nodes, err = cleanupTargetNodes(cleanupFlags{fromDetect: path, since: time.Hour, includeUntimed: true}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodes).To(Equal([]string{"node-a", "node-c"}))
+		})
+
+		It("should fail when the result cannot be read", func() {
+			_, err := cleanupTargetNodes(cleanupFlags{fromDetect: filepath.Join(GinkgoT().TempDir(), "missing.json")}, now)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("resolveConfigPath", func() {
+		var home string
+
+		BeforeEach(func() {
+			home = GinkgoT().TempDir()
+			oldHome, hadHome := os.LookupEnv("HOME")
+			Expect(os.Setenv("HOME", home)).To(Succeed())
+			DeferCleanup(func() {
+				if hadHome {
+					os.Setenv("HOME", oldHome)
+				} else {
+					os.Unsetenv("HOME")
+				}
+			})
+		})
+
+		It("should use --config when given", func() {
+			Expect(resolveConfigPath("csi-scan.yaml")).To(Equal("csi-scan.yaml"))
+		})
+
+		It("should fall back to ~/.kube/csi-scan.yaml when it exists", func() {
+			Expect(os.Mkdir(filepath.Join(home, ".kube"), 0o700)).To(Succeed())
+			path := filepath.Join(home, ".kube", "csi-scan.yaml")
+			Expect(os.WriteFile(path, []byte("driver: ebs.csi.aws.com\n"), 0o600)).To(Succeed())
+
+			Expect(resolveConfigPath("")).To(Equal(path))
+		})
+
+		It("should load no config when the default file does not exist", func() {
+			Expect(resolveConfigPath("")).To(BeEmpty())
+		})
+	})
+
+	Describe("loadValidConfig", func() {
+		It("should reject a malformed file", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+			Expect(os.WriteFile(path, []byte("methods: [events\n"), 0o600)).To(Succeed())
+
+			_, err := loadValidConfig(path)
+			Expect(err).To(MatchError(ContainSubstring("failed to parse config")))
+		})
+	})
+
+	Describe("detect flag aliases", func() {
+		It("should set the webhook flags through their Slack aliases", func() {
+			cmd := newDetectCmd()
+			Expect(cmd.Flags().Parse([]string{"--slack-webhook=https://hooks.example.com/x", "--notify-min-severity=high"})).To(Succeed())
+
+			Expect(cmd.Flags().Lookup("webhook-url").Value.String()).To(Equal("https://hooks.example.com/x"))
+			Expect(cmd.Flags().Lookup("notify-on").Value.String()).To(Equal("high"))
+			Expect(cmd.Flags().Changed("notify-on")).To(BeTrue())
+		})
+	})
+
+	Describe("applyConfig", func() {
+		It("should fill unset flags and keep flags given on the command line", func() {
+			cmd := newDetectCmd()
+			Expect(cmd.Flags().Parse([]string{"--driver=ebs.csi.aws.com"})).To(Succeed())
+
+			flags := detectFlags{targetDriver: "ebs.csi.aws.com", stuckThreshold: detect.DefaultStuckThreshold}
+			cfg := &config.Config{
+				Driver:         "cinder.csi.openstack.org",
+				StuckThreshold: "10m",
+			}
+
+			Expect(applyConfig(cmd, &flags, cfg)).To(Succeed())
+			Expect(flags.targetDriver).To(Equal("ebs.csi.aws.com"))
+			Expect(flags.stuckThreshold).To(Equal(10 * time.Minute))
+		})
+
+		It("should let an explicit flag override the file's value for the same setting", func() {
+			cmd := newDetectCmd()
+			Expect(cmd.Flags().Parse([]string{"--min-severity=critical", "--method=events"})).To(Succeed())
+
+			flags := detectFlags{minSeverity: "critical", methods: []string{"events"}}
+			cfg := &config.Config{MinSeverity: "medium", Methods: []string{"volumeattachments"}, Driver: "ebs.csi.aws.com"}
+
+			Expect(applyConfig(cmd, &flags, cfg)).To(Succeed())
+			Expect(flags.minSeverity).To(Equal("critical"))
+			Expect(flags.methods).To(Equal([]string{"events"}))
+			Expect(flags.targetDriver).To(Equal("ebs.csi.aws.com"))
+		})
+	})
+})
@@ -0,0 +1,157 @@
+package baseline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// resultKey is the ConfigMap data key holding the baseline result
+const resultKey = "result.json"
+
+// Label marks ConfigMaps that hold a detection baseline
+const Label = "kubectl-csi-scan/baseline"
+
+// maxDataSize is the most data the API server accepts in a ConfigMap
+const maxDataSize = 1024 * 1024
+
+// Delta is the change between a baseline and a new detection result
+type Delta struct {
+	Added    []types.CSIMountIssue
+	Resolved []types.CSIMountIssue
+}
+
+// Diff compares issues by their stable ID, returning those that are new in current and
+// those in the baseline that are no longer detected, each in their original order
+func Diff(baseline, current []types.CSIMountIssue) Delta {
+	before := make(map[string]bool, len(baseline))
+	for _, issue := range baseline {
+		before[issue.ID()] = true
+	}
+	after := make(map[string]bool, len(current))
+	for _, issue := range current {
+		after[issue.ID()] = true
+	}
+
+	var delta Delta
+	for _, issue := range current {
+		if !before[issue.ID()] {
+			delta.Added = append(delta.Added, issue)
+		}
+	}
+	for _, issue := range baseline {
+		if !after[issue.ID()] {
+			delta.Resolved = append(delta.Resolved, issue)
+		}
+	}
+	return delta
+}
+
+// OnlyChanged returns a copy of result holding only the issues added since the baseline,
+// with the resolved ones in Resolved. The summary describes the added issues.
+func OnlyChanged(result, baseline *types.DetectionResult) *types.DetectionResult {
+	delta := Diff(baseline.Issues, result.Issues)
+
+	changed := *result
+	changed.Issues = delta.Added
+	changed.Resolved = delta.Resolved
+	changed.Summary = detect.Summarize(delta.Added, result.Summary.MethodsUsed)
+	changed.Summary.SnapshotTime = result.Summary.SnapshotTime
+	changed.Summary.Suppressed = result.Summary.Suppressed
+	changed.Summary.Status = result.Summary.Status
+	return &changed
+}
+
+// Store keeps a baseline detection result in a ConfigMap so recurring scans, for example
+// from a CronJob, can report only what changed
+type Store struct {
+	client    client.KubernetesClient
+	namespace string
+	name      string
+}
+
+// NewStore creates a store for the ConfigMap namespace/name
+func NewStore(kubeClient client.KubernetesClient, namespace, name string) *Store {
+	return &Store{
+		client:    kubeClient,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+// Load returns the stored baseline, or nil if none has been saved yet
+func (s *Store) Load(ctx context.Context) (*types.DetectionResult, error) {
+	configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get baseline ConfigMap %s/%s: %w", s.namespace, s.name, err)
+	}
+
+	data, ok := configMap.Data[resultKey]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap %s/%s has no %s and does not hold a baseline", s.namespace, s.name, resultKey)
+	}
+	var result types.DetectionResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline in ConfigMap %s/%s: %w", s.namespace, s.name, err)
+	}
+	return &result, nil
+}
+
+// Save replaces the stored baseline with result. A result too large for a ConfigMap is
+// stored with only the fields that identify and summarize each issue, which is all a later
+// comparison needs; one still too large is an error.
+func (s *Store) Save(ctx context.Context, result *types.DetectionResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+	if len(resultKey)+len(data) > maxDataSize {
+		if data, err = json.Marshal(compact(result)); err != nil {
+			return fmt.Errorf("failed to marshal baseline: %w", err)
+		}
+		if len(resultKey)+len(data) > maxDataSize {
+			return fmt.Errorf("baseline of %d issues is %d bytes, more than a ConfigMap can hold", len(result.Issues), len(data))
+		}
+	}
+
+	return detect.ApplyConfigMap(ctx, s.client, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.name,
+			Namespace: s.namespace,
+			Labels:    map[string]string{Label: "true"},
+		},
+		Data: map[string]string{resultKey: string(data)},
+	})
+}
+
+// compact returns a copy of result whose issues keep only their identity, severity and
+// driver, without descriptions, metadata, sources or recommendations
+func compact(result *types.DetectionResult) *types.DetectionResult {
+	compacted := *result
+	compacted.Recommendations = nil
+	compacted.Resolved = nil
+	compacted.Issues = make([]types.CSIMountIssue, len(result.Issues))
+	for i, issue := range result.Issues {
+		compacted.Issues[i] = types.CSIMountIssue{
+			Type:      issue.Type,
+			Severity:  issue.Severity,
+			Node:      issue.Node,
+			Volume:    issue.Volume,
+			PVC:       issue.PVC,
+			Namespace: issue.Namespace,
+			Driver:    issue.Driver,
+		}
+	}
+	return &compacted
+}
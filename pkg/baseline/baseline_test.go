@@ -0,0 +1,150 @@
+package baseline_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/baseline"
+	"github.com/jdambly/kubectl-csi-scan/pkg/client/mocks"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("Baseline", func() {
+	stuck := types.CSIMountIssue{Type: types.StuckVolumeAttachment, Severity: types.SeverityHigh, Node: "node-1", Volume: "pv-1"}
+	multi := types.CSIMountIssue{Type: types.MultipleAttachments, Severity: types.SeverityCritical, PVC: "default/data"}
+	busy := types.CSIMountIssue{Type: types.DeviceBusy, Severity: types.SeverityMedium, Node: "node-2", Volume: "pv-2"}
+
+	Describe("Diff", func() {
+		It("should report added and resolved issues by stable ID", func() {
+			// A re-detected issue keeps its ID even though its description changed
+			stuckAgain := stuck
+			stuckAgain.Description = "attached for 2h"
+
+			delta := baseline.Diff([]types.CSIMountIssue{stuck, multi}, []types.CSIMountIssue{stuckAgain, busy})
+			Expect(delta.Added).To(Equal([]types.CSIMountIssue{busy}))
+			Expect(delta.Resolved).To(Equal([]types.CSIMountIssue{multi}))
+		})
+	})
+
+	Describe("Store", func() {
+		var (
+			ctrl           *gomock.Controller
+			mockClient     *mocks.MockKubernetesClient
+			mockCoreV1     *mocks.MockCoreV1Interface
+			mockConfigMaps *mocks.MockConfigMapInterface
+			store          *baseline.Store
+			stored         *corev1.ConfigMap
+			ctx            context.Context
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			mockClient = mocks.NewMockKubernetesClient(ctrl)
+			mockCoreV1 = mocks.NewMockCoreV1Interface(ctrl)
+			mockConfigMaps = mocks.NewMockConfigMapInterface(ctrl)
+			ctx = context.Background()
+			stored = nil
+
+			mockClient.EXPECT().CoreV1().Return(mockCoreV1).AnyTimes()
+			mockCoreV1.EXPECT().ConfigMaps("monitoring").Return(mockConfigMaps).AnyTimes()
+
+			// Back the mock with a single in-memory ConfigMap
+			notFound := apierrors.NewNotFound(corev1.Resource("configmaps"), "csi-baseline")
+			mockConfigMaps.EXPECT().Get(ctx, "csi-baseline", metav1.GetOptions{}).DoAndReturn(
+				func(context.Context, string, metav1.GetOptions) (*corev1.ConfigMap, error) {
+					if stored == nil {
+						return nil, notFound
+					}
+					return stored.DeepCopy(), nil
+				}).AnyTimes()
+			mockConfigMaps.EXPECT().Create(ctx, gomock.Any(), metav1.CreateOptions{}).DoAndReturn(
+				func(_ context.Context, configMap *corev1.ConfigMap, _ metav1.CreateOptions) (*corev1.ConfigMap, error) {
+					if stored != nil {
+						return nil, apierrors.NewAlreadyExists(corev1.Resource("configmaps"), configMap.Name)
+					}
+					stored = configMap.DeepCopy()
+					return configMap, nil
+				}).AnyTimes()
+			mockConfigMaps.EXPECT().Update(ctx, gomock.Any(), metav1.UpdateOptions{}).DoAndReturn(
+				func(_ context.Context, configMap *corev1.ConfigMap, _ metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+					stored = configMap.DeepCopy()
+					return configMap, nil
+				}).AnyTimes()
+
+			store = baseline.NewStore(mockClient, "monitoring", "csi-baseline")
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("should report just the delta on a second run against the stored baseline", func() {
+			first := &types.DetectionResult{
+				Issues:  []types.CSIMountIssue{stuck, multi},
+				Summary: types.DetectionSummary{TotalIssues: 2, MethodsUsed: []types.DetectionMethod{types.VolumeAttachmentMethod}},
+			}
+			second := &types.DetectionResult{
+				Issues:  []types.CSIMountIssue{stuck, busy},
+				Summary: types.DetectionSummary{TotalIssues: 2, MethodsUsed: []types.DetectionMethod{types.VolumeAttachmentMethod}},
+			}
+
+			// First run: nothing stored yet
+			previous, err := store.Load(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(previous).To(BeNil())
+			Expect(store.Save(ctx, first)).To(Succeed())
+			Expect(stored.Labels).To(HaveKeyWithValue(baseline.Label, "true"))
+
+			// Second run: compare against the first, then replace it
+			previous, err = store.Load(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			changed := baseline.OnlyChanged(second, previous)
+			Expect(store.Save(ctx, second)).To(Succeed())
+
+			Expect(changed.Issues).To(Equal([]types.CSIMountIssue{busy}))
+			Expect(changed.Resolved).To(Equal([]types.CSIMountIssue{multi}))
+			Expect(changed.Summary.TotalIssues).To(Equal(1))
+			Expect(changed.Summary.AffectedNodes).To(Equal([]string{"node-2"}))
+			Expect(second.Issues).To(HaveLen(2))
+
+			latest, err := store.Load(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(latest.Issues).To(Equal(second.Issues))
+		})
+
+		It("should keep only what a comparison needs when the result is too large for a ConfigMap", func() {
+			large := stuck
+			large.Description = strings.Repeat("x", 1024*1024)
+			large.Metadata = map[string]string{"event": "details"}
+
+			Expect(store.Save(ctx, &types.DetectionResult{Issues: []types.CSIMountIssue{large, multi}})).To(Succeed())
+
+			saved, err := store.Load(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved.Issues).To(Equal([]types.CSIMountIssue{stuck, multi}))
+		})
+
+		It("should fail rather than store a baseline a ConfigMap cannot hold", func() {
+			huge := stuck
+			huge.Volume = strings.Repeat("x", 1024*1024)
+
+			err := store.Save(ctx, &types.DetectionResult{Issues: []types.CSIMountIssue{huge}})
+			Expect(err).To(MatchError(ContainSubstring("more than a ConfigMap can hold")))
+			Expect(stored).To(BeNil())
+		})
+
+		It("should reject a ConfigMap that does not hold a baseline", func() {
+			stored = &corev1.ConfigMap{Data: map[string]string{"other": "x"}}
+
+			_, err := store.Load(ctx)
+			Expect(err).To(MatchError(ContainSubstring("does not hold a baseline")))
+		})
+	})
+})
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// entry is the on-disk form of a cached detection result
+type entry struct {
+	Key    string                 `json:"key"`
+	Result *types.DetectionResult `json:"result"`
+}
+
+// DetectFunc runs a fresh detection when no usable cached result exists
+type DetectFunc func(ctx context.Context) (*types.DetectionResult, error)
+
+// Cache stores a detection result in a file so that repeated runs within a TTL can reuse it
+// instead of scanning the cluster again
+type Cache struct {
+	path string
+	ttl  time.Duration
+}
+
+// New creates a cache backed by the file at path whose results expire after ttl
+func New(path string, ttl time.Duration) *Cache {
+	return &Cache{
+		path: path,
+		ttl:  ttl,
+	}
+}
+
+// Load returns the cached result for key. It reports false when the file is missing or
+// unreadable, was written for a different key, or is older than the TTL.
+func (c *Cache) Load(key string) (*types.DetectionResult, bool) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, false
+	}
+
+	var cached entry
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Result == nil {
+		return nil, false
+	}
+	if cached.Key != key {
+		return nil, false
+	}
+	if time.Since(cached.Result.GeneratedAt) > c.ttl {
+		return nil, false
+	}
+
+	return cached.Result, true
+}
+
+// Save writes a result for key, replacing the file atomically so readers never see a
+// partial write
+func (c *Cache) Save(key string, result *types.DetectionResult) error {
+	data, err := json.Marshal(entry{Key: key, Result: result})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached result: %w", err)
+	}
+
+	return writeFileAtomic(c.path, data)
+}
+
+// GetOrDetect returns the cached result for key if it is still fresh, otherwise it runs
+// detect and caches the new result. The boolean reports whether the cached result was used.
+// Failing to write the cache only costs the next run a fresh detection, so it is logged and
+// the new result is still returned.
+func (c *Cache) GetOrDetect(ctx context.Context, key string, detect DetectFunc) (*types.DetectionResult, bool, error) {
+	if result, ok := c.Load(key); ok {
+		return result, true, nil
+	}
+
+	// A failed or interrupted detection is never cached, but any partial result is passed on
+	result, err := detect(ctx)
+	if err != nil {
+		return result, false, err
+	}
+	// Nor is a best-effort result that is missing the issues of failed methods
+	if len(result.Errors) > 0 {
+		return result, false, nil
+	}
+	if err := c.Save(key, result); err != nil {
+		log.Warn().Err(err).Str("path", c.path).Msg("failed to cache the detection result")
+	}
+	return result, false, nil
+}
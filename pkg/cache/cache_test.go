@@ -0,0 +1,132 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/cache"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("Cache", func() {
+	var (
+		ctx        context.Context
+		path       string
+		c          *cache.Cache
+		detections int
+		detectFn   cache.DetectFunc
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = filepath.Join(GinkgoT().TempDir(), "result.json")
+		c = cache.New(path, 5*time.Minute)
+		detections = 0
+		detectFn = func(ctx context.Context) (*types.DetectionResult, error) {
+			detections++
+			return &types.DetectionResult{
+				Issues:      []types.CSIMountIssue{{Node: "node-1", Severity: types.SeverityHigh}},
+				GeneratedAt: time.Now(),
+			}, nil
+		}
+	})
+
+	It("should run detection and cache the result when no cache exists", func() {
+		result, cached, err := c.GetOrDetect(ctx, "key", detectFn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cached).To(BeFalse())
+		Expect(result.Issues).To(HaveLen(1))
+		Expect(detections).To(Equal(1))
+		Expect(path).To(BeAnExistingFile())
+	})
+
+	It("should reuse a fresh cached result", func() {
+		_, _, err := c.GetOrDetect(ctx, "key", detectFn)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, cached, err := c.GetOrDetect(ctx, "key", detectFn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cached).To(BeTrue())
+		Expect(result.Issues[0].Node).To(Equal("node-1"))
+		Expect(detections).To(Equal(1))
+	})
+
+	It("should re-run detection when the cached result has expired", func() {
+		stale := &types.DetectionResult{GeneratedAt: time.Now().Add(-10 * time.Minute)}
+		Expect(c.Save("key", stale)).To(Succeed())
+
+		result, cached, err := c.GetOrDetect(ctx, "key", detectFn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cached).To(BeFalse())
+		Expect(result.Issues).To(HaveLen(1))
+		Expect(detections).To(Equal(1))
+
+		// The fresh result replaces the stale one
+		_, ok := c.Load("key")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should ignore results cached for different detection options", func() {
+		Expect(c.Save("other-key", &types.DetectionResult{GeneratedAt: time.Now()})).To(Succeed())
+
+		_, cached, err := c.GetOrDetect(ctx, "key", detectFn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cached).To(BeFalse())
+		Expect(detections).To(Equal(1))
+	})
+
+	It("should ignore a corrupt cache file", func() {
+		Expect(os.WriteFile(path, []byte("not json"), 0o600)).To(Succeed())
+
+		_, ok := c.Load("key")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should pass on partial results without caching them", func() {
+		partial := &types.DetectionResult{Partial: true, GeneratedAt: time.Now()}
+		result, _, err := c.GetOrDetect(ctx, "key", func(ctx context.Context) (*types.DetectionResult, error) {
+			return partial, context.Canceled
+		})
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(result).To(BeIdenticalTo(partial))
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+
+	It("should pass on best-effort results with failed methods without caching them", func() {
+		incomplete := &types.DetectionResult{
+			Errors:      []types.MethodError{{Method: types.EventsMethod, Error: "forbidden"}},
+			GeneratedAt: time.Now(),
+		}
+		result, cached, err := c.GetOrDetect(ctx, "key", func(ctx context.Context) (*types.DetectionResult, error) {
+			return incomplete, nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cached).To(BeFalse())
+		Expect(result).To(BeIdenticalTo(incomplete))
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+
+	It("should return the detected result when it cannot be cached", func() {
+		c = cache.New(filepath.Join(path, "missing-dir", "result.json"), 5*time.Minute)
+
+		result, cached, err := c.GetOrDetect(ctx, "key", detectFn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cached).To(BeFalse())
+		Expect(result.Issues).To(HaveLen(1))
+		Expect(detections).To(Equal(1))
+	})
+
+	It("should not cache failed detections", func() {
+		_, _, err := c.GetOrDetect(ctx, "key", func(ctx context.Context) (*types.DetectionResult, error) {
+			return nil, errors.New("API error")
+		})
+		Expect(err).To(MatchError("API error"))
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// requiredResultFields are the DetectionResult fields every saved result has
+var requiredResultFields = []string{"summary", "issues", "generatedAt"}
+
+// SaveResult writes a detection result to path as indented JSON so it can be shared and
+// later loaded with LoadResult
+func SaveResult(path string, result *types.DetectionResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal detection result: %w", err)
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// LoadResult reads a detection result written by SaveResult, by detect --output=json, or
+// cached by detect --cache-file. Files that are not JSON, lack the fields of a result, or
+// were written with a different schema version are rejected with an error naming the file
+// and the problem.
+func LoadResult(path string) (*types.DetectionResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read detection result: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("%s is not a saved detection result: %s", path, describeJSONError(err))
+	}
+	// A cache file wraps the result together with the key it was detected with
+	if cached, ok := fields["result"]; ok {
+		if _, ok := fields["key"]; ok {
+			data = cached
+			fields = nil
+			if err := json.Unmarshal(data, &fields); err != nil {
+				return nil, fmt.Errorf("%s is not a saved detection result: cached %s", path, describeJSONError(err))
+			}
+		}
+	}
+	for _, name := range requiredResultFields {
+		if _, ok := fields[name]; !ok {
+			return nil, fmt.Errorf("%s is not a saved detection result: missing %q field", path, name)
+		}
+	}
+
+	var result types.DetectionResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("%s is not a saved detection result: %s", path, describeJSONError(err))
+	}
+	if result.SchemaVersion != "" && result.SchemaVersion != types.SchemaVersion {
+		return nil, fmt.Errorf("%s has schema version %s, but this version of kubectl-csi-scan reads %s",
+			path, result.SchemaVersion, types.SchemaVersion)
+	}
+	for i, issue := range result.Issues {
+		if issue.Severity.Level() == 0 {
+			return nil, fmt.Errorf("%s is not a saved detection result: issue %d has invalid severity %q", path, i, issue.Severity)
+		}
+	}
+	return &result, nil
+}
+
+// describeJSONError turns a decoding error into a message that points at the problem
+func describeJSONError(err error) string {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Sprintf("invalid JSON at byte %d: %v", syntaxErr.Offset, err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			return fmt.Sprintf("expected a JSON object, found %s", typeErr.Value)
+		}
+		return fmt.Sprintf("field %q should be %s, found %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+	return err.Error()
+}
+
+// writeFileAtomic replaces the file at path with data so readers never see a partial write
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/cache"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("Saved results", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "scan.json")
+	})
+
+	It("should load a saved result identically", func() {
+		generatedAt := time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)
+		result := &types.DetectionResult{
+			SchemaVersion: types.SchemaVersion,
+			Summary: types.DetectionSummary{
+				TotalIssues:      1,
+				IssuesBySeverity: map[types.IssueSeverity]int{types.SeverityHigh: 1},
+				IssuesByType:     map[types.IssueType]int{types.MultipleAttachments: 1},
+				AffectedNodes:    []string{"node-1"},
+				AffectedDrivers:  []string{"cinder.csi.openstack.org"},
+				Status:           types.StatusDegraded,
+				MethodsUsed:      []types.DetectionMethod{types.VolumeAttachmentMethod},
+				SnapshotTime:     generatedAt,
+			},
+			Issues: []types.CSIMountIssue{{
+				Type:        types.MultipleAttachments,
+				Severity:    types.SeverityHigh,
+				Node:        "node-1",
+				Volume:      "pv-1",
+				Driver:      "cinder.csi.openstack.org",
+				Description: "Volume attached to multiple nodes",
+				DetectedBy:  types.VolumeAttachmentMethod,
+				DetectedAt:  generatedAt,
+				OccurredAt:  generatedAt.Add(-time.Hour),
+				Metadata:    map[string]string{"attached": "true"},
+				Sources:     []types.SourceRef{{Kind: "VolumeAttachment", Name: "csi-abc"}},
+			}},
+			Recommendations: []string{"Drain node-1"},
+			GeneratedAt:     generatedAt,
+		}
+
+		Expect(cache.SaveResult(path, result)).To(Succeed())
+		loaded, err := cache.LoadResult(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(Equal(result))
+	})
+
+	It("should load a result cached by detect --cache-file", func() {
+		generatedAt := time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)
+		result := &types.DetectionResult{
+			SchemaVersion: types.SchemaVersion,
+			Summary:       types.DetectionSummary{TotalIssues: 1, AffectedNodes: []string{"node-1"}},
+			Issues: []types.CSIMountIssue{{
+				Type:     types.StuckMountReference,
+				Severity: types.SeverityMedium,
+				Node:     "node-1",
+				PVC:      "shop/data-web-0",
+			}},
+			GeneratedAt: generatedAt,
+		}
+
+		Expect(cache.New(path, time.Hour).Save("detect-options", result)).To(Succeed())
+		loaded, err := cache.LoadResult(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(Equal(result))
+	})
+
+	It("should reject a cache file without a result", func() {
+		Expect(os.WriteFile(path, []byte(`{"key": "detect-options", "result": null}`), 0o644)).To(Succeed())
+		_, err := cache.LoadResult(path)
+		Expect(err).To(MatchError(ContainSubstring(`missing "summary" field`)))
+	})
+
+	It("should reject files that are not JSON", func() {
+		Expect(os.WriteFile(path, []byte("issues: []"), 0o644)).To(Succeed())
+		_, err := cache.LoadResult(path)
+		Expect(err).To(MatchError(ContainSubstring("is not a saved detection result: invalid JSON")))
+	})
+
+	It("should reject JSON that lacks the fields of a result", func() {
+		Expect(os.WriteFile(path, []byte(`{"issues": []}`), 0o644)).To(Succeed())
+		_, err := cache.LoadResult(path)
+		Expect(err).To(MatchError(ContainSubstring(`missing "summary" field`)))
+	})
+
+	It("should name fields of the wrong type", func() {
+		data := `{"summary": {}, "issues": {}, "generatedAt": "2025-03-04T10:30:00Z"}`
+		Expect(os.WriteFile(path, []byte(data), 0o644)).To(Succeed())
+		_, err := cache.LoadResult(path)
+		Expect(err).To(MatchError(ContainSubstring(`field "issues"`)))
+	})
+
+	It("should reject issues with an unknown severity", func() {
+		data := `{"summary": {}, "issues": [{"severity": "urgent"}], "generatedAt": "2025-03-04T10:30:00Z"}`
+		Expect(os.WriteFile(path, []byte(data), 0o644)).To(Succeed())
+		_, err := cache.LoadResult(path)
+		Expect(err).To(MatchError(ContainSubstring(`invalid severity "urgent"`)))
+	})
+
+	It("should reject results of another schema version", func() {
+		data := `{"schemaVersion": "v0", "summary": {}, "issues": [], "generatedAt": "2025-03-04T10:30:00Z"}`
+		Expect(os.WriteFile(path, []byte(data), 0o644)).To(Succeed())
+		_, err := cache.LoadResult(path)
+		Expect(err).To(MatchError(ContainSubstring("schema version v0")))
+	})
+
+	It("should report a missing file", func() {
+		_, err := cache.LoadResult(filepath.Join(filepath.Dir(path), "missing.json"))
+		Expect(err).To(MatchError(ContainSubstring("failed to read detection result")))
+	})
+})
@@ -3,19 +3,83 @@ package cleanup
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"text/template"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/yaml"
 )
 
+// ErrJobRunning is returned when a cleanup job for the node is still running
+var ErrJobRunning = errors.New("cleanup job already running")
+
+// ErrRecentlyCleaned is returned when a cleanup job for the node finished within the cooldown
+var ErrRecentlyCleaned = errors.New("cleanup job finished recently")
+
+// DefaultCooldown is how recently a node's cleanup job may have finished before another
+// cleanup of the node is skipped
+const DefaultCooldown = 10 * time.Minute
+
+// DefaultPollInterval is how often WaitForJobs checks job status unless configured
+const DefaultPollInterval = 5 * time.Second
+
+// JobState is the state of a cleanup job while waiting for it
+type JobState string
+
+const (
+	JobRunning   JobState = "Running"
+	JobSucceeded JobState = "Succeeded"
+	JobFailed    JobState = "Failed"
+)
+
+// JobStatus is the state of one cleanup job at a poll
+type JobStatus struct {
+	JobName  string
+	NodeName string
+	State    JobState
+}
+
+// JobStatusFunc is called on every poll of WaitForJobs with the state of each job, in the
+// order the jobs were given
+type JobStatusFunc func(statuses []JobStatus)
+
+// WaitOptions tune how WaitForJobs polls
+type WaitOptions struct {
+	PollInterval    time.Duration // delay between polls; zero uses DefaultPollInterval
+	MaxPollInterval time.Duration // the delay doubles after each poll up to this; zero keeps it fixed
+	MaxWait         time.Duration // give up waiting after this long; zero waits until the context ends
+	OnStatus        JobStatusFunc // reports job states on every poll
+}
+
+// DefaultMountPropagation is the propagation of the kubelet-dir mount, letting unmounts in
+// the job reach the host
+const DefaultMountPropagation = corev1.MountPropagationBidirectional
+
+// readOnlyMountPropagation is the kubelet-dir propagation of read-only jobs, which still
+// see mounts made on the host but cannot use Bidirectional without privileges
+const readOnlyMountPropagation = corev1.MountPropagationHostToContainer
+
+// managedJobSelector matches the cleanup jobs created by this tool
+const managedJobSelector = "kubectl-csi-scan/managed=true"
+
+// Annotations marking jobs that report what cleanup would do, or only read the node,
+// without cleaning anything up
+const (
+	dryRunAnnotation   = "kubectl-csi-scan/dry-run"
+	readOnlyAnnotation = "kubectl-csi-scan/read-only"
+)
+
 // CleanupJobConfig holds configuration for a cleanup job
 type CleanupJobConfig struct {
 	NodeName        string
@@ -25,34 +89,102 @@ type CleanupJobConfig struct {
 	ImagePullPolicy string
 	Namespace       string
 	ServiceAccount  string
+	Recreate        bool          // replace a finished job for the node instead of creating a suffixed one
+	Cooldown        time.Duration // skip the node if a cleanup job for it finished this recently; zero disables
+	// MountPropagation of the kubelet-dir mount; empty uses DefaultMountPropagation, or
+	// HostToContainer for read-only jobs
+	MountPropagation corev1.MountPropagationMode
+	// ReadOnly runs an unprivileged job without hostPID and with read-only mounts that only
+	// reports stuck mounts, for clusters that forbid privileged containers
+	ReadOnly bool
+	// CreateRBAC also ensures the cleanup ClusterRole and a binding of it to ServiceAccount
+	CreateRBAC bool
+}
+
+// CleanupJobResult is the outcome of creating the cleanup job for one node
+type CleanupJobResult struct {
+	NodeName string
+	JobName  string
+	Err      error
 }
 
 // CleanupJobManager manages Kubernetes cleanup jobs
 type CleanupJobManager struct {
-	client    kubernetes.Interface
-	namespace string
+	client          kubernetes.Interface
+	namespace       string
+	serviceAccounts map[string]bool // service accounts known to exist, so they are checked once
+	rbac            map[string]bool // RBAC objects known to exist, by kind/name, so they are checked once
+	wait            WaitOptions
 }
 
 // NewCleanupJobManager creates a new cleanup job manager
 func NewCleanupJobManager(client kubernetes.Interface, namespace string) *CleanupJobManager {
 	return &CleanupJobManager{
-		client:    client,
-		namespace: namespace,
+		client:          client,
+		namespace:       namespace,
+		serviceAccounts: make(map[string]bool),
+		rbac:            make(map[string]bool),
 	}
 }
 
+// SetWaitOptions configures polling, backoff and status reporting of WaitForJobs
+func (m *CleanupJobManager) SetWaitOptions(opts WaitOptions) {
+	m.wait = opts
+}
+
+// CreateCleanupJobs creates cleanup jobs for several nodes that share one configuration.
+// The ServiceAccount, and with CreateRBAC its ClusterRole and binding, are ensured once up
+// front and reused by every job; a failure there aborts the batch, while per-node failures
+// are reported in the results.
+func (m *CleanupJobManager) CreateCleanupJobs(ctx context.Context, nodes []string, config CleanupJobConfig) ([]CleanupJobResult, error) {
+	objects, err := m.renderObjects(config)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objects {
+		if sa, ok := obj.(*corev1.ServiceAccount); ok {
+			if err := m.createServiceAccount(ctx, sa); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if config.CreateRBAC {
+		if err := m.CreateRBAC(ctx, config); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]CleanupJobResult, 0, len(nodes))
+	for _, node := range nodes {
+		nodeConfig := config
+		nodeConfig.NodeName = node
+		jobName, err := m.CreateCleanupJob(ctx, nodeConfig)
+		results = append(results, CleanupJobResult{NodeName: node, JobName: jobName, Err: err})
+	}
+	return results, nil
+}
+
 // CreateCleanupJob creates a cleanup job for the specified node
 func (m *CleanupJobManager) CreateCleanupJob(ctx context.Context, config CleanupJobConfig) (string, error) {
-	// Generate job manifest from template
-	manifest, err := m.generateJobManifest(config)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate job manifest: %w", err)
+	if config.Cooldown > 0 {
+		recent, err := m.RecentlyFinishedJob(ctx, config.NodeName, config.Cooldown)
+		if err != nil {
+			return "", err
+		}
+		if recent != nil {
+			return "", fmt.Errorf("%w: %s finished %s ago", ErrRecentlyCleaned, recent.Name, time.Since(jobFinishTime(recent)).Round(time.Second))
+		}
 	}
 
-	// Parse the manifest into Kubernetes objects
-	objects, err := m.parseManifest(manifest)
+	if config.CreateRBAC {
+		if err := m.CreateRBAC(ctx, config); err != nil {
+			return "", err
+		}
+	}
+
+	objects, err := m.renderObjects(config)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse job manifest: %w", err)
+		return "", err
 	}
 
 	// Create the objects in the cluster
@@ -62,8 +194,11 @@ func (m *CleanupJobManager) CreateCleanupJob(ctx context.Context, config Cleanup
 		case *corev1.ServiceAccount:
 			err = m.createServiceAccount(ctx, resource)
 		case *batchv1.Job:
-			jobName = resource.Name
-			err = m.createJob(ctx, resource)
+			err = m.resolveJobName(ctx, resource, config.Recreate)
+			if err == nil {
+				jobName = resource.Name
+				err = m.createJob(ctx, resource)
+			}
 		}
 		
 		if err != nil {
@@ -74,50 +209,167 @@ func (m *CleanupJobManager) CreateCleanupJob(ctx context.Context, config Cleanup
 	return jobName, nil
 }
 
-// WaitForJobs waits for all specified jobs to complete
+// ListManagedJobs returns the cleanup jobs created by this tool for a node, or for every
+// node if node is empty
+func (m *CleanupJobManager) ListManagedJobs(ctx context.Context, node string) ([]batchv1.Job, error) {
+	selector := managedJobSelector
+	if node != "" {
+		selector += ",node=" + node
+	}
+
+	jobs, err := m.client.BatchV1().Jobs(m.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cleanup jobs: %w", err)
+	}
+	return jobs.Items, nil
+}
+
+// RecentlyFinishedJob returns the most recently finished cleanup job for a node if it
+// finished within the given window, or nil otherwise. Only jobs that succeeded in cleaning
+// up count: failed jobs, dry runs and read-only jobs such as mount probes are ignored, so
+// they never hold back a real cleanup.
+func (m *CleanupJobManager) RecentlyFinishedJob(ctx context.Context, node string, within time.Duration) (*batchv1.Job, error) {
+	jobs, err := m.ListManagedJobs(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	var recent *batchv1.Job
+	for i := range jobs {
+		if !cleanedUp(&jobs[i]) {
+			continue
+		}
+		finishedAt := jobFinishTime(&jobs[i])
+		if finishedAt.IsZero() || time.Since(finishedAt) > within {
+			continue
+		}
+		if recent == nil || finishedAt.After(jobFinishTime(recent)) {
+			recent = &jobs[i]
+		}
+	}
+	return recent, nil
+}
+
+// renderObjects generates the job manifest for a config and parses it into Kubernetes objects
+func (m *CleanupJobManager) renderObjects(config CleanupJobConfig) ([]interface{}, error) {
+	// Generate job manifest from template
+	manifest, err := m.generateJobManifest(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate job manifest: %w", err)
+	}
+
+	// Parse the manifest into Kubernetes objects
+	objects, err := m.parseManifest(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse job manifest: %w", err)
+	}
+	return objects, nil
+}
+
+// WaitForJobs waits for all specified jobs to complete. Jobs are checked right away and
+// then at the configured interval, backing off up to the maximum interval; it returns as
+// soon as a job fails.
 func (m *CleanupJobManager) WaitForJobs(ctx context.Context, jobNames []string) error {
 	log.Info().Strs("jobs", jobNames).Msg("waiting for cleanup jobs to complete")
 
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
+	if m.wait.MaxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.wait.MaxWait)
+		defer cancel()
+	}
+
+	interval := m.wait.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return fmt.Errorf("timeout waiting for jobs to complete")
-		case <-ticker.C:
-			allComplete, err := m.checkJobsStatus(ctx, jobNames)
-			if err != nil {
-				return fmt.Errorf("failed to check job status: %w", err)
-			}
-			
-			if allComplete {
-				log.Info().Msg("all cleanup jobs completed successfully")
-				return nil
+		case <-timer.C:
+		}
+
+		statuses, err := m.jobStatuses(ctx, jobNames)
+		if err != nil {
+			return fmt.Errorf("failed to check job status: %w", err)
+		}
+		if m.wait.OnStatus != nil {
+			m.wait.OnStatus(statuses)
+		}
+
+		allComplete := true
+		for _, status := range statuses {
+			switch status.State {
+			case JobFailed:
+				return fmt.Errorf("cleanup job %s failed", status.JobName)
+			case JobRunning:
+				allComplete = false
 			}
 		}
+		if allComplete {
+			log.Info().Msg("all cleanup jobs completed successfully")
+			return nil
+		}
+
+		timer.Reset(interval)
+		if m.wait.MaxPollInterval > interval {
+			interval = min(2*interval, m.wait.MaxPollInterval)
+		}
+	}
+}
+
+// ParseMountPropagation checks a kubelet-dir mount propagation mode. Modes other than
+// Bidirectional suit stricter PodSecurity policies, but unmounts made by the job then do
+// not propagate back to the host.
+func ParseMountPropagation(mode string) (corev1.MountPropagationMode, error) {
+	switch propagation := corev1.MountPropagationMode(mode); propagation {
+	case corev1.MountPropagationBidirectional, corev1.MountPropagationHostToContainer, corev1.MountPropagationNone:
+		return propagation, nil
 	}
+	return "", fmt.Errorf("invalid mount propagation %q: must be one of Bidirectional, HostToContainer, None", mode)
 }
 
 // generateJobManifest generates a job manifest from the template
 func (m *CleanupJobManager) generateJobManifest(config CleanupJobConfig) (string, error) {
+	propagation := DefaultMountPropagation
+	if config.ReadOnly {
+		propagation = readOnlyMountPropagation
+	}
+	if config.MountPropagation != "" {
+		var err error
+		if propagation, err = ParseMountPropagation(string(config.MountPropagation)); err != nil {
+			return "", err
+		}
+	}
+	if config.ReadOnly && propagation == corev1.MountPropagationBidirectional {
+		return "", fmt.Errorf("mount propagation %s requires a privileged job and cannot be used read-only", propagation)
+	}
+
 	// Template data for manifest generation
 	templateData := struct {
-		NodeName        string
-		DryRun          bool
-		Verbose         bool
-		Image           string
-		ImagePullPolicy string
-		Namespace       string
-		ServiceAccount  string
+		NodeName         string
+		DryRun           bool
+		Verbose          bool
+		Image            string
+		ImagePullPolicy  string
+		Namespace        string
+		ServiceAccount   string
+		MountPropagation corev1.MountPropagationMode
+		ReadOnly         bool
 	}{
-		NodeName:        config.NodeName,
-		DryRun:          config.DryRun,
-		Verbose:         config.Verbose,
-		Image:           config.Image,
-		ImagePullPolicy: config.ImagePullPolicy,
-		Namespace:       config.Namespace,
-		ServiceAccount:  config.ServiceAccount,
+		NodeName:         config.NodeName,
+		DryRun:           config.DryRun,
+		Verbose:          config.Verbose,
+		Image:            config.Image,
+		ImagePullPolicy:  config.ImagePullPolicy,
+		Namespace:        config.Namespace,
+		ServiceAccount:   config.ServiceAccount,
+		MountPropagation: propagation,
+		ReadOnly:         config.ReadOnly,
 	}
 
 	// Job template
@@ -135,6 +387,7 @@ metadata:
     kubectl-csi-scan/created-by: kubectl-csi-scan
     kubectl-csi-scan/node: {{.NodeName}}
     kubectl-csi-scan/dry-run: "{{.DryRun}}"
+    kubectl-csi-scan/read-only: "{{.ReadOnly}}"
 spec:
   backoffLimit: 0
   completions: 1
@@ -157,8 +410,10 @@ spec:
         effect: NoExecute
       - operator: Exists
         effect: PreferNoSchedule
+      {{- if not .ReadOnly}}
       hostNetwork: true
       hostPID: true
+      {{- end}}
       priorityClassName: system-node-critical
       serviceAccountName: {{.ServiceAccount}}
       containers:
@@ -166,7 +421,13 @@ spec:
         image: {{.Image}}
         imagePullPolicy: {{.ImagePullPolicy}}
         securityContext:
+          {{- if .ReadOnly}}
+          privileged: false
+          allowPrivilegeEscalation: false
+          readOnlyRootFilesystem: true
+          {{- else}}
           privileged: true
+          {{- end}}
           runAsUser: 0
           runAsGroup: 0
         env:
@@ -178,10 +439,15 @@ spec:
           value: "{{.DryRun}}"
         - name: VERBOSE
           value: "{{.Verbose}}"
+        - name: SCAN_ONLY
+          value: "{{.ReadOnly}}"
         volumeMounts:
         - name: kubelet-dir
           mountPath: /var/lib/kubelet
-          mountPropagation: Bidirectional
+          mountPropagation: {{.MountPropagation}}
+          {{- if .ReadOnly}}
+          readOnly: true
+          {{- end}}
         - name: host-proc
           mountPath: /host/proc
           readOnly: true
@@ -203,6 +469,9 @@ spec:
         {{- if .Verbose}}
         - "--verbose"
         {{- end}}
+        {{- if .ReadOnly}}
+        - "--scan-only"
+        {{- end}}
       volumes:
       - name: kubelet-dir
         hostPath:
@@ -224,7 +493,44 @@ metadata:
   namespace: {{.Namespace}}
   labels:
     app: kubectl-csi-scan
-    component: cleanup-service-account`
+    component: cleanup-service-account
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: kubectl-csi-scan-cleanup
+  labels:
+    app: kubectl-csi-scan
+    component: cleanup-rbac
+rules:
+- apiGroups: [""]
+  resources: ["nodes"]
+  verbs: ["get", "list"]
+- apiGroups: ["storage.k8s.io"]
+  resources: ["volumeattachments"]
+  verbs: ["get", "list"]
+- apiGroups: [""]
+  resources: ["persistentvolumes", "persistentvolumeclaims"]
+  verbs: ["get", "list"]
+- apiGroups: [""]
+  resources: ["events"]
+  verbs: ["get", "list"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: kubectl-csi-scan-cleanup
+  labels:
+    app: kubectl-csi-scan
+    component: cleanup-rbac
+subjects:
+- kind: ServiceAccount
+  name: {{.ServiceAccount}}
+  namespace: {{.Namespace}}
+roleRef:
+  kind: ClusterRole
+  name: kubectl-csi-scan-cleanup
+  apiGroup: rbac.authorization.k8s.io`
 
 	tmpl, err := template.New("cleanup-job").Parse(jobTemplate)
 	if err != nil {
@@ -282,6 +588,28 @@ func (m *CleanupJobManager) parseManifest(manifest string) ([]interface{}, error
 					return nil, fmt.Errorf("failed to unmarshal service account: %w", err)
 				}
 				objects = append(objects, sa)
+
+			case "ClusterRole":
+				role := &rbacv1.ClusterRole{}
+				data, err := yaml.Marshal(objMap)
+				if err != nil {
+					return nil, fmt.Errorf("failed to marshal cluster role: %w", err)
+				}
+				if err := utilyaml.Unmarshal(data, role); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal cluster role: %w", err)
+				}
+				objects = append(objects, role)
+
+			case "ClusterRoleBinding":
+				binding := &rbacv1.ClusterRoleBinding{}
+				data, err := yaml.Marshal(objMap)
+				if err != nil {
+					return nil, fmt.Errorf("failed to marshal cluster role binding: %w", err)
+				}
+				if err := utilyaml.Unmarshal(data, binding); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal cluster role binding: %w", err)
+				}
+				objects = append(objects, binding)
 			}
 		}
 	}
@@ -289,12 +617,18 @@ func (m *CleanupJobManager) parseManifest(manifest string) ([]interface{}, error
 	return objects, nil
 }
 
-// createServiceAccount creates a service account if it doesn't exist
+// createServiceAccount creates a service account if it doesn't exist. Accounts this
+// manager has already found or created are not checked again.
 func (m *CleanupJobManager) createServiceAccount(ctx context.Context, sa *corev1.ServiceAccount) error {
+	if m.serviceAccounts[sa.Name] {
+		return nil
+	}
+
 	_, err := m.client.CoreV1().ServiceAccounts(m.namespace).Get(ctx, sa.Name, metav1.GetOptions{})
 	if err == nil {
 		// Service account already exists
 		log.Debug().Str("service_account", sa.Name).Msg("service account already exists")
+		m.serviceAccounts[sa.Name] = true
 		return nil
 	}
 
@@ -304,9 +638,193 @@ func (m *CleanupJobManager) createServiceAccount(ctx context.Context, sa *corev1
 	}
 
 	log.Info().Str("service_account", sa.Name).Msg("created service account")
+	m.serviceAccounts[sa.Name] = true
 	return nil
 }
 
+// CreateRBAC creates the ClusterRole the cleanup job runs with and the ClusterRoleBinding
+// granting it to the job's service account, as defined in the job template. Objects that
+// already exist are left as they are, so it is safe to call for every job.
+func (m *CleanupJobManager) CreateRBAC(ctx context.Context, config CleanupJobConfig) error {
+	objects, err := m.renderObjects(config)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		switch resource := obj.(type) {
+		case *rbacv1.ClusterRole:
+			err = m.createClusterRole(ctx, resource)
+		case *rbacv1.ClusterRoleBinding:
+			err = m.createClusterRoleBinding(ctx, resource)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createClusterRole creates a cluster role if it doesn't exist
+func (m *CleanupJobManager) createClusterRole(ctx context.Context, role *rbacv1.ClusterRole) error {
+	key := "ClusterRole/" + role.Name
+	if m.rbac[key] {
+		return nil
+	}
+
+	_, err := m.client.RbacV1().ClusterRoles().Get(ctx, role.Name, metav1.GetOptions{})
+	if err == nil {
+		log.Debug().Str("cluster_role", role.Name).Msg("cluster role already exists")
+		m.rbac[key] = true
+		return nil
+	}
+
+	_, err = m.client.RbacV1().ClusterRoles().Create(ctx, role, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create cluster role %s: %w", role.Name, err)
+	}
+
+	log.Info().Str("cluster_role", role.Name).Msg("created cluster role")
+	m.rbac[key] = true
+	return nil
+}
+
+// createClusterRoleBinding creates the cluster role binding if it doesn't exist. The
+// binding is shared by every namespace and service account cleanup runs as, so subjects
+// missing from an existing binding are added to it.
+func (m *CleanupJobManager) createClusterRoleBinding(ctx context.Context, binding *rbacv1.ClusterRoleBinding) error {
+	key := "ClusterRoleBinding/" + binding.Name
+	if m.rbac[key] {
+		return nil
+	}
+
+	existing, err := m.client.RbacV1().ClusterRoleBindings().Get(ctx, binding.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = m.client.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create cluster role binding %s: %w", binding.Name, err)
+		}
+		log.Info().Str("cluster_role_binding", binding.Name).Msg("created cluster role binding")
+	case err != nil:
+		return fmt.Errorf("failed to get cluster role binding %s: %w", binding.Name, err)
+	default:
+		missing := missingSubjects(existing.Subjects, binding.Subjects)
+		if len(missing) == 0 {
+			log.Debug().Str("cluster_role_binding", binding.Name).Msg("cluster role binding already exists")
+			break
+		}
+		existing.Subjects = append(existing.Subjects, missing...)
+		if _, err := m.client.RbacV1().ClusterRoleBindings().Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update cluster role binding %s: %w", binding.Name, err)
+		}
+		log.Info().Str("cluster_role_binding", binding.Name).Msg("added service account to cluster role binding")
+	}
+
+	m.rbac[key] = true
+	return nil
+}
+
+// missingSubjects returns the subjects of wanted that are not in have
+func missingSubjects(have, wanted []rbacv1.Subject) []rbacv1.Subject {
+	var missing []rbacv1.Subject
+	for _, subject := range wanted {
+		found := false
+		for _, existing := range have {
+			if existing.Kind == subject.Kind && existing.Name == subject.Name && existing.Namespace == subject.Namespace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, subject)
+		}
+	}
+	return missing
+}
+
+// resolveJobName checks for an existing job with the same name and adjusts the job so it
+// can be created: running jobs abort with ErrJobRunning, finished jobs are deleted when
+// recreate is set, and otherwise the new job gets a timestamp suffix
+func (m *CleanupJobManager) resolveJobName(ctx context.Context, job *batchv1.Job, recreate bool) error {
+	existing, err := m.client.BatchV1().Jobs(m.namespace).Get(ctx, job.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get job %s: %w", job.Name, err)
+	}
+
+	if !jobFinished(existing) {
+		return fmt.Errorf("%w: %s", ErrJobRunning, existing.Name)
+	}
+
+	if recreate {
+		return m.deleteJob(ctx, existing.Name)
+	}
+
+	job.Name = fmt.Sprintf("%s-%s", job.Name, strconv.FormatInt(time.Now().Unix(), 36))
+	log.Info().Str("existing_job", existing.Name).Str("job", job.Name).Msg("finished cleanup job exists, using suffixed name")
+	return nil
+}
+
+// deleteJob deletes a job and its pods and waits for it to be removed
+func (m *CleanupJobManager) deleteJob(ctx context.Context, name string) error {
+	propagation := metav1.DeletePropagationBackground
+	err := m.client.BatchV1().Jobs(m.namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete job %s: %w", name, err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, time.Second, 30*time.Second, true, func(ctx context.Context) (bool, error) {
+		_, err := m.client.BatchV1().Jobs(m.namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed waiting for job %s to be deleted: %w", name, err)
+	}
+
+	log.Info().Str("job", name).Msg("deleted finished cleanup job")
+	return nil
+}
+
+// jobFinished reports whether a job has succeeded or failed
+func jobFinished(job *batchv1.Job) bool {
+	if job.Status.Succeeded > 0 || job.Status.Failed > 0 {
+		return true
+	}
+	for _, cond := range job.Status.Conditions {
+		if (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed) && cond.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// jobFinishTime returns when a job succeeded or failed, or the zero time if it has not
+// finished or the time is not recorded
+// cleanedUp reports whether a job succeeded and was neither a dry run nor read-only
+func cleanedUp(job *batchv1.Job) bool {
+	return job.Status.Succeeded > 0 &&
+		job.Annotations[dryRunAnnotation] != "true" &&
+		job.Annotations[readOnlyAnnotation] != "true"
+}
+
+func jobFinishTime(job *batchv1.Job) time.Time {
+	if job.Status.CompletionTime != nil {
+		return job.Status.CompletionTime.Time
+	}
+	for _, cond := range job.Status.Conditions {
+		if (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed) && cond.Status == corev1.ConditionTrue {
+			return cond.LastTransitionTime.Time
+		}
+	}
+	return time.Time{}
+}
+
 // createJob creates a cleanup job
 func (m *CleanupJobManager) createJob(ctx context.Context, job *batchv1.Job) error {
 	_, err := m.client.BatchV1().Jobs(m.namespace).Create(ctx, job, metav1.CreateOptions{})
@@ -318,35 +836,27 @@ func (m *CleanupJobManager) createJob(ctx context.Context, job *batchv1.Job) err
 	return nil
 }
 
-// checkJobsStatus checks if all jobs have completed
-func (m *CleanupJobManager) checkJobsStatus(ctx context.Context, jobNames []string) (bool, error) {
-	allComplete := true
-	
+// jobStatuses returns the current state of each job
+func (m *CleanupJobManager) jobStatuses(ctx context.Context, jobNames []string) ([]JobStatus, error) {
+	statuses := make([]JobStatus, 0, len(jobNames))
 	for _, jobName := range jobNames {
 		job, err := m.client.BatchV1().Jobs(m.namespace).Get(ctx, jobName, metav1.GetOptions{})
 		if err != nil {
-			return false, fmt.Errorf("failed to get job %s: %w", jobName, err)
+			return nil, fmt.Errorf("failed to get job %s: %w", jobName, err)
 		}
 
-		// Check job status
-		if job.Status.Succeeded == 0 && job.Status.Failed == 0 {
-			// Job is still running
-			allComplete = false
-			log.Debug().Str("job", jobName).Msg("job still running")
-			continue
-		}
-
-		if job.Status.Failed > 0 {
-			// Job failed
+		status := JobStatus{JobName: jobName, NodeName: job.Labels["node"], State: JobRunning}
+		switch {
+		case job.Status.Failed > 0:
+			status.State = JobFailed
 			log.Error().Str("job", jobName).Int32("failures", job.Status.Failed).Msg("cleanup job failed")
-			return false, fmt.Errorf("cleanup job %s failed", jobName)
-		}
-
-		if job.Status.Succeeded > 0 {
-			// Job completed successfully
+		case job.Status.Succeeded > 0:
+			status.State = JobSucceeded
 			log.Info().Str("job", jobName).Msg("cleanup job completed successfully")
+		default:
+			log.Debug().Str("job", jobName).Msg("job still running")
 		}
+		statuses = append(statuses, status)
 	}
-
-	return allComplete, nil
+	return statuses, nil
 }
\ No newline at end of file
@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/gomega"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
@@ -145,6 +146,67 @@ var _ = Describe("CleanupJobManager", func() {
 				Expect(len(volumeMounts)).To(Equal(len(expectedMounts)))
 			})
 
+			It("should mount the kubelet dir with Bidirectional propagation by default", func() {
+				_, err := jobManager.CreateCleanupJob(ctx, config)
+				Expect(err).NotTo(HaveOccurred())
+
+				jobs, err := fakeClient.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				mount := jobs.Items[0].Spec.Template.Spec.Containers[0].VolumeMounts[0]
+				Expect(mount.Name).To(Equal("kubelet-dir"))
+				Expect(mount.MountPropagation).To(HaveValue(Equal(corev1.MountPropagationBidirectional)))
+			})
+
+			It("should use the configured mount propagation on the kubelet dir", func() {
+				config.MountPropagation = corev1.MountPropagationHostToContainer
+				_, err := jobManager.CreateCleanupJob(ctx, config)
+				Expect(err).NotTo(HaveOccurred())
+
+				jobs, err := fakeClient.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				mount := jobs.Items[0].Spec.Template.Spec.Containers[0].VolumeMounts[0]
+				Expect(mount.Name).To(Equal("kubelet-dir"))
+				Expect(mount.MountPropagation).To(HaveValue(Equal(corev1.MountPropagationHostToContainer)))
+			})
+
+			It("should reject an unknown mount propagation", func() {
+				config.MountPropagation = "Sideways"
+				_, err := jobManager.CreateCleanupJob(ctx, config)
+				Expect(err).To(MatchError(ContainSubstring("invalid mount propagation")))
+			})
+
+			It("should create an unprivileged job with read-only mounts when read-only", func() {
+				config.ReadOnly = true
+				_, err := jobManager.CreateCleanupJob(ctx, config)
+				Expect(err).NotTo(HaveOccurred())
+
+				jobs, err := fakeClient.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				podSpec := jobs.Items[0].Spec.Template.Spec
+				Expect(podSpec.HostPID).To(BeFalse())
+				Expect(podSpec.HostNetwork).To(BeFalse())
+
+				container := podSpec.Containers[0]
+				Expect(container.SecurityContext.Privileged).To(HaveValue(BeFalse()))
+				Expect(container.SecurityContext.AllowPrivilegeEscalation).To(HaveValue(BeFalse()))
+				Expect(container.VolumeMounts).NotTo(BeEmpty())
+				for _, mount := range container.VolumeMounts {
+					Expect(mount.ReadOnly).To(BeTrue(), "mount %s should be read-only", mount.Name)
+				}
+				Expect(container.VolumeMounts[0].MountPropagation).To(HaveValue(Equal(corev1.MountPropagationHostToContainer)))
+				Expect(container.Args).To(ContainElement("--scan-only"))
+			})
+
+			It("should reject Bidirectional propagation for a read-only job", func() {
+				config.ReadOnly = true
+				config.MountPropagation = corev1.MountPropagationBidirectional
+				_, err := jobManager.CreateCleanupJob(ctx, config)
+				Expect(err).To(MatchError(ContainSubstring("cannot be used read-only")))
+			})
+
 			It("should not create service account if it already exists", func() {
 				// Pre-create the service account
 				existingSA := &corev1.ServiceAccount{
@@ -230,6 +292,365 @@ var _ = Describe("CleanupJobManager", func() {
 				Expect(string(container.ImagePullPolicy)).To(Equal("Always"))
 			})
 		})
+
+		Context("when a job already exists for the node", func() {
+			existingJob := func(status batchv1.JobStatus) *batchv1.Job {
+				return &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "csi-mount-cleanup-test-node",
+						Namespace: namespace,
+					},
+					Status: status,
+				}
+			}
+
+			It("should skip the node if the existing job is still running", func() {
+				_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, existingJob(batchv1.JobStatus{Active: 1}), metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				jobName, err := jobManager.CreateCleanupJob(ctx, config)
+				Expect(err).To(MatchError(cleanup.ErrJobRunning))
+				Expect(err.Error()).To(ContainSubstring("csi-mount-cleanup-test-node"))
+				Expect(jobName).To(BeEmpty())
+
+				jobs, err := fakeClient.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(jobs.Items).To(HaveLen(1))
+			})
+
+			It("should delete and recreate a finished job when recreate is set", func() {
+				_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, existingJob(batchv1.JobStatus{Succeeded: 1}), metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				fakeClient.ClearActions()
+				config.Recreate = true
+
+				jobName, err := jobManager.CreateCleanupJob(ctx, config)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(jobName).To(Equal("csi-mount-cleanup-test-node"))
+
+				var jobVerbs []string
+				for _, action := range fakeClient.Actions() {
+					if action.GetResource().Resource == "jobs" && action.GetVerb() != "get" {
+						jobVerbs = append(jobVerbs, action.GetVerb())
+					}
+				}
+				Expect(jobVerbs).To(Equal([]string{"delete", "create"}))
+
+				job, err := fakeClient.BatchV1().Jobs(namespace).Get(ctx, jobName, metav1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(job.Status.Succeeded).To(BeZero())
+			})
+
+			It("should create a suffixed job when the existing job finished", func() {
+				_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, existingJob(batchv1.JobStatus{Failed: 1}), metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				jobName, err := jobManager.CreateCleanupJob(ctx, config)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(jobName).To(HavePrefix("csi-mount-cleanup-test-node-"))
+
+				jobs, err := fakeClient.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(jobs.Items).To(HaveLen(2))
+			})
+		})
+	})
+
+	Describe("CreateCleanupJobs", func() {
+		var config cleanup.CleanupJobConfig
+
+		BeforeEach(func() {
+			config = cleanup.CleanupJobConfig{
+				Image:           "test-image:latest",
+				ImagePullPolicy: "IfNotPresent",
+				Namespace:       namespace,
+				ServiceAccount:  "test-sa",
+			}
+		})
+
+		It("should ensure the service account once for a batch of nodes", func() {
+			nodes := []string{"node-1", "node-2", "node-3", "node-4", "node-5"}
+
+			results, err := jobManager.CreateCleanupJobs(ctx, nodes, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(5))
+			for i, result := range results {
+				Expect(result.Err).NotTo(HaveOccurred())
+				Expect(result.NodeName).To(Equal(nodes[i]))
+				Expect(result.JobName).To(Equal("csi-mount-cleanup-" + nodes[i]))
+			}
+
+			var serviceAccountVerbs []string
+			for _, action := range fakeClient.Actions() {
+				if action.GetResource().Resource == "serviceaccounts" {
+					serviceAccountVerbs = append(serviceAccountVerbs, action.GetVerb())
+				}
+			}
+			Expect(serviceAccountVerbs).To(Equal([]string{"get", "create"}))
+
+			jobs, err := fakeClient.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobs.Items).To(HaveLen(5))
+			for _, job := range jobs.Items {
+				Expect(job.Spec.Template.Spec.ServiceAccountName).To(Equal("test-sa"))
+			}
+		})
+
+		It("should report per-node failures without stopping the batch", func() {
+			_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "csi-mount-cleanup-node-1", Namespace: namespace},
+				Status:     batchv1.JobStatus{Active: 1},
+			}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			results, err := jobManager.CreateCleanupJobs(ctx, []string{"node-1", "node-2"}, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results[0].Err).To(MatchError(cleanup.ErrJobRunning))
+			Expect(results[1].Err).NotTo(HaveOccurred())
+			Expect(results[1].JobName).To(Equal("csi-mount-cleanup-node-2"))
+		})
+
+		It("should abort the batch if the service account cannot be created", func() {
+			fakeClient.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, fmt.Errorf("forbidden")
+			})
+
+			results, err := jobManager.CreateCleanupJobs(ctx, []string{"node-1"}, config)
+			Expect(err).To(MatchError(ContainSubstring("failed to create service account test-sa")))
+			Expect(results).To(BeNil())
+		})
+	})
+
+	Describe("CreateRBAC", func() {
+		var config cleanup.CleanupJobConfig
+
+		BeforeEach(func() {
+			config = cleanup.CleanupJobConfig{
+				NodeName:       "test-node",
+				Image:          "test-image:latest",
+				Namespace:      namespace,
+				ServiceAccount: "test-sa",
+			}
+		})
+
+		It("should create the cluster role and bind it to the service account", func() {
+			Expect(jobManager.CreateRBAC(ctx, config)).To(Succeed())
+
+			role, err := fakeClient.RbacV1().ClusterRoles().Get(ctx, "kubectl-csi-scan-cleanup", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(role.Rules).To(ConsistOf(
+				rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get", "list"}},
+				rbacv1.PolicyRule{APIGroups: []string{"storage.k8s.io"}, Resources: []string{"volumeattachments"}, Verbs: []string{"get", "list"}},
+				rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"persistentvolumes", "persistentvolumeclaims"}, Verbs: []string{"get", "list"}},
+				rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: []string{"get", "list"}},
+			))
+
+			binding, err := fakeClient.RbacV1().ClusterRoleBindings().Get(ctx, "kubectl-csi-scan-cleanup", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(binding.RoleRef).To(Equal(rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "ClusterRole",
+				Name:     "kubectl-csi-scan-cleanup",
+			}))
+			Expect(binding.Subjects).To(ConsistOf(rbacv1.Subject{
+				Kind:      "ServiceAccount",
+				Name:      "test-sa",
+				Namespace: namespace,
+			}))
+		})
+
+		It("should leave an existing cluster role as it is", func() {
+			existing := &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubectl-csi-scan-cleanup"},
+				Rules: []rbacv1.PolicyRule{
+					{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get"}},
+				},
+			}
+			_, err := fakeClient.RbacV1().ClusterRoles().Create(ctx, existing, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(jobManager.CreateRBAC(ctx, config)).To(Succeed())
+
+			roles, err := fakeClient.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roles.Items).To(HaveLen(1))
+			Expect(roles.Items[0].Rules).To(Equal(existing.Rules))
+
+			bindings, err := fakeClient.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bindings.Items).To(HaveLen(1))
+		})
+
+		It("should add the service account to a binding made for another namespace", func() {
+			other := config
+			other.Namespace = "other-namespace"
+			other.ServiceAccount = "other-sa"
+			Expect(cleanup.NewCleanupJobManager(fakeClient, "other-namespace").CreateRBAC(ctx, other)).To(Succeed())
+
+			Expect(jobManager.CreateRBAC(ctx, config)).To(Succeed())
+
+			binding, err := fakeClient.RbacV1().ClusterRoleBindings().Get(ctx, "kubectl-csi-scan-cleanup", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(binding.Subjects).To(ConsistOf(
+				rbacv1.Subject{Kind: "ServiceAccount", Name: "other-sa", Namespace: "other-namespace"},
+				rbacv1.Subject{Kind: "ServiceAccount", Name: "test-sa", Namespace: namespace},
+			))
+		})
+
+		It("should return an error if the cluster role binding cannot be read", func() {
+			fakeClient.PrependReactor("get", "clusterrolebindings", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, nil, fmt.Errorf("connection refused")
+			})
+
+			err := jobManager.CreateRBAC(ctx, config)
+			Expect(err).To(MatchError(ContainSubstring("failed to get cluster role binding kubectl-csi-scan-cleanup")))
+
+			bindings, err := fakeClient.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bindings.Items).To(BeEmpty())
+		})
+
+		It("should only be created with the job when requested", func() {
+			_, err := jobManager.CreateCleanupJob(ctx, config)
+			Expect(err).NotTo(HaveOccurred())
+			roles, err := fakeClient.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roles.Items).To(BeEmpty())
+
+			config.CreateRBAC = true
+			config.Recreate = true
+			_, err = jobManager.CreateCleanupJobs(ctx, []string{"node-a", "node-b"}, config)
+			Expect(err).NotTo(HaveOccurred())
+			roles, err = fakeClient.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roles.Items).To(HaveLen(1))
+			bindings, err := fakeClient.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bindings.Items).To(HaveLen(1))
+		})
+
+		It("should return an error if the cluster role cannot be created", func() {
+			fakeClient.PrependReactor("create", "clusterroles", func(action k8stesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, nil, fmt.Errorf("forbidden")
+			})
+			config.CreateRBAC = true
+
+			_, err := jobManager.CreateCleanupJob(ctx, config)
+			Expect(err).To(MatchError(ContainSubstring("failed to create cluster role kubectl-csi-scan-cleanup")))
+
+			jobs, err := fakeClient.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobs.Items).To(BeEmpty())
+		})
+	})
+
+	Describe("cooldown", func() {
+		var config cleanup.CleanupJobConfig
+
+		finishedJob := func(name, node string, finishedAgo time.Duration) *batchv1.Job {
+			completedAt := metav1.NewTime(time.Now().Add(-finishedAgo))
+			return &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+					Labels: map[string]string{
+						"kubectl-csi-scan/managed": "true",
+						"node":                     node,
+					},
+				},
+				Status: batchv1.JobStatus{Succeeded: 1, CompletionTime: &completedAt},
+			}
+		}
+
+		BeforeEach(func() {
+			config = cleanup.CleanupJobConfig{
+				NodeName:        "test-node",
+				Image:           "test-image:latest",
+				ImagePullPolicy: "IfNotPresent",
+				Namespace:       namespace,
+				ServiceAccount:  "test-sa",
+				Cooldown:        cleanup.DefaultCooldown,
+			}
+		})
+
+		It("should skip a node whose cleanup job finished two minutes ago", func() {
+			_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, finishedJob("csi-mount-cleanup-test-node", "test-node", 2*time.Minute), metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			jobName, err := jobManager.CreateCleanupJob(ctx, config)
+			Expect(err).To(MatchError(cleanup.ErrRecentlyCleaned))
+			Expect(err.Error()).To(ContainSubstring("csi-mount-cleanup-test-node"))
+			Expect(jobName).To(BeEmpty())
+
+			jobs, err := fakeClient.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobs.Items).To(HaveLen(1))
+		})
+
+		It("should run cleanup once the cooldown has passed", func() {
+			_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, finishedJob("csi-mount-cleanup-test-node", "test-node", time.Hour), metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			jobName, err := jobManager.CreateCleanupJob(ctx, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobName).To(HavePrefix("csi-mount-cleanup-test-node-"))
+		})
+
+		It("should ignore recent jobs for other nodes", func() {
+			_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, finishedJob("csi-mount-cleanup-other-node", "other-node", time.Minute), metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			jobName, err := jobManager.CreateCleanupJob(ctx, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobName).To(Equal("csi-mount-cleanup-test-node"))
+		})
+
+		It("should not count a recent dry run as a cleanup", func() {
+			job := finishedJob("csi-mount-cleanup-test-node", "test-node", 2*time.Minute)
+			job.Annotations = map[string]string{"kubectl-csi-scan/dry-run": "true"}
+			_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			jobName, err := jobManager.CreateCleanupJob(ctx, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobName).To(HavePrefix("csi-mount-cleanup-test-node-"))
+		})
+
+		It("should not count a recent read-only job as a cleanup", func() {
+			job := finishedJob("csi-mount-cleanup-test-node", "test-node", 2*time.Minute)
+			job.Annotations = map[string]string{"kubectl-csi-scan/read-only": "true"}
+			_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = jobManager.CreateCleanupJob(ctx, config)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should let a failed cleanup be retried within the cooldown", func() {
+			job := finishedJob("csi-mount-cleanup-test-node", "test-node", 2*time.Minute)
+			failedAt := *job.Status.CompletionTime
+			job.Status = batchv1.JobStatus{
+				Failed: 1,
+				Conditions: []batchv1.JobCondition{{
+					Type: batchv1.JobFailed, Status: corev1.ConditionTrue, LastTransitionTime: failedAt,
+				}},
+			}
+			_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			jobName, err := jobManager.CreateCleanupJob(ctx, config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobName).To(HavePrefix("csi-mount-cleanup-test-node-"))
+		})
+
+		It("should not check recent jobs when the cooldown is disabled", func() {
+			_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, finishedJob("csi-mount-cleanup-test-node", "test-node", 2*time.Minute), metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			config.Cooldown = 0
+
+			_, err = jobManager.CreateCleanupJob(ctx, config)
+			Expect(err).NotTo(HaveOccurred())
+		})
 	})
 
 	Describe("WaitForJobs", func() {
@@ -326,6 +747,90 @@ var _ = Describe("CleanupJobManager", func() {
 			})
 		})
 
+		Context("with a status hook", func() {
+			It("should report each job's state on every poll until all succeed", func() {
+				for _, jobName := range jobNames {
+					job := &batchv1.Job{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      jobName,
+							Namespace: namespace,
+							Labels:    map[string]string{"node": "node-" + strings.TrimPrefix(jobName, "test-job-")},
+						},
+					}
+					_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				// Each poll finishes the next job, so the states advance one job at a time
+				var seen [][]cleanup.JobStatus
+				jobManager.SetWaitOptions(cleanup.WaitOptions{
+					PollInterval:    time.Millisecond,
+					MaxPollInterval: 4 * time.Millisecond,
+					OnStatus: func(statuses []cleanup.JobStatus) {
+						seen = append(seen, statuses)
+						if len(seen) > len(jobNames) {
+							return
+						}
+						job, err := fakeClient.BatchV1().Jobs(namespace).Get(ctx, jobNames[len(seen)-1], metav1.GetOptions{})
+						Expect(err).NotTo(HaveOccurred())
+						job.Status.Succeeded = 1
+						_, err = fakeClient.BatchV1().Jobs(namespace).UpdateStatus(ctx, job, metav1.UpdateOptions{})
+						Expect(err).NotTo(HaveOccurred())
+					},
+				})
+
+				ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+				defer cancel()
+
+				Expect(jobManager.WaitForJobs(ctx, jobNames)).To(Succeed())
+				Expect(seen).To(Equal([][]cleanup.JobStatus{
+					{
+						{JobName: "test-job-1", NodeName: "node-1", State: cleanup.JobRunning},
+						{JobName: "test-job-2", NodeName: "node-2", State: cleanup.JobRunning},
+					},
+					{
+						{JobName: "test-job-1", NodeName: "node-1", State: cleanup.JobSucceeded},
+						{JobName: "test-job-2", NodeName: "node-2", State: cleanup.JobRunning},
+					},
+					{
+						{JobName: "test-job-1", NodeName: "node-1", State: cleanup.JobSucceeded},
+						{JobName: "test-job-2", NodeName: "node-2", State: cleanup.JobSucceeded},
+					},
+				}))
+			})
+
+			It("should report a failure before returning", func() {
+				job := &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: jobNames[0], Namespace: namespace},
+					Status:     batchv1.JobStatus{Failed: 1},
+				}
+				_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				var seen []cleanup.JobStatus
+				jobManager.SetWaitOptions(cleanup.WaitOptions{
+					OnStatus: func(statuses []cleanup.JobStatus) { seen = statuses },
+				})
+
+				err = jobManager.WaitForJobs(ctx, jobNames[:1])
+				Expect(err).To(MatchError(ContainSubstring("cleanup job test-job-1 failed")))
+				Expect(seen).To(Equal([]cleanup.JobStatus{{JobName: "test-job-1", State: cleanup.JobFailed}}))
+			})
+		})
+
+		Context("when jobs outlast the maximum wait", func() {
+			It("should give up after MaxWait", func() {
+				job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: jobNames[0], Namespace: namespace}}
+				_, err := fakeClient.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				jobManager.SetWaitOptions(cleanup.WaitOptions{PollInterval: time.Millisecond, MaxWait: 50 * time.Millisecond})
+
+				err = jobManager.WaitForJobs(ctx, jobNames[:1])
+				Expect(err).To(MatchError(ContainSubstring("timeout waiting for jobs to complete")))
+			})
+		})
+
 		Context("when job doesn't exist", func() {
 			It("should return error if job is not found", func() {
 				ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -0,0 +1,110 @@
+package cleanup
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Right after a job is created its pod may not exist yet, or its container may not have
+// started, so StreamJobLogs keeps trying to open the log for a few seconds
+const (
+	logRetryInterval = 500 * time.Millisecond
+	logRetryTimeout  = 15 * time.Second
+)
+
+// StreamJobLogs follows the log of the job's pod, writing it to w a line at a time as the
+// cleanup script runs. It returns when the pod's container exits or ctx is done.
+func (m *CleanupJobManager) StreamJobLogs(ctx context.Context, jobName string, w io.Writer) error {
+	job, err := m.client.BatchV1().Jobs(m.namespace).Get(ctx, jobName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get job %s: %w", jobName, err)
+	}
+	selector := fmt.Sprintf("component=cleanup-job,node=%s", job.Labels["node"])
+
+	var (
+		logs    io.ReadCloser
+		lastErr error
+	)
+	err = wait.PollUntilContextTimeout(ctx, logRetryInterval, logRetryTimeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := m.client.CoreV1().Pods(m.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, fmt.Errorf("failed to list pods of job %s: %w", jobName, err)
+		}
+		// Finished jobs for the same node keep their pods until they expire
+		var own []corev1.Pod
+		for _, pod := range pods.Items {
+			if pod.Labels["job-name"] == jobName {
+				own = append(own, pod)
+			}
+		}
+		if len(own) == 0 {
+			lastErr = fmt.Errorf("no pods found for job %s", jobName)
+			return false, nil
+		}
+
+		pod := newestPod(own)
+		logs, lastErr = m.client.CoreV1().Pods(m.namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Follow: true}).Stream(ctx)
+		if lastErr != nil {
+			lastErr = fmt.Errorf("failed to get logs of pod %s: %w", pod.Name, lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		if lastErr != nil && ctx.Err() == nil {
+			return lastErr
+		}
+		return err
+	}
+	defer logs.Close()
+
+	scanner := bufio.NewScanner(logs)
+	for scanner.Scan() {
+		// One write per line, so streams of several jobs sharing w do not interleave mid-line
+		if _, err := w.Write(append(scanner.Bytes(), '\n')); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read logs of job %s: %w", jobName, err)
+	}
+	return nil
+}
+
+// jobLog opens the log of a finished job's newest pod
+func (m *CleanupJobManager) jobLog(ctx context.Context, jobName string) (io.ReadCloser, error) {
+	pods, err := m.client.CoreV1().Pods(m.namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + jobName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods of job %s: %w", jobName, err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, fmt.Errorf("no pods found for job %s", jobName)
+	}
+
+	newest := newestPod(pods.Items)
+
+	logs, err := m.client.CoreV1().Pods(m.namespace).GetLogs(newest.Name, &corev1.PodLogOptions{}).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs of pod %s: %w", newest.Name, err)
+	}
+	return logs, nil
+}
+
+// newestPod returns the most recently created of the pods. A job that was retried has
+// several pods; the newest one holds the last attempt.
+func newestPod(pods []corev1.Pod) corev1.Pod {
+	newest := pods[0]
+	for _, pod := range pods[1:] {
+		if pod.CreationTimestamp.After(newest.CreationTimestamp.Time) {
+			newest = pod
+		}
+	}
+	return newest
+}
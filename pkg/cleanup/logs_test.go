@@ -0,0 +1,116 @@
+package cleanup_test
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/cleanup"
+)
+
+var _ = Describe("StreamJobLogs", func() {
+	const (
+		namespace = "test-namespace"
+		jobName   = "csi-mount-cleanup-node-1"
+	)
+
+	var (
+		fakeClient *fake.Clientset
+		jobManager *cleanup.CleanupJobManager
+		ctx        context.Context
+		streamed   int // log streams opened
+	)
+
+	jobPod := func(name, job string, created time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         namespace,
+				CreationTimestamp: metav1.NewTime(created),
+				Labels: map[string]string{
+					"component": "cleanup-job",
+					"node":      "node-1",
+					"job-name":  job,
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fakeClient = fake.NewSimpleClientset(&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      jobName,
+				Namespace: namespace,
+				Labels:    map[string]string{"component": "cleanup-job", "node": "node-1"},
+			},
+		})
+		streamed = 0
+		fakeClient.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "log" {
+				return false, nil, nil
+			}
+			opts := action.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions)
+			Expect(opts.Follow).To(BeTrue())
+			streamed++
+			return true, nil, nil
+		})
+		jobManager = cleanup.NewCleanupJobManager(fakeClient, namespace)
+	})
+
+	It("should follow the log of the job's pod", func() {
+		now := time.Now()
+		for _, pod := range []*corev1.Pod{
+			jobPod(jobName+"-first", jobName, now.Add(-time.Minute)),
+			jobPod(jobName+"-retry", jobName, now),
+		} {
+			_, err := fakeClient.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		var out bytes.Buffer
+		Expect(jobManager.StreamJobLogs(ctx, jobName, &out)).To(Succeed())
+
+		// The fake clientset serves "fake logs" for every pod
+		Expect(out.String()).To(Equal("fake logs\n"))
+		Expect(streamed).To(Equal(1))
+	})
+
+	It("should not follow the pod of an earlier job for the node", func() {
+		_, err := fakeClient.CoreV1().Pods(namespace).Create(ctx,
+			jobPod("csi-mount-cleanup-node-1-old", "csi-mount-cleanup-node-1-x7k2p", time.Now()), metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+		Expect(jobManager.StreamJobLogs(ctx, jobName, &bytes.Buffer{})).NotTo(Succeed())
+		Expect(streamed).To(BeZero())
+	})
+
+	It("should wait for the pod to be created", func() {
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(100 * time.Millisecond)
+			_, err := fakeClient.CoreV1().Pods(namespace).Create(ctx, jobPod(jobName+"-abcde", jobName, time.Now()), metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}()
+
+		var out bytes.Buffer
+		Expect(jobManager.StreamJobLogs(ctx, jobName, &out)).To(Succeed())
+		Expect(out.String()).To(Equal("fake logs\n"))
+	})
+
+	It("should fail for an unknown job", func() {
+		err := jobManager.StreamJobLogs(ctx, "csi-mount-cleanup-missing", &bytes.Buffer{})
+		Expect(err).To(MatchError(ContainSubstring("failed to get job csi-mount-cleanup-missing")))
+	})
+})
@@ -0,0 +1,45 @@
+package cleanup
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// NodeDetectionTimes returns, for each node with issues in a detection result, when the
+// most recent of them was detected. Nodes whose issues carry no detection time map to the
+// zero time.
+func NodeDetectionTimes(result *types.DetectionResult) map[string]time.Time {
+	times := make(map[string]time.Time)
+	for _, issue := range result.Issues {
+		if issue.Node == "" {
+			continue
+		}
+		if latest, ok := times[issue.Node]; !ok || issue.DetectedAt.After(latest) {
+			times[issue.Node] = issue.DetectedAt
+		}
+	}
+	return times
+}
+
+// RecentNodes returns the nodes whose issues were detected within since of now, sorted by
+// name. Nodes without a detection time are only returned when includeUntimed is set. A
+// since of 0 returns every node.
+func RecentNodes(times map[string]time.Time, since time.Duration, now time.Time, includeUntimed bool) []string {
+	cutoff := now.Add(-since)
+	var nodes []string
+	for node, detectedAt := range times {
+		switch {
+		case detectedAt.IsZero():
+			if since > 0 && !includeUntimed {
+				continue
+			}
+		case since > 0 && detectedAt.Before(cutoff):
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
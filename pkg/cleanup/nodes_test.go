@@ -0,0 +1,50 @@
+package cleanup_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/cleanup"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("Nodes from a detection result", func() {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// node-a was last seen 10 minutes ago, node-b 3 hours ago, node-c has no
+	// detection time and the cluster-wide issue has no node
+	result := &types.DetectionResult{
+		Issues: []types.CSIMountIssue{
+			{Node: "node-a", DetectedAt: now.Add(-2 * time.Hour)},
+			{Node: "node-b", DetectedAt: now.Add(-3 * time.Hour)},
+			{Node: "node-a", DetectedAt: now.Add(-10 * time.Minute)},
+			{Node: "node-c"},
+			{DetectedAt: now.Add(-time.Minute)},
+		},
+	}
+
+	It("should map each node to its most recent detection time", func() {
+		Expect(cleanup.NodeDetectionTimes(result)).To(Equal(map[string]time.Time{
+			"node-a": now.Add(-10 * time.Minute),
+			"node-b": now.Add(-3 * time.Hour),
+			"node-c": {},
+		}))
+	})
+
+	It("should return every node when no window is set", func() {
+		nodes := cleanup.RecentNodes(cleanup.NodeDetectionTimes(result), 0, now, false)
+		Expect(nodes).To(Equal([]string{"node-a", "node-b", "node-c"}))
+	})
+
+	It("should only return nodes detected within the window", func() {
+		nodes := cleanup.RecentNodes(cleanup.NodeDetectionTimes(result), time.Hour, now, false)
+		Expect(nodes).To(Equal([]string{"node-a"}))
+	})
+
+	It("should include nodes without a detection time when asked to", func() {
+		nodes := cleanup.RecentNodes(cleanup.NodeDetectionTimes(result), time.Hour, now, true)
+		Expect(nodes).To(Equal([]string{"node-a", "node-c"}))
+	})
+})
@@ -0,0 +1,52 @@
+package cleanup
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dryRunMarker precedes each change the cleanup script reports in dry-run mode
+const dryRunMarker = "DRY RUN: Would "
+
+// PlannedChange is a change a dry-run cleanup job reported it would make on its node
+type PlannedChange struct {
+	Action string // e.g. "unmount" or "remove empty directory"
+	Path   string
+}
+
+// ParseDryRunLog returns the changes a dry-run cleanup job reported in its log, in order
+func ParseDryRunLog(r io.Reader) ([]PlannedChange, error) {
+	var changes []PlannedChange
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		_, change, ok := strings.Cut(scanner.Text(), dryRunMarker)
+		if !ok {
+			continue
+		}
+		// The path is the last word; kubelet mount paths contain no spaces
+		i := strings.LastIndex(change, " ")
+		if i < 0 {
+			continue
+		}
+		changes = append(changes, PlannedChange{Action: change[:i], Path: change[i+1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cleanup job log: %w", err)
+	}
+	return changes, nil
+}
+
+// PlannedChanges reads the changes a finished dry-run job reported from the log of its
+// newest pod
+func (m *CleanupJobManager) PlannedChanges(ctx context.Context, jobName string) ([]PlannedChange, error) {
+	logs, err := m.jobLog(ctx, jobName)
+	if err != nil {
+		return nil, err
+	}
+	defer logs.Close()
+
+	return ParseDryRunLog(logs)
+}
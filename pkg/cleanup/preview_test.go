@@ -0,0 +1,67 @@
+package cleanup_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/cleanup"
+)
+
+// dryRunLog is the log of a dry-run cleanup job that found one stuck mount
+const dryRunLog = `[csi-mount-cleanup] Running in DRY RUN mode - no changes will be made
+[csi-mount-cleanup] Processing mount: /var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-3c9d8e7f/globalmount
+[csi-mount-cleanup] DRY RUN: Would unmount /var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-3c9d8e7f/globalmount
+[csi-mount-cleanup] DRY RUN: Would remove empty directory /var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-3c9d8e7f/globalmount
+[csi-mount-cleanup] DRY RUN: Would remove empty PV directory /var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-3c9d8e7f
+[csi-mount-cleanup] DRY RUN MODE: No actual changes made
+`
+
+var _ = Describe("Dry-run preview", func() {
+	Describe("ParseDryRunLog", func() {
+		It("should return each change the script would make, in order", func() {
+			changes, err := cleanup.ParseDryRunLog(strings.NewReader(dryRunLog))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(Equal([]cleanup.PlannedChange{
+				{Action: "unmount", Path: "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-3c9d8e7f/globalmount"},
+				{Action: "remove empty directory", Path: "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-3c9d8e7f/globalmount"},
+				{Action: "remove empty PV directory", Path: "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-3c9d8e7f"},
+			}))
+		})
+
+		It("should return nothing for a node without stuck mounts", func() {
+			changes, err := cleanup.ParseDryRunLog(strings.NewReader("[csi-mount-cleanup] No stuck mounts found\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(BeEmpty())
+		})
+	})
+
+	Describe("PlannedChanges", func() {
+		It("should fail when the job has no pods", func() {
+			jobManager := cleanup.NewCleanupJobManager(fake.NewSimpleClientset(), "test-namespace")
+			_, err := jobManager.PlannedChanges(context.Background(), "csi-cleanup-node-1")
+			Expect(err).To(MatchError(ContainSubstring("no pods found for job csi-cleanup-node-1")))
+		})
+
+		It("should read the log of the job's pod", func() {
+			fakeClient := fake.NewSimpleClientset(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "csi-cleanup-node-1-abcde",
+					Namespace: "test-namespace",
+					Labels:    map[string]string{"job-name": "csi-cleanup-node-1"},
+				},
+			})
+			jobManager := cleanup.NewCleanupJobManager(fakeClient, "test-namespace")
+
+			// The fake clientset serves "fake logs" for every pod
+			changes, err := jobManager.PlannedChanges(context.Background(), "csi-cleanup-node-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,90 @@
+package cleanup
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// stuckMarker precedes each mount the cleanup script reports in scan-only mode
+const stuckMarker = "STUCK: "
+
+// ParseScanLog returns the mount paths a scan-only cleanup job reported as still mounted,
+// in order
+func ParseScanLog(r io.Reader) ([]string, error) {
+	var paths []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		_, path, ok := strings.Cut(scanner.Text(), stuckMarker)
+		if !ok {
+			continue
+		}
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read scan job log: %w", err)
+	}
+	return paths, nil
+}
+
+// MountProber lists the CSI mounts still mounted on nodes by running read-only cleanup
+// jobs in scan-only mode, so detection can see real mount references instead of
+// inferring them from pod counts
+type MountProber struct {
+	manager *CleanupJobManager
+	config  CleanupJobConfig
+}
+
+// NewMountProber creates a prober whose jobs use config. The jobs always run read-only
+// with the read-only mount propagation, never unmount anything and ignore the cleanup
+// cooldown.
+func NewMountProber(manager *CleanupJobManager, config CleanupJobConfig) *MountProber {
+	config.ReadOnly = true
+	config.DryRun = false
+	config.MountPropagation = ""
+	config.Cooldown = 0
+	return &MountProber{manager: manager, config: config}
+}
+
+// ProbeMounts runs a scan job on each node, waits for all of them and returns the mount
+// paths each reported, by node. Nodes with no mounts are present with no paths.
+func (p *MountProber) ProbeMounts(ctx context.Context, nodes []string) (map[string][]string, error) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	results, err := p.manager.CreateCleanupJobs(ctx, nodes, p.config)
+	if err != nil {
+		return nil, err
+	}
+	jobNames := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("failed to create mount probe job on node %s: %w", result.NodeName, result.Err)
+		}
+		jobNames = append(jobNames, result.JobName)
+	}
+
+	if err := p.manager.WaitForJobs(ctx, jobNames); err != nil {
+		return nil, fmt.Errorf("mount probe jobs did not complete: %w", err)
+	}
+
+	mounts := make(map[string][]string, len(results))
+	for _, result := range results {
+		logs, err := p.manager.jobLog(ctx, result.JobName)
+		if err != nil {
+			return nil, err
+		}
+		paths, err := ParseScanLog(logs)
+		logs.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse mount probe of node %s: %w", result.NodeName, err)
+		}
+		mounts[result.NodeName] = paths
+	}
+	return mounts, nil
+}
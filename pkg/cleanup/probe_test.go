@@ -0,0 +1,113 @@
+package cleanup_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/cleanup"
+)
+
+// scanLog is the log of a scan-only cleanup job that found two mounts
+const scanLog = `[csi-mount-cleanup] Scanning for stuck CSI mounts on node: node-1
+[csi-mount-cleanup] STUCK: /var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-3c9d8e7f/globalmount
+[csi-mount-cleanup] STUCK: /var/lib/kubelet/plugins/kubernetes.io/csi/cinder.csi.openstack.org/0f3a9c/globalmount
+[csi-mount-cleanup] === Scan Summary ===
+[csi-mount-cleanup] Stuck mounts found: 2
+[csi-mount-cleanup] SCAN ONLY MODE: No unmounts attempted
+`
+
+var _ = Describe("Mount probe", func() {
+	Describe("ParseScanLog", func() {
+		It("should return each reported mount path, in order", func() {
+			paths, err := cleanup.ParseScanLog(strings.NewReader(scanLog))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paths).To(Equal([]string{
+				"/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-3c9d8e7f/globalmount",
+				"/var/lib/kubelet/plugins/kubernetes.io/csi/cinder.csi.openstack.org/0f3a9c/globalmount",
+			}))
+		})
+
+		It("should return nothing for a node without mounts", func() {
+			paths, err := cleanup.ParseScanLog(strings.NewReader("[csi-mount-cleanup] No stuck CSI mounts found on this node\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paths).To(BeEmpty())
+		})
+	})
+
+	Describe("ProbeMounts", func() {
+		const namespace = "test-namespace"
+
+		var (
+			fakeClient *fake.Clientset
+			prober     *cleanup.MountProber
+		)
+
+		BeforeEach(func() {
+			fakeClient = fake.NewSimpleClientset(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "csi-mount-cleanup-node-1-abcde",
+					Namespace: namespace,
+					Labels:    map[string]string{"job-name": "csi-mount-cleanup-node-1"},
+				},
+			})
+			// Jobs finish as soon as they are created
+			fakeClient.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				job := action.(k8stesting.CreateAction).GetObject().(*batchv1.Job)
+				job.Status.Succeeded = 1
+				return false, nil, nil
+			})
+
+			// Ask for everything a scan must not do; the prober overrides it
+			prober = cleanup.NewMountProber(cleanup.NewCleanupJobManager(fakeClient, namespace), cleanup.CleanupJobConfig{
+				Image:            "kubectl-csi-scan:latest",
+				ImagePullPolicy:  "IfNotPresent",
+				Namespace:        namespace,
+				ServiceAccount:   "kubectl-csi-scan-cleanup",
+				DryRun:           true,
+				MountPropagation: corev1.MountPropagationBidirectional,
+			})
+		})
+
+		It("should run a read-only scan job without Bidirectional mount propagation", func() {
+			mounts, err := prober.ProbeMounts(context.Background(), []string{"node-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mounts).To(HaveKey("node-1"))
+
+			job, err := fakeClient.BatchV1().Jobs(namespace).Get(context.Background(), "csi-mount-cleanup-node-1", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			spec := job.Spec.Template.Spec
+			Expect(spec.HostPID).To(BeFalse())
+			container := spec.Containers[0]
+			Expect(*container.SecurityContext.Privileged).To(BeFalse())
+			Expect(container.Args).To(ContainElement("--scan-only"))
+			Expect(container.Args).NotTo(ContainElement("--dry-run"))
+			Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "SCAN_ONLY", Value: "true"}))
+			for _, mount := range container.VolumeMounts {
+				Expect(mount.ReadOnly).To(BeTrue(), mount.Name)
+				if mount.MountPropagation != nil {
+					Expect(*mount.MountPropagation).NotTo(Equal(corev1.MountPropagationBidirectional), mount.Name)
+				}
+			}
+		})
+
+		It("should not create jobs when there are no nodes to probe", func() {
+			mounts, err := prober.ProbeMounts(context.Background(), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mounts).To(BeEmpty())
+
+			jobs, err := fakeClient.BatchV1().Jobs(namespace).List(context.Background(), metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobs.Items).To(BeEmpty())
+		})
+	})
+})
@@ -117,6 +117,22 @@ var _ = Describe("Cleanup Script", func() {
 		})
 	})
 
+	Describe("Scan-only mode", func() {
+		It("should report without requiring root or attempting unmounts", func() {
+			cmd := exec.Command("bash", scriptPath, "--scan-only")
+			output, err := cmd.CombinedOutput()
+
+			Expect(err).NotTo(HaveOccurred(), string(output))
+			outputStr := string(output)
+			Expect(outputStr).NotTo(ContainSubstring("must be run as root"))
+			Expect(outputStr).NotTo(ContainSubstring("unmounted"))
+			Expect(outputStr).To(SatisfyAny(
+				ContainSubstring("No stuck CSI mounts found"),
+				ContainSubstring("SCAN ONLY MODE"),
+			))
+		})
+	})
+
 	Describe("Environment Variable Handling", func() {
 		Context("when environment variables are set", func() {
 			It("should respect DRY_RUN environment variable", func() {
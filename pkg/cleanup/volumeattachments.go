@@ -0,0 +1,173 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// VolumeAttachmentTarget is a VolumeAttachment considered for deletion
+type VolumeAttachmentTarget struct {
+	Name            string
+	Node            string
+	PV              string
+	Skip            string       // why the attachment is not deleted; empty when it is eligible
+	UID             k8stypes.UID // the attachment planned for deletion, so a recreated one is left alone
+	ResourceVersion string       // the version planned for deletion, so one that changed since is left alone
+}
+
+// Eligible reports whether the attachment may be deleted
+func (t VolumeAttachmentTarget) Eligible() bool {
+	return t.Skip == ""
+}
+
+// VolumeAttachmentCleaner deletes stuck VolumeAttachments so the attacher can retry or
+// release the volume
+type VolumeAttachmentCleaner struct {
+	client kubernetes.Interface
+}
+
+// NewVolumeAttachmentCleaner creates a cleaner using the given client
+func NewVolumeAttachmentCleaner(client kubernetes.Interface) *VolumeAttachmentCleaner {
+	return &VolumeAttachmentCleaner{client: client}
+}
+
+// StuckVolumeAttachments returns the names of the VolumeAttachments behind the stuck
+// attach and detach issues of a detection result, each once, in issue order
+func StuckVolumeAttachments(result *types.DetectionResult) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, issue := range result.Issues {
+		if issue.Type != types.StuckVolumeAttachment && issue.Type != types.StuckVolumeDetachment {
+			continue
+		}
+		for _, source := range issue.Sources {
+			if source.Kind == "VolumeAttachment" && !seen[source.Name] {
+				seen[source.Name] = true
+				names = append(names, source.Name)
+			}
+		}
+	}
+	return names
+}
+
+// Plan looks up each named VolumeAttachment and decides whether it may be deleted. An
+// attachment is skipped when it no longer exists, when it is attached without errors and
+// so no longer stuck, when its volume is not a PersistentVolume, or when a running pod on
+// its node still uses the volume's claim, since deleting it would detach the volume from
+// under that pod.
+func (c *VolumeAttachmentCleaner) Plan(ctx context.Context, names []string) ([]VolumeAttachmentTarget, error) {
+	targets := make([]VolumeAttachmentTarget, 0, len(names))
+	for _, name := range names {
+		target := VolumeAttachmentTarget{Name: name}
+
+		va, err := c.client.StorageV1().VolumeAttachments().Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			target.Skip = "not found"
+			targets = append(targets, target)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get VolumeAttachment %s: %w", name, err)
+		}
+		target.Node = va.Spec.NodeName
+		target.UID = va.UID
+		target.ResourceVersion = va.ResourceVersion
+
+		// Detection results may be stale, and attachment names are derived from the volume
+		// and node, so the attachment may have recovered or been recreated since
+		if va.Status.Attached && va.Status.AttachError == nil && va.Status.DetachError == nil && va.DeletionTimestamp == nil {
+			target.Skip = "attached without errors, no longer stuck"
+			targets = append(targets, target)
+			continue
+		}
+
+		if va.Spec.Source.PersistentVolumeName == nil {
+			target.Skip = "volume is not a PersistentVolume"
+			targets = append(targets, target)
+			continue
+		}
+		target.PV = *va.Spec.Source.PersistentVolumeName
+
+		pod, err := c.runningConsumer(ctx, target.PV, target.Node)
+		if err != nil {
+			return nil, err
+		}
+		if pod != "" {
+			target.Skip = fmt.Sprintf("volume in use by running pod %s", pod)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// runningConsumer returns namespace/name of a running pod on node that uses the claim
+// bound to the PV, or "" when there is none. A PV that no longer exists or has no claim
+// cannot be in use.
+func (c *VolumeAttachmentCleaner) runningConsumer(ctx context.Context, pvName, node string) (string, error) {
+	pv, err := c.client.CoreV1().PersistentVolumes().Get(ctx, pvName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get PersistentVolume %s: %w", pvName, err)
+	}
+	claim := pv.Spec.ClaimRef
+	if claim == nil {
+		return "", nil
+	}
+
+	pods, err := c.client.CoreV1().Pods(claim.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods in namespace %s: %w", claim.Namespace, err)
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != node || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claim.Name {
+				return fmt.Sprintf("%s/%s", pod.Namespace, pod.Name), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// Delete deletes the eligible targets, returning the names deleted. Only the attachment
+// that was planned is deleted: one that was recreated or changed since is left alone and
+// reported as an error. An attachment that is already gone counts as deleted. Failures do
+// not stop the remaining deletions and are returned together.
+func (c *VolumeAttachmentCleaner) Delete(ctx context.Context, targets []VolumeAttachmentTarget) ([]string, error) {
+	var (
+		deleted []string
+		errs    []error
+	)
+	for _, target := range targets {
+		if !target.Eligible() {
+			continue
+		}
+		uid, resourceVersion := target.UID, target.ResourceVersion
+		err := c.client.StorageV1().VolumeAttachments().Delete(ctx, target.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &uid, ResourceVersion: &resourceVersion},
+		})
+		if apierrors.IsConflict(err) {
+			errs = append(errs, fmt.Errorf("VolumeAttachment %s changed since it was checked and was not deleted - run the cleanup again to check it again", target.Name))
+			continue
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete VolumeAttachment %s: %w", target.Name, err))
+			continue
+		}
+		deleted = append(deleted, target.Name)
+	}
+	return deleted, errors.Join(errs...)
+}
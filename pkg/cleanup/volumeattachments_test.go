@@ -0,0 +1,171 @@
+package cleanup_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/cleanup"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("VolumeAttachmentCleaner", func() {
+	var ctx context.Context
+
+	attachment := func(name, node, pv string) *storagev1.VolumeAttachment {
+		return &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: "uid-" + k8stypes.UID(name), ResourceVersion: "1"},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: "cinder.csi.openstack.org",
+				NodeName: node,
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pv},
+			},
+		}
+	}
+	boundPV := func(name, namespace, claim string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				ClaimRef: &corev1.ObjectReference{Namespace: namespace, Name: claim},
+			},
+		}
+	}
+	podUsing := func(name, node, claim string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PodSpec{
+				NodeName: node,
+				Volumes: []corev1.Volume{{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	remaining := func(client *fake.Clientset) []string {
+		list, err := client.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, va := range list.Items {
+			names = append(names, va.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should only delete attachments whose volume no running pod on the node uses", func() {
+		inline := attachment("csi-inline", "node-1", "")
+		inline.Spec.Source = storagev1.VolumeAttachmentSource{InlineVolumeSpec: &corev1.PersistentVolumeSpec{}}
+		objects := []runtime.Object{
+			attachment("csi-stale", "node-1", "pv-stale"),
+			boundPV("pv-stale", "default", "data-stale"),
+			podUsing("web-0", "node-2", "data-stale", corev1.PodRunning),
+			podUsing("web-old", "node-1", "data-stale", corev1.PodSucceeded),
+
+			attachment("csi-busy", "node-1", "pv-busy"),
+			boundPV("pv-busy", "default", "data-busy"),
+			podUsing("db-0", "node-1", "data-busy", corev1.PodRunning),
+
+			attachment("csi-orphan", "node-3", "pv-gone"),
+			inline,
+		}
+		client := fake.NewSimpleClientset(objects...)
+		cleaner := cleanup.NewVolumeAttachmentCleaner(client)
+
+		targets, err := cleaner.Plan(ctx, []string{"csi-stale", "csi-busy", "csi-orphan", "csi-inline", "csi-missing"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(HaveLen(5))
+		Expect(targets[0].Eligible()).To(BeTrue())
+		Expect(targets[0].PV).To(Equal("pv-stale"))
+		Expect(targets[1].Skip).To(Equal("volume in use by running pod default/db-0"))
+		Expect(targets[2].Eligible()).To(BeTrue())
+		Expect(targets[3].Skip).To(Equal("volume is not a PersistentVolume"))
+		Expect(targets[4].Skip).To(Equal("not found"))
+
+		deleted, err := cleaner.Delete(ctx, targets)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(Equal([]string{"csi-stale", "csi-orphan"}))
+		Expect(remaining(client)).To(ConsistOf("csi-busy", "csi-inline"))
+	})
+
+	It("should treat an attachment deleted in the meantime as deleted", func() {
+		client := fake.NewSimpleClientset(attachment("csi-stale", "node-1", "pv-gone"))
+		cleaner := cleanup.NewVolumeAttachmentCleaner(client)
+
+		targets, err := cleaner.Plan(ctx, []string{"csi-stale"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.StorageV1().VolumeAttachments().Delete(ctx, "csi-stale", metav1.DeleteOptions{})).To(Succeed())
+
+		deleted, err := cleaner.Delete(ctx, targets)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(Equal([]string{"csi-stale"}))
+	})
+
+	It("should skip attachments that are attached without errors by the time they are planned", func() {
+		healthy := attachment("csi-recovered", "node-1", "pv-gone")
+		healthy.Status.Attached = true
+		client := fake.NewSimpleClientset(healthy)
+
+		targets, err := cleanup.NewVolumeAttachmentCleaner(client).Plan(ctx, []string{"csi-recovered"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(HaveLen(1))
+		Expect(targets[0].Skip).To(Equal("attached without errors, no longer stuck"))
+	})
+
+	It("should only delete the attachment that was planned", func() {
+		client := fake.NewSimpleClientset(attachment("csi-stale", "node-1", "pv-gone"))
+		cleaner := cleanup.NewVolumeAttachmentCleaner(client)
+
+		targets, err := cleaner.Plan(ctx, []string{"csi-stale"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets[0].UID).To(Equal(k8stypes.UID("uid-csi-stale")))
+
+		// The attachment is recreated before the deletion, which the apiserver refuses
+		// as its UID no longer matches
+		client.PrependReactor("delete", "volumeattachments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			preconditions := action.(k8stesting.DeleteActionImpl).DeleteOptions.Preconditions
+			Expect(preconditions).NotTo(BeNil())
+			Expect(*preconditions.UID).To(Equal(k8stypes.UID("uid-csi-stale")))
+			Expect(*preconditions.ResourceVersion).To(Equal("1"))
+			return true, nil, apierrors.NewConflict(storagev1.Resource("volumeattachments"), "csi-stale", errors.New("Precondition failed: UID in precondition: uid-csi-stale, UID in object meta: uid-new"))
+		})
+
+		deleted, err := cleaner.Delete(ctx, targets)
+		Expect(err).To(MatchError(ContainSubstring("VolumeAttachment csi-stale changed since it was checked")))
+		Expect(deleted).To(BeEmpty())
+		Expect(remaining(client)).To(ConsistOf("csi-stale"))
+	})
+
+	Describe("StuckVolumeAttachments", func() {
+		It("should return the attachments of stuck attach and detach issues once each", func() {
+			source := func(name string) []types.SourceRef {
+				return []types.SourceRef{{Kind: "VolumeAttachment", Name: name}}
+			}
+			result := &types.DetectionResult{Issues: []types.CSIMountIssue{
+				{Type: types.StuckVolumeAttachment, Sources: source("csi-a")},
+				{Type: types.AttachmentNotReconciled, Sources: source("csi-b")},
+				{Type: types.StuckVolumeDetachment, Sources: source("csi-c")},
+				{Type: types.StuckVolumeDetachment, Sources: source("csi-a")},
+				{Type: types.MultiAttachError, Sources: []types.SourceRef{{Kind: "Event", Name: "ev-1"}}},
+			}}
+
+			Expect(cleanup.StuckVolumeAttachments(result)).To(Equal([]string{"csi-a", "csi-c"}))
+		})
+	})
+})
@@ -2,10 +2,15 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -13,6 +18,10 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+// ErrUnsupportedAPI is returned when the cluster does not serve the API version a
+// detection method needs
+var ErrUnsupportedAPI = errors.New("unsupported cluster API version")
+
 // Client wraps the real Kubernetes client with our interface
 type Client struct {
 	clientset kubernetes.Interface
@@ -69,6 +78,10 @@ func (c *coreV1Client) Nodes() NodeInterface {
 	return &nodeClient{client: c.client.Nodes()}
 }
 
+func (c *coreV1Client) ConfigMaps(namespace string) ConfigMapInterface {
+	return &configMapClient{client: c.client.ConfigMaps(namespace)}
+}
+
 // storageV1Client implements StorageV1Interface
 type storageV1Client struct {
 	client storagev1client.StorageV1Interface
@@ -82,6 +95,14 @@ func (c *storageV1Client) StorageClasses() StorageClassInterface {
 	return &storageClassClient{client: c.client.StorageClasses()}
 }
 
+func (c *storageV1Client) CSINodes() CSINodeInterface {
+	return &csiNodeClient{client: c.client.CSINodes()}
+}
+
+func (c *storageV1Client) CSIDrivers() CSIDriverInterface {
+	return &csiDriverClient{client: c.client.CSIDrivers()}
+}
+
 // podClient implements PodInterface
 type podClient struct {
 	client corev1client.PodInterface
@@ -125,6 +146,10 @@ func (c *persistentVolumeClaimClient) Get(ctx context.Context, name string, opts
 	return c.client.Get(ctx, name, opts)
 }
 
+func (c *persistentVolumeClaimClient) Patch(ctx context.Context, name string, pt k8stypes.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*corev1.PersistentVolumeClaim, error) {
+	return c.client.Patch(ctx, name, pt, data, opts, subresources...)
+}
+
 // eventClient implements EventInterface
 type eventClient struct {
 	client corev1client.EventInterface
@@ -151,13 +176,41 @@ func (c *nodeClient) Get(ctx context.Context, name string, opts metav1.GetOption
 	return c.client.Get(ctx, name, opts)
 }
 
+// configMapClient implements ConfigMapInterface
+type configMapClient struct {
+	client corev1client.ConfigMapInterface
+}
+
+func (c *configMapClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error) {
+	return c.client.Get(ctx, name, opts)
+}
+
+func (c *configMapClient) Create(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	return c.client.Create(ctx, configMap, opts)
+}
+
+func (c *configMapClient) Update(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+	return c.client.Update(ctx, configMap, opts)
+}
+
+// isNotServed reports whether a list failed because the API server does not serve the
+// resource at all: a RESTMapper "no matches for kind" error, or a 404 for the collection
+func isNotServed(err error) bool {
+	return meta.IsNoMatchError(err) || apierrors.IsNotFound(err)
+}
+
 // volumeAttachmentClient implements VolumeAttachmentInterface
 type volumeAttachmentClient struct {
 	client storagev1client.VolumeAttachmentInterface
 }
 
 func (c *volumeAttachmentClient) List(ctx context.Context, opts metav1.ListOptions) (*storagev1.VolumeAttachmentList, error) {
-	return c.client.List(ctx, opts)
+	list, err := c.client.List(ctx, opts)
+	if isNotServed(err) {
+		return nil, fmt.Errorf("%w: the cluster does not serve VolumeAttachments in storage.k8s.io/v1, which requires Kubernetes 1.13 or later; "+
+			"upgrade the cluster or leave out the volumeattachments method: %v", ErrUnsupportedAPI, err)
+	}
+	return list, err
 }
 
 func (c *volumeAttachmentClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*storagev1.VolumeAttachment, error) {
@@ -179,4 +232,30 @@ func (c *storageClassClient) List(ctx context.Context, opts metav1.ListOptions)
 
 func (c *storageClassClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*storagev1.StorageClass, error) {
 	return c.client.Get(ctx, name, opts)
-}
\ No newline at end of file
+}
+
+// csiNodeClient implements CSINodeInterface
+type csiNodeClient struct {
+	client storagev1client.CSINodeInterface
+}
+
+func (c *csiNodeClient) List(ctx context.Context, opts metav1.ListOptions) (*storagev1.CSINodeList, error) {
+	return c.client.List(ctx, opts)
+}
+
+func (c *csiNodeClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*storagev1.CSINode, error) {
+	return c.client.Get(ctx, name, opts)
+}
+
+// csiDriverClient implements CSIDriverInterface
+type csiDriverClient struct {
+	client storagev1client.CSIDriverInterface
+}
+
+func (c *csiDriverClient) List(ctx context.Context, opts metav1.ListOptions) (*storagev1.CSIDriverList, error) {
+	return c.client.List(ctx, opts)
+}
+
+func (c *csiDriverClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*storagev1.CSIDriver, error) {
+	return c.client.Get(ctx, name, opts)
+}
@@ -0,0 +1,71 @@
+package client_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+)
+
+var _ = Describe("Client", func() {
+	Describe("VolumeAttachments", func() {
+		var (
+			clientset *fake.Clientset
+			ctx       context.Context
+		)
+
+		BeforeEach(func() {
+			clientset = fake.NewSimpleClientset()
+			ctx = context.Background()
+		})
+
+		failList := func(err error) {
+			clientset.PrependReactor("list", "volumeattachments", func(k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, err
+			})
+		}
+
+		DescribeTable("should explain that the cluster does not serve storage.k8s.io/v1",
+			func(err error) {
+				failList(err)
+
+				_, err = client.NewClient(clientset).StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+				Expect(err).To(MatchError(client.ErrUnsupportedAPI))
+				Expect(err).To(MatchError(ContainSubstring("does not serve VolumeAttachments in storage.k8s.io/v1")))
+				Expect(err).To(MatchError(ContainSubstring("leave out the volumeattachments method")))
+			},
+			Entry("no kind match", &meta.NoKindMatchError{
+				GroupKind:        schema.GroupKind{Group: "storage.k8s.io", Kind: "VolumeAttachment"},
+				SearchedVersions: []string{"v1"},
+			}),
+			Entry("collection not found", apierrors.NewNotFound(schema.GroupResource{Group: "storage.k8s.io", Resource: "volumeattachments"}, "")),
+		)
+
+		It("should pass other errors through unchanged", func() {
+			forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "storage.k8s.io", Resource: "volumeattachments"}, "", nil)
+			failList(forbidden)
+
+			_, err := client.NewClient(clientset).StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+			Expect(err).To(Equal(forbidden))
+		})
+
+		It("should list attachments when the API is served", func() {
+			_, err := clientset.StorageV1().VolumeAttachments().Create(ctx, &storagev1.VolumeAttachment{ObjectMeta: metav1.ObjectMeta{Name: "va-1"}}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			list, err := client.NewClient(clientset).StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list.Items).To(HaveLen(1))
+		})
+	})
+})
@@ -6,6 +6,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
@@ -24,12 +25,15 @@ type CoreV1Interface interface {
 	PersistentVolumeClaims(namespace string) PersistentVolumeClaimInterface
 	Events(namespace string) EventInterface
 	Nodes() NodeInterface
+	ConfigMaps(namespace string) ConfigMapInterface
 }
 
 // StorageV1Interface defines the interface for Storage v1 API operations
 type StorageV1Interface interface {
 	VolumeAttachments() VolumeAttachmentInterface
 	StorageClasses() StorageClassInterface
+	CSINodes() CSINodeInterface
+	CSIDrivers() CSIDriverInterface
 }
 
 // PodInterface defines the interface for Pod operations
@@ -49,6 +53,7 @@ type PersistentVolumeInterface interface {
 type PersistentVolumeClaimInterface interface {
 	List(ctx context.Context, opts metav1.ListOptions) (*corev1.PersistentVolumeClaimList, error)
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.PersistentVolumeClaim, error)
+	Patch(ctx context.Context, name string, pt k8stypes.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*corev1.PersistentVolumeClaim, error)
 }
 
 // EventInterface defines the interface for Event operations
@@ -63,6 +68,13 @@ type NodeInterface interface {
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Node, error)
 }
 
+// ConfigMapInterface defines the interface for ConfigMap operations
+type ConfigMapInterface interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error)
+	Create(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error)
+	Update(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error)
+}
+
 // VolumeAttachmentInterface defines the interface for VolumeAttachment operations
 type VolumeAttachmentInterface interface {
 	List(ctx context.Context, opts metav1.ListOptions) (*storagev1.VolumeAttachmentList, error)
@@ -74,4 +86,16 @@ type VolumeAttachmentInterface interface {
 type StorageClassInterface interface {
 	List(ctx context.Context, opts metav1.ListOptions) (*storagev1.StorageClassList, error)
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*storagev1.StorageClass, error)
-}
\ No newline at end of file
+}
+
+// CSINodeInterface defines the interface for CSINode operations
+type CSINodeInterface interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*storagev1.CSINodeList, error)
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*storagev1.CSINode, error)
+}
+
+// CSIDriverInterface defines the interface for CSIDriver operations
+type CSIDriverInterface interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*storagev1.CSIDriverList, error)
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*storagev1.CSIDriver, error)
+}
@@ -18,6 +18,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	v10 "k8s.io/api/storage/v1"
 	v11 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
 	watch "k8s.io/apimachinery/pkg/watch"
 )
 
@@ -97,6 +98,20 @@ func (m *MockCoreV1Interface) EXPECT() *MockCoreV1InterfaceMockRecorder {
 	return m.recorder
 }
 
+// ConfigMaps mocks base method.
+func (m *MockCoreV1Interface) ConfigMaps(namespace string) client.ConfigMapInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigMaps", namespace)
+	ret0, _ := ret[0].(client.ConfigMapInterface)
+	return ret0
+}
+
+// ConfigMaps indicates an expected call of ConfigMaps.
+func (mr *MockCoreV1InterfaceMockRecorder) ConfigMaps(namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigMaps", reflect.TypeOf((*MockCoreV1Interface)(nil).ConfigMaps), namespace)
+}
+
 // Events mocks base method.
 func (m *MockCoreV1Interface) Events(namespace string) client.EventInterface {
 	m.ctrl.T.Helper()
@@ -191,6 +206,34 @@ func (m *MockStorageV1Interface) EXPECT() *MockStorageV1InterfaceMockRecorder {
 	return m.recorder
 }
 
+// CSIDrivers mocks base method.
+func (m *MockStorageV1Interface) CSIDrivers() client.CSIDriverInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CSIDrivers")
+	ret0, _ := ret[0].(client.CSIDriverInterface)
+	return ret0
+}
+
+// CSIDrivers indicates an expected call of CSIDrivers.
+func (mr *MockStorageV1InterfaceMockRecorder) CSIDrivers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CSIDrivers", reflect.TypeOf((*MockStorageV1Interface)(nil).CSIDrivers))
+}
+
+// CSINodes mocks base method.
+func (m *MockStorageV1Interface) CSINodes() client.CSINodeInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CSINodes")
+	ret0, _ := ret[0].(client.CSINodeInterface)
+	return ret0
+}
+
+// CSINodes indicates an expected call of CSINodes.
+func (mr *MockStorageV1InterfaceMockRecorder) CSINodes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CSINodes", reflect.TypeOf((*MockStorageV1Interface)(nil).CSINodes))
+}
+
 // StorageClasses mocks base method.
 func (m *MockStorageV1Interface) StorageClasses() client.StorageClassInterface {
 	m.ctrl.T.Helper()
@@ -396,6 +439,26 @@ func (mr *MockPersistentVolumeClaimInterfaceMockRecorder) List(ctx, opts any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPersistentVolumeClaimInterface)(nil).List), ctx, opts)
 }
 
+// Patch mocks base method.
+func (m *MockPersistentVolumeClaimInterface) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v11.PatchOptions, subresources ...string) (*v1.PersistentVolumeClaim, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name, pt, data, opts}
+	for _, a := range subresources {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Patch", varargs...)
+	ret0, _ := ret[0].(*v1.PersistentVolumeClaim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockPersistentVolumeClaimInterfaceMockRecorder) Patch(ctx, name, pt, data, opts any, subresources ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name, pt, data, opts}, subresources...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockPersistentVolumeClaimInterface)(nil).Patch), varargs...)
+}
+
 // MockEventInterface is a mock of EventInterface interface.
 type MockEventInterface struct {
 	ctrl     *gomock.Controller
@@ -504,6 +567,75 @@ func (mr *MockNodeInterfaceMockRecorder) List(ctx, opts any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNodeInterface)(nil).List), ctx, opts)
 }
 
+// MockConfigMapInterface is a mock of ConfigMapInterface interface.
+type MockConfigMapInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigMapInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockConfigMapInterfaceMockRecorder is the mock recorder for MockConfigMapInterface.
+type MockConfigMapInterfaceMockRecorder struct {
+	mock *MockConfigMapInterface
+}
+
+// NewMockConfigMapInterface creates a new mock instance.
+func NewMockConfigMapInterface(ctrl *gomock.Controller) *MockConfigMapInterface {
+	mock := &MockConfigMapInterface{ctrl: ctrl}
+	mock.recorder = &MockConfigMapInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigMapInterface) EXPECT() *MockConfigMapInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockConfigMapInterface) Create(ctx context.Context, configMap *v1.ConfigMap, opts v11.CreateOptions) (*v1.ConfigMap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, configMap, opts)
+	ret0, _ := ret[0].(*v1.ConfigMap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockConfigMapInterfaceMockRecorder) Create(ctx, configMap, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockConfigMapInterface)(nil).Create), ctx, configMap, opts)
+}
+
+// Get mocks base method.
+func (m *MockConfigMapInterface) Get(ctx context.Context, name string, opts v11.GetOptions) (*v1.ConfigMap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, name, opts)
+	ret0, _ := ret[0].(*v1.ConfigMap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockConfigMapInterfaceMockRecorder) Get(ctx, name, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockConfigMapInterface)(nil).Get), ctx, name, opts)
+}
+
+// Update mocks base method.
+func (m *MockConfigMapInterface) Update(ctx context.Context, configMap *v1.ConfigMap, opts v11.UpdateOptions) (*v1.ConfigMap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, configMap, opts)
+	ret0, _ := ret[0].(*v1.ConfigMap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockConfigMapInterfaceMockRecorder) Update(ctx, configMap, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockConfigMapInterface)(nil).Update), ctx, configMap, opts)
+}
+
 // MockVolumeAttachmentInterface is a mock of VolumeAttachmentInterface interface.
 type MockVolumeAttachmentInterface struct {
 	ctrl     *gomock.Controller
@@ -625,3 +757,111 @@ func (mr *MockStorageClassInterfaceMockRecorder) List(ctx, opts any) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockStorageClassInterface)(nil).List), ctx, opts)
 }
+
+// MockCSINodeInterface is a mock of CSINodeInterface interface.
+type MockCSINodeInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCSINodeInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockCSINodeInterfaceMockRecorder is the mock recorder for MockCSINodeInterface.
+type MockCSINodeInterfaceMockRecorder struct {
+	mock *MockCSINodeInterface
+}
+
+// NewMockCSINodeInterface creates a new mock instance.
+func NewMockCSINodeInterface(ctrl *gomock.Controller) *MockCSINodeInterface {
+	mock := &MockCSINodeInterface{ctrl: ctrl}
+	mock.recorder = &MockCSINodeInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCSINodeInterface) EXPECT() *MockCSINodeInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockCSINodeInterface) Get(ctx context.Context, name string, opts v11.GetOptions) (*v10.CSINode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, name, opts)
+	ret0, _ := ret[0].(*v10.CSINode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCSINodeInterfaceMockRecorder) Get(ctx, name, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCSINodeInterface)(nil).Get), ctx, name, opts)
+}
+
+// List mocks base method.
+func (m *MockCSINodeInterface) List(ctx context.Context, opts v11.ListOptions) (*v10.CSINodeList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, opts)
+	ret0, _ := ret[0].(*v10.CSINodeList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCSINodeInterfaceMockRecorder) List(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCSINodeInterface)(nil).List), ctx, opts)
+}
+
+// MockCSIDriverInterface is a mock of CSIDriverInterface interface.
+type MockCSIDriverInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCSIDriverInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockCSIDriverInterfaceMockRecorder is the mock recorder for MockCSIDriverInterface.
+type MockCSIDriverInterfaceMockRecorder struct {
+	mock *MockCSIDriverInterface
+}
+
+// NewMockCSIDriverInterface creates a new mock instance.
+func NewMockCSIDriverInterface(ctrl *gomock.Controller) *MockCSIDriverInterface {
+	mock := &MockCSIDriverInterface{ctrl: ctrl}
+	mock.recorder = &MockCSIDriverInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCSIDriverInterface) EXPECT() *MockCSIDriverInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockCSIDriverInterface) Get(ctx context.Context, name string, opts v11.GetOptions) (*v10.CSIDriver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, name, opts)
+	ret0, _ := ret[0].(*v10.CSIDriver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCSIDriverInterfaceMockRecorder) Get(ctx, name, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCSIDriverInterface)(nil).Get), ctx, name, opts)
+}
+
+// List mocks base method.
+func (m *MockCSIDriverInterface) List(ctx context.Context, opts v11.ListOptions) (*v10.CSIDriverList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, opts)
+	ret0, _ := ret[0].(*v10.CSIDriverList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCSIDriverInterfaceMockRecorder) List(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCSIDriverInterface)(nil).List), ctx, opts)
+}
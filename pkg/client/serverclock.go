@@ -0,0 +1,63 @@
+package client
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ServerClock follows the API server's clock through the Date header of its responses, so
+// times recorded about the cluster are not skewed by a local clock that drifted. It is safe
+// for concurrent use.
+type ServerClock struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	offset time.Duration
+}
+
+// NewServerClock creates a clock that matches the local one until a response is seen
+func NewServerClock() *ServerClock {
+	return &ServerClock{now: time.Now}
+}
+
+// Wrap returns a round tripper that records the server's clock from every response, for
+// use with rest.Config.Wrap
+func (c *ServerClock) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := rt.RoundTrip(req)
+		if err == nil {
+			c.observe(resp.Header.Get("Date"))
+		}
+		return resp, err
+	})
+}
+
+// observe updates the offset from the local clock to the server's from a Date header
+func (c *ServerClock) observe(date string) {
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// The header only has a resolution of a second, so smaller offsets are noise
+	c.offset = serverTime.Sub(c.now())
+	if c.offset > -time.Second && c.offset < time.Second {
+		c.offset = 0
+	}
+}
+
+// ServerTime converts a local time to the server's clock as of the last response
+func (c *ServerClock) ServerTime(local time.Time) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return local.Add(c.offset)
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,60 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+)
+
+var _ = Describe("ServerClock", func() {
+	var (
+		clock      *client.ServerClock
+		serverDate time.Time
+		server     *httptest.Server
+		httpClient *http.Client
+	)
+
+	BeforeEach(func() {
+		clock = client.NewServerClock()
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Date", serverDate.UTC().Format(http.TimeFormat))
+		}))
+		httpClient = &http.Client{Transport: clock.Wrap(http.DefaultTransport)}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func() {
+		resp, err := httpClient.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+	}
+
+	It("should match the local clock before any response", func() {
+		local := time.Now()
+		Expect(clock.ServerTime(local)).To(Equal(local))
+	})
+
+	It("should follow a server clock that is ahead of the local one", func() {
+		serverDate = time.Now().Add(time.Hour)
+		get()
+
+		local := time.Now()
+		Expect(clock.ServerTime(local)).To(BeTemporally("~", local.Add(time.Hour), 2*time.Second))
+	})
+
+	It("should ignore offsets within the resolution of the Date header", func() {
+		serverDate = time.Now()
+		get()
+
+		local := time.Now()
+		Expect(clock.ServerTime(local)).To(Equal(local))
+	})
+})
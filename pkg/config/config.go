@@ -0,0 +1,238 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// validSeverities lists the accepted severity values in increasing order
+var validSeverities = []string{"low", "medium", "high", "critical"}
+
+// Config holds detect settings loaded from a --config file. Empty fields fall back to the
+// flag defaults, and flags given on the command line take precedence over the file.
+type Config struct {
+	Methods               []string                `json:"methods,omitempty"`
+	Driver                string                  `json:"driver,omitempty"`
+	MinSeverity           string                  `json:"minSeverity,omitempty"`
+	NotifyOn              string                  `json:"notifyOn,omitempty"`
+	StuckThreshold        string                  `json:"stuckThreshold,omitempty"`
+	DriverStuckThresholds map[string]string       `json:"driverStuckThresholds,omitempty"`
+	DeviceBusyPatterns    []string                `json:"deviceBusyPatterns,omitempty"`
+	Suppressions          []types.SuppressionRule `json:"suppressions,omitempty"`
+	SeverityOverrides     map[string]string       `json:"severityOverrides,omitempty"`
+}
+
+// DefaultPath returns the config file detect reads when --config is not given,
+// ~/.kube/csi-scan.yaml, or "" when the home directory is unknown
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".kube", "csi-scan.yaml")
+}
+
+// Load reads a config file, rejecting keys that do not correspond to a setting
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes YAML or JSON config data, rejecting keys that do not correspond to a setting
+func Parse(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks every setting and returns one error per problem found, so all mistakes
+// in a file can be reported at once
+func (c *Config) Validate() []error {
+	var errs []error
+
+	validMethods := make(map[string]bool)
+	var methodNames []string
+	for _, method := range detect.AvailableMethods() {
+		validMethods[string(method.Method)] = true
+		methodNames = append(methodNames, string(method.Method))
+	}
+	for _, method := range c.Methods {
+		if !validMethods[method] {
+			errs = append(errs, fmt.Errorf("methods: unknown detection method %q - must be one of: %s", method, strings.Join(methodNames, ", ")))
+		}
+	}
+
+	if c.MinSeverity != "" && !isSeverity(c.MinSeverity) {
+		errs = append(errs, fmt.Errorf("minSeverity: invalid severity %q - must be one of: %s", c.MinSeverity, strings.Join(validSeverities, ", ")))
+	}
+	if c.NotifyOn != "" && !isSeverity(c.NotifyOn) {
+		errs = append(errs, fmt.Errorf("notifyOn: invalid severity %q - must be one of: %s", c.NotifyOn, strings.Join(validSeverities, ", ")))
+	}
+
+	if c.StuckThreshold != "" {
+		if _, err := parseThreshold(c.StuckThreshold); err != nil {
+			errs = append(errs, fmt.Errorf("stuckThreshold: %w", err))
+		}
+	}
+	for _, driver := range sortedKeys(c.DriverStuckThresholds) {
+		if driver == "" {
+			errs = append(errs, fmt.Errorf("driverStuckThresholds: driver name must not be empty"))
+			continue
+		}
+		if _, err := parseThreshold(c.DriverStuckThresholds[driver]); err != nil {
+			errs = append(errs, fmt.Errorf("driverStuckThresholds[%s]: %w", driver, err))
+		}
+	}
+
+	for i, pattern := range c.DeviceBusyPatterns {
+		if strings.TrimSpace(pattern) == "" {
+			errs = append(errs, fmt.Errorf("deviceBusyPatterns[%d]: pattern must not be empty", i))
+		}
+	}
+	errs = append(errs, ValidateSuppressions("suppressions", c.Suppressions)...)
+	for _, issueType := range sortedKeys(c.SeverityOverrides) {
+		if issueType == "" {
+			errs = append(errs, fmt.Errorf("severityOverrides: issue type must not be empty"))
+			continue
+		}
+		if !slices.Contains(types.IssueTypes, types.IssueType(issueType)) {
+			errs = append(errs, fmt.Errorf("severityOverrides: unknown issue type %q", issueType))
+		}
+		if severity := c.SeverityOverrides[issueType]; !isSeverity(severity) {
+			errs = append(errs, fmt.Errorf("severityOverrides[%s]: invalid severity %q - must be one of: %s", issueType, severity, strings.Join(validSeverities, ", ")))
+		}
+	}
+
+	return errs
+}
+
+// LoadSuppressions reads a file holding a list of suppression rules for detect --suppress
+// and validates every rule
+func LoadSuppressions(path string) ([]types.SuppressionRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suppression file: %w", err)
+	}
+
+	var rules []types.SuppressionRule
+	if err := yaml.UnmarshalStrict(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse suppression file: %w", err)
+	}
+	if errs := ValidateSuppressions(path, rules); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return rules, nil
+}
+
+// ValidateSuppressions checks that every rule sets at least one field and that its glob
+// patterns are well formed, prefixing errors with field
+func ValidateSuppressions(field string, rules []types.SuppressionRule) []error {
+	var errs []error
+	for i, rule := range rules {
+		if rule == (types.SuppressionRule{}) {
+			errs = append(errs, fmt.Errorf("%s[%d]: rule must set at least one of type, driver, node or pvc", field, i))
+			continue
+		}
+		globs := []struct{ name, pattern string }{
+			{"driver", rule.Driver},
+			{"node", rule.Node},
+			{"pvc", rule.PVC},
+		}
+		for _, glob := range globs {
+			if _, err := path.Match(glob.pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("%s[%d].%s: invalid glob pattern %q", field, i, glob.name, glob.pattern))
+			}
+		}
+	}
+	return errs
+}
+
+// Effective returns the settings a scan would use, with defaults filled in for every
+// field the file leaves unset
+func (c *Config) Effective() Config {
+	effective := *c
+	if len(effective.Methods) == 0 {
+		effective.Methods = []string{
+			string(types.VolumeAttachmentMethod),
+			string(types.CrossNodePVCMethod),
+			string(types.EventsMethod),
+		}
+	}
+	if effective.NotifyOn == "" {
+		effective.NotifyOn = "low"
+	}
+	if effective.StuckThreshold == "" {
+		effective.StuckThreshold = detect.DefaultStuckThreshold.String()
+	}
+	if len(effective.DeviceBusyPatterns) == 0 {
+		effective.DeviceBusyPatterns = detect.DefaultDeviceBusyPatterns
+	}
+	return effective
+}
+
+// StuckThresholdDuration returns the parsed stuck threshold, or zero if unset
+func (c *Config) StuckThresholdDuration() (time.Duration, error) {
+	if c.StuckThreshold == "" {
+		return 0, nil
+	}
+	return parseThreshold(c.StuckThreshold)
+}
+
+// SeverityOverrideValues returns the severity overrides keyed by issue type, with
+// severities normalized to lower case
+func (c *Config) SeverityOverrideValues() map[types.IssueType]types.IssueSeverity {
+	if len(c.SeverityOverrides) == 0 {
+		return nil
+	}
+	overrides := make(map[types.IssueType]types.IssueSeverity, len(c.SeverityOverrides))
+	for issueType, severity := range c.SeverityOverrides {
+		overrides[types.IssueType(issueType)] = types.IssueSeverity(strings.ToLower(severity))
+	}
+	return overrides
+}
+
+// parseThreshold parses a positive duration such as 10m
+func parseThreshold(value string) (time.Duration, error) {
+	threshold, err := time.ParseDuration(value)
+	if err != nil || threshold <= 0 {
+		return 0, fmt.Errorf("invalid duration %q - must be a positive duration such as 10m", value)
+	}
+	return threshold, nil
+}
+
+// isSeverity reports whether a value names a severity level
+func isSeverity(value string) bool {
+	for _, severity := range validSeverities {
+		if strings.EqualFold(value, severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns map keys in a stable order for deterministic error output
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
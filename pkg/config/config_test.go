@@ -0,0 +1,156 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/config"
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("Config", func() {
+	const validConfig = `
+methods: [volumeattachments, events]
+driver: cinder.csi.openstack.org
+minSeverity: medium
+notifyOn: high
+stuckThreshold: 10m
+driverStuckThresholds:
+  cinder.csi.openstack.org: 1h
+deviceBusyPatterns: ["resource busy"]
+`
+
+	Describe("Load", func() {
+		It("should load and validate a config file", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+			Expect(os.WriteFile(path, []byte(validConfig), 0o600)).To(Succeed())
+
+			cfg, err := config.Load(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(BeEmpty())
+			Expect(cfg.Driver).To(Equal("cinder.csi.openstack.org"))
+			Expect(cfg.DriverStuckThresholds).To(HaveKeyWithValue("cinder.csi.openstack.org", "1h"))
+
+			threshold, err := cfg.StuckThresholdDuration()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(threshold).To(Equal(10 * time.Minute))
+		})
+
+		It("should report a missing file", func() {
+			_, err := config.Load(filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+			Expect(err).To(MatchError(ContainSubstring("failed to read config file")))
+		})
+	})
+
+	Describe("DefaultPath", func() {
+		It("should point at csi-scan.yaml in the kubeconfig directory", func() {
+			home, err := os.UserHomeDir()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.DefaultPath()).To(Equal(filepath.Join(home, ".kube", "csi-scan.yaml")))
+		})
+	})
+
+	Describe("Parse", func() {
+		It("should reject unknown keys", func() {
+			_, err := config.Parse([]byte("stuckThreshhold: 10m\n"))
+			Expect(err).To(MatchError(ContainSubstring("stuckThreshhold")))
+		})
+
+		It("should reject values of the wrong type", func() {
+			_, err := config.Parse([]byte("methods: events\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Validate", func() {
+		DescribeTable("should report each invalid setting",
+			func(data, expected string) {
+				cfg, err := config.Parse([]byte(data))
+				Expect(err).NotTo(HaveOccurred())
+
+				errs := cfg.Validate()
+				Expect(errs).To(HaveLen(1))
+				Expect(errs[0]).To(MatchError(ContainSubstring(expected)))
+			},
+			Entry("unknown method", "methods: [events, inotify]\n", `methods: unknown detection method "inotify"`),
+			Entry("invalid minimum severity", "minSeverity: urgent\n", `minSeverity: invalid severity "urgent"`),
+			Entry("invalid notify severity", "notifyOn: sometimes\n", `notifyOn: invalid severity "sometimes"`),
+			Entry("unparseable stuck threshold", "stuckThreshold: ten minutes\n", `stuckThreshold: invalid duration "ten minutes"`),
+			Entry("non-positive stuck threshold", "stuckThreshold: 0s\n", `stuckThreshold: invalid duration "0s"`),
+			Entry("invalid driver threshold", "driverStuckThresholds:\n  ebs.csi.aws.com: 5x\n", `driverStuckThresholds[ebs.csi.aws.com]: invalid duration "5x"`),
+			Entry("empty device busy pattern", "deviceBusyPatterns: ['  ']\n", "deviceBusyPatterns[0]: pattern must not be empty"),
+			Entry("empty suppression rule", "suppressions: [{}]\n", "suppressions[0]: rule must set at least one of type, driver, node or pvc"),
+			Entry("invalid suppression glob", "suppressions: [{node: 'node-['}]\n", `suppressions[0].node: invalid glob pattern "node-["`),
+			Entry("invalid severity override", "severityOverrides:\n  multiple-attachments: urgent\n", `severityOverrides[multiple-attachments]: invalid severity "urgent"`),
+			Entry("unknown severity override type", "severityOverrides:\n  multi-attach: high\n", `severityOverrides: unknown issue type "multi-attach"`),
+		)
+
+		It("should report every problem at once", func() {
+			cfg, err := config.Parse([]byte("minSeverity: urgent\nstuckThreshold: soon\nnotifyOn: sometimes\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(HaveLen(3))
+		})
+
+		It("should accept an empty config", func() {
+			cfg, err := config.Parse([]byte(""))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(BeEmpty())
+		})
+	})
+
+	Describe("Effective", func() {
+		It("should fill in defaults for unset fields", func() {
+			cfg, err := config.Parse([]byte("driver: ebs.csi.aws.com\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			effective := cfg.Effective()
+			Expect(effective.Driver).To(Equal("ebs.csi.aws.com"))
+			Expect(effective.Methods).To(Equal([]string{"volumeattachments", "cross-node-pvc", "events"}))
+			Expect(effective.NotifyOn).To(Equal("low"))
+			Expect(effective.StuckThreshold).To(Equal(detect.DefaultStuckThreshold.String()))
+			Expect(effective.DeviceBusyPatterns).To(Equal(detect.DefaultDeviceBusyPatterns))
+			Expect(cfg.Methods).To(BeEmpty())
+		})
+	})
+
+	Describe("SeverityOverrideValues", func() {
+		It("should key overrides by issue type with normalized severities", func() {
+			cfg, err := config.Parse([]byte("severityOverrides:\n  multiple-attachments: Critical\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Validate()).To(BeEmpty())
+			Expect(cfg.SeverityOverrideValues()).To(Equal(map[types.IssueType]types.IssueSeverity{
+				types.MultipleAttachments: types.SeverityCritical,
+			}))
+		})
+	})
+
+	Describe("LoadSuppressions", func() {
+		It("should load a list of rules", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "suppress.yaml")
+			Expect(os.WriteFile(path, []byte("- pvc: 'default/scratch-*'\n- type: storage-class-misconfiguration\n  driver: nfs.csi.k8s.io\n"), 0o600)).To(Succeed())
+
+			rules, err := config.LoadSuppressions(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rules).To(Equal([]types.SuppressionRule{
+				{PVC: "default/scratch-*"},
+				{Type: types.StorageClassMisconfiguration, Driver: "nfs.csi.k8s.io"},
+			}))
+		})
+
+		It("should reject unknown fields and invalid rules", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "suppress.yaml")
+			Expect(os.WriteFile(path, []byte("- namespace: default\n"), 0o600)).To(Succeed())
+			_, err := config.LoadSuppressions(path)
+			Expect(err).To(MatchError(ContainSubstring("namespace")))
+
+			Expect(os.WriteFile(path, []byte("- node: '['\n"), 0o600)).To(Succeed())
+			_, err = config.LoadSuppressions(path)
+			Expect(err).To(MatchError(ContainSubstring("invalid glob pattern")))
+		})
+	})
+})
@@ -0,0 +1,204 @@
+package detect
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// maxRecentEvents is how many recent events a detailed analysis lists
+const maxRecentEvents = 50
+
+// DefaultTopPVCs is how many PVCs a detailed analysis ranks when no count is given
+const DefaultTopPVCs = 10
+
+// AnalyzeResult builds the detailed analysis from a saved detection result instead of the
+// cluster. A result only holds issues, so the VolumeAttachment counts and the attachments by
+// node cover the attachments that had issues, node PVC usage counts the issues on each node
+// and PVC, and the recent events are those the events method reported.
+func AnalyzeResult(result *types.DetectionResult, options types.DetectionOptions) *DetailedAnalysis {
+	analysis := &DetailedAnalysis{Driver: options.TargetDriver}
+
+	var issues []types.CSIMountIssue
+	for _, issue := range result.Issues {
+		if options.TargetDriver == "" || issue.Driver == options.TargetDriver {
+			issues = append(issues, issue)
+		}
+	}
+
+	if slices.Contains(options.Methods, types.VolumeAttachmentMethod) {
+		analyzeVolumeAttachmentIssues(analysis, issues)
+	}
+	if slices.Contains(options.Methods, types.CrossNodePVCMethod) {
+		analysis.NodePVCUsage = nodePVCUsageOf(issues)
+		analysis.TopPVCs = TopPVCs(analysis.NodePVCUsage, options.TopPVCs)
+	}
+	if slices.Contains(options.Methods, types.EventsMethod) {
+		analysis.RecentEvents = recentEventsOf(issues)
+	}
+	if slices.Contains(options.Methods, types.MetricsMethod) {
+		metricsDetector := NewMetricsDetector("", options.TargetDriver)
+		analysis.MetricQueries = metricsDetector.GetMetricQueries()
+		analysis.RecommendedAlerts = metricsDetector.GetRecommendedAlerts()
+	}
+
+	return analysis
+}
+
+// analyzeVolumeAttachmentIssues counts the VolumeAttachments named by the sources of the
+// issues the VolumeAttachment method reported, and groups those of issues on one node by
+// that node
+func analyzeVolumeAttachmentIssues(analysis *DetailedAnalysis, issues []types.CSIMountIssue) {
+	seen := make(map[string]bool)
+	attached := make(map[string]bool)
+	failed := make(map[string]bool)
+	infos := make(map[string]*types.VolumeAttachmentInfo) // name -> attachment, when its node is known
+	for _, issue := range issues {
+		if issue.DetectedBy != types.VolumeAttachmentMethod {
+			continue
+		}
+		for _, source := range issue.Sources {
+			if source.Kind != "VolumeAttachment" {
+				continue
+			}
+			seen[source.Name] = true
+			switch issue.Type {
+			case types.FailedAttachVolume:
+				failed[source.Name] = true
+			case types.StuckVolumeDetachment:
+				// A failed detach leaves the volume attached
+				failed[source.Name] = true
+				attached[source.Name] = true
+			case types.MultipleAttachments, types.AttachedWithoutClaim:
+				attached[source.Name] = true
+			}
+
+			if issue.Node == "" {
+				continue
+			}
+			info := infos[source.Name]
+			if info == nil {
+				info = &types.VolumeAttachmentInfo{Name: source.Name, Node: issue.Node, VolumeHandle: issue.Volume, Driver: issue.Driver}
+				infos[source.Name] = info
+			}
+			if message := issue.Metadata["attach_error"]; message != "" {
+				info.AttachError = message
+			}
+			if message := issue.Metadata["detach_error"]; message != "" {
+				info.DetachError = message
+			}
+		}
+	}
+
+	analysis.VolumeAttachmentCount = len(seen)
+	analysis.AttachedVolumeCount = len(attached)
+	analysis.VolumeAttachmentErrors = len(failed)
+
+	if len(infos) == 0 {
+		return
+	}
+	analysis.VolumeAttachmentsByNode = make(map[string][]types.VolumeAttachmentInfo)
+	for name, info := range infos {
+		info.Attached = attached[name]
+		analysis.VolumeAttachmentsByNode[info.Node] = append(analysis.VolumeAttachmentsByNode[info.Node], *info)
+	}
+	for _, byName := range analysis.VolumeAttachmentsByNode {
+		sort.Slice(byName, func(i, j int) bool { return byName[i].Name < byName[j].Name })
+	}
+}
+
+// nodePVCUsageOf counts the issues reported for each PVC on each node, sorted by node
+func nodePVCUsageOf(issues []types.CSIMountIssue) []types.NodePVCUsage {
+	counts := make(map[string]map[string]int) // node -> pvc -> count
+	for _, issue := range issues {
+		if issue.Node == "" || issue.PVC == "" {
+			continue
+		}
+		if counts[issue.Node] == nil {
+			counts[issue.Node] = make(map[string]int)
+		}
+		counts[issue.Node][fmt.Sprintf("%s/%s", issue.Namespace, issue.PVC)]++
+	}
+
+	usage := make([]types.NodePVCUsage, 0, len(counts))
+	for node, pvcCounts := range counts {
+		total := 0
+		for _, count := range pvcCounts {
+			total += count
+		}
+		usage = append(usage, types.NodePVCUsage{Node: node, PVCCounts: pvcCounts, Total: total})
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Node < usage[j].Node })
+	return usage
+}
+
+// TopPVCs ranks PVCs by their pod references summed over every node, then by how many
+// nodes reference them, and returns the first n (DefaultTopPVCs when n is not positive).
+// A PVC referenced from several nodes at once is often a ReadWriteOnce volume used as
+// if it were ReadWriteMany.
+func TopPVCs(usage []types.NodePVCUsage, n int) []types.PVCUsageSummary {
+	if n <= 0 {
+		n = DefaultTopPVCs
+	}
+
+	byPVC := make(map[string]*types.PVCUsageSummary)
+	for _, node := range usage {
+		for pvc, count := range node.PVCCounts {
+			if count <= 0 {
+				continue
+			}
+			summary, ok := byPVC[pvc]
+			if !ok {
+				summary = &types.PVCUsageSummary{PVC: pvc}
+				byPVC[pvc] = summary
+			}
+			summary.References += count
+			summary.NodeCount++
+		}
+	}
+
+	top := make([]types.PVCUsageSummary, 0, len(byPVC))
+	for _, summary := range byPVC {
+		top = append(top, *summary)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].References != top[j].References {
+			return top[i].References > top[j].References
+		}
+		if top[i].NodeCount != top[j].NodeCount {
+			return top[i].NodeCount > top[j].NodeCount
+		}
+		return top[i].PVC < top[j].PVC
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// recentEventsOf rebuilds the events behind the issues the events method reported from
+// the event details kept in their metadata
+func recentEventsOf(issues []types.CSIMountIssue) []types.EventInfo {
+	var events []types.EventInfo
+	for _, issue := range issues {
+		if issue.DetectedBy != types.EventsMethod {
+			continue
+		}
+		events = append(events, types.EventInfo{
+			Type:      issue.Metadata["event_type"],
+			Reason:    issue.Metadata["event_reason"],
+			Message:   issue.Metadata["full_event_message"],
+			Object:    issue.Metadata["involved_object"],
+			Namespace: issue.Metadata["event_namespace"],
+			Time:      metav1.Time{Time: issue.OccurredAt},
+		})
+		if len(events) >= maxRecentEvents {
+			break
+		}
+	}
+	return events
+}
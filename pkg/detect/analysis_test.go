@@ -0,0 +1,93 @@
+package detect_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("TopPVCs", func() {
+	usage := []types.NodePVCUsage{
+		{Node: "node-1", PVCCounts: map[string]int{"shop/cart": 4, "shop/orders": 2, "batch/scratch": 1}, Total: 7},
+		{Node: "node-2", PVCCounts: map[string]int{"shop/cart": 3, "shop/orders": 5}, Total: 8},
+		{Node: "node-3", PVCCounts: map[string]int{"shop/cart": 1, "batch/scratch": 6}, Total: 7},
+	}
+
+	It("should rank PVCs by references summed across nodes and count the nodes referencing each", func() {
+		Expect(detect.TopPVCs(usage, 0)).To(Equal([]types.PVCUsageSummary{
+			{PVC: "shop/cart", References: 8, NodeCount: 3},
+			{PVC: "batch/scratch", References: 7, NodeCount: 2},
+			{PVC: "shop/orders", References: 7, NodeCount: 2},
+		}))
+	})
+
+	It("should break ties on references by the number of nodes", func() {
+		top := detect.TopPVCs([]types.NodePVCUsage{
+			{Node: "node-1", PVCCounts: map[string]int{"a/local": 4, "a/shared": 2}},
+			{Node: "node-2", PVCCounts: map[string]int{"a/shared": 2}},
+		}, 0)
+		Expect(top).To(HaveLen(2))
+		Expect(top[0]).To(Equal(types.PVCUsageSummary{PVC: "a/shared", References: 4, NodeCount: 2}))
+		Expect(top[1]).To(Equal(types.PVCUsageSummary{PVC: "a/local", References: 4, NodeCount: 1}))
+	})
+
+	It("should keep only the top n", func() {
+		top := detect.TopPVCs(usage, 1)
+		Expect(top).To(HaveLen(1))
+		Expect(top[0].PVC).To(Equal("shop/cart"))
+	})
+
+	It("should return an empty ranking without usage", func() {
+		Expect(detect.TopPVCs(nil, 5)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("AnalyzeResult", func() {
+	It("should group the VolumeAttachments of issues on one node by that node", func() {
+		result := &types.DetectionResult{
+			Issues: []types.CSIMountIssue{
+				{
+					Type:       types.FailedAttachVolume,
+					Node:       "node-1",
+					DetectedBy: types.VolumeAttachmentMethod,
+					Metadata:   map[string]string{"attach_error": "rpc error: volume in use"},
+					Sources:    []types.SourceRef{{Kind: "VolumeAttachment", Name: "va-2"}},
+				},
+				{
+					Type:       types.StuckVolumeDetachment,
+					Node:       "node-1",
+					DetectedBy: types.VolumeAttachmentMethod,
+					Metadata:   map[string]string{"detach_error": "rpc error: device busy"},
+					Sources:    []types.SourceRef{{Kind: "VolumeAttachment", Name: "va-1"}},
+				},
+				{
+					Type:       types.StuckVolumeAttachment,
+					Node:       "node-2",
+					DetectedBy: types.VolumeAttachmentMethod,
+					Sources:    []types.SourceRef{{Kind: "VolumeAttachment", Name: "va-3"}},
+				},
+				{
+					// Spans several nodes, so it is counted but not grouped
+					Type:       types.MultipleAttachments,
+					DetectedBy: types.VolumeAttachmentMethod,
+					Sources:    []types.SourceRef{{Kind: "VolumeAttachment", Name: "va-4"}},
+				},
+			},
+		}
+
+		analysis := detect.AnalyzeResult(result, types.DetectionOptions{Methods: []types.DetectionMethod{types.VolumeAttachmentMethod}})
+		Expect(analysis.VolumeAttachmentCount).To(Equal(4))
+		Expect(analysis.VolumeAttachmentErrors).To(Equal(2))
+		Expect(analysis.VolumeAttachmentsByNode).To(Equal(map[string][]types.VolumeAttachmentInfo{
+			"node-1": {
+				{Name: "va-1", Node: "node-1", Attached: true, DetachError: "rpc error: device busy"},
+				{Name: "va-2", Node: "node-1", AttachError: "rpc error: volume in use"},
+			},
+			"node-2": {
+				{Name: "va-3", Node: "node-2"},
+			},
+		}))
+	})
+})
@@ -0,0 +1,82 @@
+package detect
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// DefaultEnrichmentErrorLimit is how many consecutive identical lookup errors disable
+// further enrichment lookups for a run
+const DefaultEnrichmentErrorLimit = 5
+
+// CircuitBreaker stops best-effort enrichment lookups once they keep failing the same
+// way, for example while an admission webhook or the API server is down, so a run does
+// not keep hammering the API for context it will not get. It is safe for concurrent use.
+type CircuitBreaker struct {
+	mu          sync.Mutex
+	name        string
+	limit       int
+	lastErr     string
+	consecutive int
+	open        bool
+}
+
+// NewCircuitBreaker creates a breaker for the named lookups that opens after limit
+// consecutive identical errors. A non-positive limit uses DefaultEnrichmentErrorLimit.
+func NewCircuitBreaker(name string, limit int) *CircuitBreaker {
+	if limit <= 0 {
+		limit = DefaultEnrichmentErrorLimit
+	}
+	return &CircuitBreaker{
+		name:  name,
+		limit: limit,
+	}
+}
+
+// Allow reports whether lookups may still be made
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.open
+}
+
+// Record notes the outcome of a lookup. A success or a different kind of error resets the
+// count; the breaker opens, logging once, when the limit of errors of the same kind in a
+// row is reached. Callers should record expected outcomes such as NotFound as successes.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.open {
+		return
+	}
+	if err == nil {
+		b.lastErr, b.consecutive = "", 0
+		return
+	}
+	if kind := errorKind(err); kind != b.lastErr {
+		b.lastErr, b.consecutive = kind, 0
+	}
+	b.consecutive++
+
+	if b.consecutive >= b.limit {
+		b.open = true
+		log.Warn().Err(err).Str("lookup", b.name).Int("consecutive_errors", b.consecutive).
+			Msg("enrichment disabled for this run after repeated identical errors")
+	}
+}
+
+// errorKind identifies the kind of a lookup error. API errors carry the name of the object
+// looked up in their message, so they are told apart by reason and status code instead,
+// making the same failure for different objects count as identical.
+func errorKind(err error) string {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return fmt.Sprintf("%s/%d", status.Status().Reason, status.Status().Code)
+	}
+	return err.Error()
+}
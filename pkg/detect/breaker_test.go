@@ -0,0 +1,64 @@
+package detect_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	webhookDown := errors.New(`Internal error occurred: failed calling webhook "validate.example.com"`)
+
+	It("should open after the limit of identical errors in a row", func() {
+		breaker := detect.NewCircuitBreaker("test", 3)
+		breaker.Record(webhookDown)
+		breaker.Record(webhookDown)
+		Expect(breaker.Allow()).To(BeTrue())
+
+		breaker.Record(webhookDown)
+		Expect(breaker.Allow()).To(BeFalse())
+
+		// Once open it stays open for the run
+		breaker.Record(nil)
+		Expect(breaker.Allow()).To(BeFalse())
+	})
+
+	It("should reset the count on a success or a different error", func() {
+		breaker := detect.NewCircuitBreaker("test", 2)
+		breaker.Record(webhookDown)
+		breaker.Record(nil)
+		breaker.Record(webhookDown)
+		breaker.Record(errors.New("connection refused"))
+		Expect(breaker.Allow()).To(BeTrue())
+	})
+
+	It("should count the same API error about different objects as identical", func() {
+		breaker := detect.NewCircuitBreaker("test", 3)
+		for _, name := range []string{"pv-a", "pv-b", "pv-c"} {
+			breaker.Record(apierrors.NewForbidden(corev1.Resource("persistentvolumes"), name, errors.New("access denied")))
+		}
+		Expect(breaker.Allow()).To(BeFalse())
+	})
+
+	It("should tell API errors of different reasons apart", func() {
+		breaker := detect.NewCircuitBreaker("test", 2)
+		breaker.Record(apierrors.NewForbidden(corev1.Resource("persistentvolumes"), "pv-a", errors.New("access denied")))
+		breaker.Record(apierrors.NewServiceUnavailable("try again later"))
+		Expect(breaker.Allow()).To(BeTrue())
+	})
+
+	It("should default a non-positive limit", func() {
+		breaker := detect.NewCircuitBreaker("test", 0)
+		for i := 0; i < detect.DefaultEnrichmentErrorLimit-1; i++ {
+			breaker.Record(webhookDown)
+		}
+		Expect(breaker.Allow()).To(BeTrue())
+		breaker.Record(webhookDown)
+		Expect(breaker.Allow()).To(BeFalse())
+	})
+})
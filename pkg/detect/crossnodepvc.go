@@ -2,20 +2,37 @@ package detect
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/jdambly/kubectl-csi-scan/pkg/client"
 	"github.com/jdambly/kubectl-csi-scan/pkg/types"
 )
 
+// errMissingPVC is returned when a pod references a PVC that does not exist
+var errMissingPVC = errors.New("PVC not found")
+
+// migratedToAnnotation is set on in-tree PVs whose operations are handled by a CSI driver
+const migratedToAnnotation = "pv.kubernetes.io/migrated-to"
+
 // CrossNodePVCDetector implements detection via cross-node PVC usage analysis
 type CrossNodePVCDetector struct {
-	client       client.KubernetesClient
-	targetDriver string
+	client            client.KubernetesClient
+	targetDriver      string
+	csiOnly           bool
+	strictDriverMatch bool
+	nodePVCWarn       int
+	storageClass      string
+	namespace         string
+	lookupErrorLimit  int
+	resourceVersion   string // of the last pod list Detect read
 }
 
 // NewCrossNodePVCDetector creates a new cross-node PVC detector
@@ -26,56 +43,156 @@ func NewCrossNodePVCDetector(kubeClient client.KubernetesClient, targetDriver st
 	}
 }
 
+// SetCSIOnly limits detection to PVCs bound to CSI-backed PersistentVolumes,
+// skipping NFS, hostPath and other in-tree volumes
+func (d *CrossNodePVCDetector) SetCSIOnly(csiOnly bool) {
+	d.csiOnly = csiOnly
+}
+
+// SetStrictDriverMatch excludes PVCs whose driver cannot be resolved or does not
+// exactly equal the target driver, instead of including them when uncertain
+func (d *CrossNodePVCDetector) SetStrictDriverMatch(strict bool) {
+	d.strictDriverMatch = strict
+}
+
+// SetNodePVCWarn reports, as informational capacity context, nodes whose pods hold more
+// than threshold PVC references in total. A threshold of 0 disables the check.
+func (d *CrossNodePVCDetector) SetNodePVCWarn(threshold int) {
+	d.nodePVCWarn = threshold
+}
+
+// SetStorageClass limits detection to PVCs whose spec names this StorageClass. Pods
+// blocked on PVCs that do not exist are skipped, as their class is unknown. Empty
+// inspects every PVC.
+func (d *CrossNodePVCDetector) SetStorageClass(name string) {
+	d.storageClass = name
+}
+
+// SetEnrichmentErrorLimit sets how many consecutive identical errors looking up the
+// drivers of PVCs stop those lookups for the rest of a run. Zero keeps
+// DefaultEnrichmentErrorLimit.
+func (d *CrossNodePVCDetector) SetEnrichmentErrorLimit(limit int) {
+	d.lookupErrorLimit = limit
+}
+
+// SetNamespace limits the pods listed to one namespace. Empty lists pods in all
+// namespaces.
+func (d *CrossNodePVCDetector) SetNamespace(namespace string) {
+	d.namespace = namespace
+}
+
 // Detect finds PVCs that appear to be used across multiple nodes
 func (d *CrossNodePVCDetector) Detect(ctx context.Context) ([]types.CSIMountIssue, error) {
 	var issues []types.CSIMountIssue
 
-	// Get all pods across all namespaces
-	pods, err := d.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list pods: %w", err)
-	}
-
 	// Track PVC usage: pvcKey (namespace/name) -> map[nodeName]podCount
 	pvcNodeUsage := make(map[string]map[string]int)
-	pvcNamespaces := make(map[string]string) // pvcKey -> namespace
-	pvcDrivers := make(map[string]string)    // pvcKey -> driver (if determinable)
-
-	for _, pod := range pods.Items {
-		if pod.Spec.NodeName == "" {
-			continue // Skip unscheduled pods
-		}
-
-		// Check each volume in the pod
-		for _, volume := range pod.Spec.Volumes {
-			if volume.PersistentVolumeClaim != nil {
-				pvcKey := fmt.Sprintf("%s/%s", pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
-				pvcNamespaces[pvcKey] = pod.Namespace
-
-				// Initialize maps if needed
-				if pvcNodeUsage[pvcKey] == nil {
-					pvcNodeUsage[pvcKey] = make(map[string]int)
+	pvcNamespaces := make(map[string]string)           // pvcKey -> namespace
+	pvcDrivers := make(map[string]string)              // pvcKey -> driver (if determinable)
+	pvcIsCSI := make(map[string]bool)                  // pvcKey -> bound PV is CSI-backed
+	pvcLastPod := make(map[string]time.Time)           // pvcKey -> newest referencing pod creation time
+	pvcPods := make(map[string][]types.SourceRef)      // pvcKey -> pods referencing the PVC
+	missingPVCs := make(map[string]bool)               // pvcKey -> PVC does not exist
+	inClass := make(map[string]bool)                   // pvcKey -> PVC is in the StorageClass, when filtering
+	pvcOwners := make(map[string]map[workloadRef]bool) // pvcKey -> workloads owning the referencing pods
+	drivers := NewCircuitBreaker("PVC driver lookup", d.lookupErrorLimit)
+
+	// Get all pods across all namespaces, a page at a time
+	err := listPods(ctx, d.client.CoreV1().Pods(d.namespace), func(pods *corev1.PodList) error {
+		// Every page of a list is served at the resourceVersion of the first
+		d.resourceVersion = pods.ResourceVersion
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName == "" {
+				if d.storageClass != "" {
+					continue
+				}
+				// Unscheduled pods can't share a PVC across nodes, but the scheduler
+				// refuses pods whose PVC is missing, so check those
+				if isUnschedulable(pod) {
+					for _, claim := range podClaims(pod) {
+						pvcKey := fmt.Sprintf("%s/%s", pod.Namespace, claim)
+						if missingPVCs[pvcKey] || d.pvcMissing(ctx, pod.Namespace, claim) {
+							missingPVCs[pvcKey] = true
+							pvcNamespaces[pvcKey] = pod.Namespace
+							trackPod(pvcKey, pod, pvcPods, pvcLastPod, pvcOwners)
+						}
+					}
 				}
+				continue
+			}
 
-				// Count usage on this node
-				pvcNodeUsage[pvcKey][pod.Spec.NodeName]++
+			// Check each volume in the pod
+			for _, volume := range pod.Spec.Volumes {
+				if volume.PersistentVolumeClaim != nil {
+					pvcKey := fmt.Sprintf("%s/%s", pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+					if d.storageClass != "" {
+						if _, resolved := inClass[pvcKey]; !resolved {
+							in, err := d.inStorageClass(ctx, pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+							if err != nil {
+								return err
+							}
+							inClass[pvcKey] = in
+						}
+						if !inClass[pvcKey] {
+							continue
+						}
+					}
+					pvcNamespaces[pvcKey] = pod.Namespace
 
-				// Try to determine driver from PVC if we haven't yet
-				if _, exists := pvcDrivers[pvcKey]; !exists {
-					driver, err := d.getPVCDriver(ctx, pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
-					if err == nil && driver != "" {
-						pvcDrivers[pvcKey] = driver
+					// Initialize maps if needed
+					if pvcNodeUsage[pvcKey] == nil {
+						pvcNodeUsage[pvcKey] = make(map[string]int)
+					}
+
+					// Count usage on this node
+					pvcNodeUsage[pvcKey][pod.Spec.NodeName]++
+					trackPod(pvcKey, pod, pvcPods, pvcLastPod, pvcOwners)
+
+					// Try to determine driver from PVC if we haven't yet
+					if _, exists := pvcDrivers[pvcKey]; !exists && !missingPVCs[pvcKey] && drivers.Allow() {
+						driver, isCSI, err := d.getPVCDriver(ctx, pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+						if errors.Is(err, errMissingPVC) {
+							missingPVCs[pvcKey] = true
+							drivers.Record(nil)
+						} else {
+							drivers.Record(ignoreNotFound(err))
+						}
+						if err == nil && driver != "" {
+							pvcDrivers[pvcKey] = driver
+							pvcIsCSI[pvcKey] = isCSI
+						}
 					}
 				}
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Report pods blocked on PVCs that do not exist. Their driver is unknown, so they
+	// are skipped like other unresolved PVCs when only CSI or strict matches are wanted.
+	for pvcKey := range missingPVCs {
+		if d.csiOnly || (d.targetDriver != "" && d.strictDriverMatch) {
+			continue
+		}
+		issue := d.missingPVCIssue(pvcKey, pvcNamespaces[pvcKey], pvcNodeUsage[pvcKey], pvcPods[pvcKey], pvcLastPod[pvcKey])
+		addOwnerMetadata(issue.Metadata, pvcNamespaces[pvcKey], pvcOwners[pvcKey])
+		issues = append(issues, issue)
 	}
 
 	// Analyze usage patterns for potential issues
 	for pvcKey, nodeUsage := range pvcNodeUsage {
+		// Skip non-CSI volumes, including PVCs whose backing could not be resolved
+		if d.csiOnly && !pvcIsCSI[pvcKey] {
+			continue
+		}
+
 		// Filter by driver if specified
 		if d.targetDriver != "" {
-			if driver, exists := pvcDrivers[pvcKey]; exists && !strings.Contains(driver, d.targetDriver) {
+			driver, exists := pvcDrivers[pvcKey]
+			if !d.matchesTargetDriver(driver, exists) {
 				continue
 			}
 		}
@@ -95,6 +212,12 @@ func (d *CrossNodePVCDetector) Detect(ctx context.Context) ([]types.CSIMountIssu
 			for node, count := range nodeUsage {
 				nodeList = append(nodeList, fmt.Sprintf("%s(%d)", node, count))
 			}
+			sort.Strings(nodeList)
+
+			description := fmt.Sprintf("PVC used on %d nodes: %v (total %d pod references)", nodeCount, nodeList, totalUsage)
+			if set := statefulSetOwner(pvcOwners[pvcKey]); set != "" {
+				description += fmt.Sprintf("; StatefulSet %s gives each replica its own claim, so pods sharing one across nodes is especially suspicious", set)
+			}
 
 			issue := types.CSIMountIssue{
 				Type:        types.MultipleAttachments,
@@ -102,15 +225,18 @@ func (d *CrossNodePVCDetector) Detect(ctx context.Context) ([]types.CSIMountIssu
 				PVC:         pvcKey,
 				Namespace:   pvcNamespaces[pvcKey],
 				Driver:      pvcDrivers[pvcKey],
-				Description: fmt.Sprintf("PVC used on %d nodes: %v (total %d pod references)", nodeCount, nodeList, totalUsage),
+				Description: description,
 				DetectedBy:  types.CrossNodePVCMethod,
 				DetectedAt:  time.Now(),
+				OccurredAt:  pvcLastPod[pvcKey],
 				Metadata: map[string]string{
 					"node_count":    fmt.Sprintf("%d", nodeCount),
 					"total_usage":   fmt.Sprintf("%d", totalUsage),
 					"nodes":         strings.Join(nodeList, ","),
 				},
+				Sources: pvcSources(pvcKey, pvcPods[pvcKey]),
 			}
+			addOwnerMetadata(issue.Metadata, pvcNamespaces[pvcKey], pvcOwners[pvcKey])
 			issues = append(issues, issue)
 		} else if totalUsage > 10 {
 			// High usage on single node - potential mount leak
@@ -130,36 +256,91 @@ func (d *CrossNodePVCDetector) Detect(ctx context.Context) ([]types.CSIMountIssu
 				Description: fmt.Sprintf("High PVC usage on single node: %d references to %s", totalUsage, pvcKey),
 				DetectedBy:  types.CrossNodePVCMethod,
 				DetectedAt:  time.Now(),
+				OccurredAt:  pvcLastPod[pvcKey],
 				Metadata: map[string]string{
 					"usage_count": fmt.Sprintf("%d", totalUsage),
 					"node":        node,
 				},
+				Sources: pvcSources(pvcKey, pvcPods[pvcKey]),
 			}
+			addOwnerMetadata(issue.Metadata, pvcNamespaces[pvcKey], pvcOwners[pvcKey])
 			issues = append(issues, issue)
 		}
 	}
 
+	if d.nodePVCWarn > 0 {
+		nodeIssues, err := d.detectBusyNodes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, nodeIssues...)
+	}
+
+	return issues, nil
+}
+
+// detectBusyNodes reports nodes whose total PVC references exceed the node warning
+// threshold. Unlike the per-PVC high usage check this says nothing about any one volume,
+// so the issues are informational.
+func (d *CrossNodePVCDetector) detectBusyNodes(ctx context.Context) ([]types.CSIMountIssue, error) {
+	usage, err := d.GetNodePVCUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []types.CSIMountIssue
+	for _, node := range usage {
+		if node.Total <= d.nodePVCWarn {
+			continue
+		}
+		issues = append(issues, types.CSIMountIssue{
+			Type:        types.HighNodePVCUsage,
+			Severity:    types.SeverityLow,
+			Node:        node.Node,
+			Driver:      d.targetDriver,
+			Description: fmt.Sprintf("Node %s has %d PVC references across %d PVCs, above the warning threshold of %d", node.Node, node.Total, len(node.PVCCounts), d.nodePVCWarn),
+			DetectedBy:  types.CrossNodePVCMethod,
+			DetectedAt:  time.Now(),
+			Metadata: map[string]string{
+				"total_usage": fmt.Sprintf("%d", node.Total),
+				"pvc_count":   fmt.Sprintf("%d", len(node.PVCCounts)),
+				"threshold":   fmt.Sprintf("%d", d.nodePVCWarn),
+			},
+		})
+	}
+
+	// Map iteration in GetNodePVCUsage is unordered; keep the output stable
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Node < issues[j].Node })
 	return issues, nil
 }
 
-// getPVCDriver attempts to determine the CSI driver for a PVC
-func (d *CrossNodePVCDetector) getPVCDriver(ctx context.Context, namespace, pvcName string) (string, error) {
+// getPVCDriver attempts to determine the CSI driver for a PVC. The returned bool
+// reports whether the bound PV is served by a CSI driver.
+func (d *CrossNodePVCDetector) getPVCDriver(ctx context.Context, namespace, pvcName string) (string, bool, error) {
 	// Get the PVC
 	pvc, err := d.client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", false, fmt.Errorf("%w: %s/%s", errMissingPVC, namespace, pvcName)
+	}
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	// Get the bound PV if it exists
 	if pvc.Spec.VolumeName != "" {
 		pv, err := d.client.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{})
 		if err != nil {
-			return "", err
+			return "", false, err
 		}
 
 		// Check if it's a CSI volume
 		if pv.Spec.CSI != nil {
-			return pv.Spec.CSI.Driver, nil
+			return pv.Spec.CSI.Driver, true, nil
+		}
+
+		// In-tree volumes handled through CSI migration are still CSI-backed
+		if driver := pv.Annotations[migratedToAnnotation]; driver != "" {
+			return driver, true, nil
 		}
 	}
 
@@ -167,14 +348,14 @@ func (d *CrossNodePVCDetector) getPVCDriver(ctx context.Context, namespace, pvcN
 	if pvc.Spec.StorageClassName != nil {
 		sc, err := d.client.StorageV1().StorageClasses().Get(ctx, *pvc.Spec.StorageClassName, metav1.GetOptions{})
 		if err != nil {
-			return "", err
+			return "", false, err
 		}
 		
 		// Storage class provisioner often matches CSI driver name
-		return sc.Provisioner, nil
+		return sc.Provisioner, false, nil
 	}
 
-	return "", fmt.Errorf("unable to determine driver for PVC %s/%s", namespace, pvcName)
+	return "", false, fmt.Errorf("unable to determine driver for PVC %s/%s", namespace, pvcName)
 }
 
 // calculateCrossNodeSeverity determines severity based on cross-node usage
@@ -203,34 +384,55 @@ func (d *CrossNodePVCDetector) calculateHighUsageSeverity(usage int) types.Issue
 
 // GetNodePVCUsage returns detailed PVC usage statistics per node
 func (d *CrossNodePVCDetector) GetNodePVCUsage(ctx context.Context) ([]types.NodePVCUsage, error) {
-	pods, err := d.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list pods: %w", err)
-	}
-
 	// Track usage per node
 	nodeUsage := make(map[string]map[string]int) // node -> pvc -> count
+	pvcMatches := make(map[string]bool)          // pvcKey -> PVC belongs to the target driver
 
-	for _, pod := range pods.Items {
-		if pod.Spec.NodeName == "" {
-			continue
-		}
+	err := listPods(ctx, d.client.CoreV1().Pods(d.namespace), func(pods *corev1.PodList) error {
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName == "" {
+				continue
+			}
 
-		if nodeUsage[pod.Spec.NodeName] == nil {
-			nodeUsage[pod.Spec.NodeName] = make(map[string]int)
-		}
+			if nodeUsage[pod.Spec.NodeName] == nil {
+				nodeUsage[pod.Spec.NodeName] = make(map[string]int)
+			}
+
+			for _, volume := range pod.Spec.Volumes {
+				if volume.PersistentVolumeClaim != nil {
+					pvcKey := fmt.Sprintf("%s/%s", pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+
+					// Filter by driver if specified, resolving each PVC once
+					if d.targetDriver != "" {
+						matches, seen := pvcMatches[pvcKey]
+						if !seen {
+							driver, _, err := d.getPVCDriver(ctx, pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+							matches = d.matchesTargetDriver(driver, err == nil && driver != "")
+							pvcMatches[pvcKey] = matches
+						}
+						if !matches {
+							continue
+						}
+					}
 
-		for _, volume := range pod.Spec.Volumes {
-			if volume.PersistentVolumeClaim != nil {
-				pvcKey := fmt.Sprintf("%s/%s", pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
-				nodeUsage[pod.Spec.NodeName][pvcKey]++
+					nodeUsage[pod.Spec.NodeName][pvcKey]++
+				}
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Convert to result format
 	var result []types.NodePVCUsage
 	for node, pvcCounts := range nodeUsage {
+		// Nodes without any of the target driver's PVCs are not relevant to the analysis
+		if d.targetDriver != "" && len(pvcCounts) == 0 {
+			continue
+		}
+
 		total := 0
 		for _, count := range pvcCounts {
 			total += count
@@ -244,4 +446,162 @@ func (d *CrossNodePVCDetector) GetNodePVCUsage(ctx context.Context) ([]types.Nod
 	}
 
 	return result, nil
-}
\ No newline at end of file
+}
+
+// missingPVCIssue builds the issue for pods that reference a PVC that does not exist
+func (d *CrossNodePVCDetector) missingPVCIssue(pvcKey, namespace string, nodeUsage map[string]int, pods []types.SourceRef, lastPod time.Time) types.CSIMountIssue {
+	podNames := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		podNames = append(podNames, pod.Name)
+	}
+	sort.Strings(podNames)
+
+	// Attribute the issue to a node only when every referencing pod is on the same one
+	node := ""
+	if len(nodeUsage) == 1 {
+		for n := range nodeUsage {
+			node = n
+		}
+	}
+
+	return types.CSIMountIssue{
+		Type:        types.MissingPVC,
+		Severity:    types.SeverityMedium,
+		Node:        node,
+		PVC:         pvcKey,
+		Namespace:   namespace,
+		Description: fmt.Sprintf("PVC %s does not exist but is referenced by pod(s) %s, which cannot start until it is created", pvcKey, strings.Join(podNames, ", ")),
+		DetectedBy:  types.CrossNodePVCMethod,
+		DetectedAt:  time.Now(),
+		OccurredAt:  lastPod,
+		Metadata: map[string]string{
+			"pods": strings.Join(podNames, ","),
+		},
+		Sources: pvcSources(pvcKey, pods),
+	}
+}
+
+// inStorageClass reports whether a PVC names the StorageClass detection is limited to. A
+// PVC that does not exist is in no class.
+func (d *CrossNodePVCDetector) inStorageClass(ctx context.Context, namespace, pvcName string) (bool, error) {
+	pvc, err := d.client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get PVC %s/%s: %w", namespace, pvcName, err)
+	}
+	return pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName == d.storageClass, nil
+}
+
+// pvcMissing reports whether a PVC is confirmed not to exist
+func (d *CrossNodePVCDetector) pvcMissing(ctx context.Context, namespace, pvcName string) bool {
+	_, err := d.client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
+	return apierrors.IsNotFound(err)
+}
+
+// isUnschedulable reports whether the scheduler has marked a pod as unschedulable
+func isUnschedulable(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			return condition.Reason == corev1.PodReasonUnschedulable
+		}
+	}
+	return false
+}
+
+// podClaims returns the names of the PVCs a pod references
+func podClaims(pod corev1.Pod) []string {
+	var claims []string
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return claims
+}
+
+// workloadRef identifies the workload that owns a pod, within the pod's namespace
+type workloadRef struct {
+	Kind string
+	Name string
+}
+
+// trackPod records a pod as referencing a PVC, keeping the newest pod creation time and
+// the workload that owns the pod
+func trackPod(pvcKey string, pod corev1.Pod, pvcPods map[string][]types.SourceRef, pvcLastPod map[string]time.Time, pvcOwners map[string]map[workloadRef]bool) {
+	if pod.CreationTimestamp.Time.After(pvcLastPod[pvcKey]) {
+		pvcLastPod[pvcKey] = pod.CreationTimestamp.Time
+	}
+	kind, name := PodOwner(&pod)
+	if pvcOwners[pvcKey] == nil {
+		pvcOwners[pvcKey] = make(map[workloadRef]bool)
+	}
+	pvcOwners[pvcKey][workloadRef{Kind: kind, Name: name}] = true
+	pvcPods[pvcKey] = append(pvcPods[pvcKey], types.SourceRef{
+		Kind:      "Pod",
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		UID:       string(pod.UID),
+	})
+}
+
+// addOwnerMetadata records the workloads owning the pods behind an issue as owner_kind,
+// owner_name and owner_namespace, so operators know who runs them. When pods of several
+// workloads share the PVC, kinds and names are comma-separated in matching order.
+func addOwnerMetadata(metadata map[string]string, namespace string, owners map[workloadRef]bool) {
+	if len(owners) == 0 {
+		return
+	}
+	refs := make([]workloadRef, 0, len(owners))
+	for ref := range owners {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Kind != refs[j].Kind {
+			return refs[i].Kind < refs[j].Kind
+		}
+		return refs[i].Name < refs[j].Name
+	})
+
+	kinds := make([]string, len(refs))
+	names := make([]string, len(refs))
+	for i, ref := range refs {
+		kinds[i] = ref.Kind
+		names[i] = ref.Name
+	}
+	metadata["owner_kind"] = strings.Join(kinds, ",")
+	metadata["owner_name"] = strings.Join(names, ",")
+	metadata["owner_namespace"] = namespace
+}
+
+// statefulSetOwner returns the name of a StatefulSet among the owners, or "" if none is
+func statefulSetOwner(owners map[workloadRef]bool) string {
+	var sets []string
+	for ref := range owners {
+		if ref.Kind == "StatefulSet" {
+			sets = append(sets, ref.Name)
+		}
+	}
+	if len(sets) == 0 {
+		return ""
+	}
+	sort.Strings(sets)
+	return sets[0]
+}
+
+// matchesTargetDriver reports whether a PVC's resolved driver matches the target driver.
+// PVCs whose driver is unknown are included unless strict driver matching is enabled.
+func (d *CrossNodePVCDetector) matchesTargetDriver(driver string, known bool) bool {
+	if d.strictDriverMatch {
+		return known && driver == d.targetDriver
+	}
+	return !known || strings.Contains(driver, d.targetDriver)
+}
+
+// pvcSources returns references to a PVC and the pods that reference it
+func pvcSources(pvcKey string, pods []types.SourceRef) []types.SourceRef {
+	namespace, name, _ := strings.Cut(pvcKey, "/")
+	sources := []types.SourceRef{{Kind: "PersistentVolumeClaim", Namespace: namespace, Name: name}}
+	return append(sources, pods...)
+}
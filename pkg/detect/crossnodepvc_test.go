@@ -2,13 +2,16 @@ package detect_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/jdambly/kubectl-csi-scan/pkg/client/mocks"
@@ -56,6 +59,28 @@ var _ = Describe("CrossNodePVCDetector", func() {
 		ctrl.Finish()
 	})
 
+	podWithClaim := func(name, node, claim string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: corev1.PodSpec{
+				NodeName: node,
+				Volumes: []corev1.Volume{
+					{
+						Name: "vol-1",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: claim,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
 	Context("NewCrossNodePVCDetector", func() {
 		It("should create detector with target driver", func() {
 			detector = detect.NewCrossNodePVCDetector(mockClient, targetDriver)
@@ -76,7 +101,7 @@ var _ = Describe("CrossNodePVCDetector", func() {
 		Context("when no pods exist", func() {
 			It("should return no issues", func() {
 				mockPods.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(&corev1.PodList{}, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -131,7 +156,7 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				}
 
 				mockPods.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(podList, nil)
 
 				// Set up mock expectations for PVC lookup that might happen during driver detection
@@ -213,7 +238,7 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				}
 
 				mockPods.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(podList, nil)
 
 				// Mock PVC lookup calls
@@ -254,6 +279,126 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				Expect(issues[0].Description).To(ContainSubstring("2 nodes"))
 			})
 
+			It("should set OccurredAt to the newest pod referencing the PVC", func() {
+				older := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+				newer := time.Now().Add(-1 * time.Hour).Truncate(time.Second)
+				pod1 := podWithClaim("pod-1", "node-1", "cross-node-pvc")
+				pod1.CreationTimestamp = metav1.NewTime(older)
+				pod2 := podWithClaim("pod-2", "node-2", "cross-node-pvc")
+				pod2.CreationTimestamp = metav1.NewTime(newer)
+
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(&corev1.PodList{Items: []corev1.Pod{pod1, pod2}}, nil)
+				mockCoreV1.EXPECT().PersistentVolumeClaims("default").Return(mockPVCs).AnyTimes()
+				mockPVCs.EXPECT().Get(ctx, "cross-node-pvc", metav1.GetOptions{}).
+					Return(nil, errors.New("not found")).AnyTimes()
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].OccurredAt).To(BeTemporally("==", newer))
+				Expect(issues[0].DetectedAt).To(BeTemporally(">", newer))
+			})
+
+			It("should list the PVC and its pods as sources", func() {
+				pod1 := podWithClaim("pod-1", "node-1", "cross-node-pvc")
+				pod1.UID = "uid-1"
+				pod2 := podWithClaim("pod-2", "node-2", "cross-node-pvc")
+				pod2.UID = "uid-2"
+
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(&corev1.PodList{Items: []corev1.Pod{pod1, pod2}}, nil)
+				mockCoreV1.EXPECT().PersistentVolumeClaims("default").Return(mockPVCs).AnyTimes()
+				mockPVCs.EXPECT().Get(ctx, "cross-node-pvc", metav1.GetOptions{}).
+					Return(nil, errors.New("not found")).AnyTimes()
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Sources).To(Equal([]types.SourceRef{
+					{Kind: "PersistentVolumeClaim", Namespace: "default", Name: "cross-node-pvc"},
+					{Kind: "Pod", Namespace: "default", Name: "pod-1", UID: "uid-1"},
+					{Kind: "Pod", Namespace: "default", Name: "pod-2", UID: "uid-2"},
+				}))
+			})
+
+			It("should name the Deployment owning ReplicaSet pods in metadata", func() {
+				isController := true
+				owned := func(name, node string) corev1.Pod {
+					pod := podWithClaim(name, node, "cross-node-pvc")
+					pod.Labels = map[string]string{"pod-template-hash": "7d9f8b6c5"}
+					pod.OwnerReferences = []metav1.OwnerReference{
+						{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "api-7d9f8b6c5", Controller: &isController},
+					}
+					return pod
+				}
+
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(&corev1.PodList{Items: []corev1.Pod{owned("api-7d9f8b6c5-abcde", "node-1"), owned("api-7d9f8b6c5-fghij", "node-2")}}, nil)
+				mockCoreV1.EXPECT().PersistentVolumeClaims("default").Return(mockPVCs).AnyTimes()
+				mockPVCs.EXPECT().Get(ctx, "cross-node-pvc", metav1.GetOptions{}).
+					Return(nil, errors.New("not found")).AnyTimes()
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("owner_kind", "Deployment"))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("owner_name", "api"))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("owner_namespace", "default"))
+				Expect(issues[0].Description).NotTo(ContainSubstring("StatefulSet"))
+			})
+
+			It("should flag StatefulSet pods sharing a PVC across nodes as especially suspicious", func() {
+				isController := true
+				pod1 := podWithClaim("db-0", "node-1", "cross-node-pvc")
+				pod2 := podWithClaim("db-1", "node-2", "cross-node-pvc")
+				for _, pod := range []*corev1.Pod{&pod1, &pod2} {
+					pod.OwnerReferences = []metav1.OwnerReference{
+						{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "db", Controller: &isController},
+					}
+				}
+
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(&corev1.PodList{Items: []corev1.Pod{pod1, pod2}}, nil)
+				mockCoreV1.EXPECT().PersistentVolumeClaims("default").Return(mockPVCs).AnyTimes()
+				mockPVCs.EXPECT().Get(ctx, "cross-node-pvc", metav1.GetOptions{}).
+					Return(nil, errors.New("not found")).AnyTimes()
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("owner_kind", "StatefulSet"))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("owner_name", "db"))
+				Expect(issues[0].Description).To(ContainSubstring("StatefulSet db"))
+				Expect(issues[0].Description).To(ContainSubstring("especially suspicious"))
+			})
+
+			It("should list every owning workload when several share the PVC", func() {
+				isController := true
+				pod1 := podWithClaim("db-0", "node-1", "cross-node-pvc")
+				pod1.OwnerReferences = []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "db", Controller: &isController},
+				}
+				pod2 := podWithClaim("debug", "node-2", "cross-node-pvc")
+
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(&corev1.PodList{Items: []corev1.Pod{pod1, pod2}}, nil)
+				mockCoreV1.EXPECT().PersistentVolumeClaims("default").Return(mockPVCs).AnyTimes()
+				mockPVCs.EXPECT().Get(ctx, "cross-node-pvc", metav1.GetOptions{}).
+					Return(nil, errors.New("not found")).AnyTimes()
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("owner_kind", "Pod,StatefulSet"))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("owner_name", "debug,db"))
+			})
+
 			It("should detect high usage on single node", func() {
 				var podList corev1.PodList
 				// Create 15 pods using the same PVC on one node
@@ -281,7 +426,7 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				}
 
 				mockPods.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(&podList, nil)
 
 				// Mock PVC lookup calls
@@ -406,7 +551,7 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				}
 
 				mockPods.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(podList, nil)
 
 				// Mock PVC lookup calls for other driver
@@ -515,7 +660,7 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				}
 
 				mockPods.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(podList, nil)
 
 				// Mock PVC without bound PV but with StorageClass
@@ -598,7 +743,7 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				}
 
 				mockPods.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(podList, nil)
 
 				// When no target driver is specified, detector will still try to get driver info
@@ -614,6 +759,184 @@ var _ = Describe("CrossNodePVCDetector", func() {
 			})
 		})
 
+		Context("when limited to CSI-backed PVCs", func() {
+			BeforeEach(func() {
+				detector = detect.NewCrossNodePVCDetector(mockClient, "")
+				detector.SetCSIOnly(true)
+
+				podList := &corev1.PodList{
+					Items: []corev1.Pod{
+						podWithClaim("csi-pod-1", "node-1", "csi-pvc"),
+						podWithClaim("csi-pod-2", "node-2", "csi-pvc"),
+						podWithClaim("nfs-pod-1", "node-1", "nfs-pvc"),
+						podWithClaim("nfs-pod-2", "node-2", "nfs-pvc"),
+					},
+				}
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(podList, nil)
+
+				mockPVCs.EXPECT().Get(ctx, "csi-pvc", metav1.GetOptions{}).Return(
+					&corev1.PersistentVolumeClaim{
+						ObjectMeta: metav1.ObjectMeta{Name: "csi-pvc", Namespace: "default"},
+						Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-csi"},
+					}, nil)
+				mockPVs.EXPECT().Get(ctx, "pv-csi", metav1.GetOptions{}).Return(
+					&corev1.PersistentVolume{
+						ObjectMeta: metav1.ObjectMeta{Name: "pv-csi"},
+						Spec: corev1.PersistentVolumeSpec{
+							PersistentVolumeSource: corev1.PersistentVolumeSource{
+								CSI: &corev1.CSIPersistentVolumeSource{
+									Driver: targetDriver,
+								},
+							},
+						},
+					}, nil)
+
+				nfsClass := "nfs"
+				mockPVCs.EXPECT().Get(ctx, "nfs-pvc", metav1.GetOptions{}).Return(
+					&corev1.PersistentVolumeClaim{
+						ObjectMeta: metav1.ObjectMeta{Name: "nfs-pvc", Namespace: "default"},
+						Spec: corev1.PersistentVolumeClaimSpec{
+							VolumeName:       "pv-nfs",
+							StorageClassName: &nfsClass,
+						},
+					}, nil)
+				mockPVs.EXPECT().Get(ctx, "pv-nfs", metav1.GetOptions{}).Return(
+					&corev1.PersistentVolume{
+						ObjectMeta: metav1.ObjectMeta{Name: "pv-nfs"},
+						Spec: corev1.PersistentVolumeSpec{
+							PersistentVolumeSource: corev1.PersistentVolumeSource{
+								NFS: &corev1.NFSVolumeSource{
+									Server: "nfs.example.com",
+									Path:   "/exports/data",
+								},
+							},
+						},
+					}, nil)
+				mockStorageClasses.EXPECT().Get(ctx, "nfs", metav1.GetOptions{}).Return(
+					&storagev1.StorageClass{
+						ObjectMeta:  metav1.ObjectMeta{Name: "nfs"},
+						Provisioner: "example.com/nfs",
+					}, nil)
+			})
+
+			It("should only flag the CSI-backed PVC", func() {
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].PVC).To(Equal("default/csi-pvc"))
+				Expect(issues[0].Driver).To(Equal(targetDriver))
+			})
+
+			It("should flag both PVCs when disabled", func() {
+				detector.SetCSIOnly(false)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(2))
+			})
+		})
+
+		Context("when limited to a StorageClass", func() {
+			It("should only inspect PVCs of that StorageClass", func() {
+				detector = detect.NewCrossNodePVCDetector(mockClient, "")
+				detector.SetStorageClass("gold")
+
+				podList := &corev1.PodList{
+					Items: []corev1.Pod{
+						podWithClaim("gold-pod-1", "node-1", "gold-pvc"),
+						podWithClaim("gold-pod-2", "node-2", "gold-pvc"),
+						podWithClaim("bronze-pod-1", "node-1", "bronze-pvc"),
+						podWithClaim("bronze-pod-2", "node-2", "bronze-pvc"),
+					},
+				}
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(podList, nil)
+
+				gold, bronze := "gold", "bronze"
+				// Looked up once for its class and once for its driver
+				mockPVCs.EXPECT().Get(ctx, "gold-pvc", metav1.GetOptions{}).Return(
+					&corev1.PersistentVolumeClaim{
+						ObjectMeta: metav1.ObjectMeta{Name: "gold-pvc", Namespace: "default"},
+						Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &gold},
+					}, nil).Times(2)
+				mockStorageClasses.EXPECT().Get(ctx, "gold", metav1.GetOptions{}).Return(
+					&storagev1.StorageClass{
+						ObjectMeta:  metav1.ObjectMeta{Name: "gold"},
+						Provisioner: targetDriver,
+					}, nil)
+				// Only looked up for its class: nothing else about it is inspected
+				mockPVCs.EXPECT().Get(ctx, "bronze-pvc", metav1.GetOptions{}).Return(
+					&corev1.PersistentVolumeClaim{
+						ObjectMeta: metav1.ObjectMeta{Name: "bronze-pvc", Namespace: "default"},
+						Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &bronze},
+					}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].PVC).To(Equal("default/gold-pvc"))
+				Expect(issues[0].Driver).To(Equal(targetDriver))
+			})
+		})
+
+		Context("with strict driver matching", func() {
+			BeforeEach(func() {
+				podList := &corev1.PodList{
+					Items: []corev1.Pod{
+						podWithClaim("pod-1", "node-1", "unresolved-pvc"),
+						podWithClaim("pod-2", "node-2", "unresolved-pvc"),
+					},
+				}
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(podList, nil)
+
+				mockPVCs.EXPECT().Get(ctx, "unresolved-pvc", metav1.GetOptions{}).Return(
+					nil, &testError{msg: "forbidden"}).AnyTimes()
+			})
+
+			It("should include PVCs with an unknown driver by default", func() {
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].PVC).To(Equal("default/unresolved-pvc"))
+			})
+
+			It("should exclude PVCs with an unknown driver when strict", func() {
+				detector.SetStrictDriverMatch(true)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(BeEmpty())
+			})
+		})
+
+		Context("when PVC lookups keep failing", func() {
+			It("should stop looking up drivers once the error limit is reached", func() {
+				detector.SetEnrichmentErrorLimit(2)
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(&corev1.PodList{Items: []corev1.Pod{
+						podWithClaim("pod-1", "node-1", "pvc-a"),
+						podWithClaim("pod-2", "node-2", "pvc-b"),
+						podWithClaim("pod-3", "node-3", "pvc-c"),
+						podWithClaim("pod-4", "node-4", "pvc-d"),
+					}}, nil)
+
+				// The apiserver names the PVC in each error, yet they are the same failure
+				mockPVCs.EXPECT().Get(ctx, gomock.Any(), metav1.GetOptions{}).DoAndReturn(
+					func(_ context.Context, name string, _ metav1.GetOptions) (*corev1.PersistentVolumeClaim, error) {
+						return nil, apierrors.NewForbidden(corev1.Resource("persistentvolumeclaims"), name, errors.New("access denied"))
+					}).Times(2)
+
+				_, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
 		Context("GetNodePVCUsage", func() {
 			BeforeEach(func() {
 				detector = detect.NewCrossNodePVCDetector(mockClient, targetDriver)
@@ -672,8 +995,11 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				}
 
 				mockPods.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(podList, nil)
+				// PVCs whose driver cannot be resolved are kept when scoping to a driver
+				mockPVCs.EXPECT().Get(ctx, "pvc-1", metav1.GetOptions{}).Return(nil, errors.New("not found"))
+				mockPVCs.EXPECT().Get(ctx, "pvc-2", metav1.GetOptions{}).Return(nil, errors.New("not found"))
 
 				usage, err := detector.GetNodePVCUsage(ctx)
 				Expect(err).NotTo(HaveOccurred())
@@ -698,6 +1024,134 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				Expect(node2Usage.Total).To(Equal(1))
 				Expect(node2Usage.PVCCounts).To(HaveKeyWithValue("default/pvc-1", 1))
 			})
+
+			It("should only count PVCs of the target driver", func() {
+				pvcPod := func(name, node, claim string) corev1.Pod {
+					return corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+						Spec: corev1.PodSpec{
+							NodeName: node,
+							Volumes: []corev1.Volume{{
+								Name: "data",
+								VolumeSource: corev1.VolumeSource{
+									PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+								},
+							}},
+						},
+					}
+				}
+				boundPVC := func(name, volume string) *corev1.PersistentVolumeClaim {
+					return &corev1.PersistentVolumeClaim{
+						ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+						Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: volume},
+					}
+				}
+				csiPV := func(name, driver string) *corev1.PersistentVolume {
+					return &corev1.PersistentVolume{
+						ObjectMeta: metav1.ObjectMeta{Name: name},
+						Spec: corev1.PersistentVolumeSpec{
+							PersistentVolumeSource: corev1.PersistentVolumeSource{
+								CSI: &corev1.CSIPersistentVolumeSource{Driver: driver},
+							},
+						},
+					}
+				}
+
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(&corev1.PodList{Items: []corev1.Pod{
+						pvcPod("pod-1", "node-1", "target-pvc"),
+						pvcPod("pod-2", "node-2", "target-pvc"),
+						pvcPod("pod-3", "node-3", "other-pvc"),
+					}}, nil)
+				mockPVCs.EXPECT().Get(ctx, "target-pvc", metav1.GetOptions{}).Return(boundPVC("target-pvc", "pv-target"), nil).Times(1)
+				mockPVCs.EXPECT().Get(ctx, "other-pvc", metav1.GetOptions{}).Return(boundPVC("other-pvc", "pv-other"), nil)
+				mockPVs.EXPECT().Get(ctx, "pv-target", metav1.GetOptions{}).Return(csiPV("pv-target", targetDriver), nil)
+				mockPVs.EXPECT().Get(ctx, "pv-other", metav1.GetOptions{}).Return(csiPV("pv-other", "other.csi.driver"), nil)
+
+				usage, err := detector.GetNodePVCUsage(ctx)
+				Expect(err).NotTo(HaveOccurred())
+
+				var nodes []string
+				for _, u := range usage {
+					nodes = append(nodes, u.Node)
+					Expect(u.PVCCounts).To(Equal(map[string]int{"default/target-pvc": 1}))
+				}
+				Expect(nodes).To(ConsistOf("node-1", "node-2"))
+			})
+		})
+
+		Context("when pods span several list pages", func() {
+			It("should process the pods of every page", func() {
+				detector = detect.NewCrossNodePVCDetector(mockClient, "")
+				gomock.InOrder(
+					mockPods.EXPECT().List(ctx, metav1.ListOptions{Limit: 500}).Return(&corev1.PodList{
+						ListMeta: metav1.ListMeta{Continue: "page-2"},
+						Items:    []corev1.Pod{podWithClaim("pod-1", "node-1", "shared")},
+					}, nil),
+					mockPods.EXPECT().List(ctx, metav1.ListOptions{Limit: 500, Continue: "page-2"}).Return(&corev1.PodList{
+						ListMeta: metav1.ListMeta{Continue: "page-3"},
+						Items:    []corev1.Pod{podWithClaim("pod-2", "node-2", "shared")},
+					}, nil),
+					mockPods.EXPECT().List(ctx, metav1.ListOptions{Limit: 500, Continue: "page-3"}).Return(&corev1.PodList{
+						Items: []corev1.Pod{podWithClaim("pod-3", "node-3", "shared")},
+					}, nil),
+				)
+				mockPVCs.EXPECT().Get(ctx, gomock.Any(), metav1.GetOptions{}).Return(nil, errors.New("lookup failed")).AnyTimes()
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Type).To(Equal(types.MultipleAttachments))
+				Expect(issues[0].PVC).To(Equal("default/shared"))
+				Expect(issues[0].Description).To(ContainSubstring("PVC used on 3 nodes"))
+			})
+
+			It("should stop at the first page that fails to list", func() {
+				detector = detect.NewCrossNodePVCDetector(mockClient, "")
+				gomock.InOrder(
+					mockPods.EXPECT().List(ctx, metav1.ListOptions{Limit: 500}).Return(&corev1.PodList{
+						ListMeta: metav1.ListMeta{Continue: "page-2"},
+						Items:    []corev1.Pod{podWithClaim("pod-1", "node-1", "shared")},
+					}, nil),
+					mockPods.EXPECT().List(ctx, metav1.ListOptions{Limit: 500, Continue: "page-2"}).Return(nil, errors.New("continue token expired")),
+				)
+				mockPVCs.EXPECT().Get(ctx, gomock.Any(), metav1.GetOptions{}).Return(nil, errors.New("lookup failed")).AnyTimes()
+
+				_, err := detector.Detect(ctx)
+				Expect(err).To(MatchError(ContainSubstring("failed to list pods: continue token expired")))
+			})
+		})
+
+		Context("with a node PVC warning threshold", func() {
+			BeforeEach(func() {
+				detector = detect.NewCrossNodePVCDetector(mockClient, "")
+				detector.SetNodePVCWarn(3)
+
+				// Detect and GetNodePVCUsage each list the pods
+				mockPods.EXPECT().List(ctx, metav1.ListOptions{Limit: 500}).Return(&corev1.PodList{
+					Items: []corev1.Pod{
+						podWithClaim("pod-1", "node-1", "data-1"),
+						podWithClaim("pod-2", "node-1", "data-2"),
+						podWithClaim("pod-3", "node-1", "data-3"),
+						podWithClaim("pod-4", "node-1", "data-4"),
+						podWithClaim("pod-5", "node-2", "data-5"),
+					},
+				}, nil).Times(2)
+				mockPVCs.EXPECT().Get(ctx, gomock.Any(), metav1.GetOptions{}).Return(nil, errors.New("lookup failed")).AnyTimes()
+			})
+
+			It("should report a single informational issue for the node over the threshold", func() {
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Type).To(Equal(types.HighNodePVCUsage))
+				Expect(issues[0].Severity).To(Equal(types.SeverityLow))
+				Expect(issues[0].Node).To(Equal("node-1"))
+				Expect(issues[0].PVC).To(BeEmpty())
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("total_usage", "4"))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("threshold", "3"))
+			})
 		})
 
 		Context("error handling", func() {
@@ -707,7 +1161,7 @@ var _ = Describe("CrossNodePVCDetector", func() {
 
 			It("should handle Pod API errors gracefully", func() {
 				mockPods.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(nil, &testError{msg: "Pod API error"})
 
 				issues, err := detector.Detect(ctx)
@@ -761,7 +1215,7 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				}
 
 				mockPods.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(podList, nil)
 
 				// Should still detect the cross-node issue even if driver lookup fails
@@ -802,7 +1256,7 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				}
 
 				mockPods.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(podList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -810,6 +1264,93 @@ var _ = Describe("CrossNodePVCDetector", func() {
 				Expect(issues).To(BeEmpty())
 			})
 		})
+
+		Context("when a pod references a missing PVC", func() {
+			var notFound error
+
+			BeforeEach(func() {
+				detector = detect.NewCrossNodePVCDetector(mockClient, "")
+				notFound = apierrors.NewNotFound(corev1.Resource("persistentvolumeclaims"), "missing-pvc")
+			})
+
+			podWithClaim := func(name, node string) corev1.Pod {
+				return corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec: corev1.PodSpec{
+						NodeName: node,
+						Volumes: []corev1.Volume{{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "missing-pvc"},
+							},
+						}},
+					},
+				}
+			}
+
+			It("should report a MissingPVC issue for a pending pod", func() {
+				pod := podWithClaim("pending-pod", "")
+				pod.Status.Conditions = []corev1.PodCondition{{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  corev1.PodReasonUnschedulable,
+					Message: `persistentvolumeclaim "missing-pvc" not found`,
+				}}
+
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(&corev1.PodList{Items: []corev1.Pod{pod}}, nil)
+				mockPVCs.EXPECT().Get(ctx, "missing-pvc", metav1.GetOptions{}).Return(nil, notFound)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Type).To(Equal(types.MissingPVC))
+				Expect(issues[0].Severity).To(Equal(types.SeverityMedium))
+				Expect(issues[0].PVC).To(Equal("default/missing-pvc"))
+				Expect(issues[0].Node).To(BeEmpty())
+				Expect(issues[0].Description).To(ContainSubstring("pending-pod"))
+				Expect(issues[0].Sources).To(ContainElement(types.SourceRef{Kind: "Pod", Namespace: "default", Name: "pending-pod"}))
+			})
+
+			It("should report a MissingPVC issue for a scheduled pod", func() {
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(&corev1.PodList{Items: []corev1.Pod{podWithClaim("running-pod", "node-1")}}, nil)
+				mockPVCs.EXPECT().Get(ctx, "missing-pvc", metav1.GetOptions{}).Return(nil, notFound)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Type).To(Equal(types.MissingPVC))
+				Expect(issues[0].Node).To(Equal("node-1"))
+			})
+
+			It("should not report PVCs that fail to load for other reasons", func() {
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(&corev1.PodList{Items: []corev1.Pod{podWithClaim("running-pod", "node-1")}}, nil)
+				mockPVCs.EXPECT().Get(ctx, "missing-pvc", metav1.GetOptions{}).Return(nil, errors.New("connection refused"))
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(BeEmpty())
+			})
+
+			It("should skip missing PVCs with strict driver matching", func() {
+				detector = detect.NewCrossNodePVCDetector(mockClient, targetDriver)
+				detector.SetStrictDriverMatch(true)
+
+				mockPods.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(&corev1.PodList{Items: []corev1.Pod{podWithClaim("running-pod", "node-1")}}, nil)
+				mockPVCs.EXPECT().Get(ctx, "missing-pvc", metav1.GetOptions{}).Return(nil, notFound)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(BeEmpty())
+			})
+		})
 	})
 
 	Context("Severity Calculation", func() {
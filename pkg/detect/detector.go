@@ -6,8 +6,6 @@ import (
 	"sort"
 	"time"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
 	"github.com/jdambly/kubectl-csi-scan/pkg/client"
 	"github.com/jdambly/kubectl-csi-scan/pkg/types"
 )
@@ -19,7 +17,14 @@ type Detector struct {
 	crossNodePVCDetector     *CrossNodePVCDetector
 	eventsDetector          *EventsDetector
 	metricsDetector         *MetricsDetector
+	storageClassDetector    *StorageClassDetector
+	driverTopologyDetector  *DriverTopologyDetector
+	nodePressureDetector    *NodePressureDetector
+	nodePluginDetector      *NodePluginDetector
+	mountProber             MountProber
 	options                 types.DetectionOptions
+	focusPV                 string // PV bound to options.PVC, resolved at the start of each run
+	serverClock             ServerClock
 }
 
 // NewDetector creates a new multi-method detector
@@ -29,106 +34,341 @@ func NewDetector(kubeClient client.KubernetesClient, options types.DetectionOpti
 		options: options,
 	}
 
-	// Initialize detection methods based on options
+	// Initialize detection methods based on options. A method listed more than once is
+	// only set up, and therefore only run, once.
+	seen := make(map[types.DetectionMethod]bool, len(options.Methods))
 	for _, method := range options.Methods {
+		if seen[method] {
+			continue
+		}
+		seen[method] = true
+
 		switch method {
 		case types.VolumeAttachmentMethod:
 			detector.volumeAttachmentDetector = NewVolumeAttachmentDetector(kubeClient, options.TargetDriver)
+			detector.volumeAttachmentDetector.SetStuckThresholds(options.StuckThreshold, options.DriverStuckThresholds)
+			detector.volumeAttachmentDetector.SetDeletionThreshold(options.DeletionThreshold)
+			detector.volumeAttachmentDetector.SetCheckClaims(options.CheckClaims)
+			detector.volumeAttachmentDetector.SetEnrichmentErrorLimit(options.EnrichmentErrorLimit)
+			detector.volumeAttachmentDetector.SetStorageClass(options.StorageClass)
 		case types.CrossNodePVCMethod:
 			detector.crossNodePVCDetector = NewCrossNodePVCDetector(kubeClient, options.TargetDriver)
+			detector.crossNodePVCDetector.SetCSIOnly(options.CSIOnly)
+			detector.crossNodePVCDetector.SetStrictDriverMatch(options.StrictDriverMatch)
+			detector.crossNodePVCDetector.SetNodePVCWarn(options.NodePVCWarn)
+			detector.crossNodePVCDetector.SetStorageClass(options.StorageClass)
+			detector.crossNodePVCDetector.SetEnrichmentErrorLimit(options.EnrichmentErrorLimit)
+			detector.crossNodePVCDetector.SetNamespace(options.Namespace)
 		case types.EventsMethod:
-			detector.eventsDetector = NewEventsDetector(kubeClient, options.TargetDriver, 1*time.Hour)
+			detector.eventsDetector = NewEventsDetector(kubeClient, options.TargetDriver, options.EventsLookback)
+			detector.eventsDetector.SetStrictDriverMatch(options.StrictDriverMatch)
+			if len(options.DeviceBusyPatterns) > 0 {
+				detector.eventsDetector.SetDeviceBusyPatterns(options.DeviceBusyPatterns)
+			}
+			detector.eventsDetector.SetFlapThreshold(options.FlapThreshold)
+			detector.eventsDetector.SetNamespace(options.Namespace)
 		case types.MetricsMethod:
-			detector.metricsDetector = NewMetricsDetector("", options.TargetDriver) // Prometheus URL would be configured
+			detector.metricsDetector = NewMetricsDetector(options.PrometheusURL, options.TargetDriver)
+		case types.StorageClassMethod:
+			detector.storageClassDetector = NewStorageClassDetector(kubeClient, options.TargetDriver)
+		case types.DriverTopologyMethod:
+			detector.driverTopologyDetector = NewDriverTopologyDetector(kubeClient, options.TargetDriver)
+			detector.driverTopologyDetector.SetNamespace(options.Namespace)
+		case types.NodeConditionsMethod:
+			detector.nodePressureDetector = NewNodePressureDetector(kubeClient)
 		}
 	}
 
+	if options.Probe {
+		detector.nodePluginDetector = NewNodePluginDetector(kubeClient, options.TargetDriver)
+		detector.nodePluginDetector.SetSelectors(options.NodePluginSelectors)
+	}
+
 	return detector
 }
 
-// DetectAll runs all configured detection methods and returns consolidated results
+// DetectAll runs all configured detection methods and returns consolidated results.
+// If ctx is cancelled part way through, the issues found by the methods that completed
+// are returned as a partial result together with the error. In best-effort mode a method
+// that fails is recorded in the result's Errors and the remaining methods still run.
 func (d *Detector) DetectAll(ctx context.Context) (*types.DetectionResult, error) {
 	var allIssues []types.CSIMountIssue
 	var methodsUsed []types.DetectionMethod
+	var methodErrors []types.MethodError
+
+	// Methods read the cluster back to back, so their views are approximately
+	// consistent as of the time the first one started, on the API server's clock when
+	// one is set. The resourceVersions of their lists say exactly what each saw.
+	snapshotTime := time.Now()
+
+	if d.options.PVC != "" {
+		if err := d.resolveFocus(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	// Run VolumeAttachment detection
 	if d.volumeAttachmentDetector != nil {
 		issues, err := d.volumeAttachmentDetector.Detect(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("VolumeAttachment detection failed: %w", err)
+		switch {
+		case err == nil:
+			allIssues = append(allIssues, issues...)
+			methodsUsed = append(methodsUsed, types.VolumeAttachmentMethod)
+		case !d.skipFailure(ctx, &methodErrors, types.VolumeAttachmentMethod, err):
+			return d.partialResult(ctx, allIssues, methodsUsed, methodErrors, snapshotTime, fmt.Errorf("VolumeAttachment detection failed: %w", err))
 		}
-		allIssues = append(allIssues, issues...)
-		methodsUsed = append(methodsUsed, types.VolumeAttachmentMethod)
 	}
 
 	// Run cross-node PVC detection
 	if d.crossNodePVCDetector != nil {
 		issues, err := d.crossNodePVCDetector.Detect(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("cross-node PVC detection failed: %w", err)
+		switch {
+		case err == nil:
+			allIssues = append(allIssues, issues...)
+			methodsUsed = append(methodsUsed, types.CrossNodePVCMethod)
+		case !d.skipFailure(ctx, &methodErrors, types.CrossNodePVCMethod, err):
+			return d.partialResult(ctx, allIssues, methodsUsed, methodErrors, snapshotTime, fmt.Errorf("cross-node PVC detection failed: %w", err))
 		}
-		allIssues = append(allIssues, issues...)
-		methodsUsed = append(methodsUsed, types.CrossNodePVCMethod)
 	}
 
 	// Run events detection
 	if d.eventsDetector != nil {
 		issues, err := d.eventsDetector.Detect(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("events detection failed: %w", err)
+		switch {
+		case err == nil:
+			allIssues = append(allIssues, issues...)
+			methodsUsed = append(methodsUsed, types.EventsMethod)
+		case !d.skipFailure(ctx, &methodErrors, types.EventsMethod, err):
+			return d.partialResult(ctx, allIssues, methodsUsed, methodErrors, snapshotTime, fmt.Errorf("events detection failed: %w", err))
 		}
-		allIssues = append(allIssues, issues...)
-		methodsUsed = append(methodsUsed, types.EventsMethod)
 	}
 
 	// Run metrics detection
 	if d.metricsDetector != nil {
 		issues, err := d.metricsDetector.Detect(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("metrics detection failed: %w", err)
+		switch {
+		case err == nil:
+			allIssues = append(allIssues, issues...)
+			methodsUsed = append(methodsUsed, types.MetricsMethod)
+		case !d.skipFailure(ctx, &methodErrors, types.MetricsMethod, err):
+			return d.partialResult(ctx, allIssues, methodsUsed, methodErrors, snapshotTime, fmt.Errorf("metrics detection failed: %w", err))
 		}
-		allIssues = append(allIssues, issues...)
-		methodsUsed = append(methodsUsed, types.MetricsMethod)
 	}
 
-	// Filter by minimum severity
-	filteredIssues := d.filterBySeverity(allIssues, d.options.MinSeverity)
+	// Run StorageClass checks
+	if d.storageClassDetector != nil {
+		issues, err := d.storageClassDetector.Detect(ctx)
+		switch {
+		case err == nil:
+			allIssues = append(allIssues, issues...)
+			methodsUsed = append(methodsUsed, types.StorageClassMethod)
+		case !d.skipFailure(ctx, &methodErrors, types.StorageClassMethod, err):
+			return d.partialResult(ctx, allIssues, methodsUsed, methodErrors, snapshotTime, fmt.Errorf("StorageClass detection failed: %w", err))
+		}
+	}
 
+	// Run driver registration checks
+	if d.driverTopologyDetector != nil {
+		issues, err := d.driverTopologyDetector.Detect(ctx)
+		switch {
+		case err == nil:
+			allIssues = append(allIssues, issues...)
+			methodsUsed = append(methodsUsed, types.DriverTopologyMethod)
+		case !d.skipFailure(ctx, &methodErrors, types.DriverTopologyMethod, err):
+			return d.partialResult(ctx, allIssues, methodsUsed, methodErrors, snapshotTime, fmt.Errorf("driver topology detection failed: %w", err))
+		}
+	}
+
+	// Take drivers from VolumeAttachments where they are known, then report a problem
+	// or a volume on a node that several methods found once
+	allIssues = ReconcileDrivers(allIssues)
+	if !d.options.NoDedup {
+		allIssues = CorrelateIssues(MergeAcrossMethods(allIssues))
+	}
+
+	// Keep issues about the focused PVC, apply severity overrides, filter by minimum
+	// severity, then drop known and accepted issues
+	filteredIssues, suppressed := d.suppress(FilterBySeverity(d.overrideSeverities(d.focus(allIssues)), d.options.MinSeverity))
+
+	// Probe the CSI node plugin pods on the nodes the remaining issues affect
+	if d.nodePluginDetector != nil {
+		issues, err := d.nodePluginDetector.Detect(ctx, affectedNodes(filteredIssues))
+		switch {
+		case err == nil:
+			probed, probeSuppressed := d.suppress(FilterBySeverity(d.overrideSeverities(issues), d.options.MinSeverity))
+			filteredIssues = append(filteredIssues, probed...)
+			suppressed += probeSuppressed
+			methodsUsed = append(methodsUsed, types.ProbeMethod)
+		case !d.skipFailure(ctx, &methodErrors, types.ProbeMethod, err):
+			return d.partialResult(ctx, allIssues, methodsUsed, methodErrors, snapshotTime, fmt.Errorf("node plugin probe failed: %w", err))
+		}
+	}
+
+	// List the mounts on nodes suspected of holding stuck mount references
+	if d.options.ProbeMounts && d.mountProber != nil {
+		mounts, err := d.mountProber.ProbeMounts(ctx, mountSuspectNodes(filteredIssues))
+		switch {
+		case err == nil:
+			probed, probeSuppressed := d.suppress(FilterBySeverity(d.overrideSeverities(MountProbeIssues(mounts)), d.options.MinSeverity))
+			filteredIssues = append(filteredIssues, probed...)
+			suppressed += probeSuppressed
+			methodsUsed = append(methodsUsed, types.MountProbeMethod)
+		case !d.skipFailure(ctx, &methodErrors, types.MountProbeMethod, err):
+			return d.partialResult(ctx, allIssues, methodsUsed, methodErrors, snapshotTime, fmt.Errorf("mount probe failed: %w", err))
+		}
+	}
+
+	// Check the conditions of the nodes the issues affect
+	if d.nodePressureDetector != nil {
+		issues, err := d.nodePressureDetector.Detect(ctx, affectedNodes(filteredIssues))
+		switch {
+		case err == nil:
+			checked, checkSuppressed := d.suppress(FilterBySeverity(d.overrideSeverities(issues), d.options.MinSeverity))
+			filteredIssues = append(filteredIssues, checked...)
+			suppressed += checkSuppressed
+			methodsUsed = append(methodsUsed, types.NodeConditionsMethod)
+		case !d.skipFailure(ctx, &methodErrors, types.NodeConditionsMethod, err):
+			return d.partialResult(ctx, allIssues, methodsUsed, methodErrors, snapshotTime, fmt.Errorf("node conditions detection failed: %w", err))
+		}
+	}
+
+	// Look up affected workloads if requested
+	var workloads []types.AffectedWorkload
+	if d.options.RecommendCleanup && d.options.WithOwners {
+		var err error
+		workloads, err = d.workloadRollup(ctx, filteredIssues)
+		if err != nil && !d.skipFailure(ctx, &methodErrors, types.WorkloadRollupMethod, err) {
+			return d.partialResult(ctx, allIssues, methodsUsed, methodErrors, snapshotTime, fmt.Errorf("workload rollup failed: %w", err))
+		}
+	}
+
+	result := d.newResult(filteredIssues, methodsUsed, snapshotTime, workloads)
+	result.Summary.Suppressed = suppressed
+	result.Errors = methodErrors
+	return result, nil
+}
+
+// newResult builds the detection result with its summary and, if requested, recommendations
+func (d *Detector) newResult(issues []types.CSIMountIssue, methodsUsed []types.DetectionMethod, snapshotTime time.Time, workloads []types.AffectedWorkload) *types.DetectionResult {
 	// Generate summary
-	summary := d.generateSummary(filteredIssues, methodsUsed)
+	summary := d.generateSummary(issues, methodsUsed)
+	summary.SnapshotTime = d.serverTime(snapshotTime)
+	summary.ResourceVersions = d.listVersions(methodsUsed)
+	summary.Status = HealthVerdict(summary.IssuesBySeverity, d.options.CriticalThresholds, d.options.DegradedThresholds)
 
 	// Generate recommendations if requested
 	var recommendations []string
 	if d.options.RecommendCleanup {
-		recommendations = d.generateRecommendations(filteredIssues)
+		recommendations = d.generateRecommendations(issues, workloads)
 	}
 
 	return &types.DetectionResult{
+		SchemaVersion:   types.SchemaVersion,
 		Summary:         summary,
-		Issues:          filteredIssues,
+		Issues:          issues,
 		Recommendations: recommendations,
 		GeneratedAt:     time.Now(),
-	}, nil
+	}
 }
 
-// filterBySeverity filters issues based on minimum severity level
-func (d *Detector) filterBySeverity(issues []types.CSIMountIssue, minSeverity types.IssueSeverity) []types.CSIMountIssue {
-	if minSeverity == "" {
+// skipFailure reports whether detection carries on after method failed with err, which it
+// does in best-effort mode unless ctx is done. The failure is then recorded in methodErrors.
+func (d *Detector) skipFailure(ctx context.Context, methodErrors *[]types.MethodError, method types.DetectionMethod, err error) bool {
+	if !d.options.BestEffort || ctx.Err() != nil {
+		return false
+	}
+	*methodErrors = append(*methodErrors, types.MethodError{Method: method, Error: err.Error()})
+	return true
+}
+
+// partialResult returns err alongside whatever was collected so far when detection stopped
+// because ctx was cancelled. Other failures return no result.
+func (d *Detector) partialResult(ctx context.Context, issues []types.CSIMountIssue, methodsUsed []types.DetectionMethod, methodErrors []types.MethodError, snapshotTime time.Time, err error) (*types.DetectionResult, error) {
+	if ctx.Err() == nil {
+		return nil, err
+	}
+
+	filtered, suppressed := d.suppress(FilterBySeverity(d.overrideSeverities(d.focus(issues)), d.options.MinSeverity))
+	result := d.newResult(filtered, methodsUsed, snapshotTime, nil)
+	result.Summary.Suppressed = suppressed
+	result.Partial = true
+	result.Errors = methodErrors
+	return result, err
+}
+
+// DefaultCriticalThresholds make any critical issue a critical status
+var DefaultCriticalThresholds = map[types.IssueSeverity]int{types.SeverityCritical: 1}
+
+// DefaultDegradedThresholds make five or more high severity issues a degraded status
+var DefaultDegradedThresholds = map[types.IssueSeverity]int{types.SeverityHigh: 5}
+
+// HealthVerdict returns the overall status for issue counts by severity. The status is
+// critical when any critical threshold is reached, otherwise degraded when any degraded
+// threshold is reached, otherwise healthy. Nil thresholds use the defaults.
+func HealthVerdict(bySeverity map[types.IssueSeverity]int, critical, degraded map[types.IssueSeverity]int) types.HealthStatus {
+	if critical == nil {
+		critical = DefaultCriticalThresholds
+	}
+	if degraded == nil {
+		degraded = DefaultDegradedThresholds
+	}
+
+	reached := func(thresholds map[types.IssueSeverity]int) bool {
+		for severity, count := range thresholds {
+			if bySeverity[severity] >= count {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case reached(critical):
+		return types.StatusCritical
+	case reached(degraded):
+		return types.StatusDegraded
+	}
+	return types.StatusHealthy
+}
+
+// affectedNodes returns the distinct nodes named by issues
+func affectedNodes(issues []types.CSIMountIssue) []string {
+	seen := make(map[string]bool)
+	var nodes []string
+	for _, issue := range issues {
+		if issue.Node != "" && !seen[issue.Node] {
+			seen[issue.Node] = true
+			nodes = append(nodes, issue.Node)
+		}
+	}
+	return nodes
+}
+
+// overrideSeverities forces the configured severity onto issues of each overridden type,
+// so that filtering and the summary use the overridden values
+func (d *Detector) overrideSeverities(issues []types.CSIMountIssue) []types.CSIMountIssue {
+	if len(d.options.SeverityOverrides) == 0 {
 		return issues
 	}
+	for i := range issues {
+		if severity, ok := d.options.SeverityOverrides[issues[i].Type]; ok {
+			issues[i].Severity = severity
+		}
+	}
+	return issues
+}
 
-	severityOrder := map[types.IssueSeverity]int{
-		types.SeverityLow:      1,
-		types.SeverityMedium:   2,
-		types.SeverityHigh:     3,
-		types.SeverityCritical: 4,
+// FilterBySeverity returns the issues at or above minSeverity, or all issues when it is empty
+func FilterBySeverity(issues []types.CSIMountIssue, minSeverity types.IssueSeverity) []types.CSIMountIssue {
+	if minSeverity == "" {
+		return issues
 	}
 
-	minLevel := severityOrder[minSeverity]
+	minLevel := minSeverity.Level()
 	var filtered []types.CSIMountIssue
 
 	for _, issue := range issues {
-		if severityOrder[issue.Severity] >= minLevel {
+		if issue.Severity.Level() >= minLevel {
 			filtered = append(filtered, issue)
 		}
 	}
@@ -138,6 +378,12 @@ func (d *Detector) filterBySeverity(issues []types.CSIMountIssue, minSeverity ty
 
 // generateSummary creates a summary of detected issues
 func (d *Detector) generateSummary(issues []types.CSIMountIssue, methodsUsed []types.DetectionMethod) types.DetectionSummary {
+	return Summarize(issues, methodsUsed)
+}
+
+// Summarize counts issues by severity and type and collects the affected nodes and
+// drivers, for results whose issues are changed after detection
+func Summarize(issues []types.CSIMountIssue, methodsUsed []types.DetectionMethod) types.DetectionSummary {
 	summary := types.DetectionSummary{
 		TotalIssues:      len(issues),
 		IssuesBySeverity: make(map[types.IssueSeverity]int),
@@ -160,9 +406,24 @@ func (d *Detector) generateSummary(issues []types.CSIMountIssue, methodsUsed []t
 			nodeSet[issue.Node] = true
 		}
 
-		// Track affected drivers
+		// Track affected drivers and tally their issues
 		if issue.Driver != "" {
 			driverSet[issue.Driver] = true
+
+			if summary.IssuesByDriver == nil {
+				summary.IssuesByDriver = make(map[string]types.DriverIssueCounts)
+			}
+			counts, ok := summary.IssuesByDriver[issue.Driver]
+			if !ok {
+				counts = types.DriverIssueCounts{
+					BySeverity: make(map[types.IssueSeverity]int),
+					ByType:     make(map[types.IssueType]int),
+				}
+			}
+			counts.Total++
+			counts.BySeverity[issue.Severity]++
+			counts.ByType[issue.Type]++
+			summary.IssuesByDriver[issue.Driver] = counts
 		}
 	}
 
@@ -182,7 +443,7 @@ func (d *Detector) generateSummary(issues []types.CSIMountIssue, methodsUsed []t
 }
 
 // generateRecommendations creates cleanup and remediation recommendations
-func (d *Detector) generateRecommendations(issues []types.CSIMountIssue) []string {
+func (d *Detector) generateRecommendations(issues []types.CSIMountIssue, workloads []types.AffectedWorkload) []string {
 	var recommendations []string
 
 	// Track types of issues found
@@ -190,20 +451,26 @@ func (d *Detector) generateRecommendations(issues []types.CSIMountIssue) []strin
 	hasMultipleAttachments := false
 	hasStuckMountReferences := false
 	hasCSIOperationFailures := false
+	hasMissingPVCs := false
+	hasUnreconciledAttachments := false
 
 	affectedNodes := make(map[string]bool)
 	affectedDrivers := make(map[string]bool)
 
 	for _, issue := range issues {
 		switch issue.Type {
-		case types.VolumeAttachmentConflict:
+		case types.VolumeAttachmentConflict, types.AttachedWithoutClaim:
 			hasVolumeAttachmentConflicts = true
 		case types.MultipleAttachments:
 			hasMultipleAttachments = true
-		case types.StuckMountReference:
+		case types.StuckMountReference, types.DeviceBusy:
 			hasStuckMountReferences = true
 		case types.CSIOperationFailure:
 			hasCSIOperationFailures = true
+		case types.MissingPVC:
+			hasMissingPVCs = true
+		case types.AttachmentNotReconciled:
+			hasUnreconciledAttachments = true
 		}
 
 		if issue.Node != "" {
@@ -242,6 +509,22 @@ func (d *Detector) generateRecommendations(issues []types.CSIMountIssue) []strin
 		)
 	}
 
+	if hasMissingPVCs {
+		recommendations = append(recommendations,
+			"6. **Restore missing PVCs**:",
+			"   - Confirm the claim is gone: kubectl get pvc -n <namespace>",
+			"   - Recreate the PVC or fix the claimName in the workload spec",
+		)
+	}
+
+	if hasUnreconciledAttachments {
+		recommendations = append(recommendations,
+			"7. **Check the external-attacher**:",
+			"   - Find the csi-attacher sidecar: kubectl get pods -A -o wide | grep -i attacher",
+			"   - Check its logs and leader election: kubectl logs -n <namespace> <controller-pod> -c csi-attacher",
+		)
+	}
+
 	// Node-specific recommendations
 	if len(affectedNodes) > 0 {
 		recommendations = append(recommendations, "\n## Affected Nodes")
@@ -252,43 +535,39 @@ func (d *Detector) generateRecommendations(issues []types.CSIMountIssue) []strin
 		}
 	}
 
+	// Workloads consuming affected PVCs
+	recommendations = append(recommendations, workloadRecommendations(workloads)...)
+
 	// Driver-specific recommendations
 	if len(affectedDrivers) > 0 {
 		recommendations = append(recommendations, "\n## Driver-Specific Actions")
 		for driver := range affectedDrivers {
-			switch driver {
-			case "cinder.csi.openstack.org":
-				recommendations = append(recommendations,
-					fmt.Sprintf("**%s**:", driver),
-					"- Consider upgrading cinder CSI driver to latest version",
-					"- Check OpenStack Cinder service health",
-					"- Review volume attachment limits in OpenStack",
-				)
-			case "rook-ceph.rbd.csi.ceph.com", "rook-ceph.cephfs.csi.ceph.com":
-				recommendations = append(recommendations,
-					fmt.Sprintf("**%s**:", driver),
-					"- Check Ceph cluster health: kubectl -n rook-ceph exec -it deploy/rook-ceph-tools -- ceph status",
-					"- Review Rook operator logs",
-					"- Verify network connectivity to Ceph cluster",
-				)
-			default:
-				recommendations = append(recommendations,
-					fmt.Sprintf("**%s**:", driver),
-					"- Check CSI driver pods are healthy",
-					"- Review driver-specific documentation for troubleshooting",
-				)
+			recommendations = append(recommendations, fmt.Sprintf("**%s**:", driver))
+			if d.options.Offline {
+				recommendations = append(recommendations, offlineDriverRecommendations(driver)...)
+			} else {
+				recommendations = append(recommendations, driverRecommendations(driver)...)
 			}
 		}
 	}
 
 	// Long-term recommendations
-	recommendations = append(recommendations,
-		"\n## Long-term Solutions",
-		"1. **Monitoring**: Set up Prometheus alerts for CSI operation failures",
-		"2. **Automation**: Deploy automated cleanup scripts for recurring issues",
-		"3. **Upgrades**: Keep CSI drivers updated to latest stable versions",
-		"4. **Documentation**: Document cleanup procedures for operations team",
-	)
+	if d.options.Offline {
+		recommendations = append(recommendations,
+			"\n## Long-term Solutions",
+			"1. **Monitoring**: Set up in-cluster Prometheus alerts for CSI operation failures",
+			"2. **Automation**: Deploy automated cleanup scripts for recurring issues",
+			"3. **Documentation**: Document cleanup procedures for operations team",
+		)
+	} else {
+		recommendations = append(recommendations,
+			"\n## Long-term Solutions",
+			"1. **Monitoring**: Set up Prometheus alerts for CSI operation failures",
+			"2. **Automation**: Deploy automated cleanup scripts for recurring issues",
+			"3. **Upgrades**: Keep CSI drivers updated to latest stable versions",
+			"4. **Documentation**: Document cleanup procedures for operations team",
+		)
+	}
 
 	// Safety warnings
 	recommendations = append(recommendations,
@@ -302,6 +581,51 @@ func (d *Detector) generateRecommendations(issues []types.CSIMountIssue) []strin
 	return recommendations
 }
 
+// driverRecommendations returns remediation steps for a CSI driver
+func driverRecommendations(driver string) []string {
+	switch driver {
+	case "cinder.csi.openstack.org":
+		return []string{
+			"- Consider upgrading cinder CSI driver to latest version",
+			"- Check OpenStack Cinder service health",
+			"- Review volume attachment limits in OpenStack",
+		}
+	case "rook-ceph.rbd.csi.ceph.com", "rook-ceph.cephfs.csi.ceph.com":
+		return []string{
+			"- Check Ceph cluster health: kubectl -n rook-ceph exec -it deploy/rook-ceph-tools -- ceph status",
+			"- Review Rook operator logs",
+			"- Verify network connectivity to Ceph cluster",
+		}
+	default:
+		return []string{
+			"- Check CSI driver pods are healthy",
+			"- Review driver-specific documentation for troubleshooting",
+		}
+	}
+}
+
+// offlineDriverRecommendations returns remediation steps for a CSI driver that only
+// need access to the cluster, for air-gapped environments
+func offlineDriverRecommendations(driver string) []string {
+	switch driver {
+	case "cinder.csi.openstack.org":
+		return []string{
+			"- Check cinder CSI controller logs: kubectl -n kube-system logs -l app=csi-cinder-controllerplugin --all-containers",
+			"- Check per-node attach limits: kubectl get csinode -o custom-columns=NODE:.metadata.name,LIMIT:.spec.drivers[*].allocatable.count",
+		}
+	case "rook-ceph.rbd.csi.ceph.com", "rook-ceph.cephfs.csi.ceph.com":
+		return []string{
+			"- Check Ceph cluster health: kubectl -n rook-ceph exec -it deploy/rook-ceph-tools -- ceph status",
+			"- Review Rook operator logs: kubectl -n rook-ceph logs deploy/rook-ceph-operator",
+		}
+	default:
+		return []string{
+			"- Check CSI driver pods are healthy: kubectl get pods -A -o wide | grep csi",
+			"- Review CSI driver logs: kubectl logs -n <namespace> <csi-pod> --all-containers",
+		}
+	}
+}
+
 // getSortedKeys returns sorted slice of map keys
 func getSortedKeys(m map[string]bool) []string {
 	keys := make([]string, 0, len(m))
@@ -314,19 +638,22 @@ func getSortedKeys(m map[string]bool) []string {
 
 // GetDetailedAnalysis provides additional detailed analysis for debugging
 func (d *Detector) GetDetailedAnalysis(ctx context.Context) (*DetailedAnalysis, error) {
-	analysis := &DetailedAnalysis{}
+	analysis := &DetailedAnalysis{Driver: d.options.TargetDriver}
 
 	// Get VolumeAttachment details if available
 	if d.volumeAttachmentDetector != nil {
-		vas, err := d.client.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+		byNode, err := d.volumeAttachmentDetector.GetVolumeAttachmentsByNode(ctx)
 		if err == nil {
-			analysis.VolumeAttachmentCount = len(vas.Items)
-			for _, va := range vas.Items {
-				if va.Status.Attached {
-					analysis.AttachedVolumeCount++
-				}
-				if va.Status.AttachError != nil || va.Status.DetachError != nil {
-					analysis.VolumeAttachmentErrors++
+			analysis.VolumeAttachmentsByNode = byNode
+			for _, infos := range byNode {
+				for _, info := range infos {
+					analysis.VolumeAttachmentCount++
+					if info.Attached {
+						analysis.AttachedVolumeCount++
+					}
+					if info.AttachError != "" || info.DetachError != "" {
+						analysis.VolumeAttachmentErrors++
+					}
 				}
 			}
 		}
@@ -337,6 +664,7 @@ func (d *Detector) GetDetailedAnalysis(ctx context.Context) (*DetailedAnalysis,
 		nodeUsage, err := d.crossNodePVCDetector.GetNodePVCUsage(ctx)
 		if err == nil {
 			analysis.NodePVCUsage = nodeUsage
+			analysis.TopPVCs = TopPVCs(nodeUsage, d.options.TopPVCs)
 		}
 	}
 
@@ -359,11 +687,14 @@ func (d *Detector) GetDetailedAnalysis(ctx context.Context) (*DetailedAnalysis,
 
 // DetailedAnalysis contains additional analysis information
 type DetailedAnalysis struct {
-	VolumeAttachmentCount  int                      `json:"volumeAttachmentCount"`
-	AttachedVolumeCount    int                      `json:"attachedVolumeCount"`
-	VolumeAttachmentErrors int                      `json:"volumeAttachmentErrors"`
-	NodePVCUsage          []types.NodePVCUsage     `json:"nodePVCUsage"`
-	RecentEvents          []types.EventInfo        `json:"recentEvents"`
-	MetricQueries         []types.MetricQuery      `json:"metricQueries"`
-	RecommendedAlerts     []string                 `json:"recommendedAlerts"`
+	Driver                  string                                  `json:"driver,omitempty"`
+	VolumeAttachmentCount   int                                     `json:"volumeAttachmentCount"`
+	AttachedVolumeCount     int                                     `json:"attachedVolumeCount"`
+	VolumeAttachmentErrors  int                                     `json:"volumeAttachmentErrors"`
+	VolumeAttachmentsByNode map[string][]types.VolumeAttachmentInfo `json:"volumeAttachmentsByNode"` // node -> attachments
+	NodePVCUsage            []types.NodePVCUsage                    `json:"nodePVCUsage"`
+	TopPVCs                 []types.PVCUsageSummary                 `json:"topPVCs"`
+	RecentEvents            []types.EventInfo                       `json:"recentEvents"`
+	MetricQueries           []types.MetricQuery                     `json:"metricQueries"`
+	RecommendedAlerts       []string                                `json:"recommendedAlerts"`
 }
\ No newline at end of file
@@ -2,6 +2,9 @@ package detect_test
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"time"
 
@@ -10,6 +13,7 @@ import (
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/jdambly/kubectl-csi-scan/pkg/client/mocks"
@@ -25,6 +29,7 @@ var _ = Describe("Detector", func() {
 		mockStorageV1 *mocks.MockStorageV1Interface
 		detector      *detect.Detector
 		ctx           context.Context
+		pvs           map[string]*corev1.PersistentVolume // PVs the API serves; others are NotFound
 	)
 
 	BeforeEach(func() {
@@ -37,6 +42,17 @@ var _ = Describe("Detector", func() {
 		// Set up default mock expectations
 		mockClient.EXPECT().CoreV1().Return(mockCoreV1).AnyTimes()
 		mockClient.EXPECT().StorageV1().Return(mockStorageV1).AnyTimes()
+
+		mockPVs := mocks.NewMockPersistentVolumeInterface(ctrl)
+		pvs = map[string]*corev1.PersistentVolume{}
+		mockCoreV1.EXPECT().PersistentVolumes().Return(mockPVs).AnyTimes()
+		mockPVs.EXPECT().Get(gomock.Any(), gomock.Any(), metav1.GetOptions{}).DoAndReturn(
+			func(_ context.Context, name string, _ metav1.GetOptions) (*corev1.PersistentVolume, error) {
+				if pv, ok := pvs[name]; ok {
+					return pv, nil
+				}
+				return nil, apierrors.NewNotFound(corev1.Resource("persistentvolumes"), name)
+			}).AnyTimes()
 	})
 
 	AfterEach(func() {
@@ -96,6 +112,48 @@ var _ = Describe("Detector", func() {
 			Expect(result.Summary.TotalIssues).To(Equal(0))
 		})
 
+		It("should record the snapshot time within the run window", func() {
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+			mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(&storagev1.VolumeAttachmentList{}, nil)
+
+			before := time.Now()
+			result, err := detector.DetectAll(ctx)
+			after := time.Now()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Summary.SnapshotTime).To(BeTemporally(">=", before))
+			Expect(result.Summary.SnapshotTime).To(BeTemporally("<=", after))
+			Expect(result.Summary.SnapshotTime).To(BeTemporally("<=", result.GeneratedAt))
+		})
+
+		It("should report the snapshot time on the API server's clock", func() {
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+			mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(&storagev1.VolumeAttachmentList{}, nil)
+			detector.SetServerClock(offsetClock(time.Hour))
+
+			before := time.Now()
+			result, err := detector.DetectAll(ctx)
+			after := time.Now()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Summary.SnapshotTime).To(BeTemporally(">=", before.Add(time.Hour)))
+			Expect(result.Summary.SnapshotTime).To(BeTemporally("<=", after.Add(time.Hour)))
+		})
+
+		It("should record the resourceVersion of the lists the methods read", func() {
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+			mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(&storagev1.VolumeAttachmentList{
+				ListMeta: metav1.ListMeta{ResourceVersion: "4711"},
+			}, nil)
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Summary.ResourceVersions).To(Equal(map[string]string{"volumeattachments": "4711"}))
+		})
+
 		It("should handle context cancellation", func() {
 			// Set up mock expectations for the VolumeAttachments call
 			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
@@ -110,6 +168,110 @@ var _ = Describe("Detector", func() {
 			Expect(err).To(MatchError(ContainSubstring("context canceled")))
 		})
 
+		It("should return partial results when cancelled between methods", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:          []types.DetectionMethod{types.VolumeAttachmentMethod, types.EventsMethod},
+				RecommendCleanup: true,
+			})
+			cancelCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+			mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(&storagev1.VolumeAttachmentList{
+				Items: []storagev1.VolumeAttachment{{
+					ObjectMeta: metav1.ObjectMeta{Name: "va-1"},
+					Spec:       storagev1.VolumeAttachmentSpec{Attacher: "test.csi.driver", NodeName: "node-1"},
+					Status: storagev1.VolumeAttachmentStatus{
+						AttachError: &storagev1.VolumeError{Message: "attach failed"},
+					},
+				}},
+			}, nil)
+
+			// The user interrupts while events are being listed
+			mockEvents := mocks.NewMockEventInterface(ctrl)
+			mockCoreV1.EXPECT().Events("").Return(mockEvents)
+			mockEvents.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(context.Context, metav1.ListOptions) (*corev1.EventList, error) {
+					cancel()
+					return nil, context.Canceled
+				})
+
+			result, err := detector.DetectAll(cancelCtx)
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(result).NotTo(BeNil())
+			Expect(result.Partial).To(BeTrue())
+			Expect(result.Issues).To(HaveLen(1))
+			Expect(result.Issues[0].Node).To(Equal("node-1"))
+			Expect(result.Summary.MethodsUsed).To(Equal([]types.DetectionMethod{types.VolumeAttachmentMethod}))
+			Expect(result.Recommendations).NotTo(BeEmpty())
+		})
+
+		It("should not return partial results for failures other than cancellation", func() {
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+			mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("forbidden"))
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(result).To(BeNil())
+		})
+
+		It("should keep the issues of the other methods when one fails in best-effort mode", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:    []types.DetectionMethod{types.VolumeAttachmentMethod, types.EventsMethod},
+				BestEffort: true,
+			})
+
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+			mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(&storagev1.VolumeAttachmentList{
+				Items: []storagev1.VolumeAttachment{{
+					ObjectMeta: metav1.ObjectMeta{Name: "va-1"},
+					Spec:       storagev1.VolumeAttachmentSpec{Attacher: "test.csi.driver", NodeName: "node-1"},
+					Status: storagev1.VolumeAttachmentStatus{
+						AttachError: &storagev1.VolumeError{Message: "attach failed"},
+					},
+				}},
+			}, nil)
+
+			// RBAC does not allow listing events
+			mockEvents := mocks.NewMockEventInterface(ctrl)
+			mockCoreV1.EXPECT().Events("").Return(mockEvents)
+			mockEvents.EXPECT().List(gomock.Any(), gomock.Any()).
+				Return(nil, apierrors.NewForbidden(corev1.Resource("events"), "", fmt.Errorf("access denied")))
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Partial).To(BeFalse())
+			Expect(result.Issues).To(HaveLen(1))
+			Expect(result.Issues[0].Node).To(Equal("node-1"))
+			Expect(result.Summary.MethodsUsed).To(Equal([]types.DetectionMethod{types.VolumeAttachmentMethod}))
+			Expect(result.Errors).To(HaveLen(1))
+			Expect(result.Errors[0].Method).To(Equal(types.EventsMethod))
+			Expect(result.Errors[0].Error).To(ContainSubstring("forbidden"))
+		})
+
+		It("should record every failed method in best-effort mode", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:    []types.DetectionMethod{types.VolumeAttachmentMethod, types.EventsMethod},
+				BestEffort: true,
+			})
+
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+			mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("forbidden"))
+			mockEvents := mocks.NewMockEventInterface(ctrl)
+			mockCoreV1.EXPECT().Events("").Return(mockEvents)
+			mockEvents.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.EventList{}, nil)
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(BeEmpty())
+			Expect(result.Summary.MethodsUsed).To(Equal([]types.DetectionMethod{types.EventsMethod}))
+			Expect(result.Errors).To(ConsistOf(HaveField("Method", types.VolumeAttachmentMethod)))
+		})
+
 		It("should handle timeout", func() {
 			timeoutCtx, cancel := context.WithTimeout(ctx, 1*time.Millisecond)
 			defer cancel()
@@ -123,6 +285,30 @@ var _ = Describe("Detector", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(MatchError(ContainSubstring("context deadline exceeded")))
 		})
+
+		It("should query the Prometheus URL with the metrics method", func() {
+			var queried bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				queried = true
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"status": "success", "data": {"resultType": "vector", "result": [
+					{"metric": {"driver_name": "test.csi.driver"}, "value": [1700000000, "1"]}
+				]}}`)
+			}))
+			defer server.Close()
+
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:       []types.DetectionMethod{types.MetricsMethod},
+				PrometheusURL: server.URL,
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queried).To(BeTrue())
+			Expect(result.Summary.MethodsUsed).To(Equal([]types.DetectionMethod{types.MetricsMethod}))
+			Expect(result.Issues).NotTo(BeEmpty())
+			Expect(result.Issues[0].DetectedBy).To(Equal(types.MetricsMethod))
+		})
 	})
 
 	Context("GetDetailedAnalysis", func() {
@@ -152,6 +338,63 @@ var _ = Describe("Detector", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(analysis).NotTo(BeNil())
 		})
+
+		It("should scope the analysis to the target driver", func() {
+			options := types.DetectionOptions{
+				Methods: []types.DetectionMethod{
+					types.VolumeAttachmentMethod,
+					types.EventsMethod,
+					types.MetricsMethod,
+				},
+				TargetDriver: "cinder.csi.openstack.org",
+			}
+			detector = detect.NewDetector(mockClient, options)
+
+			attachment := func(name, attacher string, attached bool) storagev1.VolumeAttachment {
+				return storagev1.VolumeAttachment{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec:       storagev1.VolumeAttachmentSpec{Attacher: attacher, NodeName: "node-1"},
+					Status:     storagev1.VolumeAttachmentStatus{Attached: attached},
+				}
+			}
+			event := func(name, message string) corev1.Event {
+				return corev1.Event{
+					ObjectMeta:    metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Type:          "Warning",
+					Reason:        "FailedMount",
+					Message:       message,
+					LastTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+				}
+			}
+
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockEvents := mocks.NewMockEventInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+			mockCoreV1.EXPECT().Events("").Return(mockEvents)
+			mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{
+				Items: []storagev1.VolumeAttachment{
+					attachment("va-cinder", "cinder.csi.openstack.org", true),
+					attachment("va-ebs-1", "ebs.csi.aws.com", true),
+					attachment("va-ebs-2", "ebs.csi.aws.com", false),
+				},
+			}, nil)
+			mockEvents.EXPECT().List(ctx, metav1.ListOptions{Limit: 500}).Return(&corev1.EventList{
+				Items: []corev1.Event{
+					event("cinder-event", "MountVolume.MountDevice failed: rpc error from cinder.csi.openstack.org"),
+					event("ebs-event", "MountVolume.MountDevice failed: rpc error from ebs.csi.aws.com"),
+				},
+			}, nil)
+
+			analysis, err := detector.GetDetailedAnalysis(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(analysis.Driver).To(Equal("cinder.csi.openstack.org"))
+			Expect(analysis.VolumeAttachmentCount).To(Equal(1))
+			Expect(analysis.AttachedVolumeCount).To(Equal(1))
+			Expect(analysis.RecentEvents).To(HaveLen(1))
+			Expect(analysis.RecentEvents[0].Message).To(ContainSubstring("cinder.csi.openstack.org"))
+			Expect(analysis.MetricQueries).NotTo(BeEmpty())
+			Expect(analysis.MetricQueries[0].Query).To(ContainSubstring(`driver_name="cinder.csi.openstack.org"`))
+		})
 	})
 
 	Context("Error Handling", func() {
@@ -174,6 +417,188 @@ var _ = Describe("Detector", func() {
 		})
 	})
 
+	Context("Node conditions", func() {
+		It("should check the conditions of nodes the other methods found issues on", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods: []types.DetectionMethod{types.VolumeAttachmentMethod, types.NodeConditionsMethod},
+			})
+
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+			mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(&storagev1.VolumeAttachmentList{
+				Items: []storagev1.VolumeAttachment{{
+					ObjectMeta: metav1.ObjectMeta{Name: "va-1"},
+					Spec:       storagev1.VolumeAttachmentSpec{Attacher: "test.csi.driver", NodeName: "node-1"},
+					Status: storagev1.VolumeAttachmentStatus{
+						AttachError: &storagev1.VolumeError{Message: "attach failed"},
+					},
+				}},
+			}, nil)
+
+			notReady := func(name string) corev1.Node {
+				return corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+						{Type: corev1.NodeReady, Status: corev1.ConditionFalse},
+					}},
+				}
+			}
+			mockNodes := mocks.NewMockNodeInterface(ctrl)
+			mockCoreV1.EXPECT().Nodes().Return(mockNodes)
+			mockNodes.EXPECT().List(gomock.Any(), metav1.ListOptions{}).Return(&corev1.NodeList{
+				Items: []corev1.Node{notReady("node-1"), notReady("node-2")},
+			}, nil)
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(2))
+			Expect(result.Issues[1].Type).To(Equal(types.UnhealthyNode))
+			Expect(result.Issues[1].Node).To(Equal("node-1"))
+			Expect(result.Summary.MethodsUsed).To(Equal([]types.DetectionMethod{types.VolumeAttachmentMethod, types.NodeConditionsMethod}))
+		})
+	})
+
+	Context("Namespace scoping", func() {
+		var (
+			mockPods   *mocks.MockPodInterface
+			mockEvents *mocks.MockEventInterface
+		)
+
+		BeforeEach(func() {
+			mockPods = mocks.NewMockPodInterface(ctrl)
+			mockEvents = mocks.NewMockEventInterface(ctrl)
+			mockPods.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.PodList{}, nil).AnyTimes()
+			mockEvents.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.EventList{}, nil).AnyTimes()
+		})
+
+		It("should list pods and events only in the selected namespace", func() {
+			mockCoreV1.EXPECT().Pods("shop").Return(mockPods).MinTimes(1)
+			mockCoreV1.EXPECT().Events("shop").Return(mockEvents).MinTimes(1)
+
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:   []types.DetectionMethod{types.CrossNodePVCMethod, types.EventsMethod},
+				Namespace: "shop",
+			})
+
+			_, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should list across all namespaces when none is selected", func() {
+			mockCoreV1.EXPECT().Pods("").Return(mockPods).MinTimes(1)
+			mockCoreV1.EXPECT().Events("").Return(mockEvents).MinTimes(1)
+
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods: []types.DetectionMethod{types.CrossNodePVCMethod, types.EventsMethod},
+			})
+
+			_, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("Events lookback", func() {
+		BeforeEach(func() {
+			mockEvents := mocks.NewMockEventInterface(ctrl)
+			mockCoreV1.EXPECT().Events("").Return(mockEvents)
+			mockEvents.EXPECT().List(gomock.Any(), metav1.ListOptions{Limit: 500}).Return(&corev1.EventList{
+				Items: []corev1.Event{{
+					ObjectMeta:     metav1.ObjectMeta{Name: "web-0.17a", Namespace: "shop"},
+					InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "web-0"},
+					Type:           "Warning",
+					Reason:         "FailedMount",
+					Message:        `MountVolume.MountDevice failed for volume "pvc-3c9d8e7f" : rpc error: timed out`,
+					LastTimestamp:  metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+				}},
+			}, nil)
+		})
+
+		It("should leave out events older than the default hour", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods: []types.DetectionMethod{types.EventsMethod},
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(BeEmpty())
+		})
+
+		It("should include older events within the configured lookback", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:        []types.DetectionMethod{types.EventsMethod},
+				EventsLookback: 3 * time.Hour,
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(1))
+			Expect(result.Issues[0].Volume).To(Equal("pvc-3c9d8e7f"))
+		})
+	})
+
+	Context("Correlation", func() {
+		var mockEvents *mocks.MockEventInterface
+
+		BeforeEach(func() {
+			pvName := "pvc-3c9d8e7f"
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+			mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(&storagev1.VolumeAttachmentList{
+				Items: []storagev1.VolumeAttachment{{
+					ObjectMeta: metav1.ObjectMeta{Name: "csi-0a1b2c"},
+					Spec: storagev1.VolumeAttachmentSpec{
+						Attacher: "test.csi.driver",
+						NodeName: "node-1",
+						Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+					},
+					Status: storagev1.VolumeAttachmentStatus{
+						AttachError: &storagev1.VolumeError{Message: "rpc error: timed out"},
+					},
+				}},
+			}, nil)
+
+			mockEvents = mocks.NewMockEventInterface(ctrl)
+			mockCoreV1.EXPECT().Events("").Return(mockEvents)
+			mockEvents.EXPECT().List(gomock.Any(), metav1.ListOptions{Limit: 500}).Return(&corev1.EventList{
+				Items: []corev1.Event{{
+					ObjectMeta:     metav1.ObjectMeta{Name: "web-0.17a", Namespace: "shop"},
+					InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "web-0"},
+					Source:         corev1.EventSource{Host: "node-1"},
+					Type:           "Warning",
+					Reason:         "FailedAttachVolume",
+					Message:        `AttachVolume.Attach failed for volume "pvc-3c9d8e7f" : rpc error: timed out`,
+					LastTimestamp:  metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+				}},
+			}, nil)
+		})
+
+		It("should report a volume both methods found on a node once", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods: []types.DetectionMethod{types.VolumeAttachmentMethod, types.EventsMethod},
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(1))
+			Expect(result.Summary.TotalIssues).To(Equal(1))
+			Expect(result.Issues[0].Metadata).To(HaveKeyWithValue("related_methods", "volumeattachments,events"))
+			Expect(result.Issues[0].Metadata).To(HaveKeyWithValue("volumeattachment_name", "csi-0a1b2c"))
+			Expect(result.Issues[0].Metadata).To(HaveKey("event_reason"))
+		})
+
+		It("should report each method's issue with NoDedup", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods: []types.DetectionMethod{types.VolumeAttachmentMethod, types.EventsMethod},
+				NoDedup: true,
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(2))
+			Expect(result.Issues[0].Metadata).NotTo(HaveKey("related_methods"))
+		})
+	})
+
 	Context("Severity Filtering", func() {
 		BeforeEach(func() {
 			options := types.DetectionOptions{
@@ -315,6 +740,699 @@ var _ = Describe("Detector", func() {
 			Expect(recommendations).To(ContainSubstring("Priority nodes for cleanup"))
 			Expect(recommendations).To(Or(ContainSubstring("node-1"), ContainSubstring("node-2")))
 		})
+
+		Context("in offline mode", func() {
+			recommendationsFor := func(offline bool, drivers ...string) string {
+				var items []storagev1.VolumeAttachment
+				for i, driver := range drivers {
+					items = append(items, storagev1.VolumeAttachment{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:              fmt.Sprintf("stuck-va-%d", i),
+							CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+						},
+						Spec: storagev1.VolumeAttachmentSpec{
+							Attacher: driver,
+							NodeName: "node-1",
+							Source: storagev1.VolumeAttachmentSource{
+								PersistentVolumeName: stringPtr(fmt.Sprintf("pv-%d", i)),
+							},
+						},
+					})
+				}
+
+				mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+				mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+				mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(&storagev1.VolumeAttachmentList{Items: items}, nil)
+
+				detector = detect.NewDetector(mockClient, types.DetectionOptions{
+					Methods:          []types.DetectionMethod{types.VolumeAttachmentMethod},
+					RecommendCleanup: true,
+					Offline:          offline,
+				})
+				result, err := detector.DetectAll(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				return strings.Join(result.Recommendations, "\n")
+			}
+
+			drivers := []string{"cinder.csi.openstack.org", "rook-ceph.rbd.csi.ceph.com", "test.csi.driver"}
+
+			It("should not suggest anything needing external connectivity", func() {
+				recommendations := recommendationsFor(true, drivers...)
+				for _, external := range []string{"OpenStack", "pgrad", "latest", "documentation", "network connectivity"} {
+					Expect(recommendations).NotTo(ContainSubstring(external))
+				}
+				Expect(recommendations).To(ContainSubstring("kubectl -n kube-system logs"))
+				Expect(recommendations).To(ContainSubstring("kubectl -n rook-ceph logs deploy/rook-ceph-operator"))
+			})
+
+			It("should keep external suggestions when online", func() {
+				recommendations := recommendationsFor(false, drivers...)
+				Expect(recommendations).To(ContainSubstring("OpenStack"))
+				Expect(recommendations).To(ContainSubstring("latest stable versions"))
+			})
+		})
+	})
+
+	Context("Workload Rollup", func() {
+		var (
+			mockEvents *mocks.MockEventInterface
+			mockPods   *mocks.MockPodInterface
+		)
+
+		BeforeEach(func() {
+			options := types.DetectionOptions{
+				Methods:          []types.DetectionMethod{types.EventsMethod},
+				RecommendCleanup: true,
+				WithOwners:       true,
+			}
+			detector = detect.NewDetector(mockClient, options)
+
+			mockEvents = mocks.NewMockEventInterface(ctrl)
+			mockPods = mocks.NewMockPodInterface(ctrl)
+			mockCoreV1.EXPECT().Events("").Return(mockEvents).AnyTimes()
+			mockCoreV1.EXPECT().Pods("").Return(mockPods).AnyTimes()
+		})
+
+		pvcEvent := func(pvc string) corev1.Event {
+			recentTime := time.Now().Add(-10 * time.Minute)
+			return corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      pvc + "-event",
+					Namespace: "default",
+				},
+				Type:          "Warning",
+				Reason:        "FailedAttachVolume",
+				Message:       "AttachVolume.Attach failed for volume \"pv-" + pvc + "\"",
+				LastTimestamp: metav1.NewTime(recentTime),
+				InvolvedObject: corev1.ObjectReference{
+					Kind:      "PersistentVolumeClaim",
+					Name:      pvc,
+					Namespace: "default",
+				},
+				Count: 1,
+			}
+		}
+
+		statefulSetPod := func(name, pvc string) corev1.Pod {
+			isController := true
+			return corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					OwnerReferences: []metav1.OwnerReference{
+						{Kind: "StatefulSet", Name: "web", Controller: &isController},
+					},
+				},
+				Spec: corev1.PodSpec{
+					NodeName: "node-1",
+					Volumes: []corev1.Volume{
+						{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: pvc,
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("should roll up pods of one StatefulSet into a single workload", func() {
+			mockEvents.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.EventList{
+				Items: []corev1.Event{pvcEvent("data-web-0"), pvcEvent("data-web-1")},
+			}, nil)
+			mockPods.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.PodList{
+				Items: []corev1.Pod{
+					statefulSetPod("web-0", "data-web-0"),
+					statefulSetPod("web-1", "data-web-1"),
+				},
+			}, nil)
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(2))
+
+			recommendations := strings.Join(result.Recommendations, "\n")
+			Expect(recommendations).To(ContainSubstring("## Affected Workloads"))
+			Expect(recommendations).To(ContainSubstring("- StatefulSet default/web: 2 issue(s)"))
+			Expect(strings.Count(recommendations, "StatefulSet default/web")).To(Equal(1))
+		})
+
+		It("should page through the pods of the scanned namespace only", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:          []types.DetectionMethod{types.EventsMethod},
+				RecommendCleanup: true,
+				WithOwners:       true,
+				Namespace:        "default",
+			})
+			mockCoreV1.EXPECT().Events("default").Return(mockEvents)
+			mockCoreV1.EXPECT().Pods("default").Return(mockPods)
+			mockEvents.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.EventList{
+				Items: []corev1.Event{pvcEvent("data-web-0"), pvcEvent("data-web-1")},
+			}, nil)
+			gomock.InOrder(
+				mockPods.EXPECT().List(gomock.Any(), metav1.ListOptions{Limit: 500}).Return(&corev1.PodList{
+					ListMeta: metav1.ListMeta{Continue: "page-2"},
+					Items:    []corev1.Pod{statefulSetPod("web-0", "data-web-0")},
+				}, nil),
+				mockPods.EXPECT().List(gomock.Any(), metav1.ListOptions{Limit: 500, Continue: "page-2"}).Return(&corev1.PodList{
+					Items: []corev1.Pod{statefulSetPod("web-1", "data-web-1")},
+				}, nil),
+			)
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.Join(result.Recommendations, "\n")).To(ContainSubstring("- StatefulSet default/web: 2 issue(s)"))
+		})
+
+		It("should fail when consuming pods cannot be listed", func() {
+			mockEvents.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.EventList{
+				Items: []corev1.Event{pvcEvent("data-web-0")},
+			}, nil)
+			mockPods.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, context.DeadlineExceeded)
+
+			_, err := detector.DetectAll(ctx)
+			Expect(err).To(MatchError(ContainSubstring("workload rollup failed")))
+		})
+
+		It("should record a rollup failure and carry on in best-effort mode", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:          []types.DetectionMethod{types.EventsMethod},
+				RecommendCleanup: true,
+				WithOwners:       true,
+				BestEffort:       true,
+			})
+			mockEvents.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.EventList{
+				Items: []corev1.Event{pvcEvent("data-web-0")},
+			}, nil)
+			mockPods.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("pods forbidden"))
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(1))
+			Expect(result.Errors).To(ConsistOf(types.MethodError{Method: types.WorkloadRollupMethod, Error: "failed to list pods: pods forbidden"}))
+		})
+	})
+
+	Context("Suppressions", func() {
+		var mockEvents *mocks.MockEventInterface
+
+		pvcEvent := func(pvc string) corev1.Event {
+			return corev1.Event{
+				ObjectMeta:    metav1.ObjectMeta{Name: pvc + "-event", Namespace: "default"},
+				Type:          "Warning",
+				Reason:        "FailedAttachVolume",
+				Message:       "AttachVolume.Attach failed for volume \"pv-" + pvc + "\"",
+				LastTimestamp: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+				InvolvedObject: corev1.ObjectReference{
+					Kind:      "PersistentVolumeClaim",
+					Name:      pvc,
+					Namespace: "default",
+				},
+				Count: 1,
+			}
+		}
+
+		BeforeEach(func() {
+			mockEvents = mocks.NewMockEventInterface(ctrl)
+			mockCoreV1.EXPECT().Events("").Return(mockEvents).AnyTimes()
+			mockEvents.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.EventList{
+				Items: []corev1.Event{pvcEvent("scratch-1"), pvcEvent("data-web-0")},
+			}, nil)
+		})
+
+		It("should remove issues matching a PVC rule and count them as suppressed", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:      []types.DetectionMethod{types.EventsMethod},
+				Suppressions: []types.SuppressionRule{{PVC: "default/scratch-*"}},
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(1))
+			Expect(result.Issues[0].PVC).To(Equal("data-web-0"))
+			Expect(result.Summary.TotalIssues).To(Equal(1))
+			Expect(result.Summary.Suppressed).To(Equal(1))
+		})
+
+		It("should require every field of a rule to match", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods: []types.DetectionMethod{types.EventsMethod},
+				Suppressions: []types.SuppressionRule{
+					{PVC: "scratch-*", Type: types.MultiAttachError},
+					{},
+				},
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(2))
+			Expect(result.Summary.Suppressed).To(BeZero())
+		})
+	})
+
+	Context("PVC focus", func() {
+		BeforeEach(func() {
+			mockPVCs := mocks.NewMockPersistentVolumeClaimInterface(ctrl)
+			mockPods := mocks.NewMockPodInterface(ctrl)
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockCoreV1.EXPECT().PersistentVolumeClaims("default").Return(mockPVCs).AnyTimes()
+			mockCoreV1.EXPECT().Pods("").Return(mockPods).AnyTimes()
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments).AnyTimes()
+
+			// Two claims, each bound to its own CSI volume
+			for _, claim := range []string{"data", "logs"} {
+				mockPVCs.EXPECT().Get(gomock.Any(), claim, metav1.GetOptions{}).Return(&corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: claim, Namespace: "default"},
+					Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-" + claim},
+				}, nil).AnyTimes()
+				pvs["pv-"+claim] = &corev1.PersistentVolume{
+					Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{Driver: "test.csi.driver"},
+					}},
+				}
+			}
+
+			// Both claims are used from two nodes, and both volumes failed to attach
+			pod := func(name, node, claim string) corev1.Pod {
+				return corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec: corev1.PodSpec{
+						NodeName: node,
+						Volumes: []corev1.Volume{{Name: "vol", VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+						}}},
+					},
+				}
+			}
+			mockPods.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.PodList{Items: []corev1.Pod{
+				pod("data-a", "node-1", "data"), pod("data-b", "node-2", "data"),
+				pod("logs-a", "node-1", "logs"), pod("logs-b", "node-3", "logs"),
+			}}, nil)
+
+			attachment := func(pv, node string) storagev1.VolumeAttachment {
+				return storagev1.VolumeAttachment{
+					ObjectMeta: metav1.ObjectMeta{Name: "va-" + pv},
+					Spec: storagev1.VolumeAttachmentSpec{
+						Attacher: "test.csi.driver",
+						NodeName: node,
+						Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: stringPtr(pv)},
+					},
+					Status: storagev1.VolumeAttachmentStatus{AttachError: &storagev1.VolumeError{Message: "attach timed out"}},
+				}
+			}
+			mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(&storagev1.VolumeAttachmentList{
+				Items: []storagev1.VolumeAttachment{attachment("pv-data", "node-1"), attachment("pv-logs", "node-3")},
+			}, nil)
+		})
+
+		It("should only report issues about the PVC and its bound PV", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods: []types.DetectionMethod{types.VolumeAttachmentMethod, types.CrossNodePVCMethod},
+				PVC:     "default/data",
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(2))
+			for _, issue := range result.Issues {
+				Expect([]string{issue.PVC, issue.Volume}).To(ContainElement(BeElementOf("default/data", "pv-data")))
+			}
+			Expect(result.Summary.IssuesByType).To(Equal(map[types.IssueType]int{
+				types.FailedAttachVolume:  1,
+				types.MultipleAttachments: 1,
+			}))
+		})
+
+		It("should report every PVC without a focus", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods: []types.DetectionMethod{types.VolumeAttachmentMethod, types.CrossNodePVCMethod},
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(4))
+		})
+	})
+
+	Context("Duplicate methods", func() {
+		It("should run a method listed twice only once", func() {
+			mockEvents := mocks.NewMockEventInterface(ctrl)
+			mockCoreV1.EXPECT().Events("").Return(mockEvents).AnyTimes()
+			mockEvents.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.EventList{
+				Items: []corev1.Event{{
+					ObjectMeta:     metav1.ObjectMeta{Name: "data-event", Namespace: "default"},
+					Type:           "Warning",
+					Reason:         "FailedAttachVolume",
+					Message:        "AttachVolume.Attach failed for volume \"pv-data\"",
+					LastTimestamp:  metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+					InvolvedObject: corev1.ObjectReference{Kind: "PersistentVolumeClaim", Name: "data", Namespace: "default"},
+					Count:          1,
+				}},
+			}, nil).Times(1)
+
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods: []types.DetectionMethod{types.EventsMethod, types.EventsMethod},
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(1))
+			Expect(result.Summary.MethodsUsed).To(Equal([]types.DetectionMethod{types.EventsMethod}))
+		})
+	})
+
+	Context("Severity overrides", func() {
+		BeforeEach(func() {
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments).AnyTimes()
+
+			attachment := func(name, node string) storagev1.VolumeAttachment {
+				return storagev1.VolumeAttachment{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec: storagev1.VolumeAttachmentSpec{
+						Attacher: "test.csi.driver",
+						NodeName: node,
+						Source: storagev1.VolumeAttachmentSource{
+							PersistentVolumeName: stringPtr("shared-pv"),
+						},
+					},
+					Status: storagev1.VolumeAttachmentStatus{Attached: true},
+				}
+			}
+			mockVolumeAttachments.EXPECT().List(gomock.Any(), gomock.Any()).Return(&storagev1.VolumeAttachmentList{
+				Items: []storagev1.VolumeAttachment{attachment("va-1", "node-1"), attachment("va-2", "node-2")},
+			}, nil)
+		})
+
+		It("should force the overridden severity onto the issue and the summary", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:           []types.DetectionMethod{types.VolumeAttachmentMethod},
+				SeverityOverrides: map[types.IssueType]types.IssueSeverity{types.MultipleAttachments: types.SeverityCritical},
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(1))
+			Expect(result.Issues[0].Type).To(Equal(types.MultipleAttachments))
+			Expect(result.Issues[0].Severity).To(Equal(types.SeverityCritical))
+			Expect(result.Summary.IssuesBySeverity).To(Equal(map[types.IssueSeverity]int{types.SeverityCritical: 1}))
+		})
+
+		It("should filter on the overridden severity", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:           []types.DetectionMethod{types.VolumeAttachmentMethod},
+				MinSeverity:       types.SeverityHigh,
+				SeverityOverrides: map[types.IssueType]types.IssueSeverity{types.MultipleAttachments: types.SeverityLow},
+			})
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(BeEmpty())
+		})
+	})
+
+	Describe("AffectedPVCs", func() {
+		It("should return distinct namespace/name keys in order", func() {
+			pvcs := detect.AffectedPVCs([]types.CSIMountIssue{
+				{PVC: "data-web-1", Namespace: "default"},
+				{PVC: "batch/scratch"},
+				{PVC: "data-web-1", Namespace: "default"},
+				{PVC: "orphan"},
+				{Node: "node-1"},
+			})
+			Expect(pvcs).To(Equal([]string{"batch/scratch", "default/data-web-1"}))
+		})
+	})
+
+	Describe("HealthVerdict", func() {
+		DescribeTable("should map issue distributions to a status",
+			func(bySeverity, critical, degraded map[types.IssueSeverity]int, expected types.HealthStatus) {
+				Expect(detect.HealthVerdict(bySeverity, critical, degraded)).To(Equal(expected))
+			},
+			Entry("no issues", nil, nil, nil, types.StatusHealthy),
+			Entry("a few low and medium issues",
+				map[types.IssueSeverity]int{types.SeverityLow: 10, types.SeverityMedium: 3}, nil, nil, types.StatusHealthy),
+			Entry("four high issues",
+				map[types.IssueSeverity]int{types.SeverityHigh: 4}, nil, nil, types.StatusHealthy),
+			Entry("five high issues",
+				map[types.IssueSeverity]int{types.SeverityHigh: 5}, nil, nil, types.StatusDegraded),
+			Entry("one critical issue",
+				map[types.IssueSeverity]int{types.SeverityCritical: 1}, nil, nil, types.StatusCritical),
+			Entry("critical outranks degraded",
+				map[types.IssueSeverity]int{types.SeverityCritical: 1, types.SeverityHigh: 8}, nil, nil, types.StatusCritical),
+			Entry("custom critical threshold not reached",
+				map[types.IssueSeverity]int{types.SeverityCritical: 2},
+				map[types.IssueSeverity]int{types.SeverityCritical: 3}, nil, types.StatusHealthy),
+			Entry("custom degraded threshold on medium issues",
+				map[types.IssueSeverity]int{types.SeverityMedium: 2},
+				nil, map[types.IssueSeverity]int{types.SeverityMedium: 2}, types.StatusDegraded),
+		)
+	})
+
+	Describe("DisplayVolume", func() {
+		It("should show the PVC and bound PV of an enriched issue", func() {
+			issue := types.CSIMountIssue{
+				Volume:    "6f1c2d7e-93a4-4b1f-8d52-1e0a9c3b7f40",
+				PVC:       "data-web-0",
+				Namespace: "shop",
+				Sources: []types.SourceRef{
+					{Kind: "VolumeAttachment", Name: "csi-0a1b2c"},
+					{Kind: "PersistentVolume", Name: "pvc-3c9d8e7f"},
+				},
+			}
+			Expect(detect.DisplayVolume(issue)).To(Equal("shop/data-web-0 (pvc-3c9d8e7f)"))
+		})
+
+		It("should fall back to the handle after the PVC when no PV is known", func() {
+			issue := types.CSIMountIssue{Volume: "vol-0abc123", PVC: "shop/data-web-0"}
+			Expect(detect.DisplayVolume(issue)).To(Equal("shop/data-web-0 (vol-0abc123)"))
+			issue.Volume = "unknown"
+			Expect(detect.DisplayVolume(issue)).To(Equal("shop/data-web-0"))
+		})
+
+		It("should show the raw handle of a bare issue", func() {
+			issue := types.CSIMountIssue{Volume: "vol-0abc123", Node: "node-1"}
+			Expect(detect.DisplayVolume(issue)).To(Equal("vol-0abc123"))
+		})
+	})
+
+	Describe("ReconcileDrivers", func() {
+		It("should correct an unknown events driver from the VolumeAttachment of the same volume", func() {
+			detector = detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods: []types.DetectionMethod{types.VolumeAttachmentMethod, types.EventsMethod},
+			})
+
+			mockVolumeAttachments := mocks.NewMockVolumeAttachmentInterface(ctrl)
+			mockEvents := mocks.NewMockEventInterface(ctrl)
+			mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments)
+			mockCoreV1.EXPECT().Events("").Return(mockEvents)
+			mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{
+				Items: []storagev1.VolumeAttachment{{
+					ObjectMeta: metav1.ObjectMeta{Name: "va-1", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour))},
+					Spec: storagev1.VolumeAttachmentSpec{
+						Attacher: "ebs.csi.aws.com",
+						NodeName: "node-1",
+						Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: stringPtr("pvc-3c9d8e7f")},
+					},
+					Status: storagev1.VolumeAttachmentStatus{
+						AttachError: &storagev1.VolumeError{Message: "rpc error: code = DeadlineExceeded"},
+					},
+				}},
+			}, nil)
+			mockEvents.EXPECT().List(ctx, metav1.ListOptions{Limit: 500}).Return(&corev1.EventList{
+				Items: []corev1.Event{{
+					ObjectMeta:    metav1.ObjectMeta{Name: "web-0.17a", Namespace: "shop"},
+					Type:          "Warning",
+					Reason:        "FailedAttachVolume",
+					Message:       `AttachVolume.Attach failed for volume "pvc-3c9d8e7f" : timed out waiting for the condition`,
+					LastTimestamp: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+				}},
+			}, nil)
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Issues).To(HaveLen(2))
+			for _, issue := range result.Issues {
+				Expect(issue.Volume).To(Equal("pvc-3c9d8e7f"))
+				Expect(issue.Driver).To(Equal("ebs.csi.aws.com"))
+				Expect(issue.Metadata).NotTo(HaveKey("driver_source_conflict"))
+			}
+		})
+
+		It("should record a differing extracted driver as a conflict", func() {
+			issues := detect.ReconcileDrivers([]types.CSIMountIssue{
+				{Type: types.FailedAttachVolume, Volume: "pvc-1", Driver: "ebs.csi.aws.com", DetectedBy: types.VolumeAttachmentMethod},
+				{Type: types.FailedAttachVolume, Volume: "pvc-1", Driver: "efs.csi.aws.com", DetectedBy: types.EventsMethod},
+				{Type: types.FailedAttachVolume, Volume: "pvc-2", Driver: "efs.csi.aws.com", DetectedBy: types.EventsMethod},
+			})
+			Expect(issues[1].Driver).To(Equal("ebs.csi.aws.com"))
+			Expect(issues[1].Metadata).To(HaveKeyWithValue("driver_source_conflict", "efs.csi.aws.com"))
+			Expect(issues[2].Driver).To(Equal("efs.csi.aws.com"))
+			Expect(issues[2].Metadata).To(BeEmpty())
+		})
+	})
+
+	Describe("MergeAcrossMethods", func() {
+		vaIssue := types.CSIMountIssue{
+			Type:        types.MultipleAttachments,
+			Severity:    types.SeverityMedium,
+			Volume:      "pvc-3c9d8e7f",
+			Description: "Volume attached to multiple nodes: [node-1 node-2]",
+			DetectedBy:  types.VolumeAttachmentMethod,
+			Metadata: map[string]string{
+				"attached_count": "2",
+				"attached_nodes": "[node-1 node-2]",
+			},
+			Sources: []types.SourceRef{{Kind: "VolumeAttachment", Name: "csi-0a1b2c"}},
+		}
+		eventsIssue := types.CSIMountIssue{
+			Type:        types.MultiAttachError,
+			Severity:    types.SeverityHigh,
+			Node:        "node-2",
+			Volume:      "pvc-3c9d8e7f",
+			PVC:         "data-web-0",
+			Namespace:   "shop",
+			Description: "Multi-Attach error detected: Volume is already exclusively attached to one node",
+			DetectedBy:  types.EventsMethod,
+			Metadata: map[string]string{
+				"attached_count": "1",
+				"event_reason":   "FailedAttachVolume",
+			},
+			Sources: []types.SourceRef{{Kind: "Event", Namespace: "shop", Name: "web-0.17a"}},
+		}
+
+		It("should keep both descriptions and all metadata of a multi-attach seen by two methods", func() {
+			merged := detect.MergeAcrossMethods([]types.CSIMountIssue{vaIssue, eventsIssue})
+			Expect(merged).To(HaveLen(1))
+
+			issue := merged[0]
+			Expect(issue.Type).To(Equal(types.MultipleAttachments))
+			Expect(issue.Severity).To(Equal(types.SeverityHigh))
+			Expect(issue.Node).To(Equal("node-2"))
+			Expect(issue.PVC).To(Equal("data-web-0"))
+			Expect(issue.Description).To(ContainSubstring(vaIssue.Description))
+			Expect(issue.Description).To(ContainSubstring(eventsIssue.Description))
+			Expect(issue.Metadata).To(Equal(map[string]string{
+				"attached_count":        "2",
+				"attached_nodes":        "[node-1 node-2]",
+				"events.attached_count": "1",
+				"event_reason":          "FailedAttachVolume",
+				"merged_methods":        "volumeattachments,events",
+			}))
+			Expect(issue.Sources).To(ConsistOf(vaIssue.Sources[0], eventsIssue.Sources[0]))
+
+			// The inputs are left untouched
+			Expect(vaIssue.Metadata).To(HaveLen(2))
+		})
+
+		It("should not merge issues from the same method or about other volumes", func() {
+			otherVolume := eventsIssue
+			otherVolume.Volume = "pvc-9a8b7c6d"
+			issues := []types.CSIMountIssue{eventsIssue, eventsIssue, otherVolume}
+			Expect(detect.MergeAcrossMethods(issues)).To(Equal(issues))
+		})
+	})
+
+	Describe("CorrelateIssues", func() {
+		vaIssue := types.CSIMountIssue{
+			Type:        types.FailedAttachVolume,
+			Severity:    types.SeverityMedium,
+			Node:        "node-1",
+			Volume:      "pvc-3c9d8e7f",
+			Description: "AttachVolume.Attach failed: rpc error: timed out",
+			DetectedBy:  types.VolumeAttachmentMethod,
+			Metadata:    map[string]string{"volumeattachment_name": "csi-0a1b2c"},
+			Sources:     []types.SourceRef{{Kind: "VolumeAttachment", Name: "csi-0a1b2c"}},
+		}
+		eventsIssue := types.CSIMountIssue{
+			Type:        types.FailedAttachVolume,
+			Severity:    types.SeverityHigh,
+			Node:        "node-1",
+			Volume:      "pvc-3c9d8e7f",
+			PVC:         "data-web-0",
+			Namespace:   "shop",
+			Description: "Failed to attach volume: AttachVolume.Attach failed for volume \"pvc-3c9d8e7f\"",
+			DetectedBy:  types.EventsMethod,
+			Metadata:    map[string]string{"event_reason": "FailedAttachVolume"},
+			Sources:     []types.SourceRef{{Kind: "Event", Namespace: "shop", Name: "web-0.17a"}},
+		}
+		// The cross-node PVC method knows the claim but not the PV
+		pvcIssue := types.CSIMountIssue{
+			Type:        types.StuckMountReference,
+			Severity:    types.SeverityLow,
+			Node:        "node-1",
+			PVC:         "data-web-0",
+			Namespace:   "shop",
+			Description: "PVC still referenced on a node it is not attached to",
+			DetectedBy:  types.CrossNodePVCMethod,
+			Metadata:    map[string]string{"pod_count": "2"},
+		}
+
+		It("should collapse issues about one volume on one node into the first", func() {
+			correlated := detect.CorrelateIssues([]types.CSIMountIssue{vaIssue, eventsIssue, pvcIssue})
+			Expect(correlated).To(HaveLen(1))
+
+			issue := correlated[0]
+			Expect(issue.DetectedBy).To(Equal(types.VolumeAttachmentMethod))
+			Expect(issue.Severity).To(Equal(types.SeverityHigh))
+			Expect(issue.PVC).To(Equal("data-web-0"))
+			Expect(issue.Metadata).To(Equal(map[string]string{
+				"volumeattachment_name": "csi-0a1b2c",
+				"event_reason":          "FailedAttachVolume",
+				"pod_count":             "2",
+				"related_methods":       "volumeattachments,events,cross-node-pvc",
+			}))
+			Expect(issue.Sources).To(ConsistOf(vaIssue.Sources[0], eventsIssue.Sources[0]))
+		})
+
+		It("should collapse a cross-node issue keyed by namespace/name with an events issue naming the bare PVC", func() {
+			crossNode := pvcIssue
+			crossNode.PVC = "shop/data-web-0"
+			bareEvents := eventsIssue
+			bareEvents.Volume = ""
+
+			correlated := detect.CorrelateIssues([]types.CSIMountIssue{crossNode, bareEvents})
+			Expect(correlated).To(HaveLen(1))
+			Expect(correlated[0].Metadata).To(HaveKeyWithValue("related_methods", "cross-node-pvc,events"))
+		})
+
+		It("should keep issues on other nodes, about other volumes or from the same method apart", func() {
+			otherNode := eventsIssue
+			otherNode.Node = "node-2"
+			otherVolume := eventsIssue
+			otherVolume.Volume = "pvc-9a8b7c6d"
+			otherVolume.PVC = "data-web-1"
+			issues := []types.CSIMountIssue{vaIssue, vaIssue, otherNode, otherVolume}
+			Expect(detect.CorrelateIssues(issues)).To(Equal(issues))
+		})
+	})
+
+	Describe("PodOwner", func() {
+		isController := true
+
+		It("should attribute ReplicaSet pods to their Deployment", func() {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "api-7d9f8b6c5-x2k4p",
+					Labels: map[string]string{"pod-template-hash": "7d9f8b6c5"},
+					OwnerReferences: []metav1.OwnerReference{
+						{Kind: "ReplicaSet", Name: "api-7d9f8b6c5", Controller: &isController},
+					},
+				},
+			}
+			kind, name := detect.PodOwner(pod)
+			Expect(kind).To(Equal("Deployment"))
+			Expect(name).To(Equal("api"))
+		})
+
+		It("should report bare pods as themselves", func() {
+			kind, name := detect.PodOwner(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "debug"}})
+			Expect(kind).To(Equal("Pod"))
+			Expect(name).To(Equal("debug"))
+		})
 	})
 
 	Context("FilterBySeverity Function", func() {
@@ -447,3 +1565,10 @@ func (e *testError) Error() string {
 func stringPtr(s string) *string {
 	return &s
 }
+
+// offsetClock is a server clock running ahead of the local one by a fixed offset
+type offsetClock time.Duration
+
+func (c offsetClock) ServerTime(local time.Time) time.Time {
+	return local.Add(time.Duration(c))
+}
@@ -0,0 +1,29 @@
+package detect
+
+import (
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// unknownVolume is recorded by detectors that could not determine an issue's volume
+const unknownVolume = "unknown"
+
+// DisplayVolume returns a readable name for the volume of an issue. Raw volume handles are
+// often opaque, so when the PVC is known it is shown as namespace/pvc followed by the
+// bound PV from the issue's sources, or else the handle, in parentheses. Without a PVC
+// the handle is shown as is.
+func DisplayVolume(issue types.CSIMountIssue) string {
+	pvc := issuePVCKey(issue)
+	if pvc == "" {
+		return issue.Volume
+	}
+
+	for _, source := range issue.Sources {
+		if source.Kind == "PersistentVolume" && source.Name != "" {
+			return pvc + " (" + source.Name + ")"
+		}
+	}
+	if issue.Volume == "" || issue.Volume == unknownVolume {
+		return pvc
+	}
+	return pvc + " (" + issue.Volume + ")"
+}
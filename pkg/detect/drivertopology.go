@@ -0,0 +1,196 @@
+package detect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// DriverTopologyDetector finds nodes running pods with volumes of a CSI driver whose
+// CSINode object does not list that driver. The kubelet only registers a driver once its
+// node plugin is up, so those pods cannot have their volumes mounted on the node.
+type DriverTopologyDetector struct {
+	client       client.KubernetesClient
+	targetDriver string
+	namespace    string
+}
+
+// NewDriverTopologyDetector creates a new driver topology detector
+func NewDriverTopologyDetector(kubeClient client.KubernetesClient, targetDriver string) *DriverTopologyDetector {
+	return &DriverTopologyDetector{
+		client:       kubeClient,
+		targetDriver: targetDriver,
+	}
+}
+
+// SetNamespace limits the pods listed to one namespace. Empty lists pods in all
+// namespaces.
+func (d *DriverTopologyDetector) SetNamespace(namespace string) {
+	d.namespace = namespace
+}
+
+// Detect reports, for each node and driver, the pods on the node that need the driver
+// when the node's CSINode does not register it
+func (d *DriverTopologyDetector) Detect(ctx context.Context) ([]types.CSIMountIssue, error) {
+	csiNodes, err := d.client.StorageV1().CSINodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CSINodes: %w", err)
+	}
+	registered := make(map[string]map[string]bool, len(csiNodes.Items)) // node -> driver -> registered
+	registeredNodes := make(map[string]int)                             // driver -> nodes registering it
+	for _, csiNode := range csiNodes.Items {
+		drivers := make(map[string]bool, len(csiNode.Spec.Drivers))
+		for _, driver := range csiNode.Spec.Drivers {
+			drivers[driver.Name] = true
+			registeredNodes[driver.Name]++
+		}
+		registered[csiNode.Name] = drivers
+	}
+
+	csiDrivers, err := d.client.StorageV1().CSIDrivers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CSIDrivers: %w", err)
+	}
+	installed := make(map[string]bool, len(csiDrivers.Items))
+	for _, csiDriver := range csiDrivers.Items {
+		installed[csiDriver.Name] = true
+	}
+
+	// node -> driver -> pods on the node with a volume of the driver
+	needed := make(map[string]map[string][]types.SourceRef)
+	claimDrivers := make(map[string]string) // namespace/claim -> CSI driver, empty when not CSI
+	pvs := newPVLookup(d.client, NewCircuitBreaker("PV lookup", 0))
+
+	err = listPods(ctx, d.client.CoreV1().Pods(d.namespace), func(pods *corev1.PodList) error {
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			for _, claim := range podClaims(pod) {
+				key := pod.Namespace + "/" + claim
+				driver, resolved := claimDrivers[key]
+				if !resolved {
+					driver, err = d.claimDriver(ctx, pod.Namespace, claim, pvs)
+					if err != nil {
+						return err
+					}
+					claimDrivers[key] = driver
+				}
+				if driver == "" || (d.targetDriver != "" && driver != d.targetDriver) {
+					continue
+				}
+				if registered[pod.Spec.NodeName][driver] {
+					continue
+				}
+				if needed[pod.Spec.NodeName] == nil {
+					needed[pod.Spec.NodeName] = make(map[string][]types.SourceRef)
+				}
+				needed[pod.Spec.NodeName][driver] = appendPod(needed[pod.Spec.NodeName][driver], pod)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]string, 0, len(needed))
+	for node := range needed {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	var issues []types.CSIMountIssue
+	for _, node := range nodes {
+		_, hasCSINode := registered[node]
+		drivers := make([]string, 0, len(needed[node]))
+		for driver := range needed[node] {
+			drivers = append(drivers, driver)
+		}
+		sort.Strings(drivers)
+		for _, driver := range drivers {
+			issues = append(issues, d.newIssue(node, driver, needed[node][driver], hasCSINode, registeredNodes[driver], installed[driver]))
+		}
+	}
+	return issues, nil
+}
+
+// claimDriver returns the CSI driver of the PV bound to a PVC. Claims that are missing,
+// unbound or bound to a volume that is not CSI-backed have no driver.
+func (d *DriverTopologyDetector) claimDriver(ctx context.Context, namespace, claim string, pvs *pvLookup) (string, error) {
+	pvc, err := d.client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, claim, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get PVC %s/%s: %w", namespace, claim, err)
+	}
+	if pvc.Spec.VolumeName == "" {
+		return "", nil
+	}
+
+	pv, err := pvs.get(ctx, pvc.Spec.VolumeName)
+	if apierrors.IsNotFound(err) || errors.Is(err, errLookupsDisabled) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get PV %s: %w", pvc.Spec.VolumeName, err)
+	}
+	if pv.Spec.CSI != nil {
+		return pv.Spec.CSI.Driver, nil
+	}
+	return pv.Annotations[migratedToAnnotation], nil
+}
+
+// newIssue builds the issue for a node that does not register a driver its pods need
+func (d *DriverTopologyDetector) newIssue(node, driver string, pods []types.SourceRef, hasCSINode bool, registeredNodes int, installed bool) types.CSIMountIssue {
+	reason := fmt.Sprintf("its CSINode does not list %s", driver)
+	if !hasCSINode {
+		reason = "it has no CSINode object"
+	}
+	description := fmt.Sprintf("%d pod(s) on node %s use %s volumes but %s: the driver's node plugin is not registered there, so those volumes cannot be mounted", len(pods), node, driver, reason)
+	if registeredNodes > 0 {
+		description += fmt.Sprintf(" (registered on %d other node(s))", registeredNodes)
+	}
+
+	return types.CSIMountIssue{
+		Type:        types.DriverNotRegistered,
+		Severity:    types.SeverityHigh,
+		Node:        node,
+		Driver:      driver,
+		Description: description,
+		DetectedBy:  types.DriverTopologyMethod,
+		DetectedAt:  time.Now(),
+		Metadata: map[string]string{
+			"csinode":          strconv.FormatBool(hasCSINode),
+			"registered_nodes": strconv.Itoa(registeredNodes),
+			"csidriver":        strconv.FormatBool(installed),
+			"pods":             strconv.Itoa(len(pods)),
+		},
+		Sources: append([]types.SourceRef{{Kind: "Node", Name: node}}, pods...),
+	}
+}
+
+// appendPod adds a pod to refs unless it is already the last entry, as a pod mounting
+// several claims of a driver is listed once
+func appendPod(refs []types.SourceRef, pod corev1.Pod) []types.SourceRef {
+	if n := len(refs); n > 0 && refs[n-1].Namespace == pod.Namespace && refs[n-1].Name == pod.Name {
+		return refs
+	}
+	return append(refs, types.SourceRef{
+		Kind:      "Pod",
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		UID:       string(pod.UID),
+	})
+}
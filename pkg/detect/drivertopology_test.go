@@ -0,0 +1,177 @@
+package detect_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client/mocks"
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("DriverTopologyDetector", func() {
+	const (
+		cinder = "cinder.csi.openstack.org"
+		nfs    = "nfs.csi.k8s.io"
+	)
+
+	var (
+		ctrl           *gomock.Controller
+		mockClient     *mocks.MockKubernetesClient
+		mockPods       *mocks.MockPodInterface
+		mockPVCs       *mocks.MockPersistentVolumeClaimInterface
+		mockPVs        *mocks.MockPersistentVolumeInterface
+		mockCSINodes   *mocks.MockCSINodeInterface
+		mockCSIDrivers *mocks.MockCSIDriverInterface
+		csiNodes       []storagev1.CSINode
+		ctx            context.Context
+	)
+
+	csiNode := func(name string, drivers ...string) storagev1.CSINode {
+		node := storagev1.CSINode{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, driver := range drivers {
+			node.Spec.Drivers = append(node.Spec.Drivers, storagev1.CSINodeDriver{Name: driver, NodeID: name})
+		}
+		return node
+	}
+
+	pod := func(name, node string, claims ...string) corev1.Pod {
+		p := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		for _, claim := range claims {
+			p.Spec.Volumes = append(p.Spec.Volumes, corev1.Volume{
+				Name:         claim,
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+			})
+		}
+		return p
+	}
+
+	// claim binds a PVC of the shop namespace to a PV of driver
+	claim := func(name, driver string) {
+		pvName := "pv-" + name
+		mockPVCs.EXPECT().Get(gomock.Any(), name, gomock.Any()).Return(&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: pvName},
+		}, nil).AnyTimes()
+		mockPVs.EXPECT().Get(gomock.Any(), pvName, gomock.Any()).Return(&corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: pvName},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: driver, VolumeHandle: name},
+				},
+			},
+		}, nil).AnyTimes()
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = mocks.NewMockKubernetesClient(ctrl)
+		mockCoreV1 := mocks.NewMockCoreV1Interface(ctrl)
+		mockStorageV1 := mocks.NewMockStorageV1Interface(ctrl)
+		mockPods = mocks.NewMockPodInterface(ctrl)
+		mockPVCs = mocks.NewMockPersistentVolumeClaimInterface(ctrl)
+		mockPVs = mocks.NewMockPersistentVolumeInterface(ctrl)
+		mockCSINodes = mocks.NewMockCSINodeInterface(ctrl)
+		mockCSIDrivers = mocks.NewMockCSIDriverInterface(ctrl)
+		ctx = context.Background()
+
+		mockClient.EXPECT().CoreV1().Return(mockCoreV1).AnyTimes()
+		mockClient.EXPECT().StorageV1().Return(mockStorageV1).AnyTimes()
+		mockCoreV1.EXPECT().Pods("").Return(mockPods).AnyTimes()
+		mockCoreV1.EXPECT().PersistentVolumeClaims("shop").Return(mockPVCs).AnyTimes()
+		mockCoreV1.EXPECT().PersistentVolumes().Return(mockPVs).AnyTimes()
+		mockStorageV1.EXPECT().CSINodes().Return(mockCSINodes).AnyTimes()
+		mockStorageV1.EXPECT().CSIDrivers().Return(mockCSIDrivers).AnyTimes()
+
+		// The node plugin runs on node-a only: node-b has a CSINode without cinder and
+		// node-c has no CSINode at all
+		csiNodes = []storagev1.CSINode{
+			csiNode("node-a", cinder, nfs),
+			csiNode("node-b", nfs),
+		}
+		mockCSINodes.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, metav1.ListOptions) (*storagev1.CSINodeList, error) {
+			return &storagev1.CSINodeList{Items: csiNodes}, nil
+		}).AnyTimes()
+		mockCSIDrivers.EXPECT().List(gomock.Any(), gomock.Any()).Return(&storagev1.CSIDriverList{
+			Items: []storagev1.CSIDriver{{ObjectMeta: metav1.ObjectMeta{Name: cinder}}},
+		}, nil).AnyTimes()
+
+		claim("data-web-0", cinder)
+		claim("data-web-1", cinder)
+		claim("logs-web-1", cinder)
+		claim("data-db-0", cinder)
+		claim("shared", nfs)
+		mockPVCs.EXPECT().Get(gomock.Any(), "gone", gomock.Any()).
+			Return(nil, apierrors.NewNotFound(schema.GroupResource{Resource: "persistentvolumeclaims"}, "gone")).AnyTimes()
+
+		finished := pod("migrate-0", "node-c", "data-db-0")
+		finished.Status.Phase = corev1.PodSucceeded
+		mockPods.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.PodList{
+			Items: []corev1.Pod{
+				pod("web-0", "node-a", "data-web-0", "shared"),
+				pod("web-1", "node-b", "data-web-1", "logs-web-1", "shared"),
+				pod("db-0", "node-c", "data-db-0", "gone"),
+				pod("pending", "", "data-web-0"),
+				finished,
+			},
+		}, nil).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should report the nodes whose CSINode does not register the driver their pods need", func() {
+		issues, err := detect.NewDriverTopologyDetector(mockClient, "").Detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(2))
+
+		Expect(issues[0].Type).To(Equal(types.DriverNotRegistered))
+		Expect(issues[0].Severity).To(Equal(types.SeverityHigh))
+		Expect(issues[0].DetectedBy).To(Equal(types.DriverTopologyMethod))
+		Expect(issues[0].Node).To(Equal("node-b"))
+		Expect(issues[0].Driver).To(Equal(cinder))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("csinode", "true"))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("registered_nodes", "1"))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("csidriver", "true"))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("pods", "1"))
+		Expect(issues[0].Sources).To(ContainElement(types.SourceRef{Kind: "Pod", Namespace: "shop", Name: "web-1"}))
+
+		Expect(issues[1].Node).To(Equal("node-c"))
+		Expect(issues[1].Driver).To(Equal(cinder))
+		Expect(issues[1].Metadata).To(HaveKeyWithValue("csinode", "false"))
+		Expect(issues[1].Description).To(ContainSubstring("no CSINode object"))
+		Expect(issues[1].Sources).To(ContainElement(types.SourceRef{Kind: "Pod", Namespace: "shop", Name: "db-0"}))
+		Expect(issues[1].Sources).NotTo(ContainElement(types.SourceRef{Kind: "Pod", Namespace: "shop", Name: "migrate-0"}))
+	})
+
+	It("should only check the target driver when one is set", func() {
+		issues, err := detect.NewDriverTopologyDetector(mockClient, nfs).Detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("should report nothing when every node registers the driver", func() {
+		csiNodes = []storagev1.CSINode{
+			csiNode("node-a", cinder, nfs),
+			csiNode("node-b", cinder, nfs),
+			csiNode("node-c", cinder),
+		}
+
+		issues, err := detect.NewDriverTopologyDetector(mockClient, cinder).Detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+})
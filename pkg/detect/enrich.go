@@ -0,0 +1,54 @@
+package detect
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// DefaultEnrichmentWorkers bounds the concurrent API lookups made while enriching issues
+const DefaultEnrichmentWorkers = 8
+
+// EnrichFunc looks up additional context for a single issue and records it on the issue.
+// Enrichment is best-effort, so lookups that fail should leave the issue unchanged.
+type EnrichFunc func(ctx context.Context, issue *types.CSIMountIssue)
+
+// EnrichIssues applies fn to every issue using at most workers concurrent lookups.
+// The context is checked before each lookup so a timeout or cancellation stops further
+// lookups promptly; issues enriched before that keep their changes and the context
+// error is returned.
+func EnrichIssues(ctx context.Context, issues []types.CSIMountIssue, workers int, fn EnrichFunc) error {
+	if workers <= 0 {
+		workers = DefaultEnrichmentWorkers
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				// Drain remaining work without calling out once cancelled
+				if ctx.Err() != nil {
+					continue
+				}
+				fn(ctx, &issues[i])
+			}
+		}()
+	}
+
+dispatch:
+	for i := range issues {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return ctx.Err()
+}
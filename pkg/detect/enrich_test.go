@@ -0,0 +1,95 @@
+package detect_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("EnrichIssues", func() {
+	var issues []types.CSIMountIssue
+
+	BeforeEach(func() {
+		issues = make([]types.CSIMountIssue, 10)
+		for i := range issues {
+			issues[i].Volume = fmt.Sprintf("pvc-%d", i)
+		}
+	})
+
+	markEnriched := func(issue *types.CSIMountIssue) {
+		issue.Metadata = map[string]string{"enriched": "true"}
+	}
+
+	It("should enrich every issue", func() {
+		err := detect.EnrichIssues(context.Background(), issues, 3, func(ctx context.Context, issue *types.CSIMountIssue) {
+			markEnriched(issue)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		for _, issue := range issues {
+			Expect(issue.Metadata).To(HaveKeyWithValue("enriched", "true"))
+		}
+	})
+
+	It("should never run more lookups at once than the worker limit", func() {
+		var mu sync.Mutex
+		var active, maxActive int
+		err := detect.EnrichIssues(context.Background(), issues, 2, func(ctx context.Context, issue *types.CSIMountIssue) {
+			mu.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			mu.Unlock()
+
+			markEnriched(issue)
+
+			mu.Lock()
+			active--
+			mu.Unlock()
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(maxActive).To(BeNumerically("<=", 2))
+	})
+
+	It("should stop further lookups when the context is cancelled and keep partial results", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var calls int32
+		err := detect.EnrichIssues(ctx, issues, 1, func(ctx context.Context, issue *types.CSIMountIssue) {
+			markEnriched(issue)
+			if atomic.AddInt32(&calls, 1) == 3 {
+				cancel()
+			}
+		})
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(3)))
+
+		for i, issue := range issues {
+			if i < 3 {
+				Expect(issue.Metadata).To(HaveKeyWithValue("enriched", "true"))
+			} else {
+				Expect(issue.Metadata).To(BeNil())
+			}
+		}
+	})
+
+	It("should not call out when the context is already done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		called := false
+		err := detect.EnrichIssues(ctx, issues, 4, func(ctx context.Context, issue *types.CSIMountIssue) {
+			called = true
+		})
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(called).To(BeFalse())
+	})
+})
@@ -6,65 +6,159 @@ import (
 	"strings"
 	"time"
 
+	"github.com/rs/zerolog/log"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+	"github.com/jdambly/kubectl-csi-scan/pkg/parse"
 	"github.com/jdambly/kubectl-csi-scan/pkg/types"
 )
 
+// DefaultDeviceBusyPatterns are event message substrings reported when a device is still
+// in use at the block or multipath level, which directly blocks unmount
+var DefaultDeviceBusyPatterns = []string{
+	"device is busy",
+	"target is busy",
+	"already mounted",
+}
+
+// DefaultEventProgressInterval is how many events are processed between progress log lines
+const DefaultEventProgressInterval = 1000
+
+// EventProgressFunc is called periodically while events are processed with the number
+// scanned so far, the number that produced an issue, and the total listed
+type EventProgressFunc func(scanned, matched, total int)
+
 // EventsDetector implements detection via Kubernetes events analysis
 type EventsDetector struct {
 	client       client.KubernetesClient
 	targetDriver string
 	lookbackDuration time.Duration
+	strictDriverMatch bool
+	deviceBusyPatterns []string
+	progressInterval int
+	onProgress       EventProgressFunc
+	flapThreshold    int
+	namespace        string
+	resourceVersion  string // of the last event list Detect read
 }
 
+// DefaultEventsLookback is how far back the events detector looks when no window is given
+const DefaultEventsLookback = time.Hour
+
 // NewEventsDetector creates a new events detector
 func NewEventsDetector(kubeClient client.KubernetesClient, targetDriver string, lookbackDuration time.Duration) *EventsDetector {
 	if lookbackDuration == 0 {
-		lookbackDuration = 1 * time.Hour // Default to 1 hour lookback
+		lookbackDuration = DefaultEventsLookback
 	}
 	
 	return &EventsDetector{
-		client:           kubeClient,
-		targetDriver:     targetDriver,
-		lookbackDuration: lookbackDuration,
+		client:             kubeClient,
+		targetDriver:       targetDriver,
+		lookbackDuration:   lookbackDuration,
+		deviceBusyPatterns: DefaultDeviceBusyPatterns,
+		progressInterval:   DefaultEventProgressInterval,
+		onProgress:         logEventProgress,
+		flapThreshold:      DefaultFlapThreshold,
 	}
 }
 
+// SetProgressHook replaces the debug log line written every interval processed events
+// with hook. A non-positive interval disables progress reporting.
+func (d *EventsDetector) SetProgressHook(interval int, hook EventProgressFunc) {
+	d.progressInterval = interval
+	d.onProgress = hook
+}
+
+// SetDeviceBusyPatterns replaces the message substrings that classify an event as a
+// device-busy issue. Matching is case-insensitive.
+func (d *EventsDetector) SetDeviceBusyPatterns(patterns []string) {
+	d.deviceBusyPatterns = patterns
+}
+
+// SetFlapThreshold sets how many attach and detach events a volume may have within the
+// lookback before it is reported as flapping. A non-positive threshold uses
+// DefaultFlapThreshold.
+func (d *EventsDetector) SetFlapThreshold(threshold int) {
+	if threshold <= 0 {
+		threshold = DefaultFlapThreshold
+	}
+	d.flapThreshold = threshold
+}
+
+// SetNamespace limits the events listed to one namespace. Empty lists events in all
+// namespaces.
+func (d *EventsDetector) SetNamespace(namespace string) {
+	d.namespace = namespace
+}
+
+// SetStrictDriverMatch limits driver filtering to events that name the target
+// driver, dropping generic volume events that cannot be attributed to a driver
+func (d *EventsDetector) SetStrictDriverMatch(strict bool) {
+	d.strictDriverMatch = strict
+}
+
 // Detect finds CSI-related issues from Kubernetes events
 func (d *EventsDetector) Detect(ctx context.Context) ([]types.CSIMountIssue, error) {
 	var issues []types.CSIMountIssue
 
-	// Get events from all namespaces
-	events, err := d.client.CoreV1().Events("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list events: %w", err)
-	}
-
 	cutoffTime := time.Now().Add(-d.lookbackDuration)
+	scanned := 0
+	matched := 0
+	attachments := make(attachTracker)
+
+	// Get events from the namespace, or all namespaces, a page at a time
+	err := listEvents(ctx, d.client.CoreV1().Events(d.namespace), func(events *corev1.EventList) error {
+		// Every page of a list is served at the resourceVersion of the first
+		d.resourceVersion = events.ResourceVersion
+		// The total is only known up to the end of this page unless the API server says
+		// how many events remain
+		total := scanned + len(events.Items) + remainingItems(events.ListMeta)
+		for _, event := range events.Items {
+			// Clusters with huge event volumes can take a while, so report progress periodically
+			if d.progressInterval > 0 && d.onProgress != nil && scanned > 0 && scanned%d.progressInterval == 0 {
+				d.onProgress(scanned, matched, total)
+			}
+			scanned++
 
-	for _, event := range events.Items {
-		// Skip old events
-		if event.LastTimestamp.Time.Before(cutoffTime) && event.EventTime.Time.Before(cutoffTime) {
-			continue
-		}
+			// Skip old events
+			if event.LastTimestamp.Time.Before(cutoffTime) && event.EventTime.Time.Before(cutoffTime) {
+				continue
+			}
 
-		// Filter by driver if specified
-		if d.targetDriver != "" && !d.eventMatchesDriver(event, d.targetDriver) {
-			continue
-		}
+			// Filter by driver if specified
+			if d.targetDriver != "" && !d.eventMatchesDriver(event, d.targetDriver) {
+				continue
+			}
+
+			eventTime := event.LastTimestamp.Time
+			if eventTime.IsZero() {
+				eventTime = event.EventTime.Time
+			}
+			attachments.record(d, event, eventTime)
 
-		// Analyze event for CSI mount issues
-		if issue := d.analyzeEvent(event); issue != nil {
-			issues = append(issues, *issue)
+			// Analyze event for CSI mount issues
+			if issue := d.analyzeEvent(event); issue != nil {
+				issues = append(issues, *issue)
+				matched++
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	issues = append(issues, attachments.flappingIssues(d)...)
 	return issues, nil
 }
 
+// logEventProgress writes a debug-level progress line for a long event scan
+func logEventProgress(scanned, matched, total int) {
+	log.Debug().Int("scanned", scanned).Int("matched", matched).Int("total", total).Msg("scanning events")
+}
+
 // eventMatchesDriver checks if an event is related to the target CSI driver
 func (d *EventsDetector) eventMatchesDriver(event corev1.Event, targetDriver string) bool {
 	// Check message content for driver name
@@ -72,42 +166,13 @@ func (d *EventsDetector) eventMatchesDriver(event corev1.Event, targetDriver str
 		return true
 	}
 
-	// Check if message contains any CSI driver names other than the target driver
-	// Build a list of known CSI driver patterns
-	knownDrivers := []string{
-		"cinder.csi.openstack.org",
-		"rook-ceph.rbd.csi.ceph.com", 
-		"rook-ceph.cephfs.csi.ceph.com",
-		"ebs.csi.aws.com",
-		"disk.csi.azure.com",
-		"pd.csi.storage.gke.io",
-	}
-	
-	// Add target driver to known drivers if not already present
-	if targetDriver != "" {
-		found := false
-		for _, known := range knownDrivers {
-			if known == targetDriver {
-				found = true
-				break
-			}
-		}
-		if !found {
-			knownDrivers = append(knownDrivers, targetDriver)
-		}
-	}
-	
-	// Check if message contains any other CSI driver - if so, exclude it
-	for _, driver := range knownDrivers {
-		if driver != targetDriver && strings.Contains(event.Message, driver) {
-			return false
-		}
+	// Anything below is a best guess, which strict matching does not accept
+	if d.strictDriverMatch {
+		return false
 	}
 
-	// Check if message looks like it's about a specific CSI driver but not ours
-	// Look for pattern "driver.name.domain" in the message
-	if strings.Contains(event.Message, ".csi.") {
-		// If it mentions a CSI driver pattern but not our target driver, exclude it
+	// Exclude events about a different CSI driver
+	if d.mentionsOtherDriver(event.Message, targetDriver) {
 		return false
 	}
 
@@ -125,6 +190,10 @@ func (d *EventsDetector) eventMatchesDriver(event corev1.Event, targetDriver str
 		}
 	}
 
+	if d.isDeviceBusyMessage(event.Message) {
+		return true
+	}
+
 	// Include events that mention CSI
 	if strings.Contains(event.Reason, "CSI") || strings.Contains(event.Message, "CSI") {
 		return true
@@ -133,6 +202,17 @@ func (d *EventsDetector) eventMatchesDriver(event corev1.Event, targetDriver str
 	return false
 }
 
+// mentionsOtherDriver reports whether a message names a CSI driver other than the target
+// driver. Any driver name is recognized, not only well-known ones.
+func (d *EventsDetector) mentionsOtherDriver(message, targetDriver string) bool {
+	for _, driver := range parse.ExtractDrivers(message) {
+		if driver != targetDriver {
+			return true
+		}
+	}
+	return false
+}
+
 // analyzeEvent examines an individual event for CSI mount issues
 func (d *EventsDetector) analyzeEvent(event corev1.Event) *types.CSIMountIssue {
 	eventTime := event.LastTimestamp.Time
@@ -153,7 +233,9 @@ func (d *EventsDetector) analyzeEvent(event corev1.Event) *types.CSIMountIssue {
 			Description: fmt.Sprintf("Multi-Attach error detected: %s", event.Message),
 			DetectedBy:  types.EventsMethod,
 			DetectedAt:  time.Now(),
+			OccurredAt:  eventTime,
 			Metadata:    d.buildEventMetadata(event, eventTime),
+			Sources:     eventSources(event),
 		}
 	}
 
@@ -170,7 +252,28 @@ func (d *EventsDetector) analyzeEvent(event corev1.Event) *types.CSIMountIssue {
 			Description: fmt.Sprintf("Failed to attach volume: %s", event.Message),
 			DetectedBy:  types.EventsMethod,
 			DetectedAt:  time.Now(),
+			OccurredAt:  eventTime,
 			Metadata:    d.buildEventMetadata(event, eventTime),
+			Sources:     eventSources(event),
+		}
+	}
+
+	// Device-level busy errors, e.g. multipath or block devices still held open
+	if event.Type == "Warning" && d.isDeviceBusyMessage(event.Message) {
+		return &types.CSIMountIssue{
+			Type:        types.DeviceBusy,
+			Severity:    d.calculateEventSeverity(event),
+			Node:        d.getNodeForDisplay(event),
+			Volume:      d.extractVolumeFromMessage(event.Message),
+			PVC:         d.getPVCForDisplay(event),
+			Namespace:   event.Namespace,
+			Driver:      d.extractDriverFromMessage(event.Message),
+			Description: fmt.Sprintf("Device busy, unmount blocked: %s", event.Message),
+			DetectedBy:  types.EventsMethod,
+			DetectedAt:  time.Now(),
+			OccurredAt:  eventTime,
+			Metadata:    d.buildEventMetadata(event, eventTime),
+			Sources:     eventSources(event),
 		}
 	}
 
@@ -189,7 +292,9 @@ func (d *EventsDetector) analyzeEvent(event corev1.Event) *types.CSIMountIssue {
 				Description: fmt.Sprintf("Mount reference cleanup failure: %s", event.Message),
 				DetectedBy:  types.EventsMethod,
 				DetectedAt:  time.Now(),
+				OccurredAt:  eventTime,
 				Metadata:    d.buildEventMetadata(event, eventTime),
+				Sources:     eventSources(event),
 			}
 		}
 
@@ -204,7 +309,9 @@ func (d *EventsDetector) analyzeEvent(event corev1.Event) *types.CSIMountIssue {
 			Description: fmt.Sprintf("Failed to mount volume: %s", event.Message),
 			DetectedBy:  types.EventsMethod,
 			DetectedAt:  time.Now(),
+			OccurredAt:  eventTime,
 			Metadata:    d.buildEventMetadata(event, eventTime),
+			Sources:     eventSources(event),
 		}
 	}
 
@@ -221,7 +328,9 @@ func (d *EventsDetector) analyzeEvent(event corev1.Event) *types.CSIMountIssue {
 			Description: fmt.Sprintf("CSI operation issue: %s", event.Message),
 			DetectedBy:  types.EventsMethod,
 			DetectedAt:  time.Now(),
+			OccurredAt:  eventTime,
 			Metadata:    d.buildEventMetadata(event, eventTime),
+			Sources:     eventSources(event),
 		}
 	}
 
@@ -239,6 +348,10 @@ func (d *EventsDetector) calculateEventSeverity(event corev1.Event) types.IssueS
 		return types.SeverityHigh
 	}
 
+	if d.isDeviceBusyMessage(event.Message) {
+		return types.SeverityHigh
+	}
+
 	// Higher severity for frequently occurring events
 	if event.Count >= 10 {
 		return types.SeverityCritical
@@ -251,117 +364,36 @@ func (d *EventsDetector) calculateEventSeverity(event corev1.Event) types.IssueS
 	return types.SeverityLow
 }
 
-// extractVolumeFromMessage attempts to extract volume information from event message
-func (d *EventsDetector) extractVolumeFromMessage(message string) string {
-	// Look for PVC patterns (most common for CSI volumes)
-	if strings.Contains(message, "pvc-") {
-		parts := strings.Fields(message)
-		for _, part := range parts {
-			if strings.HasPrefix(part, "pvc-") {
-				// Remove quotes and other punctuation
-				part = strings.Trim(part, "\"',.()[]")
-				return part
-			}
+// isDeviceBusyMessage reports whether a message matches one of the device-busy patterns
+func (d *EventsDetector) isDeviceBusyMessage(message string) bool {
+	lower := strings.ToLower(message)
+	for _, pattern := range d.deviceBusyPatterns {
+		if pattern != "" && strings.Contains(lower, strings.ToLower(pattern)) {
+			return true
 		}
 	}
+	return false
+}
 
-	// Look for volume handle patterns with quotes
-	volumePatterns := []string{
-		"volume \"",
-		"Volume \"",
-		"volumeHandle \"",
-		"volumeId \"",
-		"volume_id \"",
-	}
-	
-	for _, pattern := range volumePatterns {
-		if strings.Contains(message, pattern) {
-			start := strings.Index(message, pattern)
-			if start != -1 {
-				start += len(pattern)
-				end := strings.Index(message[start:], "\"")
-				if end != -1 {
-					volumeHandle := message[start : start+end]
-					if volumeHandle != "" && volumeHandle != "unknown" {
-						return volumeHandle
-					}
-				}
-			}
-		}
-	}
-	
-	// Look for volume names after specific keywords without quotes
-	volumeKeywords := []string{"volume", "Volume", "volumeHandle", "volumeId"}
-	words := strings.Fields(message)
-	
-	for i, word := range words {
-		for _, keyword := range volumeKeywords {
-			if strings.EqualFold(word, keyword) && i+1 < len(words) {
-				nextWord := strings.Trim(words[i+1], "\"',.()[]:")
-				if nextWord != "" && nextWord != "unknown" && !strings.Contains(nextWord, " ") {
-					return nextWord
-				}
-			}
-		}
-	}
-	
-	// Look for any word that looks like a volume handle (contains pvc- or is a long alphanumeric string)
-	for _, word := range words {
-		cleanWord := strings.Trim(word, "\"',.()[]:")
-		
-		// Handle malformed cases like "volumes=[volume-name"
-		if strings.Contains(cleanWord, "volumes=[") {
-			// Extract everything after "volumes=["
-			parts := strings.Split(cleanWord, "volumes=[")
-			if len(parts) > 1 {
-				volumePart := parts[1]
-				// Remove any trailing brackets or punctuation
-				volumePart = strings.Trim(volumePart, "[]()\"',.")
-				if volumePart != "" && volumePart != "unknown" {
-					return volumePart
-				}
-			}
-		}
-		
-		if strings.HasPrefix(cleanWord, "pvc-") {
-			return cleanWord
-		}
-		// Look for long alphanumeric strings that might be volume handles
-		if len(cleanWord) > 10 && strings.ContainsAny(cleanWord, "0123456789") && strings.ContainsAny(cleanWord, "abcdefghijklmnopqrstuvwxyz") {
-			// Exclude common kubernetes token patterns
-			if !strings.Contains(cleanWord, "kube-api-access-") && !strings.Contains(cleanWord, "default-token-") {
-				return cleanWord
-			}
-		}
+// extractVolumeFromMessage attempts to extract volume handle from event message
+func (d *EventsDetector) extractVolumeFromMessage(message string) string {
+	if volume := parse.ExtractVolume(message); volume != "" {
+		return volume
 	}
-
 	return "unknown"
 }
 
 // extractDriverFromMessage attempts to extract CSI driver name from event message
 func (d *EventsDetector) extractDriverFromMessage(message string) string {
-	// Look for common CSI driver patterns
-	csiDrivers := []string{
-		"cinder.csi.openstack.org",
-		"rook-ceph.rbd.csi.ceph.com",
-		"rook-ceph.cephfs.csi.ceph.com",
-		"ebs.csi.aws.com",
-		"disk.csi.azure.com",
-		"pd.csi.storage.gke.io",
-	}
-
-	for _, driver := range csiDrivers {
-		if strings.Contains(message, driver) {
-			return driver
-		}
-	}
-
-	// If target driver is specified and not found in common list, check for it
+	// Prefer the target driver when the message mentions it
 	if d.targetDriver != "" && strings.Contains(message, d.targetDriver) {
 		return d.targetDriver
 	}
 
-	return "unknown"
+	if driver := parse.ExtractDriver(message); driver != "" {
+		return driver
+	}
+	return unknownDriver
 }
 
 // extractNodeFromEvent attempts to extract node information from the event
@@ -377,42 +409,7 @@ func (d *EventsDetector) extractNodeFromEvent(event corev1.Event) string {
 	}
 	
 	// Try to extract node name from the message for volume-related events
-	nodePatterns := []string{
-		"node \"",
-		"Node \"",
-		" node ",
-		" Node ",
-	}
-	
-	for _, pattern := range nodePatterns {
-		if strings.Contains(event.Message, pattern) {
-			// Find the start of the node name
-			start := strings.Index(event.Message, pattern)
-			if start != -1 {
-				start += len(pattern)
-				
-				// Handle quoted node names
-				if strings.HasSuffix(pattern, "\"") {
-					end := strings.Index(event.Message[start:], "\"")
-					if end != -1 {
-						return event.Message[start : start+end]
-					}
-				} else {
-					// Handle space-separated node names
-					words := strings.Fields(event.Message[start:])
-					if len(words) > 0 {
-						// Clean up punctuation
-						nodeName := strings.Trim(words[0], "\"',.()[]:")
-						if nodeName != "" {
-							return nodeName
-						}
-					}
-				}
-			}
-		}
-	}
-	
-	return ""
+	return parse.ExtractNode(event.Message)
 }
 
 // extractPVCFromEvent attempts to extract PVC information from the event
@@ -424,43 +421,7 @@ func (d *EventsDetector) extractPVCFromEvent(event corev1.Event) string {
 	
 	// For Pod events, try to extract PVC name from the message
 	if event.InvolvedObject.Kind == "Pod" {
-		// Try to extract PVC name from the message
-		pvcPatterns := []string{
-			"pvc \"",
-			"PVC \"",
-			"persistentvolumeclaim \"",
-			"PersistentVolumeClaim \"",
-			"claim \"",
-		}
-		
-		for _, pattern := range pvcPatterns {
-			if strings.Contains(event.Message, pattern) {
-				start := strings.Index(event.Message, pattern)
-				if start != -1 {
-					start += len(pattern)
-					end := strings.Index(event.Message[start:], "\"")
-					if end != -1 {
-						return event.Message[start : start+end]
-					}
-				}
-			}
-		}
-		
-		// Look for PVC name patterns without quotes
-		pvcWords := []string{"pvc", "PVC", "claim"}
-		words := strings.Fields(event.Message)
-		
-		for i, word := range words {
-			for _, pvcWord := range pvcWords {
-				if strings.EqualFold(word, pvcWord) && i+1 < len(words) {
-					// Next word might be the PVC name
-					nextWord := strings.Trim(words[i+1], "\"',.()[]:")
-					if nextWord != "" && !strings.Contains(nextWord, " ") {
-						return nextWord
-					}
-				}
-			}
-		}
+		return parse.ExtractPVC(event.Message)
 	}
 	
 	return ""
@@ -578,44 +539,66 @@ func (d *EventsDetector) isCSIRelatedEvent(event corev1.Event) bool {
 
 // GetRecentEvents returns recent events that might be relevant to CSI mount issues
 func (d *EventsDetector) GetRecentEvents(ctx context.Context, maxResults int) ([]types.EventInfo, error) {
-	events, err := d.client.CoreV1().Events("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list events: %w", err)
-	}
-
 	var relevantEvents []types.EventInfo
 	cutoffTime := time.Now().Add(-d.lookbackDuration)
 
-	for _, event := range events.Items {
-		// Skip old events
-		eventTime := event.LastTimestamp.Time
-		if eventTime.IsZero() {
-			eventTime = event.EventTime.Time
-		}
-		if eventTime.Before(cutoffTime) {
-			continue
-		}
+	err := listEvents(ctx, d.client.CoreV1().Events(d.namespace), func(events *corev1.EventList) error {
+		for _, event := range events.Items {
+			// Skip old events
+			eventTime := event.LastTimestamp.Time
+			if eventTime.IsZero() {
+				eventTime = event.EventTime.Time
+			}
+			if eventTime.Before(cutoffTime) {
+				continue
+			}
 
-		// Filter for volume-related events
-		if d.eventMatchesDriver(event, d.targetDriver) || d.isVolumeRelatedEvent(event) {
-			relevantEvents = append(relevantEvents, types.EventInfo{
-				Type:      event.Type,
-				Reason:    event.Reason,
-				Message:   event.Message,
-				Object:    fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
-				Namespace: event.Namespace,
-				Time:      metav1.Time{Time: eventTime},
-			})
-
-			if len(relevantEvents) >= maxResults {
-				break
+			// Filter for volume-related events, limited to the target driver's events if specified
+			relevant := d.eventMatchesDriver(event, d.targetDriver) || d.isVolumeRelatedEvent(event)
+			if d.targetDriver != "" && !d.eventMatchesDriver(event, d.targetDriver) {
+				// Volume events that cannot be attributed to a driver are kept unless strict
+				relevant = relevant && !d.strictDriverMatch && !d.mentionsOtherDriver(event.Message, d.targetDriver)
+			}
+			if relevant {
+				relevantEvents = append(relevantEvents, types.EventInfo{
+					Type:      event.Type,
+					Reason:    event.Reason,
+					Message:   event.Message,
+					Object:    fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
+					Namespace: event.Namespace,
+					Time:      metav1.Time{Time: eventTime},
+				})
+
+				if len(relevantEvents) >= maxResults {
+					return errStopListing
+				}
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return relevantEvents, nil
 }
 
+// eventSources returns references to an event and the object it describes
+func eventSources(event corev1.Event) []types.SourceRef {
+	sources := []types.SourceRef{
+		{Kind: "Event", Namespace: event.Namespace, Name: event.Name, UID: string(event.UID)},
+	}
+	if event.InvolvedObject.Name != "" {
+		sources = append(sources, types.SourceRef{
+			Kind:      event.InvolvedObject.Kind,
+			Namespace: event.InvolvedObject.Namespace,
+			Name:      event.InvolvedObject.Name,
+			UID:       string(event.InvolvedObject.UID),
+		})
+	}
+	return sources
+}
+
 // buildEventMetadata creates comprehensive metadata from Kubernetes event
 func (d *EventsDetector) buildEventMetadata(event corev1.Event, eventTime time.Time) map[string]string {
 	metadata := map[string]string{
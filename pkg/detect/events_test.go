@@ -71,7 +71,7 @@ var _ = Describe("EventsDetector", func() {
 		Context("when no events exist", func() {
 			It("should return no issues", func() {
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(&corev1.EventList{}, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -108,7 +108,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -145,7 +145,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -156,6 +156,11 @@ var _ = Describe("EventsDetector", func() {
 				Expect(issues[0].Namespace).To(Equal("default"))
 				Expect(issues[0].DetectedBy).To(Equal(types.EventsMethod))
 				Expect(issues[0].Description).To(ContainSubstring("Multi-Attach error detected"))
+				Expect(issues[0].OccurredAt).To(BeTemporally("==", recentTime))
+				Expect(issues[0].Sources).To(Equal([]types.SourceRef{
+					{Kind: "Event", Namespace: "default", Name: "multi-attach-event"},
+					{Kind: "Pod", Name: "test-pod"},
+				}))
 				Expect(issues[0].Metadata).To(HaveKeyWithValue("count", "3"))
 				Expect(issues[0].Metadata).To(HaveKeyWithValue("event_reason", "FailedAttachVolume"))
 			})
@@ -189,7 +194,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -232,7 +237,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -246,6 +251,87 @@ var _ = Describe("EventsDetector", func() {
 				Expect(issues[0].Description).To(ContainSubstring("Mount reference cleanup failure"))
 			})
 
+			It("should classify device busy mount failures as DeviceBusy", func() {
+				recentTime := time.Now().Add(-10 * time.Minute)
+				eventList := &corev1.EventList{
+					Items: []corev1.Event{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "device-busy-event",
+								Namespace: "production",
+							},
+							Type:          "Warning",
+							Reason:        "FailedMount",
+							Message:       "MountVolume.MountDevice failed for volume \"pvc-456\" : umount /dev/mapper/mpatha: device is busy",
+							LastTimestamp: metav1.NewTime(recentTime),
+							EventTime:     metav1.NewMicroTime(recentTime),
+							Source: corev1.EventSource{
+								Component: "kubelet",
+								Host:      "node-3",
+							},
+							InvolvedObject: corev1.ObjectReference{
+								Kind: "Pod",
+								Name: "db-pod",
+							},
+							Count: 1,
+						},
+					},
+				}
+
+				mockEvents.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(eventList, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Type).To(Equal(types.DeviceBusy))
+				Expect(issues[0].Severity).To(Equal(types.SeverityHigh))
+				Expect(issues[0].Volume).To(Equal("pvc-456"))
+				Expect(issues[0].Description).To(ContainSubstring("Device busy"))
+			})
+
+			It("should use configured device busy patterns", func() {
+				detector.SetDeviceBusyPatterns([]string{"Resource Busy"})
+				recentTime := time.Now().Add(-10 * time.Minute)
+				eventList := &corev1.EventList{
+					Items: []corev1.Event{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "custom-busy-event",
+								Namespace: "default",
+							},
+							Type:          "Warning",
+							Reason:        "FailedMount",
+							Message:       "MountVolume.SetUp failed for volume \"pvc-1\" : resource busy",
+							LastTimestamp: metav1.NewTime(recentTime),
+							EventTime:     metav1.NewMicroTime(recentTime),
+						},
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "default-busy-event",
+								Namespace: "default",
+							},
+							Type:          "Warning",
+							Reason:        "FailedMount",
+							Message:       "MountVolume.SetUp failed for volume \"pvc-2\" : target is busy",
+							LastTimestamp: metav1.NewTime(recentTime),
+							EventTime:     metav1.NewMicroTime(recentTime),
+						},
+					},
+				}
+
+				mockEvents.EXPECT().
+					List(ctx, metav1.ListOptions{Limit: 500}).
+					Return(eventList, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(2))
+				Expect(issues[0].Type).To(Equal(types.DeviceBusy))
+				Expect(issues[1].Type).To(Equal(types.CSIOperationFailure))
+			})
+
 			It("should detect general FailedMount events", func() {
 				recentTime := time.Now().Add(-20 * time.Minute)
 				eventList := &corev1.EventList{
@@ -273,7 +359,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -287,6 +373,65 @@ var _ = Describe("EventsDetector", func() {
 			})
 		})
 
+		Context("when a volume keeps attaching and detaching", func() {
+			attachEvent := func(name, reason, message, node string, count int32, at time.Time) corev1.Event {
+				return corev1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "apps"},
+					Type:           "Normal",
+					Reason:         reason,
+					Message:        message,
+					LastTimestamp:  metav1.NewTime(at),
+					Source:         corev1.EventSource{Component: "attachdetach-controller", Host: node},
+					InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "apps", Name: "web-0"},
+					Count:          count,
+				}
+			}
+
+			BeforeEach(func() {
+				detector = detect.NewEventsDetector(mockClient, "", lookbackDuration)
+				detector.SetFlapThreshold(4)
+			})
+
+			It("should report the volume as flapping once it exceeds the threshold", func() {
+				now := time.Now()
+				eventList := &corev1.EventList{
+					Items: []corev1.Event{
+						attachEvent("attach-1", "SuccessfulAttachVolume", `AttachVolume.Attach succeeded for volume "pvc-flap"`, "node-a", 2, now.Add(-50*time.Minute)),
+						attachEvent("detach-1", "SuccessfulDetachVolume", `DetachVolume.Detach succeeded for volume "pvc-flap"`, "node-a", 2, now.Add(-40*time.Minute)),
+						attachEvent("attach-2", "SuccessfulAttachVolume", `AttachVolume.Attach succeeded for volume "pvc-flap"`, "node-b", 1, now.Add(-10*time.Minute)),
+						attachEvent("attach-3", "SuccessfulAttachVolume", `AttachVolume.Attach succeeded for volume "pvc-steady"`, "node-c", 1, now.Add(-5*time.Minute)),
+					},
+				}
+				mockEvents.EXPECT().List(ctx, metav1.ListOptions{Limit: 500}).Return(eventList, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Type).To(Equal(types.AttachmentFlapping))
+				Expect(issues[0].Volume).To(Equal("pvc-flap"))
+				Expect(issues[0].Node).To(Equal("node-b"))
+				Expect(issues[0].Severity).To(Equal(types.SeverityMedium))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("transitions", "5"))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("nodes", "node-a,node-b"))
+				Expect(issues[0].Sources[0].Name).To(Equal("attach-2"))
+			})
+
+			It("should not report a volume at the threshold", func() {
+				now := time.Now()
+				eventList := &corev1.EventList{
+					Items: []corev1.Event{
+						attachEvent("attach-1", "SuccessfulAttachVolume", `AttachVolume.Attach succeeded for volume "pvc-flap"`, "node-a", 2, now.Add(-50*time.Minute)),
+						attachEvent("detach-1", "SuccessfulDetachVolume", `DetachVolume.Detach succeeded for volume "pvc-flap"`, "node-a", 2, now.Add(-40*time.Minute)),
+					},
+				}
+				mockEvents.EXPECT().List(ctx, metav1.ListOptions{Limit: 500}).Return(eventList, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(BeEmpty())
+			})
+		})
+
 		Context("when filtering by target driver", func() {
 			It("should filter events by target driver name in message", func() {
 				recentTime := time.Now().Add(-30 * time.Minute)
@@ -334,7 +479,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -370,7 +515,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -378,6 +523,73 @@ var _ = Describe("EventsDetector", func() {
 				Expect(issues).To(HaveLen(1))
 				Expect(issues[0].Type).To(Equal(types.CSIOperationFailure))
 			})
+
+			Context("with strict driver matching", func() {
+				BeforeEach(func() {
+					recentTime := time.Now().Add(-30 * time.Minute)
+					eventList := &corev1.EventList{
+						Items: []corev1.Event{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name:      "generic-mount-event",
+									Namespace: "default",
+								},
+								Type:          "Warning",
+								Reason:        "FailedMount",
+								Message:       "MountVolume.SetUp failed for volume \"pvc-abc\" : mount failed",
+								LastTimestamp: metav1.NewTime(recentTime),
+								EventTime:     metav1.NewMicroTime(recentTime),
+								Source: corev1.EventSource{
+									Component: "kubelet",
+								},
+								InvolvedObject: corev1.ObjectReference{
+									Kind: "Pod",
+									Name: "generic-pod",
+								},
+								Count: 1,
+							},
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name:      "target-driver-event",
+									Namespace: "default",
+								},
+								Type:          "Warning",
+								Reason:        "FailedAttachVolume",
+								Message:       "AttachVolume.Attach failed for volume: test.csi.driver error",
+								LastTimestamp: metav1.NewTime(recentTime),
+								EventTime:     metav1.NewMicroTime(recentTime),
+								Source: corev1.EventSource{
+									Component: "attachdetach-controller",
+								},
+								InvolvedObject: corev1.ObjectReference{
+									Kind: "Pod",
+									Name: "target-pod",
+								},
+								Count: 1,
+							},
+						},
+					}
+
+					mockEvents.EXPECT().
+						List(ctx, metav1.ListOptions{Limit: 500}).
+						Return(eventList, nil)
+				})
+
+				It("should include events without a driver by default", func() {
+					issues, err := detector.Detect(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(issues).To(HaveLen(2))
+				})
+
+				It("should exclude events without a driver when strict", func() {
+					detector.SetStrictDriverMatch(true)
+
+					issues, err := detector.Detect(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(issues).To(HaveLen(1))
+					Expect(issues[0].Driver).To(Equal(targetDriver))
+				})
+			})
 		})
 
 		Context("when no target driver specified", func() {
@@ -412,7 +624,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -492,7 +704,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				events, err := detector.GetRecentEvents(ctx, 10)
@@ -548,7 +760,7 @@ var _ = Describe("EventsDetector", func() {
 				eventList := &corev1.EventList{Items: eventItems}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				events, err := detector.GetRecentEvents(ctx, 5)
@@ -564,7 +776,7 @@ var _ = Describe("EventsDetector", func() {
 
 			It("should handle Events API errors gracefully", func() {
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(nil, &testError{msg: "Events API error"})
 
 				issues, err := detector.Detect(ctx)
@@ -578,7 +790,7 @@ var _ = Describe("EventsDetector", func() {
 				cancel()
 
 				mockEvents.EXPECT().
-					List(cancelCtx, metav1.ListOptions{}).
+					List(cancelCtx, metav1.ListOptions{Limit: 500}).
 					Return(nil, &testError{msg: "context canceled"})
 
 				issues, err := detector.Detect(cancelCtx)
@@ -587,6 +799,91 @@ var _ = Describe("EventsDetector", func() {
 				Expect(issues).To(BeNil())
 			})
 		})
+
+		Context("when scanning many events", func() {
+			It("should report progress every interval processed events", func() {
+				recentTime := time.Now().Add(-10 * time.Minute)
+				var items []corev1.Event
+				for i := 0; i < 25; i++ {
+					event := corev1.Event{
+						ObjectMeta:    metav1.ObjectMeta{Name: fmt.Sprintf("event-%d", i), Namespace: "default"},
+						Type:          "Normal",
+						Reason:        "Scheduled",
+						Message:       fmt.Sprintf("Successfully assigned default/pod-%d to node-1", i),
+						LastTimestamp: metav1.NewTime(recentTime),
+					}
+					if i%2 == 0 {
+						event.Type = "Warning"
+						event.Reason = "FailedAttachVolume"
+						event.Message = fmt.Sprintf("Multi-Attach error for volume pvc-%d", i)
+					}
+					items = append(items, event)
+				}
+				mockEvents.EXPECT().List(ctx, metav1.ListOptions{Limit: 500}).Return(&corev1.EventList{Items: items}, nil)
+
+				type progress struct{ scanned, matched, total int }
+				var reports []progress
+				detector.SetProgressHook(10, func(scanned, matched, total int) {
+					reports = append(reports, progress{scanned, matched, total})
+				})
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(13))
+				Expect(reports).To(Equal([]progress{{10, 5, 25}, {20, 10, 25}}))
+			})
+
+			It("should process every page and report progress across pages", func() {
+				recentTime := time.Now().Add(-10 * time.Minute)
+				page := func(from, to int) []corev1.Event {
+					var items []corev1.Event
+					for i := from; i < to; i++ {
+						items = append(items, corev1.Event{
+							ObjectMeta:    metav1.ObjectMeta{Name: fmt.Sprintf("event-%d", i), Namespace: "default"},
+							Type:          "Warning",
+							Reason:        "FailedAttachVolume",
+							Message:       fmt.Sprintf("Multi-Attach error for volume pvc-%d", i),
+							LastTimestamp: metav1.NewTime(recentTime),
+						})
+					}
+					return items
+				}
+				remaining := int64(8)
+				gomock.InOrder(
+					mockEvents.EXPECT().List(ctx, metav1.ListOptions{Limit: 500}).Return(&corev1.EventList{
+						ListMeta: metav1.ListMeta{Continue: "page-2", RemainingItemCount: &remaining},
+						Items:    page(0, 7),
+					}, nil),
+					mockEvents.EXPECT().List(ctx, metav1.ListOptions{Limit: 500, Continue: "page-2"}).Return(&corev1.EventList{
+						Items: page(7, 15),
+					}, nil),
+				)
+
+				type progress struct{ scanned, matched, total int }
+				var reports []progress
+				detector.SetProgressHook(5, func(scanned, matched, total int) {
+					reports = append(reports, progress{scanned, matched, total})
+				})
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(15))
+				Expect(reports).To(Equal([]progress{{5, 5, 15}, {10, 10, 15}}))
+			})
+
+			It("should not report progress when disabled", func() {
+				mockEvents.EXPECT().List(ctx, metav1.ListOptions{Limit: 500}).Return(&corev1.EventList{
+					Items: make([]corev1.Event, 5),
+				}, nil)
+
+				called := false
+				detector.SetProgressHook(0, func(scanned, matched, total int) { called = true })
+
+				_, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(called).To(BeFalse())
+			})
+		})
 	})
 
 	Context("Severity Calculation", func() {
@@ -622,7 +919,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -672,7 +969,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -714,7 +1011,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := detector.Detect(ctx)
@@ -764,7 +1061,7 @@ var _ = Describe("EventsDetector", func() {
 					}
 
 					mockEvents.EXPECT().
-						List(ctx, metav1.ListOptions{}).
+						List(ctx, metav1.ListOptions{Limit: 500}).
 						Return(eventList, nil)
 
 					issues, err := detector.Detect(ctx)
@@ -813,7 +1110,7 @@ var _ = Describe("EventsDetector", func() {
 					}
 
 					mockEvents.EXPECT().
-						List(ctx, metav1.ListOptions{}).
+						List(ctx, metav1.ListOptions{Limit: 500}).
 						Return(eventList, nil)
 
 					issues, err := detector.Detect(ctx)
@@ -860,7 +1157,7 @@ var _ = Describe("EventsDetector", func() {
 					}
 
 					mockEvents.EXPECT().
-						List(ctx, metav1.ListOptions{}).
+						List(ctx, metav1.ListOptions{Limit: 500}).
 						Return(eventList, nil)
 
 					issues, err := detector.Detect(ctx)
@@ -930,7 +1227,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := driverDetector.Detect(ctx)
@@ -939,6 +1236,49 @@ var _ = Describe("EventsDetector", func() {
 				Expect(issues[0].Driver).To(Equal("ebs.csi.aws.com"))
 			})
 
+			DescribeTable("should recognize any driver named in the message",
+				func(targetDriver, reason, message string, included bool) {
+					driverDetector := detect.NewEventsDetector(mockClient, targetDriver, lookbackDuration)
+					recentTime := time.Now().Add(-30 * time.Minute)
+					mockEvents.EXPECT().
+						List(ctx, metav1.ListOptions{Limit: 500}).
+						Return(&corev1.EventList{Items: []corev1.Event{{
+							ObjectMeta:     metav1.ObjectMeta{Name: "event", Namespace: "default"},
+							Type:           "Warning",
+							Reason:         reason,
+							Message:        message,
+							LastTimestamp:  metav1.NewTime(recentTime),
+							Source:         corev1.EventSource{Component: "kubelet"},
+							InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-0"},
+							Count:          1,
+						}}}, nil)
+
+					issues, err := driverDetector.Detect(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					if included {
+						Expect(issues).To(HaveLen(1))
+					} else {
+						Expect(issues).To(BeEmpty())
+					}
+				},
+				Entry("vSphere event for vSphere", "csi.vsphere.vmware.com", "FailedAttachVolume",
+					`AttachVolume.Attach failed for volume "pvc-1" : rpc error: code = Internal desc = failed to attach disk with csi.vsphere.vmware.com`, true),
+				Entry("vSphere event for NFS", "nfs.csi.k8s.io", "FailedAttachVolume",
+					`AttachVolume.Attach failed for volume "pvc-1" : rpc error: code = Internal desc = failed to attach disk with csi.vsphere.vmware.com`, false),
+				Entry("vSphere event for EBS", "ebs.csi.aws.com", "FailedMount",
+					`MountVolume.MountDevice failed for volume "pvc-2" : kubernetes.io/csi: attacher.MountDevice failed to create newCsiDriverClient: driver name csi.vsphere.vmware.com not found in the list of registered CSI drivers`, false),
+				Entry("NFS event for vSphere", "csi.vsphere.vmware.com", "FailedMount",
+					`MountVolume.SetUp failed for volume "pvc-3" : rpc error: code = Internal desc = mount failed: exit status 32 from nfs.csi.k8s.io`, false),
+				Entry("Filestore event for GKE PD", "pd.csi.storage.gke.io", "FailedMount",
+					`MountVolume.MountDevice failed for volume "pvc-4" : rpc error from filestore.csi.storage.gke.io: instance not ready`, false),
+				Entry("Longhorn event for Longhorn", "driver.longhorn.io", "FailedMount",
+					`MountVolume.MountDevice failed for volume "pvc-5" : kubernetes.io/csi: attacher.MountDevice failed to create newCsiDriverClient: driver name driver.longhorn.io not found`, true),
+				Entry("event without a driver for vSphere", "csi.vsphere.vmware.com", "FailedMount",
+					"Unable to attach or mount volumes: unmounted volumes=[data], unattached volumes=[data]: timed out waiting for the condition", true),
+				Entry("plugin socket path for SMB", "smb.csi.k8s.io", "FailedMount",
+					"MountVolume.MountDevice failed: dial unix /csi/csi.sock: connect: connection refused", true),
+			)
+
 			It("should include all CSI events when no target driver is set", func() {
 				// Test without target driver
 				allDriverDetector := detect.NewEventsDetector(mockClient, "", lookbackDuration)
@@ -969,7 +1309,7 @@ var _ = Describe("EventsDetector", func() {
 				}
 
 				mockEvents.EXPECT().
-					List(ctx, metav1.ListOptions{}).
+					List(ctx, metav1.ListOptions{Limit: 500}).
 					Return(eventList, nil)
 
 				issues, err := allDriverDetector.Detect(ctx)
@@ -0,0 +1,108 @@
+package detect
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// DefaultFlapThreshold is how many attach and detach events a volume may have within the
+// lookback before it is reported as flapping
+const DefaultFlapThreshold = 10
+
+// attachTransitionReasons are the event reasons recorded when a volume attaches to or
+// detaches from a node, successfully or not
+var attachTransitionReasons = []string{
+	"SuccessfulAttachVolume",
+	"FailedAttachVolume",
+	"SuccessfulDetachVolume",
+	"FailedDetachVolume",
+}
+
+// attachHistory accumulates the attach and detach events seen for one volume
+type attachHistory struct {
+	volume      string
+	transitions int
+	nodes       map[string]bool
+	driver      string
+	lastSeen    time.Time
+	lastEvent   corev1.Event
+}
+
+// attachTracker counts attach and detach events per volume during an event scan
+type attachTracker map[string]*attachHistory
+
+// record adds an event to the volume's history if it is an attach or detach event
+func (t attachTracker) record(d *EventsDetector, event corev1.Event, eventTime time.Time) {
+	if !slices.Contains(attachTransitionReasons, event.Reason) {
+		return
+	}
+	volume := d.extractVolumeFromMessage(event.Message)
+	if volume == "unknown" {
+		return
+	}
+
+	history, ok := t[volume]
+	if !ok {
+		history = &attachHistory{volume: volume, nodes: make(map[string]bool)}
+		t[volume] = history
+	}
+	// Repeated events are aggregated into one with a count
+	history.transitions += max(int(event.Count), 1)
+	if node := d.getNodeForDisplay(event); node != "" {
+		history.nodes[node] = true
+	}
+	if driver := d.extractDriverFromMessage(event.Message); driver != "unknown" {
+		history.driver = driver
+	}
+	if !eventTime.Before(history.lastSeen) {
+		history.lastSeen = eventTime
+		history.lastEvent = event
+	}
+}
+
+// flappingIssues reports volumes with more attach and detach events than the threshold,
+// ordered by volume
+func (t attachTracker) flappingIssues(d *EventsDetector) []types.CSIMountIssue {
+	var issues []types.CSIMountIssue
+	for _, volume := range slices.Sorted(maps.Keys(t)) {
+		history := t[volume]
+		if history.transitions <= d.flapThreshold {
+			continue
+		}
+
+		nodes := slices.Sorted(maps.Keys(history.nodes))
+		severity := types.SeverityMedium
+		if history.transitions > 2*d.flapThreshold {
+			severity = types.SeverityHigh
+		}
+		event := history.lastEvent
+		issues = append(issues, types.CSIMountIssue{
+			Type:        types.AttachmentFlapping,
+			Severity:    severity,
+			Node:        d.getNodeForDisplay(event),
+			Volume:      volume,
+			PVC:         d.getPVCForDisplay(event),
+			Namespace:   event.Namespace,
+			Driver:      history.driver,
+			Description: fmt.Sprintf("Volume %s attached or detached %d times in the last %s (threshold %d), indicating attachment instability", volume, history.transitions, d.lookbackDuration, d.flapThreshold),
+			DetectedBy:  types.EventsMethod,
+			DetectedAt:  time.Now(),
+			OccurredAt:  history.lastSeen,
+			Metadata: map[string]string{
+				"transitions": fmt.Sprintf("%d", history.transitions),
+				"threshold":   fmt.Sprintf("%d", d.flapThreshold),
+				"nodes":       strings.Join(nodes, ","),
+				"lookback":    d.lookbackDuration.String(),
+			},
+			Sources: eventSources(event),
+		})
+	}
+	return issues
+}
@@ -0,0 +1,48 @@
+package detect
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// resolveFocus looks up the PV bound to the PVC the detection is focused on, since
+// VolumeAttachment issues only name the PV. A PVC that does not exist is not an error:
+// issues such as a missing PVC still name it.
+func (d *Detector) resolveFocus(ctx context.Context) error {
+	namespace, name, ok := strings.Cut(d.options.PVC, "/")
+	if !ok {
+		return fmt.Errorf("invalid PVC %q: must be namespace/name", d.options.PVC)
+	}
+
+	pvc, err := d.client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get PVC %s: %w", d.options.PVC, err)
+	}
+	d.focusPV = pvc.Spec.VolumeName
+	return nil
+}
+
+// focus keeps only the issues about the PVC the detection is focused on, matched by the
+// PVC itself or by its bound PV
+func (d *Detector) focus(issues []types.CSIMountIssue) []types.CSIMountIssue {
+	if d.options.PVC == "" {
+		return issues
+	}
+
+	var focused []types.CSIMountIssue
+	for _, issue := range issues {
+		if issuePVCKey(issue) == d.options.PVC || (d.focusPV != "" && issue.Volume == d.focusPV) {
+			focused = append(focused, issue)
+		}
+	}
+	return focused
+}
@@ -0,0 +1,213 @@
+package detect
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// mergeKinds groups the issue types that different methods report for the same problem
+// on a volume, such as a multi-attach seen both in VolumeAttachments and in events
+var mergeKinds = map[types.IssueType]string{
+	types.MultipleAttachments: "multi-attach",
+	types.MultiAttachError:    "multi-attach",
+}
+
+// mergedMethodsKey is the metadata key listing the methods whose findings were merged
+const mergedMethodsKey = "merged_methods"
+
+// relatedMethodsKey is the metadata key listing the methods whose issues about the same
+// volume on the same node were correlated into one
+const relatedMethodsKey = "related_methods"
+
+// driverConflictKey is the metadata key holding a driver that was replaced by the one
+// the VolumeAttachment method found for the same volume
+const driverConflictKey = "driver_source_conflict"
+
+// unknownDriver is recorded by detectors that could not determine an issue's driver
+const unknownDriver = "unknown"
+
+// ReconcileDrivers corrects the driver of issues about a volume that the VolumeAttachment
+// method also reported on. Its driver comes from the VolumeAttachment itself, while other
+// methods such as events extract theirs from messages and can get it wrong. A differing
+// driver that was not merely unknown is kept in Metadata["driver_source_conflict"].
+func ReconcileDrivers(issues []types.CSIMountIssue) []types.CSIMountIssue {
+	authoritative := make(map[string]string)
+	for _, issue := range issues {
+		if issue.DetectedBy == types.VolumeAttachmentMethod && issue.Volume != "" && issue.Volume != unknownVolume &&
+			issue.Driver != "" && issue.Driver != unknownDriver {
+			authoritative[issue.Volume] = issue.Driver
+		}
+	}
+
+	reconciled := make([]types.CSIMountIssue, 0, len(issues))
+	for _, issue := range issues {
+		driver, ok := authoritative[issue.Volume]
+		if ok && issue.Driver != driver {
+			if issue.Driver != "" && issue.Driver != unknownDriver {
+				metadata := make(map[string]string, len(issue.Metadata)+1)
+				for key, value := range issue.Metadata {
+					metadata[key] = value
+				}
+				metadata[driverConflictKey] = issue.Driver
+				issue.Metadata = metadata
+			}
+			issue.Driver = driver
+		}
+		reconciled = append(reconciled, issue)
+	}
+	return reconciled
+}
+
+// MergeAcrossMethods folds issues that different methods report for the same problem on
+// the same volume into the first of them, so one problem is reported once. No evidence is
+// dropped: distinct descriptions are joined, sources are combined, and metadata keys are
+// united, with a later method's value kept under "<method>.<key>" when the key is taken.
+// Issues from the same method are never merged with each other.
+func MergeAcrossMethods(issues []types.CSIMountIssue) []types.CSIMountIssue {
+	merged := make([]types.CSIMountIssue, 0, len(issues))
+	// Index in merged of the issue each kind and volume is folded into
+	into := make(map[string]int)
+	for _, issue := range issues {
+		kind, ok := mergeKinds[issue.Type]
+		if !ok || issue.Volume == "" || issue.Volume == unknownVolume {
+			merged = append(merged, issue)
+			continue
+		}
+
+		key := kind + "|" + issue.Volume
+		i, seen := into[key]
+		if !seen || slices.Contains(mergedMethods(merged[i]), string(issue.DetectedBy)) {
+			into[key] = len(merged)
+			merged = append(merged, issue)
+			continue
+		}
+		methods := mergedMethods(merged[i])
+		merged[i] = mergeIssue(merged[i], issue)
+		merged[i].Metadata[mergedMethodsKey] = strings.Join(append(methods, string(issue.DetectedBy)), ",")
+	}
+	return merged
+}
+
+// CorrelateIssues folds issues that different methods report about the same volume on the
+// same node into the first of them, whatever their types, so that one stuck volume seen
+// as a stuck VolumeAttachment, a mount failure event and a cross-node reference counts as
+// one issue. Issues are about the same volume when they share their PV or their PVC. The
+// result is merged as by MergeAcrossMethods, with Metadata["related_methods"] listing the
+// methods that saw it. Issues from the same method are never correlated with each other.
+func CorrelateIssues(issues []types.CSIMountIssue) []types.CSIMountIssue {
+	correlated := make([]types.CSIMountIssue, 0, len(issues))
+	// Index in correlated of the issue each node and volume is folded into
+	into := make(map[string]int)
+	for _, issue := range issues {
+		keys := correlationKeys(issue)
+
+		i, seen := -1, false
+		for _, key := range keys {
+			if i, seen = into[key]; seen {
+				break
+			}
+		}
+		if !seen || slices.ContainsFunc(relatedMethods(issue), func(method string) bool {
+			return slices.Contains(relatedMethods(correlated[i]), method)
+		}) {
+			for _, key := range keys {
+				into[key] = len(correlated)
+			}
+			correlated = append(correlated, issue)
+			continue
+		}
+
+		methods := append(relatedMethods(correlated[i]), relatedMethods(issue)...)
+		correlated[i] = mergeIssue(correlated[i], issue)
+		correlated[i].Metadata[relatedMethodsKey] = strings.Join(methods, ",")
+		// The folded issue may have added a PV or PVC the first one lacked
+		for _, key := range correlationKeys(correlated[i]) {
+			into[key] = i
+		}
+	}
+	return correlated
+}
+
+// correlationKeys returns the keys an issue is correlated under: its node with its PV and
+// with its PVC, whichever are known. Issues without a node are not correlated.
+func correlationKeys(issue types.CSIMountIssue) []string {
+	if issue.Node == "" {
+		return nil
+	}
+	var keys []string
+	if issue.Volume != "" && issue.Volume != unknownVolume {
+		keys = append(keys, issue.Node+"|pv|"+issue.Volume)
+	}
+	// The cross-node PVC method names claims as namespace/name, other methods by name
+	if pvc := issuePVCKey(issue); pvc != "" {
+		keys = append(keys, issue.Node+"|pvc|"+pvc)
+	}
+	return keys
+}
+
+// relatedMethods returns the methods that saw an issue, including those merged into it
+func relatedMethods(issue types.CSIMountIssue) []string {
+	if methods, ok := issue.Metadata[relatedMethodsKey]; ok {
+		return strings.Split(methods, ",")
+	}
+	return mergedMethods(issue)
+}
+
+// mergedMethods returns the methods that contributed to an issue
+func mergedMethods(issue types.CSIMountIssue) []string {
+	if methods, ok := issue.Metadata[mergedMethodsKey]; ok {
+		return strings.Split(methods, ",")
+	}
+	return []string{string(issue.DetectedBy)}
+}
+
+// mergeIssue folds other into issue. The result keeps the type and method of issue, takes
+// the higher severity and the earlier occurrence, and fills fields issue left empty. Its
+// metadata is a new map, which callers record the contributing methods in.
+func mergeIssue(issue, other types.CSIMountIssue) types.CSIMountIssue {
+	if other.Severity.Level() > issue.Severity.Level() {
+		issue.Severity = other.Severity
+	}
+	if !other.OccurredAt.IsZero() && (issue.OccurredAt.IsZero() || other.OccurredAt.Before(issue.OccurredAt)) {
+		issue.OccurredAt = other.OccurredAt
+	}
+	issue.Node = cmp.Or(issue.Node, other.Node)
+	issue.PVC = cmp.Or(issue.PVC, other.PVC)
+	issue.Namespace = cmp.Or(issue.Namespace, other.Namespace)
+	issue.Driver = cmp.Or(issue.Driver, other.Driver)
+
+	if other.Description != "" && !slices.Contains(strings.Split(issue.Description, "; "), other.Description) {
+		if issue.Description == "" {
+			issue.Description = other.Description
+		} else {
+			issue.Description += "; " + other.Description
+		}
+	}
+
+	metadata := make(map[string]string, len(issue.Metadata)+len(other.Metadata)+1)
+	for key, value := range issue.Metadata {
+		metadata[key] = value
+	}
+	for key, value := range other.Metadata {
+		if key == mergedMethodsKey || key == relatedMethodsKey {
+			continue
+		}
+		if existing, taken := metadata[key]; taken && existing != value {
+			key = string(other.DetectedBy) + "." + key
+		}
+		metadata[key] = value
+	}
+	issue.Metadata = metadata
+
+	sources := slices.Clone(issue.Sources)
+	for _, source := range other.Sources {
+		if !slices.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+	}
+	issue.Sources = sources
+	return issue
+}
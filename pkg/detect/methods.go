@@ -0,0 +1,94 @@
+package detect
+
+import (
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// AvailableMethods returns a description of every supported detection method,
+// including the API objects it reads and the RBAC permissions it needs
+func AvailableMethods() []types.MethodInfo {
+	return []types.MethodInfo{
+		{
+			Method:      types.VolumeAttachmentMethod,
+			Analysis:    true,
+			Description: "Check VolumeAttachment API objects for errors, stuck attachments and multi-node conflicts",
+			Reads: []string{
+				"VolumeAttachment (storage.k8s.io/v1)",
+				"PersistentVolume (v1), for the CSI driver of attached volumes",
+				"PersistentVolumeClaim (v1) with --check-claims",
+			},
+			Permissions: []types.Permission{
+				{Resource: "volumeattachments.storage.k8s.io", Verbs: []string{"list"}},
+				{Resource: "persistentvolumes", Verbs: []string{"get"}},
+				{Resource: "persistentvolumeclaims", Verbs: []string{"get"}},
+			},
+		},
+		{
+			Method:      types.CrossNodePVCMethod,
+			Analysis:    true,
+			Description: "Analyze pod PVC usage to find claims mounted on more than one node or missing entirely",
+			Reads: []string{
+				"Pod (v1)",
+				"PersistentVolumeClaim (v1)",
+				"PersistentVolume (v1)",
+				"StorageClass (storage.k8s.io/v1)",
+			},
+			Permissions: []types.Permission{
+				{Resource: "pods", Verbs: []string{"list"}},
+				{Resource: "persistentvolumeclaims", Verbs: []string{"get"}},
+				{Resource: "persistentvolumes", Verbs: []string{"get"}},
+				{Resource: "storageclasses.storage.k8s.io", Verbs: []string{"get"}},
+			},
+		},
+		{
+			Method:      types.EventsMethod,
+			Analysis:    true,
+			Description: "Scan recent Kubernetes events for Multi-Attach, attach and mount failures",
+			Reads:       []string{"Event (v1)"},
+			Permissions: []types.Permission{
+				{Resource: "events", Verbs: []string{"list"}},
+			},
+		},
+		{
+			Method:      types.MetricsMethod,
+			Analysis:    true,
+			Description: "Query Prometheus for CSI operation failures and timeouts",
+			Reads:       []string{"Prometheus HTTP API (no Kubernetes objects)"},
+			Permissions: []types.Permission{},
+		},
+		{
+			Method:      types.StorageClassMethod,
+			Description: "Report StorageClass binding mode, expansion and reclaim settings that commonly cause problems",
+			Reads:       []string{"StorageClass (storage.k8s.io/v1)"},
+			Permissions: []types.Permission{
+				{Resource: "storageclasses.storage.k8s.io", Verbs: []string{"list"}},
+			},
+		},
+		{
+			Method:      types.DriverTopologyMethod,
+			Description: "Find nodes running pods with CSI volumes whose CSINode does not register the volumes' driver",
+			Reads: []string{
+				"CSINode (storage.k8s.io/v1)",
+				"CSIDriver (storage.k8s.io/v1)",
+				"Pod (v1)",
+				"PersistentVolumeClaim (v1)",
+				"PersistentVolume (v1)",
+			},
+			Permissions: []types.Permission{
+				{Resource: "csinodes.storage.k8s.io", Verbs: []string{"list"}},
+				{Resource: "csidrivers.storage.k8s.io", Verbs: []string{"list"}},
+				{Resource: "pods", Verbs: []string{"list"}},
+				{Resource: "persistentvolumeclaims", Verbs: []string{"get"}},
+				{Resource: "persistentvolumes", Verbs: []string{"get"}},
+			},
+		},
+		{
+			Method:      types.NodeConditionsMethod,
+			Description: "Report nodes with issues from the other methods that are NotReady or under disk or PID pressure",
+			Reads:       []string{"Node (v1)"},
+			Permissions: []types.Permission{
+				{Resource: "nodes", Verbs: []string{"list"}},
+			},
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package detect_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("AvailableMethods", func() {
+	It("should describe every detection method", func() {
+		methods := detect.AvailableMethods()
+
+		var names []types.DetectionMethod
+		for _, m := range methods {
+			names = append(names, m.Method)
+			Expect(m.Description).NotTo(BeEmpty())
+			Expect(m.Reads).NotTo(BeEmpty())
+		}
+		Expect(names).To(ConsistOf(
+			types.VolumeAttachmentMethod,
+			types.CrossNodePVCMethod,
+			types.EventsMethod,
+			types.MetricsMethod,
+			types.StorageClassMethod,
+			types.NodeConditionsMethod,
+			types.DriverTopologyMethod,
+		))
+	})
+
+	It("should list the RBAC permissions required by Kubernetes-backed methods", func() {
+		for _, m := range detect.AvailableMethods() {
+			if m.Method == types.MetricsMethod {
+				Expect(m.Permissions).To(BeEmpty())
+				continue
+			}
+			Expect(m.Permissions).NotTo(BeEmpty())
+		}
+	})
+
+	It("should mark the methods the detailed analysis covers", func() {
+		var analyses []types.DetectionMethod
+		for _, m := range detect.AvailableMethods() {
+			if m.Analysis {
+				analyses = append(analyses, m.Method)
+			}
+		}
+		Expect(analyses).To(Equal([]types.DetectionMethod{
+			types.VolumeAttachmentMethod,
+			types.CrossNodePVCMethod,
+			types.EventsMethod,
+			types.MetricsMethod,
+		}))
+	})
+})
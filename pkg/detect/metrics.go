@@ -2,15 +2,43 @@ package detect
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"os"
+	"time"
 
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client"
 	"github.com/jdambly/kubectl-csi-scan/pkg/types"
 )
 
+// DefaultDriverLabel is the label CSI sidecars use for the driver name on csi_operations_seconds
+const DefaultDriverLabel = "driver_name"
+
+// GrafanaDashboardLabel is the label the Grafana sidecar watches for dashboard ConfigMaps
+const GrafanaDashboardLabel = "grafana_dashboard"
+
+// prometheusTimeout bounds each request to Prometheus
+const prometheusTimeout = 30 * time.Second
+
 // MetricsDetector implements detection via Prometheus metrics analysis
 type MetricsDetector struct {
 	prometheusURL string
 	targetDriver  string
+	compareDriver string
+	driverLabel   string
+	httpClient    *http.Client
+	threshold     float64
 }
 
 // NewMetricsDetector creates a new metrics detector
@@ -18,74 +46,302 @@ func NewMetricsDetector(prometheusURL, targetDriver string) *MetricsDetector {
 	return &MetricsDetector{
 		prometheusURL: prometheusURL,
 		targetDriver:  targetDriver,
+		driverLabel:   DefaultDriverLabel,
+		httpClient:    newPrometheusClient(nil),
+	}
+}
+
+// newPrometheusClient returns an HTTP client that goes through the proxy set in
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY, as Prometheus is often only reachable through one
+// in corporate networks. A nil tlsConfig trusts the system cert pool.
+func newPrometheusClient(tlsConfig *tls.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{
+		Transport: transport,
+		Timeout:   prometheusTimeout,
+	}
+}
+
+// SetTLS overrides how the Prometheus server certificate is verified: caFile is a PEM
+// bundle trusted instead of the system cert pool, and insecure skips verification
+// altogether. With neither set the system cert pool is used.
+func (d *MetricsDetector) SetTLS(caFile string, insecure bool) error {
+	if caFile == "" && !insecure {
+		d.httpClient = newPrometheusClient(nil)
+		return nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecure, //nolint:gosec // explicitly requested by the user
 	}
+	if caFile != "" {
+		data, err := os.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read Prometheus CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("no PEM certificates found in Prometheus CA file %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	d.httpClient = newPrometheusClient(tlsConfig)
+	return nil
+}
+
+// SetPrometheusTLS sets how the metrics method verifies the Prometheus server certificate,
+// as MetricsDetector.SetTLS does. It does nothing when the metrics method is not enabled.
+func (d *Detector) SetPrometheusTLS(caFile string, insecure bool) error {
+	if d.metricsDetector == nil {
+		return nil
+	}
+	return d.metricsDetector.SetTLS(caFile, insecure)
+}
+
+// HTTPClient returns the client used for Prometheus requests
+func (d *MetricsDetector) HTTPClient() *http.Client {
+	return d.httpClient
 }
 
-// Detect finds CSI mount issues using Prometheus metrics
-// Note: This is a framework implementation - actual Prometheus client integration would be needed
+// SetDriverLabel sets the label that holds the driver name in CSI operation metrics, for
+// Prometheus setups that relabel it (e.g. driver or csi_driver). Empty uses DefaultDriverLabel.
+func (d *MetricsDetector) SetDriverLabel(label string) {
+	if label == "" {
+		label = DefaultDriverLabel
+	}
+	d.driverLabel = label
+}
+
+// SetCompareDriver enables A/B mode, generating driver-specific queries and dashboard
+// series for a second driver alongside the target driver
+func (d *MetricsDetector) SetCompareDriver(driver string) {
+	d.compareDriver = driver
+}
+
+// SetThreshold sets the value a series returned by a query must exceed to be reported.
+// The default of 0 reports every series with a positive value.
+func (d *MetricsDetector) SetThreshold(threshold float64) {
+	d.threshold = threshold
+}
+
+// Detect finds CSI mount issues by running the metric queries against Prometheus,
+// reporting every series whose value exceeds the threshold as a CSI operation failure.
+// Without a Prometheus URL there is nothing to query and no issues are returned.
 func (d *MetricsDetector) Detect(ctx context.Context) ([]types.CSIMountIssue, error) {
-	// Framework implementation returns empty results but no error
-	// In a full implementation, these would query Prometheus and analyze results
-	return []types.CSIMountIssue{}, nil
+	if d.prometheusURL == "" {
+		return []types.CSIMountIssue{}, nil
+	}
+
+	// Passing the whole client, not just its transport, keeps its timeout on every request
+	promClient, err := promapi.NewClient(promapi.Config{
+		Address: d.prometheusURL,
+		Client:  d.httpClient,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid Prometheus URL %s: %w", d.prometheusURL, err)
+	}
+	api := promv1.NewAPI(promClient)
+
+	issues := []types.CSIMountIssue{}
+	for _, query := range d.metricQueries() {
+		queryCtx, cancel := context.WithTimeout(ctx, prometheusTimeout)
+		value, warnings, err := api.Query(queryCtx, query.Query, time.Now())
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("prometheus query %q failed: %w", query.Name, err)
+		}
+		for _, warning := range warnings {
+			log.Warn().Str("query", query.Name).Msg(warning)
+		}
+
+		vector, ok := value.(model.Vector)
+		if !ok {
+			continue
+		}
+		for _, sample := range vector {
+			if float64(sample.Value) > d.threshold {
+				issues = append(issues, d.sampleIssue(query, sample))
+			}
+		}
+	}
+	return issues, nil
 }
 
-// GetMetricQueries returns the Prometheus queries for detecting CSI mount issues
-func (d *MetricsDetector) GetMetricQueries() []types.MetricQuery {
-	queries := []types.MetricQuery{
-		{
-			Name:        "CSI Attach Failures",
-			Query:       fmt.Sprintf(`csi_operations_seconds{driver_name="%s",grpc_status_code!="OK",method_name=~".*Attach.*"}`, d.targetDriver),
-			Description: "CSI attach operations with non-OK gRPC status codes",
-		},
-		{
-			Name:        "CSI Mount Failures",
-			Query:       fmt.Sprintf(`csi_operations_seconds{driver_name="%s",grpc_status_code!="OK",method_name=~".*Mount.*"}`, d.targetDriver),
-			Description: "CSI mount operations with non-OK gRPC status codes",
-		},
-		{
-			Name:        "CSI Operation Timeouts",
-			Query:       fmt.Sprintf(`csi_operations_seconds{driver_name="%s"} > 120 # timeout detection`, d.targetDriver),
-			Description: "CSI operations taking longer than 2 minutes (timeout indicator)",
-		},
-		{
-			Name:        "Storage Operation Failures",
-			Query:       fmt.Sprintf(`storage_operation_duration_seconds{volume_plugin=~".*%s.*",status="fail-unknown"}`, d.targetDriver),
-			Description: "Storage operations that failed with unknown status",
-		},
-		{
-			Name:        "Volume Attachment Conflicts",
-			Query:       `count(kube_volumeattachment_info{status_attached="true"}) by (volumeattachment) > 1`,
-			Description: "VolumeAttachments with conflicting attachment states",
-		},
-		{
-			Name:        "High Operation Duration",
-			Query:       fmt.Sprintf(`storage_operation_duration_seconds{volume_plugin=~".*%s.*"} > 300`, d.targetDriver),
-			Description: "Storage operations taking longer than 5 minutes",
-		},
-		{
-			Name:        "CSI Node Operations",
-			Query:       fmt.Sprintf(`csi_operations_seconds{driver_name="%s",method_name=~"NodePublishVolume|NodeUnpublishVolume|NodeStageVolume|NodeUnstageVolume"}`, d.targetDriver),
-			Description: "CSI node-level operations that might indicate mount/unmount issues",
-		},
-		{
-			Name:        "Failed Mount Events",
-			Query:       `kube_event_total{reason="FailedMount",type="Warning"}`,
-			Description: "Kubernetes events for failed mount operations",
-		},
-		{
-			Name:        "Failed Attach Events",
-			Query:       `kube_event_total{reason="FailedAttachVolume",type="Warning"}`,
-			Description: "Kubernetes events for failed volume attachment",
+// sampleIssue describes a series that exceeded the threshold. The driver is the one the
+// query was generated for, else the series' driver label, else the target driver.
+func (d *MetricsDetector) sampleIssue(query metricQuery, sample *model.Sample) types.CSIMountIssue {
+	driver := query.driver
+	if driver == "" {
+		driver = string(sample.Metric[model.LabelName(d.driverLabel)])
+	}
+	if driver == "" {
+		driver = d.targetDriver
+	}
+
+	return types.CSIMountIssue{
+		Type:        types.CSIOperationFailure,
+		Severity:    query.severity,
+		Node:        string(sample.Metric["node"]),
+		Driver:      driver,
+		Description: fmt.Sprintf("%s: %s is %g", query.Name, sample.Metric, float64(sample.Value)),
+		DetectedBy:  types.MetricsMethod,
+		DetectedAt:  time.Now(),
+		OccurredAt:  sample.Timestamp.Time(),
+		Metadata: map[string]string{
+			"query":  query.Query,
+			"series": sample.Metric.String(),
+			"value":  fmt.Sprintf("%g", float64(sample.Value)),
 		},
 	}
+}
+
+// metricQueryTemplate is a Prometheus query, optionally parameterized by driver name
+type metricQueryTemplate struct {
+	name        string
+	query       string // %[1]s is replaced by the driver name and %[2]s by the driver label when perDriver is set
+	description string
+	perDriver   bool
+	severity    types.IssueSeverity // of issues raised for series the query returns
+}
+
+// metricQuery is a query generated from a template, with the driver it was generated for
+type metricQuery struct {
+	types.MetricQuery
+	driver   string
+	severity types.IssueSeverity
+}
+
+// metricQueryTemplates are the queries for detecting CSI mount issues, in output order
+var metricQueryTemplates = []metricQueryTemplate{
+	{
+		name:        "CSI Attach Failures",
+		query:       `csi_operations_seconds{%[2]s="%[1]s",grpc_status_code!="OK",method_name=~".*Attach.*"}`,
+		description: "CSI attach operations with non-OK gRPC status codes",
+		perDriver:   true,
+		severity:    types.SeverityHigh,
+	},
+	{
+		name:        "CSI Mount Failures",
+		query:       `csi_operations_seconds{%[2]s="%[1]s",grpc_status_code!="OK",method_name=~".*Mount.*"}`,
+		description: "CSI mount operations with non-OK gRPC status codes",
+		perDriver:   true,
+		severity:    types.SeverityHigh,
+	},
+	{
+		name:        "CSI Operation Timeouts",
+		query:       `csi_operations_seconds{%[2]s="%[1]s"} > 120 # timeout detection`,
+		description: "CSI operations taking longer than 2 minutes (timeout indicator)",
+		perDriver:   true,
+		severity:    types.SeverityMedium,
+	},
+	{
+		name:        "Storage Operation Failures",
+		query:       `storage_operation_duration_seconds{volume_plugin=~".*%[1]s.*",status="fail-unknown"}`,
+		description: "Storage operations that failed with unknown status",
+		perDriver:   true,
+		severity:    types.SeverityHigh,
+	},
+	{
+		name:        "Volume Attachment Conflicts",
+		query:       `count(kube_volumeattachment_info{status_attached="true"}) by (volumeattachment) > 1`,
+		description: "VolumeAttachments with conflicting attachment states",
+		severity:    types.SeverityCritical,
+	},
+	{
+		name:        "High Operation Duration",
+		query:       `storage_operation_duration_seconds{volume_plugin=~".*%[1]s.*"} > 300`,
+		description: "Storage operations taking longer than 5 minutes",
+		perDriver:   true,
+		severity:    types.SeverityMedium,
+	},
+	{
+		name:        "CSI Node Operations",
+		query:       `csi_operations_seconds{%[2]s="%[1]s",method_name=~"NodePublishVolume|NodeUnpublishVolume|NodeStageVolume|NodeUnstageVolume"}`,
+		description: "CSI node-level operations that might indicate mount/unmount issues",
+		perDriver:   true,
+		severity:    types.SeverityLow,
+	},
+	{
+		name:        "Failed Mount Events",
+		query:       `kube_event_total{reason="FailedMount",type="Warning"}`,
+		description: "Kubernetes events for failed mount operations",
+		severity:    types.SeverityMedium,
+	},
+	{
+		name:        "Failed Attach Events",
+		query:       `kube_event_total{reason="FailedAttachVolume",type="Warning"}`,
+		description: "Kubernetes events for failed volume attachment",
+		severity:    types.SeverityMedium,
+	},
+}
+
+// drivers returns the drivers queries are generated for: the target driver, followed
+// by the comparison driver in A/B mode
+func (d *MetricsDetector) drivers() []string {
+	if d.compareDriver == "" {
+		return []string{d.targetDriver}
+	}
+	return []string{d.targetDriver, d.compareDriver}
+}
+
+// GetMetricQueries returns the Prometheus queries for detecting CSI mount issues. With a
+// comparison driver, each driver-specific query is returned once per driver.
+func (d *MetricsDetector) GetMetricQueries() []types.MetricQuery {
+	var queries []types.MetricQuery
+	for _, query := range d.metricQueries() {
+		queries = append(queries, query.MetricQuery)
+	}
+	return queries
+}
+
+// metricQueries generates the queries from the templates for the configured drivers
+func (d *MetricsDetector) metricQueries() []metricQuery {
+	var queries []metricQuery
+	for _, template := range metricQueryTemplates {
+		if !template.perDriver {
+			queries = append(queries, metricQuery{
+				MetricQuery: types.MetricQuery{
+					Name:        template.name,
+					Query:       template.query,
+					Description: template.description,
+				},
+				severity: template.severity,
+			})
+			continue
+		}
+
+		for _, driver := range d.drivers() {
+			name := template.name
+			if d.compareDriver != "" {
+				name = fmt.Sprintf("%s (%s)", template.name, driver)
+			}
+			queries = append(queries, metricQuery{
+				MetricQuery: types.MetricQuery{
+					Name:        name,
+					Query:       fmt.Sprintf(template.query, driver, d.driverLabel),
+					Description: template.description,
+				},
+				driver:   driver,
+				severity: template.severity,
+			})
+		}
+	}
 
 	// Add driver-specific queries if target driver is specified
 	if d.targetDriver != "" {
-		queries = append(queries, types.MetricQuery{
-			Name:        "driver_specific_errors",
-			Query:       fmt.Sprintf(`{__name__=~".*%s.*"} != 0`, d.targetDriver),
-			Description: fmt.Sprintf("Any metrics containing the driver name '%s' with non-zero values", d.targetDriver),
-		})
+		for _, driver := range d.drivers() {
+			queries = append(queries, metricQuery{
+				MetricQuery: types.MetricQuery{
+					Name:        "driver_specific_errors",
+					Query:       fmt.Sprintf(`{__name__=~".*%s.*"} != 0`, driver),
+					Description: fmt.Sprintf("Any metrics containing the driver name '%s' with non-zero values", driver),
+				},
+				driver:   driver,
+				severity: types.SeverityLow,
+			})
+		}
 	}
 
 	return queries
@@ -95,14 +351,14 @@ func (d *MetricsDetector) GetMetricQueries() []types.MetricQuery {
 func (d *MetricsDetector) GetRecommendedAlerts() []string {
 	return []string{
 		fmt.Sprintf(`alert: CSIOperationFailures
-expr: rate(csi_operations_seconds{driver_name="%s",grpc_status_code!="OK"}[5m]) > 0.1
+expr: rate(csi_operations_seconds{%s="%s",grpc_status_code!="OK"}[5m]) > 0.1
 for: 2m
 labels:
   severity: warning
   component: storage
 annotations:
   summary: "High rate of CSI operation failures"
-  description: "CSI driver %s is experiencing {{ $value }} failures per second"`, d.targetDriver, d.targetDriver),
+  description: "CSI driver %s is experiencing {{ $value }} failures per second"`, d.driverLabel, d.targetDriver, d.targetDriver),
 
 		fmt.Sprintf(`alert: StorageOperationFailures
 expr: rate(storage_operation_duration_seconds{volume_plugin=~".*%s.*",status="fail-unknown"}[5m]) > 0.1
@@ -124,7 +380,7 @@ annotations:
   summary: "Multiple VolumeAttachments for same volume"
   description: "Volume {{ $labels.volumeattachment }} appears to be attached to multiple nodes"`,
 
-		`alert: LongRunningCSIOperations
+		fmt.Sprintf(`alert: LongRunningCSIOperations
 expr: csi_operations_seconds > 300
 for: 5m
 labels:
@@ -132,7 +388,7 @@ labels:
   component: storage
 annotations:
   summary: "CSI operation taking too long"
-  description: "CSI operation {{ $labels.method_name }} for driver {{ $labels.driver_name }} has been running for {{ $value }} seconds"`,
+  description: "CSI operation {{ $labels.method_name }} for driver {{ $labels.%s }} has been running for {{ $value }} seconds"`, d.driverLabel),
 
 		`alert: MultiAttachErrors
 expr: increase(kube_event_total{reason="FailedAttachVolume",type="Warning"}[5m]) > 0
@@ -146,59 +402,112 @@ annotations:
 	}
 }
 
-// GenerateGrafanaDashboard returns a JSON dashboard configuration for CSI metrics
+// grafanaPanel is a panel of the generated Grafana dashboard
+type grafanaPanel struct {
+	Title   string          `json:"title"`
+	Type    string          `json:"type"`
+	Targets []grafanaTarget `json:"targets"`
+}
+
+// grafanaTarget is a query shown in a panel
+type grafanaTarget struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+}
+
+// GenerateGrafanaDashboard returns a JSON dashboard configuration for CSI metrics. With a
+// comparison driver, driver-specific panels show one series per driver.
 func (d *MetricsDetector) GenerateGrafanaDashboard() string {
-	// This would return a Grafana dashboard JSON for visualizing CSI mount issues
-	// Simplified version for demonstration
-	return fmt.Sprintf(`{
-  "dashboard": {
-    "title": "CSI Mount Detective - %s",
-    "panels": [
-      {
-        "title": "CSI Operation Failures",
-        "type": "graph",
-        "targets": [
-          {
-            "expr": "rate(csi_operations_seconds{driver_name=\"%s\",grpc_status_code!=\"OK\"}[5m])"
-          }
-        ]
-      },
-      {
-        "title": "Storage Operation Duration",
-        "type": "graph", 
-        "targets": [
-          {
-            "expr": "storage_operation_duration_seconds{volume_plugin=~\".*%s.*\"}"
-          }
-        ]
-      },
-      {
-        "title": "Volume Attachment Conflicts",
-        "type": "stat",
-        "targets": [
-          {
-            "expr": "count(kube_volumeattachment_info{status_attached=\"true\"}) by (volumeattachment) > 1"
-          }
-        ]
-      },
-      {
-        "title": "Failed Mount Events",
-        "type": "stat",
-        "targets": [
-          {
-            "expr": "kube_event_total{reason=\"FailedMount\",type=\"Warning\"}"
-          }
-        ]
-      }
-    ]
-  }
-}`, d.targetDriver, d.targetDriver, d.targetDriver)
-}
-
-// Note: In a full implementation, this would include:
-// 1. Actual Prometheus client integration (github.com/prometheus/client_golang)
-// 2. Query execution and result parsing
-// 3. Threshold-based analysis to determine issue severity
-// 4. Time-series analysis to detect patterns
-// 5. Correlation between different metrics
-// 6. Integration with Alertmanager for notifications
\ No newline at end of file
+	dashboard := map[string]interface{}{
+		"dashboard": d.grafanaDashboard(),
+	}
+
+	// Marshalling plain strings and slices cannot fail
+	data, _ := json.MarshalIndent(dashboard, "", "  ")
+	return string(data)
+}
+
+// DashboardConfigMap returns a ConfigMap carrying the Grafana dashboard JSON, labelled so
+// the Grafana sidecar picks it up. The sidecar expects the bare dashboard model rather than
+// the API envelope printed by GenerateGrafanaDashboard.
+func (d *MetricsDetector) DashboardConfigMap(namespace, name string) *corev1.ConfigMap {
+	// Marshalling plain strings and slices cannot fail
+	data, _ := json.MarshalIndent(d.grafanaDashboard(), "", "  ")
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				GrafanaDashboardLabel: "1",
+			},
+		},
+		Data: map[string]string{
+			name + ".json": string(data),
+		},
+	}
+}
+
+// ApplyConfigMap creates the ConfigMap, or replaces the data and labels of an existing one
+// with the same name
+func ApplyConfigMap(ctx context.Context, kubeClient client.KubernetesClient, configMap *corev1.ConfigMap) error {
+	configMaps := kubeClient.CoreV1().ConfigMaps(configMap.Namespace)
+
+	_, err := configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create ConfigMap %s/%s: %w", configMap.Namespace, configMap.Name, err)
+	}
+
+	existing, err := configMaps.Get(ctx, configMap.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap %s/%s: %w", configMap.Namespace, configMap.Name, err)
+	}
+	if existing.Labels == nil {
+		existing.Labels = map[string]string{}
+	}
+	for key, value := range configMap.Labels {
+		existing.Labels[key] = value
+	}
+	existing.Data = configMap.Data
+	if _, err := configMaps.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update ConfigMap %s/%s: %w", configMap.Namespace, configMap.Name, err)
+	}
+	return nil
+}
+
+// grafanaDashboard returns the dashboard model
+func (d *MetricsDetector) grafanaDashboard() map[string]interface{} {
+	title := "CSI Mount Detective - " + d.targetDriver
+	if d.compareDriver != "" {
+		title = fmt.Sprintf("CSI Mount Detective - %s vs %s", d.targetDriver, d.compareDriver)
+	}
+
+	panels := []grafanaPanel{
+		{Title: "CSI Operation Failures", Type: "graph", Targets: d.driverTargets(`rate(csi_operations_seconds{%[2]s="%[1]s",grpc_status_code!="OK"}[5m])`)},
+		{Title: "Storage Operation Duration", Type: "graph", Targets: d.driverTargets(`storage_operation_duration_seconds{volume_plugin=~".*%[1]s.*"}`)},
+		{Title: "Volume Attachment Conflicts", Type: "stat", Targets: []grafanaTarget{{Expr: `count(kube_volumeattachment_info{status_attached="true"}) by (volumeattachment) > 1`}}},
+		{Title: "Failed Mount Events", Type: "stat", Targets: []grafanaTarget{{Expr: `kube_event_total{reason="FailedMount",type="Warning"}`}}},
+	}
+
+	return map[string]interface{}{
+		"title":  title,
+		"panels": panels,
+	}
+}
+
+// driverTargets returns one panel target per driver for an expression containing a %[1]s
+// placeholder for the driver name and optionally %[2]s for the driver label, labelled by
+// driver in A/B mode
+func (d *MetricsDetector) driverTargets(expr string) []grafanaTarget {
+	var targets []grafanaTarget
+	for _, driver := range d.drivers() {
+		target := grafanaTarget{Expr: fmt.Sprintf(expr, driver, d.driverLabel)}
+		if d.compareDriver != "" {
+			target.LegendFormat = driver
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
@@ -2,11 +2,27 @@ package detect_test
 
 import (
 	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/jdambly/kubectl-csi-scan/pkg/client/mocks"
 	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
 )
 
 var _ = Describe("MetricsDetector", func() {
@@ -40,17 +56,182 @@ var _ = Describe("MetricsDetector", func() {
 		})
 	})
 
+	Context("HTTPClient", func() {
+		transportOf := func(d *detect.MetricsDetector) *http.Transport {
+			transport, ok := d.HTTPClient().Transport.(*http.Transport)
+			Expect(ok).To(BeTrue())
+			return transport
+		}
+
+		It("should use the environment proxy and the system cert pool by default", func() {
+			transport := transportOf(detect.NewMetricsDetector(prometheusURL, targetDriver))
+			Expect(reflect.ValueOf(transport.Proxy).Pointer()).To(Equal(reflect.ValueOf(http.ProxyFromEnvironment).Pointer()))
+			Expect(transport.TLSClientConfig).To(BeNil())
+		})
+
+		It("should keep the environment proxy when TLS is overridden", func() {
+			detector = detect.NewMetricsDetector(prometheusURL, targetDriver)
+			Expect(detector.SetTLS("", true)).To(Succeed())
+
+			transport := transportOf(detector)
+			Expect(reflect.ValueOf(transport.Proxy).Pointer()).To(Equal(reflect.ValueOf(http.ProxyFromEnvironment).Pointer()))
+			Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+		})
+
+		It("should reject a CA file without certificates", func() {
+			caFile := filepath.Join(GinkgoT().TempDir(), "ca.pem")
+			Expect(os.WriteFile(caFile, []byte("not a certificate"), 0o600)).To(Succeed())
+
+			detector = detect.NewMetricsDetector(prometheusURL, targetDriver)
+			Expect(detector.SetTLS(caFile, false)).To(MatchError(ContainSubstring("no PEM certificates")))
+		})
+	})
+
+	Context("Detector.SetPrometheusTLS", func() {
+		var caFile string
+
+		BeforeEach(func() {
+			caFile = filepath.Join(GinkgoT().TempDir(), "ca.pem")
+			Expect(os.WriteFile(caFile, []byte("not a certificate"), 0o600)).To(Succeed())
+		})
+
+		It("should configure the metrics method", func() {
+			d := detect.NewDetector(nil, types.DetectionOptions{Methods: []types.DetectionMethod{types.MetricsMethod}})
+			Expect(d.SetPrometheusTLS(caFile, false)).To(MatchError(ContainSubstring("no PEM certificates")))
+			Expect(d.SetPrometheusTLS("", true)).To(Succeed())
+		})
+
+		It("should do nothing when the metrics method is not enabled", func() {
+			d := detect.NewDetector(nil, types.DetectionOptions{Methods: []types.DetectionMethod{types.StorageClassMethod}})
+			Expect(d.SetPrometheusTLS(caFile, false)).To(Succeed())
+		})
+	})
+
 	Context("Detect", func() {
 		BeforeEach(func() {
 			detector = detect.NewMetricsDetector(prometheusURL, targetDriver)
 		})
 
-		It("should return framework implementation notice", func() {
+		It("should return no issues without a Prometheus URL", func() {
+			detector = detect.NewMetricsDetector("", targetDriver)
 			issues, err := detector.Detect(ctx)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(issues).To(BeEmpty())
-			// Note: The current implementation is a framework placeholder
-			// that returns empty results but no error
+		})
+
+		Context("against a Prometheus server", func() {
+			var (
+				server  *httptest.Server
+				queries []string
+			)
+
+			BeforeEach(func() {
+				queries = nil
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.URL.Path).To(Equal("/api/v1/query"))
+					Expect(r.ParseForm()).To(Succeed())
+					query := r.Form.Get("query")
+					queries = append(queries, query)
+
+					result := "[]"
+					switch {
+					case strings.Contains(query, `grpc_status_code!="OK",method_name=~".*Attach.*"`):
+						result = `[
+							{"metric": {"driver_name": "test.csi.driver", "node": "node-1", "method_name": "ControllerPublishVolume"}, "value": [1700000000, "3"]},
+							{"metric": {"driver_name": "test.csi.driver", "node": "node-2", "method_name": "ControllerPublishVolume"}, "value": [1700000000, "0"]}
+						]`
+					case strings.Contains(query, "kube_volumeattachment_info"):
+						result = `[{"metric": {"volumeattachment": "csi-abc"}, "value": [1700000000, "2"]}]`
+					}
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprintf(w, `{"status": "success", "data": {"resultType": "vector", "result": %s}}`, result)
+				}))
+				detector = detect.NewMetricsDetector(server.URL, targetDriver)
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("should run every metric query", func() {
+				_, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+
+				var expected []string
+				for _, query := range detector.GetMetricQueries() {
+					expected = append(expected, query.Query)
+				}
+				Expect(queries).To(Equal(expected))
+			})
+
+			It("should report series above the threshold as CSI operation failures", func() {
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(2))
+
+				Expect(issues[0].Type).To(Equal(types.CSIOperationFailure))
+				Expect(issues[0].DetectedBy).To(Equal(types.MetricsMethod))
+				Expect(issues[0].Driver).To(Equal(targetDriver))
+				Expect(issues[0].Node).To(Equal("node-1"))
+				Expect(issues[0].Severity).To(Equal(types.SeverityHigh))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("value", "3"))
+				Expect(issues[0].Description).To(ContainSubstring("CSI Attach Failures"))
+
+				// Queries that are not per driver fall back to the target driver
+				Expect(issues[1].Driver).To(Equal(targetDriver))
+				Expect(issues[1].Severity).To(Equal(types.SeverityCritical))
+				Expect(issues[1].Node).To(BeEmpty())
+			})
+
+			It("should only report series above a configured threshold", func() {
+				detector.SetThreshold(2)
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Node).To(Equal("node-1"))
+			})
+
+			It("should fail when Prometheus returns an error", func() {
+				server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, `{"status": "error", "errorType": "bad_data", "error": "parse error"}`)
+				})
+				_, err := detector.Detect(ctx)
+				Expect(err).To(MatchError(ContainSubstring(`prometheus query "CSI Attach Failures" failed`)))
+			})
+		})
+
+		Context("against a Prometheus server with a certificate from its own CA", func() {
+			var server *httptest.Server
+
+			BeforeEach(func() {
+				server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprint(w, `{"status": "success", "data": {"resultType": "vector", "result": []}}`)
+				}))
+				detector = detect.NewMetricsDetector(server.URL, targetDriver)
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("should fail to verify the server certificate against the system cert pool", func() {
+				_, err := detector.Detect(ctx)
+				Expect(err).To(MatchError(ContainSubstring("certificate")))
+			})
+
+			It("should query it with the configured client", func() {
+				caFile := filepath.Join(GinkgoT().TempDir(), "ca.pem")
+				certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+				Expect(os.WriteFile(caFile, certPEM, 0o600)).To(Succeed())
+				Expect(detector.SetTLS(caFile, false)).To(Succeed())
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(BeEmpty())
+			})
 		})
 
 		Context("when Prometheus is configured", func() {
@@ -247,6 +428,82 @@ var _ = Describe("MetricsDetector", func() {
 			})
 		})
 
+		Context("with a comparison driver", func() {
+			BeforeEach(func() {
+				detector = detect.NewMetricsDetector(prometheusURL, "cinder.csi.openstack.org")
+				detector.SetCompareDriver("ebs.csi.aws.com")
+			})
+
+			It("should generate driver-specific queries for both drivers", func() {
+				queries := detector.GetMetricQueries()
+
+				var names []string
+				for _, query := range queries {
+					names = append(names, query.Name)
+				}
+				Expect(names).To(ContainElements(
+					"CSI Attach Failures (cinder.csi.openstack.org)",
+					"CSI Attach Failures (ebs.csi.aws.com)",
+				))
+				Expect(names).To(ContainElement("Volume Attachment Conflicts"))
+			})
+
+			It("should show one series per driver in dashboard panels", func() {
+				var dashboard struct {
+					Dashboard struct {
+						Title  string `json:"title"`
+						Panels []struct {
+							Title   string `json:"title"`
+							Targets []struct {
+								Expr         string `json:"expr"`
+								LegendFormat string `json:"legendFormat"`
+							} `json:"targets"`
+						} `json:"panels"`
+					} `json:"dashboard"`
+				}
+				Expect(json.Unmarshal([]byte(detector.GenerateGrafanaDashboard()), &dashboard)).To(Succeed())
+				Expect(dashboard.Dashboard.Title).To(ContainSubstring("cinder.csi.openstack.org vs ebs.csi.aws.com"))
+
+				failures := dashboard.Dashboard.Panels[0]
+				Expect(failures.Title).To(Equal("CSI Operation Failures"))
+				Expect(failures.Targets).To(HaveLen(2))
+				Expect(failures.Targets[0].Expr).To(ContainSubstring(`driver_name="cinder.csi.openstack.org"`))
+				Expect(failures.Targets[0].LegendFormat).To(Equal("cinder.csi.openstack.org"))
+				Expect(failures.Targets[1].Expr).To(ContainSubstring(`driver_name="ebs.csi.aws.com"`))
+				Expect(failures.Targets[1].LegendFormat).To(Equal("ebs.csi.aws.com"))
+			})
+		})
+
+		Context("with a custom driver label", func() {
+			BeforeEach(func() {
+				detector = detect.NewMetricsDetector(prometheusURL, "ebs.csi.aws.com")
+				detector.SetDriverLabel("csi_driver")
+			})
+
+			It("should use the label in driver-specific queries", func() {
+				var attachFailures string
+				for _, query := range detector.GetMetricQueries() {
+					Expect(query.Query).NotTo(ContainSubstring("driver_name"))
+					Expect(query.Query).NotTo(ContainSubstring("%!"))
+					if query.Name == "CSI Attach Failures" {
+						attachFailures = query.Query
+					}
+				}
+				Expect(attachFailures).To(HavePrefix(`csi_operations_seconds{csi_driver="ebs.csi.aws.com",`))
+			})
+
+			It("should use the label in alerts and the dashboard", func() {
+				Expect(detector.GetRecommendedAlerts()[0]).To(ContainSubstring(`csi_driver="ebs.csi.aws.com"`))
+				Expect(detector.GetRecommendedAlerts()).To(ContainElement(ContainSubstring("$labels.csi_driver")))
+				Expect(detector.GenerateGrafanaDashboard()).To(ContainSubstring(`csi_driver=\"ebs.csi.aws.com\"`))
+			})
+
+			It("should fall back to the default label when set to empty", func() {
+				detector.SetDriverLabel("")
+				Expect(detector.GetMetricQueries()[0].Query).To(ContainSubstring(`driver_name="ebs.csi.aws.com"`))
+			})
+		})
+
 		Context("without target driver filter", func() {
 			BeforeEach(func() {
 				detector = detect.NewMetricsDetector(prometheusURL, "")
@@ -299,18 +556,16 @@ var _ = Describe("MetricsDetector", func() {
 			cancel()
 
 			// Should not hang or panic with cancelled context
-			issues, err := detector.Detect(cancelCtx)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(issues).NotTo(BeNil())
+			_, err := detector.Detect(cancelCtx)
+			Expect(err).To(MatchError(context.Canceled))
 		})
 
 		It("should handle invalid Prometheus URL gracefully", func() {
-			detector = detect.NewMetricsDetector("invalid-url", targetDriver)
+			detector = detect.NewMetricsDetector("://invalid-url", targetDriver)
 			
 			// Should not panic with invalid URL
-			issues, err := detector.Detect(ctx)
-			Expect(err).NotTo(HaveOccurred())
-			Expect(issues).NotTo(BeNil())
+			_, err := detector.Detect(ctx)
+			Expect(err).To(MatchError(ContainSubstring("invalid Prometheus URL")))
 		})
 	})
 
@@ -339,6 +594,79 @@ var _ = Describe("MetricsDetector", func() {
 			Expect(dashboard).To(ContainSubstring("{"))
 		})
 	})
+
+	Context("Dashboard ConfigMap", func() {
+		var (
+			ctrl           *gomock.Controller
+			mockClient     *mocks.MockKubernetesClient
+			mockCoreV1     *mocks.MockCoreV1Interface
+			mockConfigMaps *mocks.MockConfigMapInterface
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			mockClient = mocks.NewMockKubernetesClient(ctrl)
+			mockCoreV1 = mocks.NewMockCoreV1Interface(ctrl)
+			mockConfigMaps = mocks.NewMockConfigMapInterface(ctrl)
+
+			mockClient.EXPECT().CoreV1().Return(mockCoreV1).AnyTimes()
+			mockCoreV1.EXPECT().ConfigMaps("monitoring").Return(mockConfigMaps).AnyTimes()
+
+			detector = detect.NewMetricsDetector("", targetDriver)
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("should create a ConfigMap labelled for the Grafana sidecar with the dashboard JSON", func() {
+			var created *corev1.ConfigMap
+			mockConfigMaps.EXPECT().Create(ctx, gomock.Any(), metav1.CreateOptions{}).
+				DoAndReturn(func(_ context.Context, configMap *corev1.ConfigMap, _ metav1.CreateOptions) (*corev1.ConfigMap, error) {
+					created = configMap
+					return configMap, nil
+				})
+
+			Expect(detect.ApplyConfigMap(ctx, mockClient, detector.DashboardConfigMap("monitoring", "csi-dashboard"))).To(Succeed())
+
+			Expect(created.Namespace).To(Equal("monitoring"))
+			Expect(created.Name).To(Equal("csi-dashboard"))
+			Expect(created.Labels).To(HaveKeyWithValue("grafana_dashboard", "1"))
+			Expect(created.Data).To(HaveKey("csi-dashboard.json"))
+
+			var model map[string]interface{}
+			Expect(json.Unmarshal([]byte(created.Data["csi-dashboard.json"]), &model)).To(Succeed())
+			Expect(model).To(HaveKeyWithValue("title", "CSI Mount Detective - "+targetDriver))
+			Expect(model).To(HaveKey("panels"))
+			Expect(model).NotTo(HaveKey("dashboard"))
+		})
+
+		It("should update an existing ConfigMap, keeping its other labels", func() {
+			existing := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "csi-dashboard", Namespace: "monitoring", Labels: map[string]string{"team": "storage"}},
+				Data:       map[string]string{"old.json": "{}"},
+			}
+			mockConfigMaps.EXPECT().Create(ctx, gomock.Any(), metav1.CreateOptions{}).
+				Return(nil, apierrors.NewAlreadyExists(corev1.Resource("configmaps"), "csi-dashboard"))
+			mockConfigMaps.EXPECT().Get(ctx, "csi-dashboard", metav1.GetOptions{}).Return(existing, nil)
+			mockConfigMaps.EXPECT().Update(ctx, gomock.Any(), metav1.UpdateOptions{}).
+				DoAndReturn(func(_ context.Context, configMap *corev1.ConfigMap, _ metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+					Expect(configMap.Labels).To(Equal(map[string]string{"team": "storage", "grafana_dashboard": "1"}))
+					Expect(configMap.Data).To(HaveKey("csi-dashboard.json"))
+					Expect(configMap.Data).NotTo(HaveKey("old.json"))
+					return configMap, nil
+				})
+
+			Expect(detect.ApplyConfigMap(ctx, mockClient, detector.DashboardConfigMap("monitoring", "csi-dashboard"))).To(Succeed())
+		})
+
+		It("should return an error when the ConfigMap cannot be created", func() {
+			mockConfigMaps.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(nil, errors.New("forbidden"))
+
+			err := detect.ApplyConfigMap(ctx, mockClient, detector.DashboardConfigMap("monitoring", "csi-dashboard"))
+			Expect(err).To(MatchError(ContainSubstring("failed to create ConfigMap monitoring/csi-dashboard")))
+		})
+	})
 })
 
 // Additional test helper functions could be added here for more complex
@@ -0,0 +1,88 @@
+package detect
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// csiPluginDir precedes the driver or pv/<name> part of a kubelet CSI global mount path
+const csiPluginDir = "kubernetes.io/csi/"
+
+// MountProber lists the CSI mounts still mounted on nodes, by node. It is implemented
+// outside this package by jobs that run on the nodes, as the API has no view of mounts.
+type MountProber interface {
+	ProbeMounts(ctx context.Context, nodes []string) (map[string][]string, error)
+}
+
+// SetMountProber sets the prober used when options.ProbeMounts is enabled
+func (d *Detector) SetMountProber(prober MountProber) {
+	d.mountProber = prober
+}
+
+// mountSuspectNodes returns the nodes of the issues that point at leftover mount
+// references, in a stable order
+func mountSuspectNodes(issues []types.CSIMountIssue) []string {
+	var suspects []types.CSIMountIssue
+	for _, issue := range issues {
+		if issue.Type == types.StuckMountReference || issue.Type == types.DeviceBusy {
+			suspects = append(suspects, issue)
+		}
+	}
+	nodes := affectedNodes(suspects)
+	sort.Strings(nodes)
+	return nodes
+}
+
+// MountProbeIssues turns the mount paths a probe reported into one stuck mount reference
+// issue per path, carrying the path in metadata
+func MountProbeIssues(mounts map[string][]string) []types.CSIMountIssue {
+	nodes := make([]string, 0, len(mounts))
+	for node := range mounts {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	var issues []types.CSIMountIssue
+	for _, node := range nodes {
+		for _, path := range mounts[node] {
+			volume, driver := parseMountPath(path)
+			issues = append(issues, types.CSIMountIssue{
+				Type:        types.StuckMountReference,
+				Severity:    types.SeverityMedium,
+				Node:        node,
+				Volume:      volume,
+				Driver:      driver,
+				Description: fmt.Sprintf("CSI global mount %s is still mounted on node %s", path, node),
+				DetectedBy:  types.MountProbeMethod,
+				DetectedAt:  time.Now(),
+				Metadata: map[string]string{
+					"mount_path": path,
+				},
+				Sources: []types.SourceRef{{Kind: "Node", Name: node}},
+			})
+		}
+	}
+	return issues
+}
+
+// parseMountPath returns the PV of a .../kubernetes.io/csi/pv/<pv>/globalmount path, or
+// the driver of a .../kubernetes.io/csi/<driver>/<hash>/globalmount path
+func parseMountPath(path string) (volume, driver string) {
+	_, rest, ok := strings.Cut(path, csiPluginDir)
+	if !ok {
+		return "", ""
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	if parts[0] == "pv" {
+		return parts[1], ""
+	}
+	return "", parts[0]
+}
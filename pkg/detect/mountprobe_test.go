@@ -0,0 +1,127 @@
+package detect_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client/mocks"
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// stubProber records the nodes it was asked to probe and returns fixed mounts
+type stubProber struct {
+	nodes  []string
+	mounts map[string][]string
+}
+
+func (p *stubProber) ProbeMounts(_ context.Context, nodes []string) (map[string][]string, error) {
+	p.nodes = nodes
+	return p.mounts, nil
+}
+
+var _ = Describe("Mount probe", func() {
+	const (
+		pvMount     = "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-7b1e2f44/globalmount"
+		driverMount = "/var/lib/kubelet/plugins/kubernetes.io/csi/cinder.csi.openstack.org/0f3a9c/globalmount"
+	)
+
+	Describe("MountProbeIssues", func() {
+		It("should report each mount path as a stuck mount reference on its node", func() {
+			issues := detect.MountProbeIssues(map[string][]string{
+				"node-b": {driverMount},
+				"node-a": {pvMount},
+				"node-c": nil,
+			})
+
+			Expect(issues).To(HaveLen(2))
+			Expect(issues[0].Node).To(Equal("node-a"))
+			Expect(issues[0].Type).To(Equal(types.StuckMountReference))
+			Expect(issues[0].DetectedBy).To(Equal(types.MountProbeMethod))
+			Expect(issues[0].Volume).To(Equal("pvc-7b1e2f44"))
+			Expect(issues[0].Metadata).To(HaveKeyWithValue("mount_path", pvMount))
+
+			Expect(issues[1].Node).To(Equal("node-b"))
+			Expect(issues[1].Driver).To(Equal("cinder.csi.openstack.org"))
+			Expect(issues[1].Volume).To(BeEmpty())
+			Expect(issues[1].Metadata).To(HaveKeyWithValue("mount_path", driverMount))
+		})
+	})
+
+	Describe("DetectAll with ProbeMounts", func() {
+		var (
+			ctrl       *gomock.Controller
+			mockClient *mocks.MockKubernetesClient
+			prober     *stubProber
+			ctx        context.Context
+		)
+
+		BeforeEach(func() {
+			ctrl = gomock.NewController(GinkgoT())
+			mockClient = mocks.NewMockKubernetesClient(ctrl)
+			mockCoreV1 := mocks.NewMockCoreV1Interface(ctrl)
+			mockEvents := mocks.NewMockEventInterface(ctrl)
+			ctx = context.Background()
+
+			mockClient.EXPECT().CoreV1().Return(mockCoreV1).AnyTimes()
+			mockCoreV1.EXPECT().Events("").Return(mockEvents).AnyTimes()
+			mockEvents.EXPECT().List(gomock.Any(), gomock.Any()).Return(&corev1.EventList{
+				Items: []corev1.Event{{
+					ObjectMeta:     metav1.ObjectMeta{Name: "web-0.17b", Namespace: "shop"},
+					InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "web-0"},
+					Source:         corev1.EventSource{Host: "node-3"},
+					Type:           "Warning",
+					Reason:         "FailedMount",
+					Message:        `UnmountDevice failed for volume "pvc-7b1e2f44" : GetDeviceMountRefs check failed: the device mount path is still mounted by other references`,
+					LastTimestamp:  metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+				}},
+			}, nil).AnyTimes()
+
+			prober = &stubProber{mounts: map[string][]string{"node-3": {pvMount}}}
+		})
+
+		AfterEach(func() {
+			ctrl.Finish()
+		})
+
+		It("should probe the nodes with suspected stuck mount references and add the mounts found", func() {
+			detector := detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods:     []types.DetectionMethod{types.EventsMethod},
+				ProbeMounts: true,
+			})
+			detector.SetMountProber(prober)
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(prober.nodes).To(Equal([]string{"node-3"}))
+			Expect(result.Summary.MethodsUsed).To(ContainElement(types.MountProbeMethod))
+
+			var probed []types.CSIMountIssue
+			for _, issue := range result.Issues {
+				if issue.DetectedBy == types.MountProbeMethod {
+					probed = append(probed, issue)
+				}
+			}
+			Expect(probed).To(HaveLen(1))
+			Expect(probed[0].Metadata).To(HaveKeyWithValue("mount_path", pvMount))
+		})
+
+		It("should not probe unless ProbeMounts is set", func() {
+			detector := detect.NewDetector(mockClient, types.DetectionOptions{
+				Methods: []types.DetectionMethod{types.EventsMethod},
+			})
+			detector.SetMountProber(prober)
+
+			result, err := detector.DetectAll(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(prober.nodes).To(BeNil())
+			Expect(result.Summary.MethodsUsed).NotTo(ContainElement(types.MountProbeMethod))
+		})
+	})
+})
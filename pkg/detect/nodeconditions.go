@@ -0,0 +1,116 @@
+package detect
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// pressureConditions are the node conditions that, when true, commonly make CSI mount and
+// unmount operations on the node fail or hang
+var pressureConditions = []corev1.NodeConditionType{corev1.NodeDiskPressure, corev1.NodePIDPressure}
+
+// NodePressureDetector checks the conditions of nodes that already have mount issues, since
+// a NotReady node or one under disk or PID pressure often explains them
+type NodePressureDetector struct {
+	client client.KubernetesClient
+}
+
+// NewNodePressureDetector creates a new node conditions detector
+func NewNodePressureDetector(kubeClient client.KubernetesClient) *NodePressureDetector {
+	return &NodePressureDetector{
+		client: kubeClient,
+	}
+}
+
+// Detect lists the nodes and reports those among the given nodes that are not Ready or
+// are under disk or PID pressure, with one issue per node
+func (d *NodePressureDetector) Detect(ctx context.Context, nodes []string) ([]types.CSIMountIssue, error) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	affected := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		affected[node] = true
+	}
+
+	nodeList, err := d.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	var issues []types.CSIMountIssue
+	for _, node := range nodeList.Items {
+		if !affected[node.Name] {
+			continue
+		}
+		if issue, ok := d.checkNode(node); ok {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// checkNode builds an issue for a node that is not Ready or is under pressure. A NotReady
+// node is high severity, since nothing on it can be mounted or unmounted until it returns.
+func (d *NodePressureDetector) checkNode(node corev1.Node) (types.CSIMountIssue, bool) {
+	var problems []corev1.NodeCondition
+	for _, condition := range node.Status.Conditions {
+		switch {
+		case condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue:
+			problems = append(problems, condition)
+		case isPressureCondition(condition.Type) && condition.Status == corev1.ConditionTrue:
+			problems = append(problems, condition)
+		}
+	}
+	if len(problems) == 0 {
+		return types.CSIMountIssue{}, false
+	}
+
+	severity := types.SeverityMedium
+	var names, details []string
+	var since time.Time
+	for _, condition := range problems {
+		name := string(condition.Type)
+		if condition.Type == corev1.NodeReady {
+			name = "NotReady"
+			severity = types.SeverityHigh
+		}
+		names = append(names, name)
+		details = append(details, fmt.Sprintf("%s=%s", condition.Type, condition.Status))
+		if since.IsZero() || condition.LastTransitionTime.Time.Before(since) {
+			since = condition.LastTransitionTime.Time
+		}
+	}
+
+	return types.CSIMountIssue{
+		Type:        types.UnhealthyNode,
+		Severity:    severity,
+		Node:        node.Name,
+		Description: fmt.Sprintf("Node %s is %s: CSI mount and unmount operations on this node may fail or hang until it recovers", node.Name, strings.Join(names, ", ")),
+		DetectedBy:  types.NodeConditionsMethod,
+		DetectedAt:  time.Now(),
+		OccurredAt:  since,
+		Metadata: map[string]string{
+			"conditions": strings.Join(details, ","),
+		},
+		Sources: []types.SourceRef{{Kind: "Node", Name: node.Name, UID: string(node.UID)}},
+	}, true
+}
+
+// isPressureCondition reports whether a condition type is one of the pressureConditions
+func isPressureCondition(conditionType corev1.NodeConditionType) bool {
+	for _, pressure := range pressureConditions {
+		if conditionType == pressure {
+			return true
+		}
+	}
+	return false
+}
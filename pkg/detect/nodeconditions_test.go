@@ -0,0 +1,164 @@
+package detect_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client/mocks"
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("NodePressureDetector", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockClient *mocks.MockKubernetesClient
+		mockCoreV1 *mocks.MockCoreV1Interface
+		mockNodes  *mocks.MockNodeInterface
+		detector   *detect.NodePressureDetector
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = mocks.NewMockKubernetesClient(ctrl)
+		mockCoreV1 = mocks.NewMockCoreV1Interface(ctrl)
+		mockNodes = mocks.NewMockNodeInterface(ctrl)
+		ctx = context.Background()
+
+		mockClient.EXPECT().CoreV1().Return(mockCoreV1).AnyTimes()
+		mockCoreV1.EXPECT().Nodes().Return(mockNodes).AnyTimes()
+
+		detector = detect.NewNodePressureDetector(mockClient)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	node := func(name string, ready corev1.ConditionStatus, pressure ...corev1.NodeConditionType) corev1.Node {
+		conditions := []corev1.NodeCondition{{
+			Type:               corev1.NodeReady,
+			Status:             ready,
+			LastTransitionTime: metav1.NewTime(since),
+		}}
+		for _, conditionType := range []corev1.NodeConditionType{corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure} {
+			status := corev1.ConditionFalse
+			for _, p := range pressure {
+				if p == conditionType {
+					status = corev1.ConditionTrue
+				}
+			}
+			conditions = append(conditions, corev1.NodeCondition{
+				Type:               conditionType,
+				Status:             status,
+				LastTransitionTime: metav1.NewTime(since.Add(time.Hour)),
+			})
+		}
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: "uid-1"},
+			Status:     corev1.NodeStatus{Conditions: conditions},
+		}
+	}
+
+	It("should report a NotReady node as high severity", func() {
+		mockNodes.EXPECT().List(ctx, metav1.ListOptions{}).Return(&corev1.NodeList{
+			Items: []corev1.Node{node("node-1", corev1.ConditionFalse), node("node-2", corev1.ConditionTrue)},
+		}, nil)
+
+		issues, err := detector.Detect(ctx, []string{"node-1", "node-2"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Type).To(Equal(types.UnhealthyNode))
+		Expect(issues[0].Severity).To(Equal(types.SeverityHigh))
+		Expect(issues[0].Node).To(Equal("node-1"))
+		Expect(issues[0].DetectedBy).To(Equal(types.NodeConditionsMethod))
+		Expect(issues[0].Description).To(ContainSubstring("Node node-1 is NotReady"))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("conditions", "Ready=False"))
+		Expect(issues[0].OccurredAt).To(Equal(since))
+		Expect(issues[0].Sources).To(ConsistOf(types.SourceRef{Kind: "Node", Name: "node-1", UID: "uid-1"}))
+	})
+
+	It("should treat an unknown Ready condition as NotReady", func() {
+		mockNodes.EXPECT().List(ctx, metav1.ListOptions{}).Return(&corev1.NodeList{
+			Items: []corev1.Node{node("node-1", corev1.ConditionUnknown)},
+		}, nil)
+
+		issues, err := detector.Detect(ctx, []string{"node-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("conditions", "Ready=Unknown"))
+	})
+
+	It("should report disk and PID pressure as medium severity", func() {
+		mockNodes.EXPECT().List(ctx, metav1.ListOptions{}).Return(&corev1.NodeList{
+			Items: []corev1.Node{
+				node("node-1", corev1.ConditionTrue, corev1.NodeDiskPressure),
+				node("node-2", corev1.ConditionTrue, corev1.NodeDiskPressure, corev1.NodePIDPressure),
+			},
+		}, nil)
+
+		issues, err := detector.Detect(ctx, []string{"node-1", "node-2"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(2))
+		Expect(issues[0].Severity).To(Equal(types.SeverityMedium))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("conditions", "DiskPressure=True"))
+		Expect(issues[1].Severity).To(Equal(types.SeverityMedium))
+		Expect(issues[1].Description).To(ContainSubstring("Node node-2 is DiskPressure, PIDPressure"))
+		Expect(issues[1].OccurredAt).To(Equal(since.Add(time.Hour)))
+	})
+
+	It("should report a NotReady node under pressure once", func() {
+		mockNodes.EXPECT().List(ctx, metav1.ListOptions{}).Return(&corev1.NodeList{
+			Items: []corev1.Node{node("node-1", corev1.ConditionFalse, corev1.NodeDiskPressure)},
+		}, nil)
+
+		issues, err := detector.Detect(ctx, []string{"node-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Severity).To(Equal(types.SeverityHigh))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("conditions", "Ready=False,DiskPressure=True"))
+		Expect(issues[0].OccurredAt).To(Equal(since))
+	})
+
+	It("should ignore memory pressure and healthy nodes", func() {
+		mockNodes.EXPECT().List(ctx, metav1.ListOptions{}).Return(&corev1.NodeList{
+			Items: []corev1.Node{node("node-1", corev1.ConditionTrue, corev1.NodeMemoryPressure), node("node-2", corev1.ConditionTrue)},
+		}, nil)
+
+		issues, err := detector.Detect(ctx, []string{"node-1", "node-2"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("should ignore unhealthy nodes without issues", func() {
+		mockNodes.EXPECT().List(ctx, metav1.ListOptions{}).Return(&corev1.NodeList{
+			Items: []corev1.Node{node("node-3", corev1.ConditionFalse, corev1.NodeDiskPressure)},
+		}, nil)
+
+		issues, err := detector.Detect(ctx, []string{"node-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("should not list nodes when no node is affected", func() {
+		issues, err := detector.Detect(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("should return an error when listing nodes fails", func() {
+		mockNodes.EXPECT().List(ctx, gomock.Any()).Return(nil, errors.New("API error"))
+
+		_, err := detector.Detect(ctx, []string{"node-1"})
+		Expect(err).To(MatchError(ContainSubstring("failed to list nodes")))
+	})
+})
@@ -0,0 +1,138 @@
+package detect
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// DefaultNodePluginSelectors are the label selectors of the node plugin DaemonSet pods
+// deployed by the upstream manifests and Helm charts of common CSI drivers
+var DefaultNodePluginSelectors = map[string]string{
+	"cinder.csi.openstack.org": "app=csi-cinder-nodeplugin",
+	"ebs.csi.aws.com":          "app=ebs-csi-node",
+	"disk.csi.azure.com":       "app=csi-azuredisk-node",
+	"pd.csi.storage.gke.io":    "app=gcp-compute-persistent-disk-csi-driver",
+	"rbd.csi.ceph.com":         "app=csi-rbdplugin",
+	"cephfs.csi.ceph.com":      "app=csi-cephfsplugin",
+}
+
+// NodePluginDetector checks the health of CSI node plugin pods on nodes that already have
+// mount issues, since those problems frequently trace back to an unhealthy plugin
+type NodePluginDetector struct {
+	client       client.KubernetesClient
+	targetDriver string
+	selectors    map[string]string
+}
+
+// NewNodePluginDetector creates a new node plugin detector using the default selectors
+func NewNodePluginDetector(kubeClient client.KubernetesClient, targetDriver string) *NodePluginDetector {
+	selectors := make(map[string]string, len(DefaultNodePluginSelectors))
+	for driver, selector := range DefaultNodePluginSelectors {
+		selectors[driver] = selector
+	}
+	return &NodePluginDetector{
+		client:       kubeClient,
+		targetDriver: targetDriver,
+		selectors:    selectors,
+	}
+}
+
+// SetSelectors adds or replaces the node plugin label selectors of individual drivers
+func (d *NodePluginDetector) SetSelectors(overrides map[string]string) {
+	for driver, selector := range overrides {
+		d.selectors[driver] = selector
+	}
+}
+
+// Detect lists the node plugin pods of each known driver, or only of the target driver if
+// one is set, and reports those on the given nodes that are not Running and Ready
+func (d *NodePluginDetector) Detect(ctx context.Context, nodes []string) ([]types.CSIMountIssue, error) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	affected := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		affected[node] = true
+	}
+
+	var issues []types.CSIMountIssue
+	for _, driver := range d.drivers() {
+		selector := d.selectors[driver]
+		pods, err := d.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list node plugin pods for %s: %w", driver, err)
+		}
+
+		for _, pod := range pods.Items {
+			if !affected[pod.Spec.NodeName] || podHealthy(pod) {
+				continue
+			}
+			issues = append(issues, d.newIssue(driver, pod))
+		}
+	}
+
+	return issues, nil
+}
+
+// drivers returns the drivers to probe in a stable order
+func (d *NodePluginDetector) drivers() []string {
+	if d.targetDriver != "" {
+		if _, ok := d.selectors[d.targetDriver]; ok {
+			return []string{d.targetDriver}
+		}
+		return nil
+	}
+
+	drivers := make([]string, 0, len(d.selectors))
+	for driver := range d.selectors {
+		drivers = append(drivers, driver)
+	}
+	sort.Strings(drivers)
+	return drivers
+}
+
+// newIssue builds an informational issue for an unhealthy node plugin pod
+func (d *NodePluginDetector) newIssue(driver string, pod corev1.Pod) types.CSIMountIssue {
+	phase, ready := podStatus(pod)
+	return types.CSIMountIssue{
+		Type:        types.UnhealthyNodePlugin,
+		Severity:    types.SeverityLow,
+		Node:        pod.Spec.NodeName,
+		Driver:      driver,
+		Description: fmt.Sprintf("CSI node plugin pod %s/%s for %s on node %s is %s and not ready: mounts on this node may fail until it recovers", pod.Namespace, pod.Name, driver, pod.Spec.NodeName, phase),
+		DetectedBy:  types.ProbeMethod,
+		DetectedAt:  time.Now(),
+		OccurredAt:  pod.CreationTimestamp.Time,
+		Metadata: map[string]string{
+			"pod":   pod.Name,
+			"phase": string(phase),
+			"ready": ready,
+		},
+		Sources: []types.SourceRef{{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, UID: string(pod.UID)}},
+	}
+}
+
+// podHealthy reports whether a pod is Running with its Ready condition true
+func podHealthy(pod corev1.Pod) bool {
+	phase, ready := podStatus(pod)
+	return phase == corev1.PodRunning && ready == string(corev1.ConditionTrue)
+}
+
+// podStatus returns a pod's phase and the status of its Ready condition
+func podStatus(pod corev1.Pod) (corev1.PodPhase, string) {
+	ready := string(corev1.ConditionUnknown)
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			ready = string(condition.Status)
+		}
+	}
+	return pod.Status.Phase, ready
+}
@@ -0,0 +1,111 @@
+package detect_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client/mocks"
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("NodePluginDetector", func() {
+	const (
+		driver   = "cinder.csi.openstack.org"
+		selector = "app=csi-cinder-nodeplugin"
+	)
+
+	var (
+		ctrl       *gomock.Controller
+		mockClient *mocks.MockKubernetesClient
+		mockCoreV1 *mocks.MockCoreV1Interface
+		mockPods   *mocks.MockPodInterface
+		detector   *detect.NodePluginDetector
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = mocks.NewMockKubernetesClient(ctrl)
+		mockCoreV1 = mocks.NewMockCoreV1Interface(ctrl)
+		mockPods = mocks.NewMockPodInterface(ctrl)
+		ctx = context.Background()
+
+		mockClient.EXPECT().CoreV1().Return(mockCoreV1).AnyTimes()
+		mockCoreV1.EXPECT().Pods("").Return(mockPods).AnyTimes()
+
+		detector = detect.NewNodePluginDetector(mockClient, driver)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	pluginPod := func(name, node string, phase corev1.PodPhase, ready corev1.ConditionStatus) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system"},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{
+				Phase:      phase,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+
+	It("should report a not-ready node plugin pod on an affected node", func() {
+		mockPods.EXPECT().List(ctx, metav1.ListOptions{LabelSelector: selector}).Return(&corev1.PodList{
+			Items: []corev1.Pod{
+				pluginPod("csi-cinder-nodeplugin-a", "node-1", corev1.PodRunning, corev1.ConditionFalse),
+				pluginPod("csi-cinder-nodeplugin-b", "node-2", corev1.PodRunning, corev1.ConditionTrue),
+			},
+		}, nil)
+
+		issues, err := detector.Detect(ctx, []string{"node-1", "node-2"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Type).To(Equal(types.UnhealthyNodePlugin))
+		Expect(issues[0].Severity).To(Equal(types.SeverityLow))
+		Expect(issues[0].Node).To(Equal("node-1"))
+		Expect(issues[0].Driver).To(Equal(driver))
+		Expect(issues[0].DetectedBy).To(Equal(types.ProbeMethod))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("ready", "False"))
+		Expect(issues[0].Sources).To(ConsistOf(types.SourceRef{Kind: "Pod", Namespace: "kube-system", Name: "csi-cinder-nodeplugin-a"}))
+	})
+
+	It("should ignore unhealthy pods on nodes without issues", func() {
+		mockPods.EXPECT().List(ctx, metav1.ListOptions{LabelSelector: selector}).Return(&corev1.PodList{
+			Items: []corev1.Pod{pluginPod("csi-cinder-nodeplugin-c", "node-3", corev1.PodPending, corev1.ConditionFalse)},
+		}, nil)
+
+		issues, err := detector.Detect(ctx, []string{"node-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("should not list pods when no node is affected", func() {
+		issues, err := detector.Detect(ctx, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("should use an overridden selector for the driver", func() {
+		detector.SetSelectors(map[string]string{driver: "app.kubernetes.io/name=cinder-csi"})
+		mockPods.EXPECT().List(ctx, metav1.ListOptions{LabelSelector: "app.kubernetes.io/name=cinder-csi"}).Return(&corev1.PodList{}, nil)
+
+		_, err := detector.Detect(ctx, []string{"node-1"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return an error when listing pods fails", func() {
+		mockPods.EXPECT().List(ctx, gomock.Any()).Return(nil, errors.New("API error"))
+
+		_, err := detector.Detect(ctx, []string{"node-1"})
+		Expect(err).To(MatchError(ContainSubstring("failed to list node plugin pods for cinder.csi.openstack.org")))
+	})
+})
@@ -0,0 +1,72 @@
+package detect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+)
+
+// listPageSize is how many pods or events are requested at a time, so that scans of very
+// large clusters hold one page in memory instead of every object
+const listPageSize = 500
+
+// errStopListing is returned by a page callback to stop listing early without an error
+var errStopListing = errors.New("stop listing")
+
+// listPods lists pods a page at a time, calling fn with each page until the last one or
+// until fn returns an error. errStopListing ends listing without an error.
+func listPods(ctx context.Context, pods client.PodInterface, fn func(page *corev1.PodList) error) error {
+	opts := metav1.ListOptions{Limit: listPageSize}
+	for {
+		page, err := pods.List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+		if err := fn(page); err != nil {
+			if errors.Is(err, errStopListing) {
+				return nil
+			}
+			return err
+		}
+		if page.Continue == "" {
+			return nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// listEvents lists events a page at a time, calling fn with each page until the last one
+// or until fn returns an error. errStopListing ends listing without an error.
+func listEvents(ctx context.Context, events client.EventInterface, fn func(page *corev1.EventList) error) error {
+	opts := metav1.ListOptions{Limit: listPageSize}
+	for {
+		page, err := events.List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list events: %w", err)
+		}
+		if err := fn(page); err != nil {
+			if errors.Is(err, errStopListing) {
+				return nil
+			}
+			return err
+		}
+		if page.Continue == "" {
+			return nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// remainingItems returns the number of objects the API server reports are left after a
+// page, or 0 when it does not say
+func remainingItems(meta metav1.ListMeta) int {
+	if meta.RemainingItemCount == nil {
+		return 0
+	}
+	return int(*meta.RemainingItemCount)
+}
@@ -0,0 +1,52 @@
+package detect
+
+import (
+	"time"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// ServerClock converts local times to the API server's clock. client.ServerClock
+// implements it from the Date headers of the server's responses.
+type ServerClock interface {
+	ServerTime(local time.Time) time.Time
+}
+
+// SetServerClock sets the clock the snapshot time is reported on, so it does not depend on
+// the local clock being in sync with the cluster's. Without one the local clock is used.
+func (d *Detector) SetServerClock(clock ServerClock) {
+	d.serverClock = clock
+}
+
+// serverTime converts a local time to the server's clock, if one is set
+func (d *Detector) serverTime(local time.Time) time.Time {
+	if d.serverClock == nil {
+		return local
+	}
+	return d.serverClock.ServerTime(local)
+}
+
+// listVersions returns the resourceVersion of the list read by each completed method, by
+// the resource listed. Lists at the same resourceVersion saw the same state of the cluster.
+func (d *Detector) listVersions(methodsUsed []types.DetectionMethod) map[string]string {
+	versions := make(map[string]string)
+	record := func(resource, version string) {
+		if version != "" {
+			versions[resource] = version
+		}
+	}
+	for _, method := range methodsUsed {
+		switch method {
+		case types.VolumeAttachmentMethod:
+			record("volumeattachments", d.volumeAttachmentDetector.resourceVersion)
+		case types.CrossNodePVCMethod:
+			record("pods", d.crossNodePVCDetector.resourceVersion)
+		case types.EventsMethod:
+			record("events", d.eventsDetector.resourceVersion)
+		}
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	return versions
+}
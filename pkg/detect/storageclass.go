@@ -0,0 +1,156 @@
+package detect
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// topologyConstrainedDrivers provision zonal volumes that can only attach to nodes in the
+// same zone, so binding them before a pod is scheduled can pick the wrong zone
+var topologyConstrainedDrivers = map[string]bool{
+	"cinder.csi.openstack.org": true,
+	"ebs.csi.aws.com":          true,
+	"disk.csi.azure.com":       true,
+	"pd.csi.storage.gke.io":    true,
+}
+
+// StorageClassDetector implements detection of StorageClass settings that commonly cause mount problems
+type StorageClassDetector struct {
+	client       client.KubernetesClient
+	targetDriver string
+}
+
+// NewStorageClassDetector creates a new StorageClass detector
+func NewStorageClassDetector(kubeClient client.KubernetesClient, targetDriver string) *StorageClassDetector {
+	return &StorageClassDetector{
+		client:       kubeClient,
+		targetDriver: targetDriver,
+	}
+}
+
+// Detect reports informational issues for StorageClass binding, expansion and reclaim settings
+func (d *StorageClassDetector) Detect(ctx context.Context) ([]types.CSIMountIssue, error) {
+	var issues []types.CSIMountIssue
+
+	classes, err := d.client.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list StorageClasses: %w", err)
+	}
+
+	var targets []storagev1.StorageClass
+	needsClaims := false
+	for _, sc := range classes.Items {
+		// Filter by driver if specified
+		if d.targetDriver != "" && sc.Provisioner != d.targetDriver {
+			continue
+		}
+		targets = append(targets, sc)
+		if !allowsExpansion(sc) {
+			needsClaims = true
+		}
+	}
+
+	// Disabled expansion only matters once someone tries to resize, so claims are only
+	// listed when a class could be affected
+	resizing := map[string][]string{}
+	if needsClaims {
+		pvcs, err := d.client.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list PersistentVolumeClaims: %w", err)
+		}
+		for _, pvc := range pvcs.Items {
+			if pvc.Spec.StorageClassName != nil && resizeIncomplete(pvc) {
+				resizing[*pvc.Spec.StorageClassName] = append(resizing[*pvc.Spec.StorageClassName], pvc.Namespace+"/"+pvc.Name)
+			}
+		}
+	}
+
+	for _, sc := range targets {
+		issues = append(issues, d.checkStorageClass(sc, resizing[sc.Name])...)
+	}
+
+	return issues, nil
+}
+
+// allowsExpansion reports whether a StorageClass lets its PVCs be resized
+func allowsExpansion(sc storagev1.StorageClass) bool {
+	return sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion
+}
+
+// resizeIncomplete reports whether a PVC has a resize that is still pending or has failed
+func resizeIncomplete(pvc corev1.PersistentVolumeClaim) bool {
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Status == corev1.ConditionTrue &&
+			(condition.Type == corev1.PersistentVolumeClaimResizing || condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending) {
+			return true
+		}
+	}
+	// The resize status is cleared once a resize completes
+	if _, ok := pvc.Status.AllocatedResourceStatuses[corev1.ResourceStorage]; ok {
+		return true
+	}
+
+	requested, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity, bound := pvc.Status.Capacity[corev1.ResourceStorage]
+	return ok && bound && requested.Cmp(capacity) > 0
+}
+
+// checkStorageClass inspects a single StorageClass for problematic settings. resizing
+// names the class's PVCs with a pending or failed resize.
+func (d *StorageClassDetector) checkStorageClass(sc storagev1.StorageClass, resizing []string) []types.CSIMountIssue {
+	var issues []types.CSIMountIssue
+
+	bindingMode := storagev1.VolumeBindingImmediate
+	if sc.VolumeBindingMode != nil {
+		bindingMode = *sc.VolumeBindingMode
+	}
+
+	switch {
+	case bindingMode == storagev1.VolumeBindingWaitForFirstConsumer:
+		issues = append(issues, d.newIssue(sc, "volumeBindingMode", string(bindingMode),
+			fmt.Sprintf("StorageClass %s uses WaitForFirstConsumer binding: PVCs stay Pending until a pod is scheduled, and pod anti-affinity or node selectors that exclude the driver's topology can leave them unbindable", sc.Name)))
+	case len(sc.AllowedTopologies) > 0 || topologyConstrainedDrivers[sc.Provisioner]:
+		issues = append(issues, d.newIssue(sc, "volumeBindingMode", string(bindingMode),
+			fmt.Sprintf("StorageClass %s uses Immediate binding with topology-constrained driver %s: volumes can be provisioned in a zone where the consuming pod cannot run, consider WaitForFirstConsumer", sc.Name, sc.Provisioner)))
+	}
+
+	if !allowsExpansion(sc) && len(resizing) > 0 {
+		issues = append(issues, d.newIssue(sc, "allowVolumeExpansion", "false",
+			fmt.Sprintf("StorageClass %s does not allow volume expansion but PVCs %s have a pending or failed resize: full volumes must be migrated by hand", sc.Name, strings.Join(resizing, ", "))))
+	}
+
+	if sc.ReclaimPolicy != nil && *sc.ReclaimPolicy == corev1.PersistentVolumeReclaimRetain {
+		issues = append(issues, d.newIssue(sc, "reclaimPolicy", string(*sc.ReclaimPolicy),
+			fmt.Sprintf("StorageClass %s retains volumes: deleted PVCs leave Released PVs and backend volumes that must be cleaned up manually", sc.Name)))
+	}
+
+	return issues
+}
+
+// newIssue builds an informational StorageClass issue for a single setting
+func (d *StorageClassDetector) newIssue(sc storagev1.StorageClass, setting, value, description string) types.CSIMountIssue {
+	return types.CSIMountIssue{
+		Type:        types.StorageClassMisconfiguration,
+		Severity:    types.SeverityLow,
+		Driver:      sc.Provisioner,
+		Description: description,
+		DetectedBy:  types.StorageClassMethod,
+		DetectedAt:  time.Now(),
+		OccurredAt:  sc.CreationTimestamp.Time,
+		Metadata: map[string]string{
+			"storage_class": sc.Name,
+			"setting":       setting,
+			"value":         value,
+		},
+		Sources: []types.SourceRef{{Kind: "StorageClass", Name: sc.Name, UID: string(sc.UID)}},
+	}
+}
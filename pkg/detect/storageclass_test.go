@@ -0,0 +1,191 @@
+package detect_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client/mocks"
+	"github.com/jdambly/kubectl-csi-scan/pkg/detect"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("StorageClassDetector", func() {
+	var (
+		ctrl               *gomock.Controller
+		mockClient         *mocks.MockKubernetesClient
+		mockStorageV1      *mocks.MockStorageV1Interface
+		mockStorageClasses *mocks.MockStorageClassInterface
+		mockCoreV1         *mocks.MockCoreV1Interface
+		mockPVCs           *mocks.MockPersistentVolumeClaimInterface
+		detector           *detect.StorageClassDetector
+		ctx                context.Context
+		targetDriver       string
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = mocks.NewMockKubernetesClient(ctrl)
+		mockStorageV1 = mocks.NewMockStorageV1Interface(ctrl)
+		mockStorageClasses = mocks.NewMockStorageClassInterface(ctrl)
+		mockCoreV1 = mocks.NewMockCoreV1Interface(ctrl)
+		mockPVCs = mocks.NewMockPersistentVolumeClaimInterface(ctrl)
+		ctx = context.Background()
+		targetDriver = "test.csi.driver"
+
+		mockClient.EXPECT().StorageV1().Return(mockStorageV1).AnyTimes()
+		mockStorageV1.EXPECT().StorageClasses().Return(mockStorageClasses).AnyTimes()
+		mockClient.EXPECT().CoreV1().Return(mockCoreV1).AnyTimes()
+		mockCoreV1.EXPECT().PersistentVolumeClaims("").Return(mockPVCs).AnyTimes()
+
+		detector = detect.NewStorageClassDetector(mockClient, targetDriver)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	storageClass := func(name, provisioner string, mode storagev1.VolumeBindingMode) storagev1.StorageClass {
+		expand := true
+		return storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: name},
+			Provisioner:          provisioner,
+			VolumeBindingMode:    &mode,
+			AllowVolumeExpansion: &expand,
+		}
+	}
+
+	It("should report a WaitForFirstConsumer class as an informational issue", func() {
+		created := time.Now().Add(-24 * time.Hour)
+		sc := storageClass("wffc", targetDriver, storagev1.VolumeBindingWaitForFirstConsumer)
+		sc.CreationTimestamp = metav1.NewTime(created)
+		mockStorageClasses.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.StorageClassList{
+			Items: []storagev1.StorageClass{sc},
+		}, nil)
+
+		issues, err := detector.Detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Type).To(Equal(types.StorageClassMisconfiguration))
+		Expect(issues[0].Severity).To(Equal(types.SeverityLow))
+		Expect(issues[0].Driver).To(Equal(targetDriver))
+		Expect(issues[0].DetectedBy).To(Equal(types.StorageClassMethod))
+		Expect(issues[0].Description).To(ContainSubstring("WaitForFirstConsumer"))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("storage_class", "wffc"))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("setting", "volumeBindingMode"))
+		Expect(issues[0].OccurredAt).To(BeTemporally("==", created))
+		Expect(issues[0].Sources).To(ConsistOf(types.SourceRef{Kind: "StorageClass", Name: "wffc"}))
+	})
+
+	It("should report Immediate binding on a topology-constrained driver", func() {
+		detector = detect.NewStorageClassDetector(mockClient, "")
+		mockStorageClasses.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.StorageClassList{
+			Items: []storagev1.StorageClass{
+				storageClass("cinder", "cinder.csi.openstack.org", storagev1.VolumeBindingImmediate),
+			},
+		}, nil)
+
+		issues, err := detector.Detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Description).To(ContainSubstring("Immediate binding"))
+	})
+
+	pvc := func(name, class, requested, capacity string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &class,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(requested)},
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+			},
+		}
+	}
+
+	It("should report disabled expansion when a resize is pending, and retained volumes", func() {
+		sc := storageClass("retain", targetDriver, storagev1.VolumeBindingImmediate)
+		sc.AllowVolumeExpansion = nil
+		retain := corev1.PersistentVolumeReclaimRetain
+		sc.ReclaimPolicy = &retain
+		mockStorageClasses.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.StorageClassList{
+			Items: []storagev1.StorageClass{sc},
+		}, nil)
+		mockPVCs.EXPECT().List(ctx, metav1.ListOptions{}).Return(&corev1.PersistentVolumeClaimList{
+			Items: []corev1.PersistentVolumeClaim{
+				pvc("data", "retain", "20Gi", "10Gi"),
+				pvc("logs", "retain", "10Gi", "10Gi"),
+			},
+		}, nil)
+
+		issues, err := detector.Detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		var settings []string
+		for _, issue := range issues {
+			settings = append(settings, issue.Metadata["setting"])
+			if issue.Metadata["setting"] == "allowVolumeExpansion" {
+				Expect(issue.Description).To(ContainSubstring("shop/data"))
+				Expect(issue.Description).NotTo(ContainSubstring("shop/logs"))
+			}
+		}
+		Expect(settings).To(ConsistOf("allowVolumeExpansion", "reclaimPolicy"))
+	})
+
+	It("should report disabled expansion when a resize failed", func() {
+		sc := storageClass("fixed", targetDriver, storagev1.VolumeBindingImmediate)
+		sc.AllowVolumeExpansion = nil
+		failed := pvc("data", "fixed", "10Gi", "10Gi")
+		failed.Status.AllocatedResourceStatuses = map[corev1.ResourceName]corev1.ClaimResourceStatus{
+			corev1.ResourceStorage: corev1.PersistentVolumeClaimNodeResizeFailed,
+		}
+		mockStorageClasses.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.StorageClassList{
+			Items: []storagev1.StorageClass{sc},
+		}, nil)
+		mockPVCs.EXPECT().List(ctx, metav1.ListOptions{}).Return(&corev1.PersistentVolumeClaimList{
+			Items: []corev1.PersistentVolumeClaim{failed},
+		}, nil)
+
+		issues, err := detector.Detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Metadata).To(HaveKeyWithValue("setting", "allowVolumeExpansion"))
+	})
+
+	It("should not report disabled expansion without a resize", func() {
+		sc := storageClass("fixed", targetDriver, storagev1.VolumeBindingImmediate)
+		sc.AllowVolumeExpansion = nil
+		mockStorageClasses.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.StorageClassList{
+			Items: []storagev1.StorageClass{sc},
+		}, nil)
+		mockPVCs.EXPECT().List(ctx, metav1.ListOptions{}).Return(&corev1.PersistentVolumeClaimList{
+			Items: []corev1.PersistentVolumeClaim{pvc("data", "fixed", "10Gi", "10Gi")},
+		}, nil)
+
+		issues, err := detector.Detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("should skip classes for other drivers", func() {
+		mockStorageClasses.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.StorageClassList{
+			Items: []storagev1.StorageClass{
+				storageClass("other", "other.csi.driver", storagev1.VolumeBindingWaitForFirstConsumer),
+			},
+		}, nil)
+
+		issues, err := detector.Detect(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+})
@@ -0,0 +1,80 @@
+package detect
+
+import (
+	"path"
+	"strings"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// suppress removes issues matching any suppression rule and returns the remaining issues
+// with the number removed
+func (d *Detector) suppress(issues []types.CSIMountIssue) ([]types.CSIMountIssue, int) {
+	if len(d.options.Suppressions) == 0 {
+		return issues, 0
+	}
+
+	var kept []types.CSIMountIssue
+	suppressed := 0
+	for _, issue := range issues {
+		if isSuppressed(issue, d.options.Suppressions) {
+			suppressed++
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	return kept, suppressed
+}
+
+// isSuppressed reports whether an issue matches any of the suppression rules
+func isSuppressed(issue types.CSIMountIssue, rules []types.SuppressionRule) bool {
+	for _, rule := range rules {
+		if ruleMatches(rule, issue) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleMatches reports whether every field set in a rule matches the issue. A rule with
+// no fields set matches nothing.
+func ruleMatches(rule types.SuppressionRule, issue types.CSIMountIssue) bool {
+	if rule == (types.SuppressionRule{}) {
+		return false
+	}
+	if rule.Type != "" && rule.Type != issue.Type {
+		return false
+	}
+	if rule.Driver != "" && !globMatches(rule.Driver, issue.Driver) {
+		return false
+	}
+	if rule.Node != "" && !globMatches(rule.Node, issue.Node) {
+		return false
+	}
+	if rule.PVC != "" && !pvcMatches(rule.PVC, issue) {
+		return false
+	}
+	return true
+}
+
+// pvcMatches matches a PVC glob against the issue's PVC as namespace/name and as a bare name
+func pvcMatches(pattern string, issue types.CSIMountIssue) bool {
+	if issue.PVC == "" {
+		return false
+	}
+
+	qualified, name := issue.PVC, issue.PVC
+	if _, pvcName, ok := strings.Cut(issue.PVC, "/"); ok {
+		name = pvcName
+	} else if issue.Namespace != "" {
+		qualified = issue.Namespace + "/" + issue.PVC
+	}
+	return globMatches(pattern, qualified) || globMatches(pattern, name)
+}
+
+// globMatches reports whether value matches a glob pattern. Invalid patterns match nothing;
+// they are rejected when rules are loaded.
+func globMatches(pattern, value string) bool {
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
@@ -2,28 +2,102 @@ package detect
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+	"github.com/jdambly/kubectl-csi-scan/pkg/parse"
 	"github.com/jdambly/kubectl-csi-scan/pkg/types"
 )
 
+// attacherFinalizerPrefix starts the finalizer the external-attacher adds to a
+// VolumeAttachment when it begins processing it
+const attacherFinalizerPrefix = "external-attacher/"
+
+// DefaultStuckThreshold is how long a VolumeAttachment may stay unattached before it is reported as stuck
+const DefaultStuckThreshold = 30 * time.Minute
+
+// DefaultDeletionThreshold is how long a deleted VolumeAttachment may keep its finalizers
+// before it is reported as stuck detaching
+const DefaultDeletionThreshold = 10 * time.Minute
+
 // VolumeAttachmentDetector implements detection via VolumeAttachment API objects
 type VolumeAttachmentDetector struct {
-	client       client.KubernetesClient
-	targetDriver string
+	client            client.KubernetesClient
+	targetDriver      string
+	stuckThreshold    time.Duration
+	driverThresholds  map[string]time.Duration
+	deletionThreshold time.Duration
+	checkClaims       bool
+	claimErrorLimit   int
+	storageClass      string
+	resourceVersion   string // of the last VolumeAttachment list Detect read
 }
 
 // NewVolumeAttachmentDetector creates a new VolumeAttachment detector
 func NewVolumeAttachmentDetector(kubeClient client.KubernetesClient, targetDriver string) *VolumeAttachmentDetector {
 	return &VolumeAttachmentDetector{
-		client:       kubeClient,
-		targetDriver: targetDriver,
+		client:            kubeClient,
+		targetDriver:      targetDriver,
+		stuckThreshold:    DefaultStuckThreshold,
+		deletionThreshold: DefaultDeletionThreshold,
+	}
+}
+
+// SetStuckThresholds sets how long an unattached VolumeAttachment may wait before it is
+// reported as stuck. perDriver overrides the default for specific CSI drivers, since
+// normal attach latency varies widely between backends. A zero default keeps DefaultStuckThreshold.
+func (d *VolumeAttachmentDetector) SetStuckThresholds(defaultThreshold time.Duration, perDriver map[string]time.Duration) {
+	if defaultThreshold > 0 {
+		d.stuckThreshold = defaultThreshold
+	}
+	d.driverThresholds = perDriver
+}
+
+// SetDeletionThreshold sets how long a deleted VolumeAttachment may wait on its finalizers
+// before it is reported as stuck detaching. Zero keeps DefaultDeletionThreshold.
+func (d *VolumeAttachmentDetector) SetDeletionThreshold(threshold time.Duration) {
+	if threshold > 0 {
+		d.deletionThreshold = threshold
+	}
+}
+
+// SetCheckClaims enables looking up the PV and claim behind every attached VolumeAttachment
+// to report volumes still attached after their PVC was deleted. It costs two API reads per
+// attached volume, so it is off by default.
+func (d *VolumeAttachmentDetector) SetCheckClaims(check bool) {
+	d.checkClaims = check
+}
+
+// SetEnrichmentErrorLimit sets how many consecutive identical lookup errors stop the claim
+// check for the rest of a run. Zero keeps DefaultEnrichmentErrorLimit.
+func (d *VolumeAttachmentDetector) SetEnrichmentErrorLimit(limit int) {
+	d.claimErrorLimit = limit
+}
+
+// SetStorageClass limits detection to VolumeAttachments of PVs in this StorageClass.
+// Inline volumes and PVs that no longer exist are skipped. Empty inspects every
+// VolumeAttachment.
+func (d *VolumeAttachmentDetector) SetStorageClass(name string) {
+	d.storageClass = name
+}
+
+// stuckThresholdFor returns the stuck threshold that applies to a driver
+func (d *VolumeAttachmentDetector) stuckThresholdFor(driver string) time.Duration {
+	if threshold, ok := d.driverThresholds[driver]; ok && threshold > 0 {
+		return threshold
 	}
+	return d.stuckThreshold
 }
 
 // Detect finds VolumeAttachment conflicts and stuck attachments
@@ -35,38 +109,48 @@ func (d *VolumeAttachmentDetector) Detect(ctx context.Context) ([]types.CSIMount
 	if err != nil {
 		return nil, fmt.Errorf("failed to list VolumeAttachments: %w", err)
 	}
+	d.resourceVersion = vas.ResourceVersion
 
 	// Track attachments by volume handle for conflict detection
 	volumeAttachments := make(map[string][]types.VolumeAttachmentInfo)
 	attachedVAs := make(map[string]types.VolumeAttachmentInfo)
+	vaRefs := make(map[string]types.SourceRef) // VolumeAttachment name -> source reference
+	pvNames := make(map[string]string)         // VolumeAttachment name -> attached PV
+	var attachedPVs []storagev1.VolumeAttachment
+	pvs := newPVLookup(d.client, NewCircuitBreaker("PV lookup", d.claimErrorLimit))
 
 	for _, va := range vas.Items {
+		driver := d.resolveDriver(ctx, va, pvs)
+
 		// Filter by driver if specified
-		if d.targetDriver != "" && va.Spec.Attacher != d.targetDriver {
+		if d.targetDriver != "" && driver != d.targetDriver {
 			continue
 		}
 
-		vaInfo := types.VolumeAttachmentInfo{
-			Name:           va.Name,
-			Node:           va.Spec.NodeName,
-			VolumeHandle:   d.getVolumeHandle(va.Spec.Source),
-			Driver:         va.Spec.Attacher, // Attacher field contains the CSI driver name
-			Attached:       va.Status.Attached,
-			LastTransition: va.CreationTimestamp,
+		if d.storageClass != "" {
+			in, err := d.inStorageClass(ctx, va, pvs)
+			if err != nil {
+				return nil, err
+			}
+			if !in {
+				continue
+			}
 		}
 
-		if va.Status.AttachError != nil {
-			vaInfo.AttachError = va.Status.AttachError.Message
-		}
-		if va.Status.DetachError != nil {
-			vaInfo.DetachError = va.Status.DetachError.Message
-		}
+		vaInfo := d.attachmentInfo(va, driver)
 
 		volumeHandle := vaInfo.VolumeHandle
 		volumeAttachments[volumeHandle] = append(volumeAttachments[volumeHandle], vaInfo)
+		vaRefs[va.Name] = volumeAttachmentRef(va)
+		if va.Spec.Source.PersistentVolumeName != nil {
+			pvNames[va.Name] = *va.Spec.Source.PersistentVolumeName
+		}
 
 		if va.Status.Attached {
 			attachedVAs[volumeHandle] = vaInfo
+			if va.Spec.Source.PersistentVolumeName != nil {
+				attachedPVs = append(attachedPVs, va)
+			}
 		}
 
 		// Check for attach/detach errors
@@ -90,35 +174,58 @@ func (d *VolumeAttachmentDetector) Detect(ctx context.Context) ([]types.CSIMount
 				Description: d.formatErrorDescription(va),
 				DetectedBy:  types.VolumeAttachmentMethod,
 				DetectedAt:  time.Now(),
+				OccurredAt:  va.CreationTimestamp.Time,
 				Metadata: map[string]string{
 					"volumeattachment_name": va.Name,
 					"attach_error":          vaInfo.AttachError,
 					"detach_error":          vaInfo.DetachError,
 				},
+				Sources: []types.SourceRef{volumeAttachmentRef(va)},
 			}
 			issues = append(issues, issue)
 		}
 
-		// Check for stuck attachments (not attached after significant time)
-		if !va.Status.Attached && va.Status.AttachError == nil {
+		// Check for deletions held up by finalizers. The attacher removes its finalizer once
+		// the volume is detached, so a lingering one means the detach never finished, even
+		// when no DetachError was recorded.
+		if va.DeletionTimestamp != nil && len(va.Finalizers) > 0 && va.Status.DetachError == nil {
+			if issue, ok := d.stuckDeletionIssue(va, vaInfo); ok {
+				issues = append(issues, issue)
+			}
+		}
+
+		// Check for stuck attachments (not attached after significant time). An attachment
+		// being deleted is not attaching.
+		if !va.Status.Attached && va.Status.AttachError == nil && va.DeletionTimestamp == nil {
 			timeSinceCreation := time.Since(va.CreationTimestamp.Time)
-			if timeSinceCreation > 30*time.Minute { // Consider stuck after 30 minutes
+			stuckThreshold := d.stuckThresholdFor(vaInfo.Driver)
+			if timeSinceCreation > stuckThreshold {
 				severity := d.calculateStuckAttachmentSeverity(timeSinceCreation)
+				issueType := types.StuckVolumeAttachment
+				description := fmt.Sprintf("Volume stuck in attaching state for %v", timeSinceCreation.Round(time.Minute))
+				if !reconciled(va) {
+					issueType = types.AttachmentNotReconciled
+					description = fmt.Sprintf("VolumeAttachment not picked up by any attacher for %v: check that the external-attacher for %s is running",
+						timeSinceCreation.Round(time.Minute), vaInfo.Driver)
+				}
 				issue := types.CSIMountIssue{
-					Type:        types.StuckVolumeAttachment,
+					Type:        issueType,
 					Severity:    severity,
 					Node:        va.Spec.NodeName,
 					Volume:      volumeHandle,
 					Driver:      vaInfo.Driver,
-					Description: fmt.Sprintf("Volume stuck in attaching state for %v", timeSinceCreation.Round(time.Minute)),
+					Description: description,
 					DetectedBy:  types.VolumeAttachmentMethod,
 					DetectedAt:  time.Now(),
+					OccurredAt:  va.CreationTimestamp.Time,
 					Metadata: map[string]string{
 						"volume_attachment_name": va.Name,
 						"stuck_duration":        timeSinceCreation.String(),
 						"created_at":           va.CreationTimestamp.Format(time.RFC3339),
 						"age_hours":            fmt.Sprintf("%.1f", timeSinceCreation.Hours()),
+						"stuck_threshold":      stuckThreshold.String(),
 					},
+					Sources: []types.SourceRef{volumeAttachmentRef(va)},
 				}
 				issues = append(issues, issue)
 			}
@@ -131,14 +238,22 @@ func (d *VolumeAttachmentDetector) Detect(ctx context.Context) ([]types.CSIMount
 			// Check if multiple are attached
 			attachedCount := 0
 			var attachedNodes []string
+			var conflictStart time.Time // the most recent attachment is when the conflict began
+			var sources []types.SourceRef
 			for _, attachment := range attachments {
 				if attachment.Attached {
 					attachedCount++
 					attachedNodes = append(attachedNodes, attachment.Node)
+					sources = append(sources, vaRefs[attachment.Name])
+					if attachment.LastTransition.Time.After(conflictStart) {
+						conflictStart = attachment.LastTransition.Time
+					}
 				}
 			}
 
 			if attachedCount > 1 {
+				// Sort so the same conflict renders identically across runs
+				sort.Strings(attachedNodes)
 				severity := d.calculateMultiAttachSeverity(attachedCount)
 				issue := types.CSIMountIssue{
 					Type:        types.MultipleAttachments,
@@ -148,30 +263,338 @@ func (d *VolumeAttachmentDetector) Detect(ctx context.Context) ([]types.CSIMount
 					Description: fmt.Sprintf("Volume attached to multiple nodes: %v", attachedNodes),
 					DetectedBy:  types.VolumeAttachmentMethod,
 					DetectedAt:  time.Now(),
+					OccurredAt:  conflictStart,
 					Metadata: map[string]string{
 						"attached_count": fmt.Sprintf("%d", attachedCount),
 						"attached_nodes": fmt.Sprintf("%v", attachedNodes),
 						"total_attachments": fmt.Sprintf("%d", len(attachments)),
 					},
+					Sources: sources,
 				}
 				issues = append(issues, issue)
 			}
 		}
 	}
 
+	err = EnrichIssues(ctx, issues, DefaultEnrichmentWorkers, func(ctx context.Context, issue *types.CSIMountIssue) {
+		d.addClaim(ctx, issue, pvNames, pvs)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim lookup interrupted: %w", err)
+	}
+
+	if d.checkClaims {
+		claimIssues, err := d.detectAttachedWithoutClaim(ctx, attachedPVs, pvs)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, claimIssues...)
+	}
+
 	return issues, nil
 }
 
-// matchesDriver checks if the VolumeAttachmentSource matches the target driver
-func (d *VolumeAttachmentDetector) matchesDriver(source storagev1.VolumeAttachmentSource, targetDriver string) bool {
-	if source.PersistentVolumeName != nil {
-		// We would need to fetch the PV to get the CSI driver, for now assume match
+// addClaim records the PV behind the first VolumeAttachment of an issue as a source, and the
+// claim it is bound to, so the issue can be shown by its PVC rather than the volume handle.
+// The PV was usually already read to resolve the driver; one that cannot be read leaves the
+// issue unchanged. It runs through EnrichIssues, so it must be safe for concurrent use.
+func (d *VolumeAttachmentDetector) addClaim(ctx context.Context, issue *types.CSIMountIssue, pvNames map[string]string, pvs *pvLookup) {
+	for _, source := range issue.Sources {
+		pvName, ok := pvNames[source.Name]
+		if source.Kind != "VolumeAttachment" || !ok {
+			continue
+		}
+		pv, err := pvs.get(ctx, pvName)
+		if err != nil {
+			return
+		}
+		issue.Sources = append(issue.Sources, types.SourceRef{Kind: "PersistentVolume", Name: pvName, UID: string(pv.UID)})
+		if claimRef := pv.Spec.ClaimRef; claimRef != nil {
+			issue.PVC = claimRef.Name
+			issue.Namespace = claimRef.Namespace
+		}
+		return
+	}
+}
+
+// stuckDeletionIssue reports a deleted VolumeAttachment whose finalizers have kept it
+// around for longer than the deletion threshold
+func (d *VolumeAttachmentDetector) stuckDeletionIssue(va storagev1.VolumeAttachment, vaInfo types.VolumeAttachmentInfo) (types.CSIMountIssue, bool) {
+	deletingFor := time.Since(va.DeletionTimestamp.Time)
+	if deletingFor <= d.deletionThreshold {
+		return types.CSIMountIssue{}, false
+	}
+	finalizers := strings.Join(va.Finalizers, ",")
+	return types.CSIMountIssue{
+		Type:     types.StuckVolumeDetachment,
+		Severity: d.calculateStuckAttachmentSeverity(deletingFor),
+		Node:     va.Spec.NodeName,
+		Volume:   vaInfo.VolumeHandle,
+		Driver:   vaInfo.Driver,
+		Description: fmt.Sprintf("VolumeAttachment %s deleted %v ago but still held by finalizers %s: the volume was never detached from node %s",
+			va.Name, deletingFor.Round(time.Minute), finalizers, va.Spec.NodeName),
+		DetectedBy: types.VolumeAttachmentMethod,
+		DetectedAt: time.Now(),
+		OccurredAt: va.DeletionTimestamp.Time,
+		Metadata: map[string]string{
+			"volumeattachment_name": va.Name,
+			"finalizers":            finalizers,
+			"deletion_timestamp":    va.DeletionTimestamp.Format(time.RFC3339),
+			"deleting_for":          deletingFor.Round(time.Second).String(),
+			"deletion_threshold":    d.deletionThreshold.String(),
+		},
+		Sources: []types.SourceRef{volumeAttachmentRef(va)},
+	}, true
+}
+
+// detectAttachedWithoutClaim reports attached PVs whose ClaimRef points to a PVC that no
+// longer exists, meaning the detach that should have followed the deletion never happened.
+// Lookups are best-effort: a PV or PVC that cannot be read is skipped, and once lookups
+// keep failing the same way the remaining volumes are not checked.
+func (d *VolumeAttachmentDetector) detectAttachedWithoutClaim(ctx context.Context, attached []storagev1.VolumeAttachment, pvs *pvLookup) ([]types.CSIMountIssue, error) {
+	var issues []types.CSIMountIssue
+	for _, va := range attached {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("claim check interrupted: %w", err)
+		}
+		if !pvs.breaker.Allow() {
+			break
+		}
+
+		pvName := *va.Spec.Source.PersistentVolumeName
+		pv, err := pvs.get(ctx, pvName)
+		if err != nil || pv.Spec.ClaimRef == nil {
+			continue
+		}
+		claimRef := pv.Spec.ClaimRef
+
+		pvc, err := d.client.CoreV1().PersistentVolumeClaims(claimRef.Namespace).Get(ctx, claimRef.Name, metav1.GetOptions{})
+		pvs.breaker.Record(ignoreNotFound(err))
+		if err != nil && !apierrors.IsNotFound(err) {
+			continue
+		}
+		// The claim still exists unless it is a PVC recreated with the same name after the
+		// bound one was deleted
+		if err == nil && (claimRef.UID == "" || pvc.UID == claimRef.UID) {
+			continue
+		}
+
+		claim := claimRef.Namespace + "/" + claimRef.Name
+		issues = append(issues, types.CSIMountIssue{
+			Type:        types.AttachedWithoutClaim,
+			Severity:    types.SeverityHigh,
+			Node:        va.Spec.NodeName,
+			Volume:      pvName,
+			PVC:         claimRef.Name,
+			Namespace:   claimRef.Namespace,
+			Driver:      d.resolveDriver(ctx, va, pvs),
+			Description: fmt.Sprintf("Volume %s is still attached to node %s but its claim %s no longer exists: the detach never happened", pvName, va.Spec.NodeName, claim),
+			DetectedBy:  types.VolumeAttachmentMethod,
+			DetectedAt:  time.Now(),
+			OccurredAt:  va.CreationTimestamp.Time,
+			Metadata: map[string]string{
+				"volumeattachment_name": va.Name,
+				"claim":                 claim,
+			},
+			Sources: []types.SourceRef{
+				volumeAttachmentRef(va),
+				{Kind: "PersistentVolume", Name: pv.Name, UID: string(pv.UID)},
+			},
+		})
+	}
+	return issues, nil
+}
+
+// ignoreNotFound returns nil for NotFound errors, which are an expected lookup outcome
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// errLookupsDisabled is returned for PVs not fetched because the lookup breaker is open
+var errLookupsDisabled = errors.New("lookups disabled after repeated errors")
+
+// pvLookup reads PVs for one Detect call, so a PV needed by several checks is fetched once.
+// Failed lookups are recorded in the breaker, which stops further fetches once they keep
+// failing the same way. It is safe for concurrent use.
+type pvLookup struct {
+	client  client.KubernetesClient
+	breaker *CircuitBreaker
+	mu      sync.Mutex
+	pvs     map[string]*corev1.PersistentVolume
+	errs    map[string]error
+}
+
+func newPVLookup(kubeClient client.KubernetesClient, breaker *CircuitBreaker) *pvLookup {
+	return &pvLookup{
+		client:  kubeClient,
+		breaker: breaker,
+		pvs:     make(map[string]*corev1.PersistentVolume),
+		errs:    make(map[string]error),
+	}
+}
+
+// get returns the named PV, fetching it on first use
+func (l *pvLookup) get(ctx context.Context, name string) (*corev1.PersistentVolume, error) {
+	l.mu.Lock()
+	pv, found := l.pvs[name]
+	err, failed := l.errs[name]
+	l.mu.Unlock()
+	if found {
+		return pv, nil
+	}
+	if failed {
+		return nil, err
+	}
+	if !l.breaker.Allow() {
+		return nil, errLookupsDisabled
+	}
+
+	// The lock is not held over the API call, so concurrent lookups of an uncached PV
+	// may both fetch it; the results are the same either way
+	pv, err = l.client.CoreV1().PersistentVolumes().Get(ctx, name, metav1.GetOptions{})
+	l.breaker.Record(ignoreNotFound(err))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err != nil {
+		l.errs[name] = err
+		return nil, err
+	}
+	l.pvs[name] = pv
+	return pv, nil
+}
+
+// resolveDriver returns the CSI driver of a VolumeAttachment. For a PV-backed attachment it
+// is the driver in the PV's CSI source; when the PV cannot be read or is not a CSI volume,
+// the Attacher field names it. An attacher that does not name a driver, such as an in-tree
+// plugin name, is only used when neither the inline volume spec nor the attach/detach
+// error text names one.
+func (d *VolumeAttachmentDetector) resolveDriver(ctx context.Context, va storagev1.VolumeAttachment, pvs *pvLookup) string {
+	if pvName := va.Spec.Source.PersistentVolumeName; pvName != nil {
+		pv, err := pvs.get(ctx, *pvName)
+		if err == nil && pv.Spec.CSI != nil && pv.Spec.CSI.Driver != "" {
+			return pv.Spec.CSI.Driver
+		}
+		if ignoreNotFound(err) != nil {
+			log.Debug().Err(err).Str("pv", *pvName).Msg("falling back to the attacher as the driver")
+		}
+	}
+	if !genericAttacher(va.Spec.Attacher) {
+		return va.Spec.Attacher
+	}
+	if source := va.Spec.Source; source.InlineVolumeSpec != nil && source.InlineVolumeSpec.CSI != nil {
+		return source.InlineVolumeSpec.CSI.Driver
+	}
+	for _, volumeErr := range []*storagev1.VolumeError{va.Status.AttachError, va.Status.DetachError} {
+		if volumeErr == nil {
+			continue
+		}
+		if driver := parse.ExtractDriver(volumeErr.Message); driver != "" {
+			return driver
+		}
+	}
+	return va.Spec.Attacher
+}
+
+// genericAttacher reports whether a VolumeAttachment attacher is missing or an in-tree
+// plugin name like kubernetes.io/csi rather than the name of a CSI driver
+func genericAttacher(attacher string) bool {
+	return attacher == "" || strings.HasPrefix(attacher, "kubernetes.io/")
+}
+
+// attachmentInfo summarizes a VolumeAttachment whose driver has been resolved
+func (d *VolumeAttachmentDetector) attachmentInfo(va storagev1.VolumeAttachment, driver string) types.VolumeAttachmentInfo {
+	info := types.VolumeAttachmentInfo{
+		Name:           va.Name,
+		Node:           va.Spec.NodeName,
+		VolumeHandle:   d.getVolumeHandle(va.Spec.Source),
+		Driver:         driver,
+		Attached:       va.Status.Attached,
+		LastTransition: va.CreationTimestamp,
+	}
+	if va.Status.AttachError != nil {
+		info.AttachError = va.Status.AttachError.Message
+	}
+	if va.Status.DetachError != nil {
+		info.DetachError = va.Status.DetachError.Message
+	}
+	return info
+}
+
+// GetVolumeAttachmentsByNode returns the VolumeAttachments of the target driver, or of
+// every driver if none is set, grouped by the node they attach to and sorted by name. The
+// StorageClass filter applies as it does to Detect.
+func (d *VolumeAttachmentDetector) GetVolumeAttachmentsByNode(ctx context.Context) (map[string][]types.VolumeAttachmentInfo, error) {
+	vas, err := d.client.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VolumeAttachments: %w", err)
+	}
+
+	byNode := make(map[string][]types.VolumeAttachmentInfo)
+	pvs := newPVLookup(d.client, NewCircuitBreaker("PV lookup", d.claimErrorLimit))
+	for _, va := range vas.Items {
+		driver := d.resolveDriver(ctx, va, pvs)
+		if d.targetDriver != "" && driver != d.targetDriver {
+			continue
+		}
+		if d.storageClass != "" {
+			in, err := d.inStorageClass(ctx, va, pvs)
+			if err != nil {
+				return nil, err
+			}
+			if !in {
+				continue
+			}
+		}
+		byNode[va.Spec.NodeName] = append(byNode[va.Spec.NodeName], d.attachmentInfo(va, driver))
+	}
+
+	for _, infos := range byNode {
+		sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	}
+	return byNode, nil
+}
+
+// volumeAttachmentRef returns a source reference to a VolumeAttachment
+func volumeAttachmentRef(va storagev1.VolumeAttachment) types.SourceRef {
+	return types.SourceRef{Kind: "VolumeAttachment", Name: va.Name, UID: string(va.UID)}
+}
+
+// inStorageClass reports whether the PV of a VolumeAttachment is in the StorageClass
+// detection is limited to
+func (d *VolumeAttachmentDetector) inStorageClass(ctx context.Context, va storagev1.VolumeAttachment, pvs *pvLookup) (bool, error) {
+	if va.Spec.Source.PersistentVolumeName == nil {
+		return false, nil
+	}
+	pvName := *va.Spec.Source.PersistentVolumeName
+
+	pv, err := pvs.get(ctx, pvName)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get PV %s: %w", pvName, err)
+	}
+	return pv.Spec.StorageClassName == d.storageClass, nil
+}
+
+// reconciled reports whether an attacher has started processing a VolumeAttachment. One
+// that has an empty status and no external-attacher finalizer has never been touched,
+// which points at the attacher not running rather than at a slow attach.
+func reconciled(va storagev1.VolumeAttachment) bool {
+	status := va.Status
+	if status.Attached || status.AttachError != nil || status.DetachError != nil || len(status.AttachmentMetadata) > 0 {
 		return true
 	}
-	if source.InlineVolumeSpec != nil && source.InlineVolumeSpec.CSI != nil {
-		return source.InlineVolumeSpec.CSI.Driver == targetDriver
+	for _, finalizer := range va.Finalizers {
+		if strings.HasPrefix(finalizer, attacherFinalizerPrefix) {
+			return true
+		}
 	}
-	return true // Conservative approach - include if uncertain
+	return false
 }
 
 // getVolumeHandle extracts the volume handle from VolumeAttachmentSource
@@ -185,19 +608,6 @@ func (d *VolumeAttachmentDetector) getVolumeHandle(source storagev1.VolumeAttach
 	return "unknown"
 }
 
-// getDriverName extracts the CSI driver name from VolumeAttachmentSource  
-func (d *VolumeAttachmentDetector) getDriverName(source storagev1.VolumeAttachmentSource) string {
-	if source.InlineVolumeSpec != nil && source.InlineVolumeSpec.CSI != nil {
-		return source.InlineVolumeSpec.CSI.Driver
-	}
-	// For PV sources, we'd need to fetch the PV to get the driver
-	// Return empty string if we can't determine driver
-	if d.targetDriver != "" {
-		return d.targetDriver // Use target driver as fallback when filtering
-	}
-	return "" // Unknown driver when not filtering
-}
-
 // calculateSeverity determines issue severity based on VolumeAttachment state
 func (d *VolumeAttachmentDetector) calculateSeverity(va storagev1.VolumeAttachment, allAttachments []types.VolumeAttachmentInfo) types.IssueSeverity {
 	attachedCount := 0
@@ -9,6 +9,7 @@ import (
 	"go.uber.org/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/jdambly/kubectl-csi-scan/pkg/client/mocks"
@@ -22,6 +23,10 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 		mockClient               *mocks.MockKubernetesClient
 		mockStorageV1            *mocks.MockStorageV1Interface
 		mockVolumeAttachments    *mocks.MockVolumeAttachmentInterface
+		mockCoreV1               *mocks.MockCoreV1Interface
+		pvs                      map[string]*corev1.PersistentVolume // PVs the API serves; others are NotFound
+		pvErr                    error                               // returned by every PV lookup when set
+		pvGets                   int
 		detector                 *detect.VolumeAttachmentDetector
 		ctx                      context.Context
 		targetDriver             string
@@ -38,6 +43,23 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 		// Set up mock expectations
 		mockClient.EXPECT().StorageV1().Return(mockStorageV1).AnyTimes()
 		mockStorageV1.EXPECT().VolumeAttachments().Return(mockVolumeAttachments).AnyTimes()
+
+		mockCoreV1 = mocks.NewMockCoreV1Interface(ctrl)
+		mockPVs := mocks.NewMockPersistentVolumeInterface(ctrl)
+		pvs, pvErr, pvGets = map[string]*corev1.PersistentVolume{}, nil, 0
+		mockClient.EXPECT().CoreV1().Return(mockCoreV1).AnyTimes()
+		mockCoreV1.EXPECT().PersistentVolumes().Return(mockPVs).AnyTimes()
+		mockPVs.EXPECT().Get(gomock.Any(), gomock.Any(), metav1.GetOptions{}).DoAndReturn(
+			func(_ context.Context, name string, _ metav1.GetOptions) (*corev1.PersistentVolume, error) {
+				pvGets++
+				if pvErr != nil {
+					return nil, pvErr
+				}
+				if pv, ok := pvs[name]; ok {
+					return pv, nil
+				}
+				return nil, apierrors.NewNotFound(corev1.Resource("persistentvolumes"), name)
+			}).AnyTimes()
 	})
 
 	AfterEach(func() {
@@ -113,6 +135,7 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 							ObjectMeta: metav1.ObjectMeta{
 								Name:              "stuck-va",
 								CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+								Finalizers:        []string{"external-attacher/" + targetDriver},
 							},
 							Spec: storagev1.VolumeAttachmentSpec{
 								Attacher: targetDriver,
@@ -139,6 +162,106 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 				Expect(issues[0].Volume).To(Equal("stuck-pv"))
 				Expect(issues[0].Node).To(Equal("node-1"))
 				Expect(issues[0].DetectedBy).To(Equal(types.VolumeAttachmentMethod))
+				Expect(issues[0].OccurredAt).To(BeTemporally("==", vaList.Items[0].CreationTimestamp.Time))
+				Expect(issues[0].Sources).To(ConsistOf(types.SourceRef{Kind: "VolumeAttachment", Name: "stuck-va"}))
+			})
+
+			It("should report a VolumeAttachment no attacher has touched as not reconciled", func() {
+				mockVolumeAttachments.EXPECT().
+					List(ctx, metav1.ListOptions{}).
+					Return(&storagev1.VolumeAttachmentList{Items: []storagev1.VolumeAttachment{{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:              "untouched-va",
+							CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+						},
+						Spec: storagev1.VolumeAttachmentSpec{
+							Attacher: targetDriver,
+							NodeName: "node-1",
+							Source: storagev1.VolumeAttachmentSource{
+								PersistentVolumeName: stringPtr("untouched-pv"),
+							},
+						},
+					}}}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Type).To(Equal(types.AttachmentNotReconciled))
+				Expect(issues[0].Volume).To(Equal("untouched-pv"))
+				Expect(issues[0].Description).To(ContainSubstring("external-attacher for " + targetDriver))
+			})
+
+			It("should apply per-driver stuck thresholds to the same attachment age", func() {
+				unattached := func(name, driver string) storagev1.VolumeAttachment {
+					return storagev1.VolumeAttachment{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:              name,
+							CreationTimestamp: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+							Finalizers:        []string{"external-attacher/" + driver},
+						},
+						Spec: storagev1.VolumeAttachmentSpec{
+							Attacher: driver,
+							NodeName: "node-1",
+							Source: storagev1.VolumeAttachmentSource{
+								PersistentVolumeName: stringPtr(name + "-pv"),
+							},
+						},
+					}
+				}
+
+				detector = detect.NewVolumeAttachmentDetector(mockClient, "")
+				detector.SetStuckThresholds(time.Hour, map[string]time.Duration{
+					"fast.csi.driver": 2 * time.Minute,
+					"slow.csi.driver": 30 * time.Minute,
+				})
+
+				mockVolumeAttachments.EXPECT().
+					List(ctx, metav1.ListOptions{}).
+					Return(&storagev1.VolumeAttachmentList{Items: []storagev1.VolumeAttachment{
+						unattached("fast", "fast.csi.driver"),
+						unattached("slow", "slow.csi.driver"),
+						unattached("default", "other.csi.driver"),
+					}}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Type).To(Equal(types.StuckVolumeAttachment))
+				Expect(issues[0].Driver).To(Equal("fast.csi.driver"))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("stuck_threshold", "2m0s"))
+			})
+
+			It("should set OccurredAt to the most recent conflicting attachment", func() {
+				older := time.Now().Add(-3 * time.Hour)
+				newer := time.Now().Add(-1 * time.Hour)
+				var items []storagev1.VolumeAttachment
+				for node, created := range map[string]time.Time{"node-1": older, "node-2": newer} {
+					items = append(items, storagev1.VolumeAttachment{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:              "multi-va-" + node,
+							CreationTimestamp: metav1.NewTime(created),
+						},
+						Spec: storagev1.VolumeAttachmentSpec{
+							Attacher: targetDriver,
+							NodeName: node,
+							Source: storagev1.VolumeAttachmentSource{
+								PersistentVolumeName: stringPtr("multi-pv"),
+							},
+						},
+						Status: storagev1.VolumeAttachmentStatus{
+							Attached: true,
+						},
+					})
+				}
+
+				mockVolumeAttachments.EXPECT().
+					List(ctx, metav1.ListOptions{}).
+					Return(&storagev1.VolumeAttachmentList{Items: items}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].OccurredAt).To(BeTemporally("==", newer))
 			})
 
 			It("should detect multiple attachments for same volume", func() {
@@ -190,6 +313,42 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 				Expect(issues[0].Description).To(ContainSubstring("multiple nodes"))
 			})
 
+			It("should list attached nodes in sorted order", func() {
+				var items []storagev1.VolumeAttachment
+				for _, node := range []string{"node-c", "node-a", "node-b"} {
+					items = append(items, storagev1.VolumeAttachment{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "multi-va-" + node,
+						},
+						Spec: storagev1.VolumeAttachmentSpec{
+							Attacher: targetDriver,
+							NodeName: node,
+							Source: storagev1.VolumeAttachmentSource{
+								PersistentVolumeName: stringPtr("multi-pv"),
+							},
+						},
+						Status: storagev1.VolumeAttachmentStatus{
+							Attached: true,
+						},
+					})
+				}
+
+				mockVolumeAttachments.EXPECT().
+					List(ctx, metav1.ListOptions{}).
+					Return(&storagev1.VolumeAttachmentList{Items: items}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Description).To(Equal("Volume attached to multiple nodes: [node-a node-b node-c]"))
+				Expect(issues[0].Sources).To(ConsistOf(
+					types.SourceRef{Kind: "VolumeAttachment", Name: "multi-va-node-a"},
+					types.SourceRef{Kind: "VolumeAttachment", Name: "multi-va-node-b"},
+					types.SourceRef{Kind: "VolumeAttachment", Name: "multi-va-node-c"},
+				))
+				Expect(issues[0].Metadata["attached_nodes"]).To(Equal("[node-a node-b node-c]"))
+			})
+
 			It("should detect attachment with errors", func() {
 				vaList := &storagev1.VolumeAttachmentList{
 					Items: []storagev1.VolumeAttachment{
@@ -229,6 +388,228 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 			})
 		})
 
+		Context("when the PV names the CSI driver", func() {
+			failedVA := func(name, attacher, pv string) storagev1.VolumeAttachment {
+				return storagev1.VolumeAttachment{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec: storagev1.VolumeAttachmentSpec{
+						Attacher: attacher,
+						NodeName: "node-1",
+						Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: stringPtr(pv)},
+					},
+					Status: storagev1.VolumeAttachmentStatus{
+						AttachError: &storagev1.VolumeError{Message: "attach failed"},
+					},
+				}
+			}
+			csiPV := func(name, driver string) *corev1.PersistentVolume {
+				return &corev1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{Driver: driver, VolumeHandle: name},
+					}},
+				}
+			}
+
+			It("should filter on the PV's driver rather than the attacher", func() {
+				pvs["other-pv"] = csiPV("other-pv", "other.csi.driver")
+				pvs["target-pv"] = csiPV("target-pv", targetDriver)
+				mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{
+					Items: []storagev1.VolumeAttachment{
+						failedVA("other-va", targetDriver, "other-pv"),
+						failedVA("target-va", "other.csi.driver", "target-pv"),
+					},
+				}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Volume).To(Equal("target-pv"))
+				Expect(issues[0].Driver).To(Equal(targetDriver))
+			})
+
+			It("should report the PV's driver without a target driver", func() {
+				detector = detect.NewVolumeAttachmentDetector(mockClient, "")
+				pvs["other-pv"] = csiPV("other-pv", "other.csi.driver")
+				mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{
+					Items: []storagev1.VolumeAttachment{failedVA("other-va", targetDriver, "other-pv")},
+				}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Driver).To(Equal("other.csi.driver"))
+			})
+
+			It("should read each PV once per run", func() {
+				pvs["shared-pv"] = csiPV("shared-pv", targetDriver)
+				mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{
+					Items: []storagev1.VolumeAttachment{
+						failedVA("va-1", targetDriver, "shared-pv"),
+						failedVA("va-2", targetDriver, "shared-pv"),
+					},
+				}, nil)
+
+				_, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pvGets).To(Equal(1))
+			})
+
+			It("should fall back to the attacher when the PV cannot be read", func() {
+				pvErr = &testError{msg: "forbidden"}
+				mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{
+					Items: []storagev1.VolumeAttachment{failedVA("target-va", targetDriver, "target-pv")},
+				}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Driver).To(Equal(targetDriver))
+				Expect(issues[0].PVC).To(BeEmpty())
+				Expect(issues[0].Sources).To(HaveLen(1))
+			})
+
+			It("should record the PV and the claim bound to it", func() {
+				pv := csiPV("target-pv", targetDriver)
+				pv.UID = "pv-uid"
+				pv.Spec.ClaimRef = &corev1.ObjectReference{Namespace: "shop", Name: "data-web-0"}
+				pvs["target-pv"] = pv
+				mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{
+					Items: []storagev1.VolumeAttachment{failedVA("target-va", targetDriver, "target-pv")},
+				}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].PVC).To(Equal("data-web-0"))
+				Expect(issues[0].Namespace).To(Equal("shop"))
+				Expect(issues[0].Sources).To(ContainElement(types.SourceRef{Kind: "PersistentVolume", Name: "target-pv", UID: "pv-uid"}))
+				Expect(detect.DisplayVolume(issues[0])).To(Equal("shop/data-web-0 (target-pv)"))
+				Expect(pvGets).To(Equal(1))
+			})
+
+			It("should stop looking up claims once the context is done", func() {
+				cancelCtx, cancel := context.WithCancel(ctx)
+				pvs["target-pv"] = csiPV("target-pv", targetDriver)
+				mockVolumeAttachments.EXPECT().List(cancelCtx, metav1.ListOptions{}).DoAndReturn(
+					func(context.Context, metav1.ListOptions) (*storagev1.VolumeAttachmentList, error) {
+						// Cancelled once the attachments are read, before their claims are
+						cancel()
+						return &storagev1.VolumeAttachmentList{
+							Items: []storagev1.VolumeAttachment{failedVA("target-va", targetDriver, "target-pv")},
+						}, nil
+					})
+
+				issues, err := detector.Detect(cancelCtx)
+				Expect(err).To(MatchError(context.Canceled))
+				Expect(err).To(MatchError(ContainSubstring("claim lookup interrupted")))
+				Expect(issues).To(BeNil())
+			})
+
+			It("should record the claim of a volume attached to several nodes", func() {
+				pv := csiPV("shared-pv", targetDriver)
+				pv.Spec.ClaimRef = &corev1.ObjectReference{Namespace: "shop", Name: "data-web-0"}
+				pvs["shared-pv"] = pv
+				attached := func(name, node string) storagev1.VolumeAttachment {
+					va := failedVA(name, targetDriver, "shared-pv")
+					va.Spec.NodeName = node
+					va.Status = storagev1.VolumeAttachmentStatus{Attached: true}
+					return va
+				}
+				mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{
+					Items: []storagev1.VolumeAttachment{attached("va-1", "node-1"), attached("va-2", "node-2")},
+				}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Type).To(Equal(types.MultipleAttachments))
+				Expect(detect.DisplayVolume(issues[0])).To(Equal("shop/data-web-0 (shared-pv)"))
+			})
+		})
+
+		Context("when the attacher does not name a driver", func() {
+			erroredAttachment := func(attacher, message string) storagev1.VolumeAttachment {
+				return storagev1.VolumeAttachment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "unattributed-va",
+					},
+					Spec: storagev1.VolumeAttachmentSpec{
+						Attacher: attacher,
+						NodeName: "node-1",
+						Source: storagev1.VolumeAttachmentSource{
+							PersistentVolumeName: stringPtr("error-pv"),
+						},
+					},
+					Status: storagev1.VolumeAttachmentStatus{
+						AttachError: &storagev1.VolumeError{
+							Time:    metav1.NewTime(time.Now()),
+							Message: message,
+						},
+					},
+				}
+			}
+
+			DescribeTable("should recover the driver from the attach error message",
+				func(attacher string) {
+					mockVolumeAttachments.EXPECT().
+						List(ctx, metav1.ListOptions{}).
+						Return(&storagev1.VolumeAttachmentList{Items: []storagev1.VolumeAttachment{
+							erroredAttachment(attacher, "rpc error: code = Internal desc = attach failed for driver cinder.csi.openstack.org: volume is in use"),
+						}}, nil)
+
+					detector = detect.NewVolumeAttachmentDetector(mockClient, "cinder.csi.openstack.org")
+					issues, err := detector.Detect(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(issues).To(HaveLen(1))
+					Expect(issues[0].Type).To(Equal(types.FailedAttachVolume))
+					Expect(issues[0].Driver).To(Equal("cinder.csi.openstack.org"))
+				},
+				Entry("with no attacher", ""),
+				Entry("with an in-tree plugin attacher", "kubernetes.io/csi"),
+			)
+
+			It("should keep the attacher when the error text names no driver", func() {
+				mockVolumeAttachments.EXPECT().
+					List(ctx, metav1.ListOptions{}).
+					Return(&storagev1.VolumeAttachmentList{Items: []storagev1.VolumeAttachment{
+						erroredAttachment("kubernetes.io/csi", "rpc error: code = Internal desc = volume is in use"),
+					}}, nil)
+
+				detector = detect.NewVolumeAttachmentDetector(mockClient, "")
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Driver).To(Equal("kubernetes.io/csi"))
+			})
+		})
+
+		It("should prefer a driver-named attacher over the attach error text", func() {
+			va := storagev1.VolumeAttachment{
+				ObjectMeta: metav1.ObjectMeta{Name: "attributed-va"},
+				Spec: storagev1.VolumeAttachmentSpec{
+					Attacher: "ebs.csi.aws.com",
+					NodeName: "node-1",
+					Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: stringPtr("error-pv")},
+				},
+				Status: storagev1.VolumeAttachmentStatus{
+					AttachError: &storagev1.VolumeError{
+						Time:    metav1.NewTime(time.Now()),
+						Message: "attach failed for driver cinder.csi.openstack.org",
+					},
+				},
+			}
+			mockVolumeAttachments.EXPECT().
+				List(ctx, metav1.ListOptions{}).
+				Return(&storagev1.VolumeAttachmentList{Items: []storagev1.VolumeAttachment{va}}, nil)
+
+			detector = detect.NewVolumeAttachmentDetector(mockClient, "")
+			issues, err := detector.Detect(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issues).To(HaveLen(1))
+			Expect(issues[0].Driver).To(Equal("ebs.csi.aws.com"))
+		})
+
 		Context("when detach issues exist", func() {
 			It("should detect volume stuck in detaching state", func() {
 				vaList := &storagev1.VolumeAttachmentList{
@@ -237,7 +618,7 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 							ObjectMeta: metav1.ObjectMeta{
 								Name:              "detaching-va",
 								DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-1 * time.Hour)},
-								Finalizers:        []string{"external-attacher/test-csi-driver"},
+								Finalizers:        []string{"external-attacher/" + targetDriver},
 							},
 							Spec: storagev1.VolumeAttachmentSpec{
 								Attacher: targetDriver,
@@ -269,6 +650,62 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 				Expect(issues[0].Node).To(Equal("node-1"))
 				Expect(issues[0].Description).To(ContainSubstring("Failed to detach volume"))
 			})
+
+			Context("when a deleted attachment is held by finalizers without a detach error", func() {
+				deleting := func(deletedAgo time.Duration) *storagev1.VolumeAttachmentList {
+					return &storagev1.VolumeAttachmentList{
+						Items: []storagev1.VolumeAttachment{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name:              "finalized-va",
+									CreationTimestamp: metav1.NewTime(time.Now().Add(-3 * time.Hour)),
+									DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-deletedAgo)},
+									Finalizers:        []string{"external-attacher/" + targetDriver},
+								},
+								Spec: storagev1.VolumeAttachmentSpec{
+									Attacher: targetDriver,
+									NodeName: "node-2",
+									Source: storagev1.VolumeAttachmentSource{
+										PersistentVolumeName: stringPtr("finalized-pv"),
+									},
+								},
+								Status: storagev1.VolumeAttachmentStatus{
+									Attached: true,
+								},
+							},
+						},
+					}
+				}
+
+				It("should report it as stuck detaching once the deletion threshold has passed", func() {
+					vaList := deleting(time.Hour)
+					mockVolumeAttachments.EXPECT().
+						List(ctx, metav1.ListOptions{}).
+						Return(vaList, nil)
+
+					issues, err := detector.Detect(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(issues).To(HaveLen(1))
+					Expect(issues[0].Type).To(Equal(types.StuckVolumeDetachment))
+					Expect(issues[0].Volume).To(Equal("finalized-pv"))
+					Expect(issues[0].Node).To(Equal("node-2"))
+					Expect(issues[0].OccurredAt).To(BeTemporally("==", vaList.Items[0].DeletionTimestamp.Time))
+					Expect(issues[0].Metadata).To(HaveKeyWithValue("finalizers", "external-attacher/"+targetDriver))
+					Expect(issues[0].Metadata).To(HaveKeyWithValue("deletion_threshold", detect.DefaultDeletionThreshold.String()))
+					Expect(issues[0].Description).To(ContainSubstring("external-attacher/" + targetDriver))
+				})
+
+				It("should wait for the configured deletion threshold", func() {
+					mockVolumeAttachments.EXPECT().
+						List(ctx, metav1.ListOptions{}).
+						Return(deleting(time.Hour), nil)
+
+					detector.SetDeletionThreshold(2 * time.Hour)
+					issues, err := detector.Detect(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(issues).To(BeEmpty())
+				})
+			})
 		})
 
 		Context("when filtering by target driver", func() {
@@ -390,6 +827,217 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 				Expect(issues).To(BeNil())
 			})
 		})
+
+		Context("when limited to a StorageClass", func() {
+			It("should only inspect VolumeAttachments of PVs in that StorageClass", func() {
+				failedVA := func(name, pv string) storagev1.VolumeAttachment {
+					return storagev1.VolumeAttachment{
+						ObjectMeta: metav1.ObjectMeta{Name: name},
+						Spec: storagev1.VolumeAttachmentSpec{
+							Attacher: targetDriver,
+							NodeName: "node-1",
+							Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: stringPtr(pv)},
+						},
+						Status: storagev1.VolumeAttachmentStatus{
+							AttachError: &storagev1.VolumeError{Message: "attach failed"},
+						},
+					}
+				}
+				inline := failedVA("inline-va", "")
+				inline.Spec.Source = storagev1.VolumeAttachmentSource{
+					InlineVolumeSpec: &corev1.PersistentVolumeSpec{
+						PersistentVolumeSource: corev1.PersistentVolumeSource{
+							CSI: &corev1.CSIPersistentVolumeSource{Driver: targetDriver, VolumeHandle: "vol-inline"},
+						},
+					},
+				}
+				mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{
+					Items: []storagev1.VolumeAttachment{failedVA("gold-va", "gold-pv"), failedVA("bronze-va", "bronze-pv"), inline},
+				}, nil)
+				pvs["gold-pv"] = &corev1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: "gold-pv"},
+					Spec:       corev1.PersistentVolumeSpec{StorageClassName: "gold"},
+				}
+				pvs["bronze-pv"] = &corev1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: "bronze-pv"},
+					Spec:       corev1.PersistentVolumeSpec{StorageClassName: "bronze"},
+				}
+
+				detector.SetStorageClass("gold")
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Volume).To(Equal("gold-pv"))
+				// Each PV is read once for both its driver and its StorageClass
+				Expect(pvGets).To(Equal(2))
+			})
+		})
+
+		Context("when checking the claims of attached volumes", func() {
+			var mockPVCs *mocks.MockPersistentVolumeClaimInterface
+
+			BeforeEach(func() {
+				mockPVCs = mocks.NewMockPersistentVolumeClaimInterface(ctrl)
+				mockCoreV1.EXPECT().PersistentVolumeClaims("default").Return(mockPVCs).AnyTimes()
+
+				detector = detect.NewVolumeAttachmentDetector(mockClient, targetDriver)
+				detector.SetCheckClaims(true)
+
+				mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{
+					Items: []storagev1.VolumeAttachment{{
+						ObjectMeta: metav1.ObjectMeta{Name: "orphan-va"},
+						Spec: storagev1.VolumeAttachmentSpec{
+							Attacher: targetDriver,
+							NodeName: "node-1",
+							Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: stringPtr("orphan-pv")},
+						},
+						Status: storagev1.VolumeAttachmentStatus{Attached: true},
+					}},
+				}, nil)
+				pvs["orphan-pv"] = &corev1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: "orphan-pv"},
+					Spec: corev1.PersistentVolumeSpec{
+						ClaimRef: &corev1.ObjectReference{Namespace: "default", Name: "data-web-0", UID: "claim-uid"},
+					},
+				}
+			})
+
+			It("should report an attached volume whose claim was deleted", func() {
+				mockPVCs.EXPECT().Get(ctx, "data-web-0", metav1.GetOptions{}).
+					Return(nil, apierrors.NewNotFound(corev1.Resource("persistentvolumeclaims"), "data-web-0"))
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Type).To(Equal(types.AttachedWithoutClaim))
+				Expect(issues[0].Severity).To(Equal(types.SeverityHigh))
+				Expect(issues[0].Node).To(Equal("node-1"))
+				Expect(issues[0].Volume).To(Equal("orphan-pv"))
+				Expect(issues[0].PVC).To(Equal("data-web-0"))
+				Expect(issues[0].Namespace).To(Equal("default"))
+				Expect(issues[0].Metadata).To(HaveKeyWithValue("claim", "default/data-web-0"))
+			})
+
+			It("should report a claim recreated with the same name", func() {
+				mockPVCs.EXPECT().Get(ctx, "data-web-0", metav1.GetOptions{}).Return(&corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: "data-web-0", Namespace: "default", UID: "new-uid"},
+				}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(HaveLen(1))
+				Expect(issues[0].Type).To(Equal(types.AttachedWithoutClaim))
+			})
+
+			It("should not report a volume whose claim still exists", func() {
+				mockPVCs.EXPECT().Get(ctx, "data-web-0", metav1.GetOptions{}).Return(&corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: "data-web-0", Namespace: "default", UID: "claim-uid"},
+				}, nil)
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(BeEmpty())
+			})
+
+			It("should skip claims that cannot be read", func() {
+				mockPVCs.EXPECT().Get(ctx, "data-web-0", metav1.GetOptions{}).Return(nil, &testError{msg: "forbidden"})
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(BeEmpty())
+			})
+		})
+
+		Context("when claim lookups keep failing the same way", func() {
+			It("should stop further lookups once the breaker trips and still complete detection", func() {
+				detector = detect.NewVolumeAttachmentDetector(mockClient, targetDriver)
+				detector.SetCheckClaims(true)
+				detector.SetEnrichmentErrorLimit(3)
+
+				var items []storagev1.VolumeAttachment
+				for _, name := range []string{"pv-1", "pv-2", "pv-3", "pv-4", "pv-5"} {
+					items = append(items, storagev1.VolumeAttachment{
+						ObjectMeta: metav1.ObjectMeta{Name: name + "-va"},
+						Spec: storagev1.VolumeAttachmentSpec{
+							Attacher: targetDriver,
+							NodeName: "node-1",
+							Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: stringPtr(name)},
+						},
+						Status: storagev1.VolumeAttachmentStatus{Attached: true},
+					})
+				}
+				mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{Items: items}, nil)
+				pvErr = &testError{msg: "failed calling webhook"}
+
+				issues, err := detector.Detect(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(issues).To(BeEmpty())
+				Expect(pvGets).To(Equal(3))
+			})
+		})
+	})
+
+	Context("GetVolumeAttachmentsByNode", func() {
+		attachment := func(name, node, attacher string, attached bool) storagev1.VolumeAttachment {
+			pv := "pv-" + name
+			return storagev1.VolumeAttachment{
+				ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour))},
+				Spec: storagev1.VolumeAttachmentSpec{
+					Attacher: attacher,
+					NodeName: node,
+					Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pv},
+				},
+				Status: storagev1.VolumeAttachmentStatus{Attached: attached},
+			}
+		}
+
+		It("should group attachments by node, keeping their errors and attach state", func() {
+			detector = detect.NewVolumeAttachmentDetector(mockClient, targetDriver)
+
+			failedAttach := attachment("va-attach-error", "node-2", targetDriver, false)
+			failedAttach.Status.AttachError = &storagev1.VolumeError{Message: "rpc error: volume in use"}
+			failedDetach := attachment("va-detach-error", "node-2", targetDriver, true)
+			failedDetach.Status.DetachError = &storagev1.VolumeError{Message: "rpc error: device busy"}
+
+			mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(&storagev1.VolumeAttachmentList{
+				Items: []storagev1.VolumeAttachment{
+					attachment("va-b", "node-1", targetDriver, true),
+					failedDetach,
+					attachment("va-stuck", "node-3", targetDriver, false),
+					attachment("va-a", "node-1", targetDriver, true),
+					failedAttach,
+					attachment("va-other", "node-1", "other.csi.driver", true),
+				},
+			}, nil)
+
+			byNode, err := detector.GetVolumeAttachmentsByNode(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(byNode).To(HaveLen(3))
+
+			Expect(byNode["node-1"]).To(HaveLen(2))
+			Expect(byNode["node-1"][0].Name).To(Equal("va-a"))
+			Expect(byNode["node-1"][1].Name).To(Equal("va-b"))
+
+			Expect(byNode["node-2"]).To(HaveLen(2))
+			Expect(byNode["node-2"][0].Name).To(Equal("va-attach-error"))
+			Expect(byNode["node-2"][0].AttachError).To(Equal("rpc error: volume in use"))
+			Expect(byNode["node-2"][0].Attached).To(BeFalse())
+			Expect(byNode["node-2"][1].DetachError).To(Equal("rpc error: device busy"))
+			Expect(byNode["node-2"][1].Attached).To(BeTrue())
+
+			Expect(byNode["node-3"]).To(HaveLen(1))
+			Expect(byNode["node-3"][0].Name).To(Equal("va-stuck"))
+			Expect(byNode["node-3"][0].Attached).To(BeFalse())
+			Expect(byNode["node-3"][0].Driver).To(Equal(targetDriver))
+		})
+
+		It("should return an error when VolumeAttachments cannot be listed", func() {
+			detector = detect.NewVolumeAttachmentDetector(mockClient, "")
+			mockVolumeAttachments.EXPECT().List(ctx, metav1.ListOptions{}).Return(nil, &testError{msg: "forbidden"})
+
+			_, err := detector.GetVolumeAttachmentsByNode(ctx)
+			Expect(err).To(MatchError(ContainSubstring("failed to list VolumeAttachments")))
+		})
 	})
 
 	Context("Severity Calculation", func() {
@@ -514,7 +1162,7 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 		})
 
 		It("should exercise private functions through inline volume specs", func() {
-			// This test exercises getDriverName, getVolumeHandle, and matchesDriver
+			// This test exercises resolveDriver and getVolumeHandle
 			// functions indirectly by using inline volume specs
 			vaList := &storagev1.VolumeAttachmentList{
 				Items: []storagev1.VolumeAttachment{
@@ -555,12 +1203,12 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 			// Verify the private functions worked correctly
 			issue := issues[0]
 			Expect(issue.Volume).To(Equal("inline-vol-123")) // getVolumeHandle worked
-			Expect(issue.Driver).To(Equal("test.csi.driver")) // getDriverName worked
-			// matchesDriver worked (issue was detected, not filtered out)
+			Expect(issue.Driver).To(Equal("test.csi.driver")) // resolveDriver worked
+			// driver filtering worked (issue was detected, not filtered out)
 		})
 
 		It("should filter out volumes from different drivers using inline specs", func() {
-			// This exercises the matchesDriver function to return false
+			// This exercises driver filtering with a non-matching inline driver
 			vaList := &storagev1.VolumeAttachmentList{
 				Items: []storagev1.VolumeAttachment{
 					{
@@ -595,11 +1243,11 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 
 			issues, err := detector.Detect(ctx)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(issues).To(BeEmpty()) // Should be filtered out by matchesDriver
+			Expect(issues).To(BeEmpty()) // Should be filtered out by driver
 		})
 
-		It("should handle missing inline volume spec in getDriverName", func() {
-			// This exercises getDriverName with no inline spec - should use fallback
+		It("should fall back to the attacher when the PV cannot be read", func() {
+			// This exercises resolveDriver with no inline spec and no PV - should use fallback
 			vaList := &storagev1.VolumeAttachmentList{
 				Items: []storagev1.VolumeAttachment{
 					{
@@ -629,13 +1277,13 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(issues).To(HaveLen(1))
 			
-			// Should use target driver as fallback for getDriverName
+			// Should use the attacher as fallback for resolveDriver
 			issue := issues[0]
 			Expect(issue.Driver).To(Equal("test.csi.driver"))
 			Expect(issue.Volume).To(Equal("pv-only-vol"))
 		})
 
-		It("should handle detector without target driver in getDriverName", func() {
+		It("should handle detector without target driver in resolveDriver", func() {
 			// Create detector without target driver
 			noTargetDetector := detect.NewVolumeAttachmentDetector(mockClient, "")
 			
@@ -668,7 +1316,7 @@ var _ = Describe("VolumeAttachmentDetector", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(issues).To(HaveLen(1))
 			
-			// Driver should come from Attacher field, not getDriverName function
+			// Driver should come from Attacher field, as the PV does not exist
 			issue := issues[0]
 			Expect(issue.Driver).To(Equal("any.csi.driver"))
 			Expect(issue.Volume).To(Equal("no-target-vol"))
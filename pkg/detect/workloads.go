@@ -0,0 +1,145 @@
+package detect
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// maxWorkloadsInRollup caps how many workloads are listed in recommendations
+const maxWorkloadsInRollup = 10
+
+// PodOwner returns the kind and name of the workload that controls a pod.
+// Pods owned by a ReplicaSet are attributed to the parent Deployment using the
+// pod-template-hash label, avoiding an extra API lookup. Bare pods return "Pod".
+func PodOwner(pod *corev1.Pod) (string, string) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "Pod", pod.Name
+	}
+
+	if ref.Kind == "ReplicaSet" {
+		if hash := pod.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(ref.Name, "-"+hash) {
+			return "Deployment", strings.TrimSuffix(ref.Name, "-"+hash)
+		}
+	}
+
+	return ref.Kind, ref.Name
+}
+
+// workloadRollup maps issues with a resolvable PVC to the workloads consuming it,
+// returning the most affected workloads ordered by issue count. Only pods in the scanned
+// namespace are read, a page at a time.
+func (d *Detector) workloadRollup(ctx context.Context, issues []types.CSIMountIssue) ([]types.AffectedWorkload, error) {
+	// Index consuming pods by PVC key (namespace/name)
+	podsByPVC := make(map[string][]*corev1.Pod)
+	err := listPods(ctx, d.client.CoreV1().Pods(d.options.Namespace), func(pods *corev1.PodList) error {
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			for _, volume := range pod.Spec.Volumes {
+				if volume.PersistentVolumeClaim != nil {
+					pvcKey := fmt.Sprintf("%s/%s", pod.Namespace, volume.PersistentVolumeClaim.ClaimName)
+					podsByPVC[pvcKey] = append(podsByPVC[pvcKey], pod)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workloads := make(map[string]*types.AffectedWorkload)
+	for _, issue := range issues {
+		pvcKey := issuePVCKey(issue)
+		if pvcKey == "" {
+			continue
+		}
+
+		// Count each issue once per workload, however many replicas share the PVC
+		counted := make(map[string]bool)
+		for _, pod := range podsByPVC[pvcKey] {
+			kind, name := PodOwner(pod)
+			key := fmt.Sprintf("%s/%s/%s", kind, pod.Namespace, name)
+			if counted[key] {
+				continue
+			}
+			counted[key] = true
+
+			if workloads[key] == nil {
+				workloads[key] = &types.AffectedWorkload{Kind: kind, Namespace: pod.Namespace, Name: name}
+			}
+			workloads[key].IssueCount++
+		}
+	}
+
+	var result []types.AffectedWorkload
+	for _, workload := range workloads {
+		result = append(result, *workload)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].IssueCount != result[j].IssueCount {
+			return result[i].IssueCount > result[j].IssueCount
+		}
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	if len(result) > maxWorkloadsInRollup {
+		result = result[:maxWorkloadsInRollup]
+	}
+	return result, nil
+}
+
+// workloadRecommendations renders the workload rollup as a recommendations section
+func workloadRecommendations(workloads []types.AffectedWorkload) []string {
+	if len(workloads) == 0 {
+		return nil
+	}
+
+	recommendations := []string{"\n## Affected Workloads"}
+	for _, workload := range workloads {
+		recommendations = append(recommendations, fmt.Sprintf("- %s %s/%s: %d issue(s)",
+			workload.Kind, workload.Namespace, workload.Name, workload.IssueCount))
+	}
+	return recommendations
+}
+
+// issuePVCKey returns the namespace/name key of the PVC an issue refers to, if known
+func issuePVCKey(issue types.CSIMountIssue) string {
+	if issue.PVC == "" {
+		return ""
+	}
+	if strings.Contains(issue.PVC, "/") {
+		return issue.PVC
+	}
+	if issue.Namespace == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", issue.Namespace, issue.PVC)
+}
+
+// AffectedPVCs returns the distinct namespace/name keys of the PVCs named in issues, sorted.
+// PVCs whose namespace cannot be determined are left out.
+func AffectedPVCs(issues []types.CSIMountIssue) []string {
+	seen := make(map[string]bool)
+	var pvcs []string
+	for _, issue := range issues {
+		key := issuePVCKey(issue)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		pvcs = append(pvcs, key)
+	}
+	sort.Strings(pvcs)
+	return pvcs
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// DefaultJob is the Pushgateway job of scans whose kubeconfig context is unknown, such as
+// scans running in-cluster
+const DefaultJob = "kubectl-csi-scan"
+
+// JobName returns the Pushgateway job for scans of a kubeconfig context, so that pushes
+// from different clusters do not replace each other
+func JobName(kubeContext string) string {
+	if kubeContext == "" {
+		return DefaultJob
+	}
+	return DefaultJob + "-" + kubeContext
+}
+
+// PushgatewayExporter pushes detection metrics to a Prometheus Pushgateway, so issue
+// counts can be tracked over time from one-off or scheduled scans
+type PushgatewayExporter struct {
+	url string
+	job string
+}
+
+// NewPushgatewayExporter creates an exporter pushing to the Pushgateway at url under job
+func NewPushgatewayExporter(url, job string) *PushgatewayExporter {
+	return &PushgatewayExporter{url: url, job: job}
+}
+
+// Push replaces the metrics of the exporter's job with those of result. Every severity and
+// issue type combination found is pushed; combinations without issues are left out rather
+// than pushed as zero.
+func (e *PushgatewayExporter) Push(ctx context.Context, result *types.DetectionResult, duration time.Duration) error {
+	issues := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "csi_scan_issues_total",
+		Help: "CSI mount issues found by the last scan, by severity and issue type.",
+	}, []string{"severity", "type"})
+	affectedNodes := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "csi_scan_affected_nodes",
+		Help: "Nodes with at least one CSI mount issue in the last scan.",
+	})
+	runDuration := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "csi_scan_run_duration_seconds",
+		Help: "How long the last scan took.",
+	})
+
+	for _, issue := range result.Issues {
+		issues.WithLabelValues(string(issue.Severity), string(issue.Type)).Inc()
+	}
+	affectedNodes.Set(float64(len(result.Summary.AffectedNodes)))
+	runDuration.Set(duration.Seconds())
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(issues, affectedNodes, runDuration)
+
+	if err := push.New(e.url, e.job).Gatherer(registry).PushContext(ctx); err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %w", e.url, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package export_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/export"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("PushgatewayExporter", func() {
+	var (
+		server   *httptest.Server
+		method   string
+		path     string
+		families map[string]*dto.MetricFamily
+		status   int
+	)
+
+	BeforeEach(func() {
+		families = make(map[string]*dto.MetricFamily)
+		status = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			path = r.URL.Path
+			decoder := expfmt.NewDecoder(r.Body, expfmt.ResponseFormat(r.Header))
+			for {
+				family := &dto.MetricFamily{}
+				if err := decoder.Decode(family); err != nil {
+					if !errors.Is(err, io.EOF) {
+						http.Error(w, err.Error(), http.StatusBadRequest)
+						return
+					}
+					break
+				}
+				families[family.GetName()] = family
+			}
+			w.WriteHeader(status)
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	labels := func(metric *dto.Metric) map[string]string {
+		values := make(map[string]string)
+		for _, pair := range metric.GetLabel() {
+			values[pair.GetName()] = pair.GetValue()
+		}
+		return values
+	}
+
+	result := &types.DetectionResult{
+		Summary: types.DetectionSummary{AffectedNodes: []string{"node-1", "node-2"}},
+		Issues: []types.CSIMountIssue{
+			{Type: types.StuckVolumeAttachment, Severity: types.SeverityHigh, Node: "node-1"},
+			{Type: types.StuckVolumeAttachment, Severity: types.SeverityHigh, Node: "node-2"},
+			{Type: types.MultiAttachError, Severity: types.SeverityCritical, Node: "node-2"},
+		},
+	}
+
+	It("should push issue counts by severity and type, affected nodes and run duration under the job", func() {
+		exporter := export.NewPushgatewayExporter(server.URL, export.JobName("prod-east"))
+		Expect(exporter.Push(context.Background(), result, 1500*time.Millisecond)).To(Succeed())
+
+		Expect(method).To(Equal(http.MethodPut))
+		Expect(path).To(Equal("/metrics/job/kubectl-csi-scan-prod-east"))
+		Expect(families).To(HaveKey("csi_scan_issues_total"))
+		Expect(families).To(HaveKey("csi_scan_affected_nodes"))
+		Expect(families).To(HaveKey("csi_scan_run_duration_seconds"))
+
+		counts := make(map[string]float64)
+		for _, metric := range families["csi_scan_issues_total"].GetMetric() {
+			l := labels(metric)
+			counts[l["severity"]+"/"+l["type"]] = metric.GetGauge().GetValue()
+		}
+		Expect(counts).To(Equal(map[string]float64{
+			"high/stuck-volume-attachment":               2,
+			"critical/" + string(types.MultiAttachError): 1,
+		}))
+		Expect(families["csi_scan_affected_nodes"].GetMetric()[0].GetGauge().GetValue()).To(Equal(2.0))
+		Expect(families["csi_scan_run_duration_seconds"].GetMetric()[0].GetGauge().GetValue()).To(Equal(1.5))
+	})
+
+	It("should report a Pushgateway that rejects the push", func() {
+		status = http.StatusInternalServerError
+		exporter := export.NewPushgatewayExporter(server.URL, export.DefaultJob)
+		err := exporter.Push(context.Background(), result, time.Second)
+		Expect(err).To(MatchError(ContainSubstring("failed to push metrics to " + server.URL)))
+	})
+
+	Describe("JobName", func() {
+		It("should derive the job from the kubeconfig context", func() {
+			Expect(export.JobName("prod-east")).To(Equal("kubectl-csi-scan-prod-east"))
+		})
+
+		It("should fall back to the default job without a context", func() {
+			Expect(export.JobName("")).To(Equal(export.DefaultJob))
+		})
+	})
+})
@@ -0,0 +1,100 @@
+package multicluster
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// DefaultClusterTimeout is how long detection may run against a single cluster
+const DefaultClusterTimeout = 2 * time.Minute
+
+// ClusterResult is the detection result for one kubeconfig context. A cluster that timed
+// out or was interrupted can carry both a partial result and an error.
+type ClusterResult struct {
+	Context string                 `json:"context"`
+	Result  *types.DetectionResult `json:"result,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+	Err     error                  `json:"-"`
+}
+
+// ScanFunc runs detection against the cluster of one kubeconfig context
+type ScanFunc func(ctx context.Context, kubeContext string) (*types.DetectionResult, error)
+
+// Options configures a multi-cluster scan
+type Options struct {
+	Concurrency int                 // clusters scanned at once; values below 1 scan in series
+	Timeout     time.Duration       // per-cluster timeout; zero uses DefaultClusterTimeout
+	OnResult    func(ClusterResult) // called as each cluster finishes, never concurrently
+}
+
+// Scan runs scan against every context, at most opts.Concurrency at a time and each under
+// its own timeout. A failing cluster is recorded in its result without stopping the
+// others. Results are returned in the order of contexts.
+func Scan(ctx context.Context, contexts []string, opts Options, scan ScanFunc) []ClusterResult {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultClusterTimeout
+	}
+
+	results := make([]ClusterResult, len(contexts))
+	slots := make(chan struct{}, concurrency)
+	var (
+		wg       sync.WaitGroup
+		reportMu sync.Mutex
+	)
+
+	for i, kubeContext := range contexts {
+		wg.Add(1)
+		go func(i int, kubeContext string) {
+			defer wg.Done()
+
+			result := scanCluster(ctx, kubeContext, timeout, slots, scan)
+			results[i] = result
+
+			if opts.OnResult != nil {
+				reportMu.Lock()
+				opts.OnResult(result)
+				reportMu.Unlock()
+			}
+		}(i, kubeContext)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// scanCluster waits for a free slot and scans one cluster under its timeout
+func scanCluster(ctx context.Context, kubeContext string, timeout time.Duration, slots chan struct{}, scan ScanFunc) ClusterResult {
+	if err := ctx.Err(); err != nil {
+		return newClusterResult(kubeContext, nil, err)
+	}
+
+	select {
+	case slots <- struct{}{}:
+		defer func() { <-slots }()
+	case <-ctx.Done():
+		return newClusterResult(kubeContext, nil, ctx.Err())
+	}
+
+	clusterCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := scan(clusterCtx, kubeContext)
+	return newClusterResult(kubeContext, result, err)
+}
+
+// newClusterResult tags a detection outcome with its context
+func newClusterResult(kubeContext string, result *types.DetectionResult, err error) ClusterResult {
+	clusterResult := ClusterResult{Context: kubeContext, Result: result, Err: err}
+	if err != nil {
+		clusterResult.Error = err.Error()
+	}
+	return clusterResult
+}
@@ -0,0 +1,106 @@
+package multicluster_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/multicluster"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("Scan", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should scan every context with bounded concurrency and isolate failures", func() {
+		var running, maxRunning int32
+		scan := func(ctx context.Context, kubeContext string) (*types.DetectionResult, error) {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				seen := atomic.LoadInt32(&maxRunning)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxRunning, seen, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			if kubeContext == "staging" {
+				return nil, errors.New("connection refused")
+			}
+			return &types.DetectionResult{
+				Issues: []types.CSIMountIssue{{Node: kubeContext + "-node"}},
+			}, nil
+		}
+
+		var finished []string
+		results := multicluster.Scan(ctx, []string{"prod-east", "staging", "prod-west"}, multicluster.Options{
+			Concurrency: 2,
+			Timeout:     time.Second,
+			OnResult: func(result multicluster.ClusterResult) {
+				finished = append(finished, result.Context)
+			},
+		}, scan)
+
+		Expect(results).To(HaveLen(3))
+		Expect(finished).To(ConsistOf("prod-east", "staging", "prod-west"))
+		Expect(maxRunning).To(BeNumerically("<=", 2))
+
+		Expect(results[0].Context).To(Equal("prod-east"))
+		Expect(results[0].Err).NotTo(HaveOccurred())
+		Expect(results[0].Result.Issues[0].Node).To(Equal("prod-east-node"))
+
+		Expect(results[1].Context).To(Equal("staging"))
+		Expect(results[1].Err).To(MatchError("connection refused"))
+		Expect(results[1].Error).To(Equal("connection refused"))
+		Expect(results[1].Result).To(BeNil())
+
+		Expect(results[2].Context).To(Equal("prod-west"))
+		Expect(results[2].Err).NotTo(HaveOccurred())
+		Expect(results[2].Result.Issues[0].Node).To(Equal("prod-west-node"))
+	})
+
+	It("should apply the timeout to each cluster separately", func() {
+		scan := func(ctx context.Context, kubeContext string) (*types.DetectionResult, error) {
+			if kubeContext == "slow" {
+				<-ctx.Done()
+				return &types.DetectionResult{Partial: true}, ctx.Err()
+			}
+			return &types.DetectionResult{}, nil
+		}
+
+		results := multicluster.Scan(ctx, []string{"slow", "fast"}, multicluster.Options{
+			Concurrency: 2,
+			Timeout:     50 * time.Millisecond,
+		}, scan)
+
+		Expect(results[0].Err).To(MatchError(context.DeadlineExceeded))
+		Expect(results[0].Result.Partial).To(BeTrue())
+		Expect(results[1].Err).NotTo(HaveOccurred())
+	})
+
+	It("should not start clusters once the scan is cancelled", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		var scanned int32
+		results := multicluster.Scan(cancelled, []string{"a", "b"}, multicluster.Options{Concurrency: 1}, func(ctx context.Context, kubeContext string) (*types.DetectionResult, error) {
+			atomic.AddInt32(&scanned, 1)
+			return &types.DetectionResult{}, ctx.Err()
+		})
+
+		Expect(results).To(HaveLen(2))
+		for _, result := range results {
+			Expect(result.Err).To(MatchError(context.Canceled))
+		}
+		Expect(scanned).To(BeZero())
+	})
+})
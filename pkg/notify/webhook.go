@@ -0,0 +1,198 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// maxTopIssues caps how many individual issues are included in a notification
+const maxTopIssues = 5
+
+// maxTopNodes caps how many affected nodes are named in a notification
+const maxTopNodes = 5
+
+// maxIssueDescription caps how many characters of an issue description a notification
+// quotes, since kubelet error messages can run to several kilobytes
+const maxIssueDescription = 300
+
+// maxSectionText is the most text Slack accepts in a section block; longer payloads are
+// rejected as a whole
+const maxSectionText = 3000
+
+// mrkdwnEscaper escapes the characters Slack reads as mrkdwn control sequences
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// WebhookNotifier posts detection summaries to a Slack-compatible incoming webhook
+type WebhookNotifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a notifier for the given webhook URL
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SlackMessage is the payload accepted by Slack incoming webhooks
+type SlackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []SlackBlock `json:"blocks,omitempty"`
+}
+
+// SlackBlock is a Block Kit layout block
+type SlackBlock struct {
+	Type   string      `json:"type"`
+	Text   *SlackText  `json:"text,omitempty"`
+	Fields []SlackText `json:"fields,omitempty"`
+}
+
+// SlackText is a Block Kit text object
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Notify posts a summary of the result if any issue meets the severity floor.
+// It reports whether a notification was sent.
+func (n *WebhookNotifier) Notify(ctx context.Context, result *types.DetectionResult, notifyOn types.IssueSeverity) (bool, error) {
+	var matching []types.CSIMountIssue
+	for _, issue := range result.Issues {
+		if issue.Severity.Level() >= notifyOn.Level() {
+			matching = append(matching, issue)
+		}
+	}
+	if len(matching) == 0 {
+		return false, nil
+	}
+
+	body, err := json.Marshal(BuildSlackMessage(result, matching))
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return false, fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	return true, nil
+}
+
+// BuildSlackMessage formats the detection summary, the nodes with the most of the given
+// issues and the most severe of them as a Slack message. Issue descriptions are shortened
+// and escaped so the message stays within Slack's limits.
+func BuildSlackMessage(result *types.DetectionResult, issues []types.CSIMountIssue) SlackMessage {
+	summary := result.Summary
+	text := fmt.Sprintf("CSI mount scan found %d issue(s) on %d node(s)", summary.TotalIssues, len(summary.AffectedNodes))
+
+	var severityFields []SlackText
+	for _, severity := range types.Severities {
+		severityFields = append(severityFields, SlackText{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*%s:* %d", severity, summary.IssuesBySeverity[severity]),
+		})
+	}
+
+	blocks := []SlackBlock{
+		{Type: "header", Text: &SlackText{Type: "plain_text", Text: "CSI mount issues detected"}},
+		{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: text}},
+		{Type: "section", Fields: severityFields},
+	}
+
+	if nodes := topNodes(issues); nodes != "" {
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{Type: "mrkdwn", Text: "*Top affected nodes:* " + nodes},
+		})
+	}
+
+	// Most severe first; stable so detection order breaks ties
+	top := append([]types.CSIMountIssue(nil), issues...)
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].Severity.Level() > top[j].Severity.Level()
+	})
+	if len(top) > maxTopIssues {
+		top = top[:maxTopIssues]
+	}
+
+	section := "*Top issues:*"
+	for _, issue := range top {
+		line := fmt.Sprintf("• [%s] %s: %s", issue.Severity, issue.Type,
+			mrkdwnEscaper.Replace(truncate(issue.Description, maxIssueDescription)))
+		if len(section)+1+len(line) > maxSectionText {
+			break
+		}
+		section += "\n" + line
+	}
+	blocks = append(blocks, SlackBlock{
+		Type: "section",
+		Text: &SlackText{Type: "mrkdwn", Text: section},
+	})
+
+	return SlackMessage{Text: text, Blocks: blocks}
+}
+
+// truncate shortens text to at most limit characters, marking the cut with an ellipsis
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// topNodes lists the nodes with the most issues, with their issue counts, as
+// "node-1 (3), node-2 (1)". Nodes beyond maxTopNodes are only counted.
+func topNodes(issues []types.CSIMountIssue) string {
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		if issue.Node != "" {
+			counts[issue.Node]++
+		}
+	}
+
+	nodes := make([]string, 0, len(counts))
+	for node := range counts {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if counts[nodes[i]] != counts[nodes[j]] {
+			return counts[nodes[i]] > counts[nodes[j]]
+		}
+		return nodes[i] < nodes[j]
+	})
+
+	var parts []string
+	for i, node := range nodes {
+		if i == maxTopNodes {
+			parts = append(parts, fmt.Sprintf("and %d more", len(nodes)-maxTopNodes))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d)", node, counts[node]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,188 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/notify"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("WebhookNotifier", func() {
+	var (
+		server   *httptest.Server
+		requests []*http.Request
+		payloads []notify.SlackMessage
+		status   int
+		result   *types.DetectionResult
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		payloads = nil
+		status = http.StatusOK
+		ctx = context.Background()
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			var msg notify.SlackMessage
+			Expect(json.Unmarshal(body, &msg)).To(Succeed())
+			requests = append(requests, r)
+			payloads = append(payloads, msg)
+
+			w.WriteHeader(status)
+			w.Write([]byte("ok"))
+		}))
+
+		result = &types.DetectionResult{
+			Summary: types.DetectionSummary{
+				TotalIssues: 2,
+				IssuesBySeverity: map[types.IssueSeverity]int{
+					types.SeverityCritical: 1,
+					types.SeverityMedium:   1,
+				},
+				AffectedNodes: []string{"node-1", "node-2"},
+			},
+			Issues: []types.CSIMountIssue{
+				{
+					Type:        types.StuckVolumeAttachment,
+					Severity:    types.SeverityMedium,
+					Node:        "node-2",
+					Description: "Volume stuck attaching",
+				},
+				{
+					Type:        types.MultipleAttachments,
+					Severity:    types.SeverityCritical,
+					Node:        "node-1",
+					Description: "Volume attached to multiple nodes",
+				},
+			},
+			GeneratedAt: time.Now(),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should post a Slack-formatted summary", func() {
+		sent, err := notify.NewWebhookNotifier(server.URL).Notify(ctx, result, types.SeverityLow)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sent).To(BeTrue())
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Method).To(Equal(http.MethodPost))
+		Expect(requests[0].Header.Get("Content-Type")).To(Equal("application/json"))
+
+		msg := payloads[0]
+		Expect(msg.Text).To(Equal("CSI mount scan found 2 issue(s) on 2 node(s)"))
+		Expect(msg.Blocks[0].Type).To(Equal("header"))
+
+		var severityFields []string
+		for _, field := range msg.Blocks[2].Fields {
+			severityFields = append(severityFields, field.Text)
+		}
+		Expect(severityFields).To(Equal([]string{"*critical:* 1", "*high:* 0", "*medium:* 1", "*low:* 0"}))
+
+		Expect(msg.Blocks[3].Text.Text).To(Equal("*Top affected nodes:* node-1 (1), node-2 (1)"))
+		Expect(msg.Blocks[4].Text.Text).To(Equal("*Top issues:*\n" +
+			"• [critical] multiple-attachments: Volume attached to multiple nodes\n" +
+			"• [medium] stuck-volume-attachment: Volume stuck attaching"))
+	})
+
+	It("should name the nodes with the most issues first", func() {
+		result.Issues = nil
+		for i, node := range []string{"node-a", "node-b", "node-b", "node-c", "node-d", "node-e", "node-f", "node-f", "node-f", "node-g"} {
+			result.Issues = append(result.Issues, types.CSIMountIssue{
+				Type:        types.DeviceBusy,
+				Severity:    types.SeverityHigh,
+				Node:        node,
+				Description: fmt.Sprintf("Device busy %d", i),
+			})
+		}
+
+		_, err := notify.NewWebhookNotifier(server.URL).Notify(ctx, result, types.SeverityLow)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(payloads[0].Blocks[3].Text.Text).To(Equal(
+			"*Top affected nodes:* node-f (3), node-b (2), node-a (1), node-c (1), node-d (1), and 2 more"))
+	})
+
+	It("should escape and shorten issue descriptions to fit a Slack section", func() {
+		result.Issues = nil
+		for i := 0; i < 5; i++ {
+			result.Issues = append(result.Issues, types.CSIMountIssue{
+				Type:        types.DeviceBusy,
+				Severity:    types.SeverityHigh,
+				Node:        "node-1",
+				Description: fmt.Sprintf("umount <%d> failed & retried: %s", i, strings.Repeat("x", 5000)),
+			})
+		}
+
+		_, err := notify.NewWebhookNotifier(server.URL).Notify(ctx, result, types.SeverityLow)
+		Expect(err).NotTo(HaveOccurred())
+
+		section := payloads[0].Blocks[4].Text.Text
+		Expect(len(section)).To(BeNumerically("<=", 3000))
+		lines := strings.Split(section, "\n")
+		Expect(lines).To(HaveLen(6))
+		Expect(lines[1]).To(HavePrefix("• [high] device-busy: umount &lt;0&gt; failed &amp; retried: xxx"))
+		Expect(lines[1]).To(HaveSuffix("x…"))
+	})
+
+	It("should leave out issues that would take the section past Slack's limit", func() {
+		result.Issues = nil
+		for i := 0; i < 5; i++ {
+			result.Issues = append(result.Issues, types.CSIMountIssue{
+				Type:        types.DeviceBusy,
+				Severity:    types.SeverityHigh,
+				Node:        "node-1",
+				Description: strings.Repeat("&", 5000),
+			})
+		}
+
+		_, err := notify.NewWebhookNotifier(server.URL).Notify(ctx, result, types.SeverityLow)
+		Expect(err).NotTo(HaveOccurred())
+
+		section := payloads[0].Blocks[4].Text.Text
+		Expect(len(section)).To(BeNumerically("<=", 3000))
+		Expect(strings.Split(section, "\n")).To(HaveLen(2))
+	})
+
+	It("should only include issues meeting the severity floor", func() {
+		sent, err := notify.NewWebhookNotifier(server.URL).Notify(ctx, result, types.SeverityHigh)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sent).To(BeTrue())
+
+		Expect(payloads[0].Blocks[4].Text.Text).NotTo(ContainSubstring("stuck-volume-attachment"))
+		// node-2 only has the medium issue
+		Expect(payloads[0].Blocks[3].Text.Text).To(Equal("*Top affected nodes:* node-1 (1)"))
+	})
+
+	It("should not post when no issue meets the severity floor", func() {
+		result.Issues = result.Issues[:1]
+
+		sent, err := notify.NewWebhookNotifier(server.URL).Notify(ctx, result, types.SeverityHigh)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sent).To(BeFalse())
+		Expect(requests).To(BeEmpty())
+	})
+
+	It("should return an error for non-2xx responses", func() {
+		status = http.StatusForbidden
+
+		_, err := notify.NewWebhookNotifier(server.URL).Notify(ctx, result, types.SeverityLow)
+		Expect(err).To(MatchError(ContainSubstring("webhook returned 403 Forbidden: ok")))
+	})
+})
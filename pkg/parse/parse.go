@@ -0,0 +1,167 @@
+package parse
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// trimChars is the punctuation stripped from unquoted words pulled out of messages
+const trimChars = "\"',.()[]:"
+
+// knownDrivers are matched before the generic CSI driver pattern so the
+// canonical name wins when a message contains several driver-like strings
+var knownDrivers = []string{
+	"cinder.csi.openstack.org",
+	"rook-ceph.rbd.csi.ceph.com",
+	"rook-ceph.cephfs.csi.ceph.com",
+	"ebs.csi.aws.com",
+	"disk.csi.azure.com",
+	"pd.csi.storage.gke.io",
+}
+
+var (
+	// pvcHandleRegex matches dynamically provisioned volume names such as pvc-1a2b3c
+	pvcHandleRegex = regexp.MustCompile(`(?:^|[\s"'(\[=/])(pvc-[A-Za-z0-9-]+)`)
+
+	// quotedVolumeRegex matches volume "name" and its handle/id variants
+	quotedVolumeRegex = regexp.MustCompile(`\b(?:[Vv]olume|volumeHandle|volumeId|volume_id) "([^"]*)"`)
+
+	// volumeKeywordRegex matches an unquoted word following a volume keyword
+	volumeKeywordRegex = regexp.MustCompile(`(?i)(?:^|\s)(?:volume|volumehandle|volumeid)\s+(\S+)`)
+
+	// volumeListRegex matches kubelet's volumes=[name ...] lists
+	volumeListRegex = regexp.MustCompile(`volumes=\[([^\]\s"',()]+)`)
+
+	// csiDriverRegex matches CSI driver names such as test.csi.example.com
+	csiDriverRegex = regexp.MustCompile(`(?:^|[^a-z0-9.-])((?:[a-z0-9-]+\.)*csi(?:\.[a-z0-9-]+)+)`)
+
+	// quotedNodeRegex matches node "name"
+	quotedNodeRegex = regexp.MustCompile(`[Nn]ode "([^"]*)"`)
+
+	// nodeKeywordRegex matches an unquoted word following node
+	nodeKeywordRegex = regexp.MustCompile(`(?:^|\s)[Nn]ode\s+(\S+)`)
+
+	// quotedPVCRegex matches pvc "name", claim "name" and persistentvolumeclaim "name"
+	quotedPVCRegex = regexp.MustCompile(`(?i)\b(?:pvc|persistentvolumeclaim|claim) "([^"]*)"`)
+
+	// pvcKeywordRegex matches an unquoted word following pvc or claim
+	pvcKeywordRegex = regexp.MustCompile(`(?i)(?:^|\s)(?:pvc|claim)\s+(\S+)`)
+)
+
+// ExtractVolume returns the volume name or handle referenced in an event message,
+// or an empty string if none is found
+func ExtractVolume(message string) string {
+	// Dynamically provisioned PV names are the most common and least ambiguous
+	if m := pvcHandleRegex.FindStringSubmatch(message); m != nil {
+		return m[1]
+	}
+
+	for _, m := range quotedVolumeRegex.FindAllStringSubmatch(message, -1) {
+		if isVolumeName(m[1]) {
+			return m[1]
+		}
+	}
+
+	for _, m := range volumeKeywordRegex.FindAllStringSubmatch(message, -1) {
+		if word := strings.Trim(m[1], trimChars); isVolumeName(word) {
+			return word
+		}
+	}
+
+	if m := volumeListRegex.FindStringSubmatch(message); m != nil {
+		if volume := strings.Trim(m[1], trimChars); isVolumeName(volume) {
+			return volume
+		}
+	}
+
+	// Fall back to anything shaped like an opaque volume handle
+	for _, word := range strings.Fields(message) {
+		if word = strings.Trim(word, trimChars); looksLikeVolumeHandle(word) {
+			return word
+		}
+	}
+
+	return ""
+}
+
+// ExtractDriver returns the CSI driver name referenced in an event message,
+// or an empty string if none is found
+func ExtractDriver(message string) string {
+	if drivers := ExtractDrivers(message); len(drivers) > 0 {
+		return drivers[0]
+	}
+	return ""
+}
+
+// ExtractDrivers returns every CSI driver name referenced in an event message, each once:
+// known drivers first, then any other name containing a csi label, such as
+// csi.vsphere.vmware.com or nfs.csi.k8s.io
+func ExtractDrivers(message string) []string {
+	var drivers []string
+	for _, driver := range knownDrivers {
+		if strings.Contains(message, driver) {
+			drivers = append(drivers, driver)
+		}
+	}
+
+	for _, m := range csiDriverRegex.FindAllStringSubmatch(message, -1) {
+		// Plugin socket paths like .../csi.sock are not driver names
+		if !strings.HasSuffix(m[1], ".sock") && !slices.Contains(drivers, m[1]) {
+			drivers = append(drivers, m[1])
+		}
+	}
+
+	return drivers
+}
+
+// ExtractNode returns the node name referenced in an event message,
+// or an empty string if none is found
+func ExtractNode(message string) string {
+	if m := quotedNodeRegex.FindStringSubmatch(message); m != nil {
+		return m[1]
+	}
+
+	for _, m := range nodeKeywordRegex.FindAllStringSubmatch(message, -1) {
+		if node := strings.Trim(m[1], trimChars); node != "" {
+			return node
+		}
+	}
+
+	return ""
+}
+
+// ExtractPVC returns the PersistentVolumeClaim name referenced in an event message,
+// or an empty string if none is found
+func ExtractPVC(message string) string {
+	if m := quotedPVCRegex.FindStringSubmatch(message); m != nil {
+		return m[1]
+	}
+
+	for _, m := range pvcKeywordRegex.FindAllStringSubmatch(message, -1) {
+		if pvc := strings.Trim(m[1], trimChars); pvc != "" {
+			return pvc
+		}
+	}
+
+	return ""
+}
+
+// isVolumeName reports whether an extracted word is usable as a volume name
+func isVolumeName(name string) bool {
+	return name != "" && name != "unknown"
+}
+
+// looksLikeVolumeHandle reports whether a word resembles an opaque volume handle,
+// such as a cloud provider volume ID
+func looksLikeVolumeHandle(word string) bool {
+	if len(word) <= 10 {
+		return false
+	}
+	if !strings.ContainsAny(word, "0123456789") || !strings.ContainsAny(word, "abcdefghijklmnopqrstuvwxyz") {
+		return false
+	}
+
+	// Exclude projected service account token volumes
+	return !strings.Contains(word, "kube-api-access-") && !strings.Contains(word, "default-token-")
+}
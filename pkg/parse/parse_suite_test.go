@@ -0,0 +1,13 @@
+package parse_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestParse(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Parse Suite")
+}
@@ -0,0 +1,100 @@
+package parse_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/parse"
+)
+
+var _ = Describe("Parse", func() {
+	DescribeTable("ExtractVolume",
+		func(message, expected string) {
+			Expect(parse.ExtractVolume(message)).To(Equal(expected))
+		},
+		Entry("unquoted pvc handle", "AttachVolume.Attach failed for volume pvc-123abc", "pvc-123abc"),
+		Entry("quoted pvc handle", "Failed to mount volume \"pvc-456def\" on node", "pvc-456def"),
+		Entry("pvc handle with uuid", "Multi-Attach error for volume \"pvc-0a1b2c3d-4e5f-6789-abcd-ef0123456789\" Volume is already exclusively attached", "pvc-0a1b2c3d-4e5f-6789-abcd-ef0123456789"),
+		Entry("pvc handle in parentheses", "detach failed (pvc-789) timed out", "pvc-789"),
+		Entry("quoted volume name", "AttachVolume failed for volume \"my-volume\"", "my-volume"),
+		Entry("quoted Volume name", "Volume \"data-vol\" is busy", "data-vol"),
+		Entry("quoted volumeHandle", "NodeStage failed for volumeHandle \"vol-0abc\"", "vol-0abc"),
+		Entry("quoted volumeId", "ControllerPublish failed volumeId \"abc-1\"", "abc-1"),
+		Entry("quoted volume_id", "rpc error volume_id \"id-99\"", "id-99"),
+		Entry("skips empty quoted volume", "volume \"\" not found, volumeId \"real-id\"", "real-id"),
+		Entry("unquoted volume keyword", "Failed to detach volume data-disk: busy", "data-disk"),
+		Entry("skips unknown volume", "Detach failed for volume unknown", ""),
+		Entry("volumes list", "Unable to attach or mount volumes: unmounted volumes=[data], unattached volumes=[data]", "data"),
+		Entry("opaque volume handle", "Attach failed: vol-0123456789abcdef timed out", "vol-0123456789abcdef"),
+		Entry("ignores service account tokens", "MountVolume.SetUp failed for kube-api-access-abc12", ""),
+		Entry("no volume found", "Some generic error message", ""),
+		Entry("does not match pvc suffix", "Mount failed for logs-pvc", ""),
+	)
+
+	DescribeTable("ExtractDriver",
+		func(message, expected string) {
+			Expect(parse.ExtractDriver(message)).To(Equal(expected))
+		},
+		Entry("EBS CSI driver", "AttachVolume failed: ebs.csi.aws.com error", "ebs.csi.aws.com"),
+		Entry("Ceph RBD driver", "Mount failed: rook-ceph.rbd.csi.ceph.com timeout", "rook-ceph.rbd.csi.ceph.com"),
+		Entry("Ceph FS driver", "rook-ceph.cephfs.csi.ceph.com not ready", "rook-ceph.cephfs.csi.ceph.com"),
+		Entry("Azure disk driver", "Volume error: disk.csi.azure.com unavailable", "disk.csi.azure.com"),
+		Entry("GKE PD driver", "pd.csi.storage.gke.io: quota exceeded", "pd.csi.storage.gke.io"),
+		Entry("Cinder driver", "cinder.csi.openstack.org attach timeout", "cinder.csi.openstack.org"),
+		Entry("generic driver", "AttachVolume.Attach failed for volume: test.csi.driver error", "test.csi.driver"),
+		Entry("driver with csi prefix", "driver name csi.vsphere.vmware.com not found", "csi.vsphere.vmware.com"),
+		Entry("quoted driver", "driver name \"nfs.csi.k8s.io\" not found in the list of registered CSI drivers", "nfs.csi.k8s.io"),
+		Entry("known driver in socket path", "dial unix /var/lib/kubelet/plugins/ebs.csi.aws.com/csi.sock: connect refused", "ebs.csi.aws.com"),
+		Entry("ignores socket path", "dial unix /csi/csi.sock: connect refused", ""),
+		Entry("unknown driver", "Some generic volume error", ""),
+	)
+
+	DescribeTable("ExtractDrivers",
+		func(message string, expected []string) {
+			Expect(parse.ExtractDrivers(message)).To(Equal(expected))
+		},
+		Entry("known and other drivers", "ebs.csi.aws.com volume migrated from csi.vsphere.vmware.com", []string{"ebs.csi.aws.com", "csi.vsphere.vmware.com"}),
+		Entry("repeated driver", "nfs.csi.k8s.io: mount of nfs.csi.k8s.io share failed", []string{"nfs.csi.k8s.io"}),
+		Entry("ignores socket path", "dial unix /csi/csi.sock: connect refused", nil),
+		Entry("no driver", "Some generic volume error", nil),
+	)
+
+	DescribeTable("ExtractNode",
+		func(message, expected string) {
+			Expect(parse.ExtractNode(message)).To(Equal(expected))
+		},
+		Entry("quoted node", "Failed to attach volume to node \"worker-node-3\"", "worker-node-3"),
+		Entry("unquoted node", "Volume attachment failed on node worker-node-4 due to error", "worker-node-4"),
+		Entry("capital Node", "Volume error on Node worker-node-5", "worker-node-5"),
+		Entry("trailing punctuation", "Error on node worker-node-6:", "worker-node-6"),
+		Entry("node at start of message", "node worker-7 is not ready", "worker-7"),
+		Entry("no node found", "Volume failed", ""),
+		Entry("node keyword at end", "Failed to mount volume on node", ""),
+	)
+
+	DescribeTable("ExtractPVC",
+		func(message, expected string) {
+			Expect(parse.ExtractPVC(message)).To(Equal(expected))
+		},
+		Entry("quoted PVC", "Failed to mount volume from PVC \"data-pvc\"", "data-pvc"),
+		Entry("quoted pvc", "waiting for pvc \"web-pvc\" to bind", "web-pvc"),
+		Entry("quoted persistentvolumeclaim", "persistentvolumeclaim \"db-data\" not found", "db-data"),
+		Entry("quoted PersistentVolumeClaim", "PersistentVolumeClaim \"db-logs\" is being deleted", "db-logs"),
+		Entry("quoted claim", "bound to claim \"default/app\"", "default/app"),
+		Entry("unquoted claim", "Mount failed for claim cache-pvc", "cache-pvc"),
+		Entry("unquoted pvc", "Error with pvc logs-pvc", "logs-pvc"),
+		Entry("volume claim", "Failed to attach volume claim storage-pvc", "storage-pvc"),
+		Entry("no PVC found", "Volume failed", ""),
+	)
+
+	DescribeTable("Redact",
+		func(message, expected string) {
+			Expect(parse.Redact(message)).To(Equal(expected))
+		},
+		Entry("pvc handle", "Multi-Attach error for volume \"pvc-0a1b2c3d-4e5f-6789-abcd-ef0123456789\" Volume is already exclusively attached", "Multi-Attach error for volume \"pvc-0a1b...\" Volume is already exclusively attached"),
+		Entry("quoted volumeHandle", "NodeStage failed for volumeHandle \"vol-0abc1234def56789\": rpc error", "NodeStage failed for volumeHandle \"vol-0abc...\": rpc error"),
+		Entry("unquoted volume id", "detach failed for volume 3f1c9a52-77e4-4b1e-9d0a-5b2e8c6f1a90, retrying", "detach failed for volume 3f1c9a52..., retrying"),
+		Entry("short handle kept", "AttachVolume failed for volume \"data\"", "AttachVolume failed for volume \"data\""),
+		Entry("no handle", "context deadline exceeded", "context deadline exceeded"),
+	)
+})
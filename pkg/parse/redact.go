@@ -0,0 +1,62 @@
+package parse
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// redactKeep is how many leading characters of a volume handle survive redaction, enough
+// to tell volumes apart in a log without exposing the full identifier
+const redactKeep = 8
+
+// handleRegexes match volume handles in messages; group 1 is the handle
+var handleRegexes = []*regexp.Regexp{pvcHandleRegex, quotedVolumeRegex, volumeKeywordRegex}
+
+// ShortenHandle truncates a volume handle to its first few characters
+func ShortenHandle(handle string) string {
+	if len(handle) <= redactKeep {
+		return handle
+	}
+	return handle[:redactKeep] + "..."
+}
+
+// Redact shortens every volume handle found in a message, leaving the rest of the
+// message intact so it still explains what failed
+func Redact(message string) string {
+	type span struct{ start, end int }
+	var spans []span
+	for _, re := range handleRegexes {
+		for _, m := range re.FindAllStringSubmatchIndex(message, -1) {
+			start, end := m[2], m[3]
+			// Keep surrounding punctuation such as quotes or a trailing comma
+			for start < end && strings.ContainsRune(trimChars, rune(message[start])) {
+				start++
+			}
+			for end > start && strings.ContainsRune(trimChars, rune(message[end-1])) {
+				end--
+			}
+			if isVolumeName(message[start:end]) {
+				spans = append(spans, span{start, end})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return message
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		// The patterns can match the same handle more than once
+		if s.start < last {
+			continue
+		}
+		b.WriteString(message[last:s.start])
+		b.WriteString(ShortenHandle(message[s.start:s.end]))
+		last = s.end
+	}
+	b.WriteString(message[last:])
+	return b.String()
+}
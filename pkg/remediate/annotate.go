@@ -0,0 +1,81 @@
+package remediate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client"
+)
+
+// DefaultAnnotation is applied when no annotation is given, marking a PVC for follow-up
+const DefaultAnnotation = "kubectl-csi-scan/needs-attention=true"
+
+// PVCAnnotator applies an annotation to PersistentVolumeClaims, for example to trigger a
+// controller reconcile or to mark stuck claims for follow-up
+type PVCAnnotator struct {
+	client client.KubernetesClient
+	dryRun bool
+}
+
+// NewPVCAnnotator creates an annotator. In dry-run mode the API server validates each
+// patch without persisting it.
+func NewPVCAnnotator(kubeClient client.KubernetesClient, dryRun bool) *PVCAnnotator {
+	return &PVCAnnotator{
+		client: kubeClient,
+		dryRun: dryRun,
+	}
+}
+
+// Annotate sets key=value on the PVC namespace/name with a merge patch, leaving its other
+// annotations untouched
+func (a *PVCAnnotator) Annotate(ctx context.Context, pvc, key, value string) error {
+	namespace, name, err := SplitPVC(pvc)
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{key: value},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build annotation patch: %w", err)
+	}
+
+	opts := metav1.PatchOptions{}
+	if a.dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	if _, err := a.client.CoreV1().PersistentVolumeClaims(namespace).Patch(ctx, name, k8stypes.MergePatchType, patch, opts); err != nil {
+		return fmt.Errorf("failed to annotate PVC %s: %w", pvc, err)
+	}
+	return nil
+}
+
+// ParseAnnotation splits a key=value annotation and checks that the key is valid
+func ParseAnnotation(annotation string) (string, string, error) {
+	key, value, ok := strings.Cut(annotation, "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid annotation %q: must be key=value", annotation)
+	}
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid annotation key %q: %s", key, strings.Join(errs, "; "))
+	}
+	return key, value, nil
+}
+
+// SplitPVC splits a namespace/name PVC reference
+func SplitPVC(pvc string) (string, string, error) {
+	namespace, name, ok := strings.Cut(pvc, "/")
+	if !ok || namespace == "" || name == "" {
+		return "", "", fmt.Errorf("invalid PVC %q: must be namespace/name", pvc)
+	}
+	return namespace, name, nil
+}
@@ -0,0 +1,92 @@
+package remediate_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/client/mocks"
+	"github.com/jdambly/kubectl-csi-scan/pkg/remediate"
+)
+
+var _ = Describe("PVCAnnotator", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockClient *mocks.MockKubernetesClient
+		mockCoreV1 *mocks.MockCoreV1Interface
+		mockPVCs   *mocks.MockPersistentVolumeClaimInterface
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockClient = mocks.NewMockKubernetesClient(ctrl)
+		mockCoreV1 = mocks.NewMockCoreV1Interface(ctrl)
+		mockPVCs = mocks.NewMockPersistentVolumeClaimInterface(ctrl)
+		ctx = context.Background()
+
+		mockClient.EXPECT().CoreV1().Return(mockCoreV1).AnyTimes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should merge-patch the annotation onto the targeted PVC", func() {
+		mockCoreV1.EXPECT().PersistentVolumeClaims("default").Return(mockPVCs)
+		mockPVCs.EXPECT().Patch(ctx, "data-web-0", k8stypes.MergePatchType,
+			[]byte(`{"metadata":{"annotations":{"kubectl-csi-scan/needs-attention":"true"}}}`),
+			metav1.PatchOptions{},
+		).Return(&corev1.PersistentVolumeClaim{}, nil)
+
+		annotator := remediate.NewPVCAnnotator(mockClient, false)
+		Expect(annotator.Annotate(ctx, "default/data-web-0", "kubectl-csi-scan/needs-attention", "true")).To(Succeed())
+	})
+
+	It("should ask the API server for a dry run", func() {
+		mockCoreV1.EXPECT().PersistentVolumeClaims("batch").Return(mockPVCs)
+		mockPVCs.EXPECT().Patch(ctx, "scratch", k8stypes.MergePatchType, gomock.Any(),
+			metav1.PatchOptions{DryRun: []string{metav1.DryRunAll}},
+		).Return(&corev1.PersistentVolumeClaim{}, nil)
+
+		annotator := remediate.NewPVCAnnotator(mockClient, true)
+		Expect(annotator.Annotate(ctx, "batch/scratch", "example.com/reconcile", "now")).To(Succeed())
+	})
+
+	It("should report a failed patch", func() {
+		mockCoreV1.EXPECT().PersistentVolumeClaims("default").Return(mockPVCs)
+		mockPVCs.EXPECT().Patch(ctx, "missing", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("not found"))
+
+		annotator := remediate.NewPVCAnnotator(mockClient, false)
+		err := annotator.Annotate(ctx, "default/missing", "example.com/reconcile", "now")
+		Expect(err).To(MatchError(ContainSubstring("failed to annotate PVC default/missing")))
+	})
+
+	It("should reject PVCs without a namespace", func() {
+		annotator := remediate.NewPVCAnnotator(mockClient, false)
+		Expect(annotator.Annotate(ctx, "data-web-0", "example.com/reconcile", "now")).To(MatchError(ContainSubstring("must be namespace/name")))
+	})
+
+	Describe("ParseAnnotation", func() {
+		It("should split key and value", func() {
+			key, value, err := remediate.ParseAnnotation(remediate.DefaultAnnotation)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal("kubectl-csi-scan/needs-attention"))
+			Expect(value).To(Equal("true"))
+		})
+
+		It("should reject annotations without a value or with an invalid key", func() {
+			_, _, err := remediate.ParseAnnotation("example.com/reconcile")
+			Expect(err).To(MatchError(ContainSubstring("must be key=value")))
+
+			_, _, err = remediate.ParseAnnotation("bad key=now")
+			Expect(err).To(MatchError(ContainSubstring("invalid annotation key")))
+		})
+	})
+})
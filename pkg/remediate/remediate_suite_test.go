@@ -0,0 +1,13 @@
+package remediate_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRemediate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Remediate Suite")
+}
@@ -0,0 +1,185 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// htmlTemplate lays out the HTML report. Styles are inline so the page can be shared as a
+// single file; html/template escapes every value taken from the cluster, such as event
+// messages quoted in issue descriptions.
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": func(values []string) string { return strings.Join(values, ", ") },
+	"orDash": func(value string) string {
+		if value == "" {
+			return "-"
+		}
+		return value
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>CSI Mount Incident Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { margin-bottom: 0.2em; }
+.generated { color: #57606a; margin-top: 0; }
+.card { display: inline-block; border: 1px solid #d0d7de; border-radius: 6px; padding: 1em 1.5em; margin-bottom: 1.5em; }
+.card table td { padding: 0.2em 1em 0.2em 0; }
+.warning { background: #fff8c5; border: 1px solid #d4a72c; border-radius: 6px; padding: 0.5em 1em; margin-bottom: 1em; }
+table.issues { border-collapse: collapse; width: 100%; }
+table.issues th, table.issues td { border: 1px solid #d0d7de; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+table.issues th { background: #f6f8fa; }
+tr.critical { background: #ffebe9; }
+tr.high { background: #fff1e5; }
+tr.medium { background: #fff8c5; }
+tr.low { background: #f6f8fa; }
+.badge { display: inline-block; border-radius: 1em; padding: 0.1em 0.7em; color: #fff; font-size: 0.85em; font-weight: 600; }
+.badge.critical { background: #cf222e; }
+.badge.high { background: #bc4c00; }
+.badge.medium { background: #9a6700; }
+.badge.low { background: #57606a; }
+.status.critical { color: #cf222e; }
+.status.degraded { color: #9a6700; }
+.status.healthy { color: #1a7f37; }
+</style>
+</head>
+<body>
+<h1>CSI Mount Incident Report</h1>
+<p class="generated">Generated {{.Generated}}</p>
+
+<h2>Summary</h2>
+<div class="card">
+<table>
+{{- if .Status}}
+<tr><td>Status</td><td class="status {{.Status}}"><strong>{{.Status}}</strong></td></tr>
+{{- end}}
+<tr><td>Total issues</td><td><strong>{{.TotalIssues}}</strong></td></tr>
+{{- range .Severities}}
+<tr><td><span class="badge {{.Severity}}">{{.Severity}}</span></td><td>{{.Count}}</td></tr>
+{{- end}}
+{{- if .Suppressed}}
+<tr><td>Suppressed</td><td>{{.Suppressed}}</td></tr>
+{{- end}}
+<tr><td>Affected nodes</td><td>{{len .AffectedNodes}}</td></tr>
+{{- if .AffectedDrivers}}
+<tr><td>Affected drivers</td><td>{{join .AffectedDrivers}}</td></tr>
+{{- end}}
+{{- if .Methods}}
+<tr><td>Detection methods</td><td>{{join .Methods}}</td></tr>
+{{- end}}
+</table>
+</div>
+{{- if .Partial}}
+<div class="warning">Partial result: detection stopped before every method completed.</div>
+{{- end}}
+{{- range .Errors}}
+<div class="warning">Method {{.Method}} failed: {{.Error}}</div>
+{{- end}}
+
+<h2>Issues</h2>
+{{- if .Issues}}
+<table class="issues">
+<tr><th>Severity</th><th>Type</th><th>Node</th><th>Volume</th><th>PVC</th><th>Driver</th><th>Description</th></tr>
+{{- range .Issues}}
+<tr class="{{.Severity}}"><td><span class="badge {{.Severity}}">{{.Severity}}</span></td><td>{{.Type}}</td><td>{{orDash .Node}}</td><td>{{orDash .Volume}}</td><td>{{orDash .PVC}}</td><td>{{orDash .Driver}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No CSI mount issues detected.</p>
+{{- end}}
+
+<h2>Recommendations</h2>
+{{- if .Recommendations}}
+{{- range .Recommendations}}
+{{- if .Heading}}
+<h3>{{.Text}}</h3>
+{{- else}}
+<p>{{.Text}}</p>
+{{- end}}
+{{- end}}
+{{- else}}
+<p><em>No recommendations were generated. Re-run detect with --recommend-cleanup to include them.</em></p>
+{{- end}}
+</body>
+</html>
+`))
+
+// htmlReport is the data the HTML template renders
+type htmlReport struct {
+	Generated       string
+	Status          types.HealthStatus
+	TotalIssues     int
+	Severities      []severityCount
+	Suppressed      int
+	AffectedNodes   []string
+	AffectedDrivers []string
+	Methods         []string
+	Partial         bool
+	Errors          []types.MethodError
+	Issues          []types.CSIMountIssue
+	Recommendations []recommendationLine
+}
+
+// severityCount is one row of the summary card's severity breakdown
+type severityCount struct {
+	Severity types.IssueSeverity
+	Count    int
+}
+
+// recommendationLine is one line of the recommendations, which are written as markdown
+type recommendationLine struct {
+	Text    string
+	Heading bool
+}
+
+// WriteHTMLReport renders a detection result as a self-contained HTML page with a summary
+// card, a color-coded table of issues, most severe first, and the recommendations
+func WriteHTMLReport(w io.Writer, result *types.DetectionResult) error {
+	summary := result.Summary
+	data := htmlReport{
+		Generated:       result.GeneratedAt.Format(time.RFC3339),
+		Status:          summary.Status,
+		TotalIssues:     summary.TotalIssues,
+		Suppressed:      summary.Suppressed,
+		AffectedNodes:   summary.AffectedNodes,
+		AffectedDrivers: summary.AffectedDrivers,
+		Partial:         result.Partial,
+		Errors:          result.Errors,
+		Issues:          append([]types.CSIMountIssue(nil), result.Issues...),
+	}
+	for _, severity := range types.Severities {
+		if count := summary.IssuesBySeverity[severity]; count > 0 {
+			data.Severities = append(data.Severities, severityCount{Severity: severity, Count: count})
+		}
+	}
+	for _, method := range summary.MethodsUsed {
+		data.Methods = append(data.Methods, string(method))
+	}
+	sort.SliceStable(data.Issues, func(i, j int) bool {
+		return data.Issues[i].Severity.Level() > data.Issues[j].Severity.Level()
+	})
+	for _, rec := range result.Recommendations {
+		rec = strings.TrimSpace(rec)
+		if rec == "" {
+			continue
+		}
+		if heading := strings.TrimLeft(rec, "#"); heading != rec {
+			data.Recommendations = append(data.Recommendations, recommendationLine{Text: strings.TrimSpace(heading), Heading: true})
+			continue
+		}
+		data.Recommendations = append(data.Recommendations, recommendationLine{Text: rec})
+	}
+
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return nil
+}
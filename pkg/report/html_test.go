@@ -0,0 +1,77 @@
+package report_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/report"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("WriteHTMLReport", func() {
+	var result *types.DetectionResult
+
+	BeforeEach(func() {
+		result = &types.DetectionResult{
+			Summary: types.DetectionSummary{
+				TotalIssues: 2,
+				IssuesBySeverity: map[types.IssueSeverity]int{
+					types.SeverityCritical: 1,
+					types.SeverityLow:      1,
+				},
+				AffectedNodes: []string{"node-a", "node-b"},
+				Status:        types.StatusCritical,
+				MethodsUsed:   []types.DetectionMethod{types.EventsMethod},
+			},
+			Issues: []types.CSIMountIssue{
+				{Type: types.CSIOperationFailure, Severity: types.SeverityLow, Node: "node-a", Volume: "pvc-1", Description: `MountVolume failed: <script>alert("x")</script>`},
+				{Type: types.MultipleAttachments, Severity: types.SeverityCritical, Node: "node-b", Volume: "pvc-2", Description: "Volume attached to multiple nodes"},
+			},
+			Recommendations: []string{
+				"## Immediate Actions",
+				"1. **Check VolumeAttachment objects**: kubectl get volumeattachments -o wide",
+			},
+			GeneratedAt: time.Date(2025, 9, 8, 15, 30, 0, 0, time.UTC),
+		}
+	})
+
+	render := func() string {
+		var buf bytes.Buffer
+		Expect(report.WriteHTMLReport(&buf, result)).To(Succeed())
+		return buf.String()
+	}
+
+	It("should render the summary, issues and recommendations", func() {
+		out := render()
+		Expect(out).To(HavePrefix("<!DOCTYPE html>"))
+		Expect(out).To(ContainSubstring("Generated 2025-09-08T15:30:00Z"))
+		Expect(out).To(ContainSubstring(`<span class="badge critical">critical</span></td><td>1</td>`))
+		Expect(out).To(ContainSubstring(`<span class="badge low">low</span></td><td>1</td>`))
+		Expect(out).To(ContainSubstring("<td>node-a</td>"))
+		Expect(out).To(ContainSubstring("<td>node-b</td>"))
+		Expect(out).To(ContainSubstring("<h3>Immediate Actions</h3>"))
+		Expect(out).NotTo(ContainSubstring("<link"))
+	})
+
+	It("should list the most severe issues first", func() {
+		out := render()
+		Expect(bytes.Index([]byte(out), []byte(`<tr class="critical">`))).To(BeNumerically("<", bytes.Index([]byte(out), []byte(`<tr class="low">`))))
+	})
+
+	It("should escape event messages", func() {
+		out := render()
+		Expect(out).NotTo(ContainSubstring("<script>"))
+		Expect(out).To(ContainSubstring("&lt;script&gt;"))
+	})
+
+	It("should note when there are no issues or recommendations", func() {
+		result.Issues = nil
+		result.Recommendations = nil
+		out := render()
+		Expect(out).To(ContainSubstring("No CSI mount issues detected."))
+		Expect(out).To(ContainSubstring("No recommendations were generated"))
+	})
+})
@@ -0,0 +1,192 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// clusterWideHeading titles the section for issues not tied to a single node
+const clusterWideHeading = "Cluster-wide Issues"
+
+// WriteIncidentReport renders a detection result as a self-contained markdown incident
+// report with a table of contents, summary, per-node sections and recommendations
+func WriteIncidentReport(w io.Writer, result *types.DetectionResult) error {
+	var b strings.Builder
+
+	issuesByNode, clusterWide := groupByNode(result.Issues)
+	nodes := make([]string, 0, len(issuesByNode))
+	for node := range issuesByNode {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	fmt.Fprintf(&b, "# CSI Mount Incident Report\n\n")
+	fmt.Fprintf(&b, "**Generated:** %s\n\n", result.GeneratedAt.Format(time.RFC3339))
+
+	// Table of contents
+	fmt.Fprintf(&b, "## Table of Contents\n\n")
+	fmt.Fprintf(&b, "- [Summary](#%s)\n", anchor("Summary"))
+	if len(nodes) > 0 {
+		fmt.Fprintf(&b, "- [Affected Nodes](#%s)\n", anchor("Affected Nodes"))
+		for _, node := range nodes {
+			fmt.Fprintf(&b, "  - [%s](#%s)\n", node, anchor(nodeHeading(node)))
+		}
+	}
+	if len(clusterWide) > 0 {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", clusterWideHeading, anchor(clusterWideHeading))
+	}
+	fmt.Fprintf(&b, "- [Recommendations](#%s)\n\n", anchor("Recommendations"))
+
+	writeSummary(&b, result)
+
+	if len(nodes) > 0 {
+		fmt.Fprintf(&b, "## Affected Nodes\n\n")
+		for _, node := range nodes {
+			fmt.Fprintf(&b, "### %s\n\n", nodeHeading(node))
+			writeIssueTable(&b, issuesByNode[node])
+		}
+	}
+
+	if len(clusterWide) > 0 {
+		fmt.Fprintf(&b, "## %s\n\n", clusterWideHeading)
+		writeIssueTable(&b, clusterWide)
+	}
+
+	writeRecommendations(&b, result.Recommendations)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeSummary writes the headline statistics of the result
+func writeSummary(b *strings.Builder, result *types.DetectionResult) {
+	summary := result.Summary
+
+	fmt.Fprintf(b, "## Summary\n\n")
+	fmt.Fprintf(b, "| Metric | Value |\n")
+	fmt.Fprintf(b, "|--------|-------|\n")
+	fmt.Fprintf(b, "| Total issues | %d |\n", summary.TotalIssues)
+	for _, severity := range types.Severities {
+		if count := summary.IssuesBySeverity[severity]; count > 0 {
+			fmt.Fprintf(b, "| %s | %d |\n", severity, count)
+		}
+	}
+	if summary.Suppressed > 0 {
+		fmt.Fprintf(b, "| Suppressed | %d |\n", summary.Suppressed)
+	}
+	fmt.Fprintf(b, "| Affected nodes | %d |\n", len(summary.AffectedNodes))
+	if len(summary.AffectedDrivers) > 0 {
+		fmt.Fprintf(b, "| Affected drivers | %s |\n", escapeCell(strings.Join(summary.AffectedDrivers, ", ")))
+	}
+	if len(summary.MethodsUsed) > 0 {
+		methods := make([]string, 0, len(summary.MethodsUsed))
+		for _, method := range summary.MethodsUsed {
+			methods = append(methods, string(method))
+		}
+		fmt.Fprintf(b, "| Detection methods | %s |\n", strings.Join(methods, ", "))
+	}
+	if !summary.SnapshotTime.IsZero() {
+		fmt.Fprintf(b, "| Cluster snapshot | %s |\n", summary.SnapshotTime.Format(time.RFC3339))
+	}
+	if result.Partial {
+		fmt.Fprintf(b, "| Partial result | yes - detection stopped before every method completed |\n")
+	}
+	for _, methodErr := range result.Errors {
+		fmt.Fprintf(b, "| Failed method | %s: %s |\n", methodErr.Method, escapeCell(methodErr.Error))
+	}
+	fmt.Fprintf(b, "\n")
+}
+
+// writeIssueTable writes issues as a markdown table, most severe first
+func writeIssueTable(b *strings.Builder, issues []types.CSIMountIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Severity.Level() > issues[j].Severity.Level()
+	})
+
+	fmt.Fprintf(b, "| Severity | Type | Volume | PVC | Driver | Description |\n")
+	fmt.Fprintf(b, "|----------|------|--------|-----|--------|-------------|\n")
+	for _, issue := range issues {
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s | %s |\n",
+			issue.Severity,
+			issue.Type,
+			cellOrDash(issue.Volume),
+			cellOrDash(issue.PVC),
+			cellOrDash(issue.Driver),
+			escapeCell(issue.Description),
+		)
+	}
+	fmt.Fprintf(b, "\n")
+}
+
+// writeRecommendations writes the recommendations, demoting their headings so they
+// nest under the report's own Recommendations section
+func writeRecommendations(b *strings.Builder, recommendations []string) {
+	fmt.Fprintf(b, "## Recommendations\n\n")
+	if len(recommendations) == 0 {
+		fmt.Fprintf(b, "_No recommendations were generated. Re-run detect with --recommend-cleanup to include them._\n")
+		return
+	}
+
+	for _, rec := range recommendations {
+		rec = strings.TrimPrefix(rec, "\n")
+		if strings.HasPrefix(rec, "## ") {
+			fmt.Fprintf(b, "\n#%s\n\n", rec)
+			continue
+		}
+		fmt.Fprintf(b, "%s\n", rec)
+	}
+}
+
+// groupByNode splits issues into per-node lists and issues without a node
+func groupByNode(issues []types.CSIMountIssue) (map[string][]types.CSIMountIssue, []types.CSIMountIssue) {
+	byNode := make(map[string][]types.CSIMountIssue)
+	var clusterWide []types.CSIMountIssue
+
+	for _, issue := range issues {
+		if issue.Node == "" {
+			clusterWide = append(clusterWide, issue)
+			continue
+		}
+		byNode[issue.Node] = append(byNode[issue.Node], issue)
+	}
+
+	return byNode, clusterWide
+}
+
+// nodeHeading returns the section heading for a node
+func nodeHeading(node string) string {
+	return "Node: " + node
+}
+
+// anchor converts a heading into the fragment identifier generated by GitHub-style renderers
+func anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// cellOrDash returns a table cell value, using "-" for empty values
+func cellOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return escapeCell(value)
+}
+
+// escapeCell keeps a value on a single table row
+func escapeCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
@@ -0,0 +1,106 @@
+package report_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/report"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("WriteIncidentReport", func() {
+	var result *types.DetectionResult
+
+	BeforeEach(func() {
+		result = &types.DetectionResult{
+			Summary: types.DetectionSummary{
+				TotalIssues: 3,
+				IssuesBySeverity: map[types.IssueSeverity]int{
+					types.SeverityHigh: 2,
+					types.SeverityLow:  1,
+				},
+				AffectedNodes:   []string{"node-a", "node-b"},
+				AffectedDrivers: []string{"cinder.csi.openstack.org"},
+				MethodsUsed:     []types.DetectionMethod{types.VolumeAttachmentMethod},
+			},
+			Issues: []types.CSIMountIssue{
+				{Type: types.StuckVolumeAttachment, Severity: types.SeverityHigh, Node: "node-b", Volume: "pvc-1", Description: "Volume stuck in attaching state"},
+				{Type: types.FailedAttachVolume, Severity: types.SeverityLow, Node: "node-a", Volume: "pvc-2", Description: "attach failed | retrying"},
+				{Type: types.MultipleAttachments, Severity: types.SeverityHigh, Volume: "pvc-3", Description: "Volume attached to multiple nodes"},
+			},
+			Recommendations: []string{
+				"## Immediate Actions",
+				"1. **Check VolumeAttachment objects**: kubectl get volumeattachments -o wide",
+				"\n## Affected Nodes",
+			},
+			GeneratedAt: time.Date(2025, 9, 8, 15, 30, 0, 0, time.UTC),
+		}
+	})
+
+	render := func() string {
+		var buf bytes.Buffer
+		Expect(report.WriteIncidentReport(&buf, result)).To(Succeed())
+		return buf.String()
+	}
+
+	It("should include a table of contents linking every section", func() {
+		out := render()
+		Expect(out).To(ContainSubstring("## Table of Contents"))
+		Expect(out).To(ContainSubstring("- [Summary](#summary)"))
+		Expect(out).To(ContainSubstring("  - [node-a](#node-node-a)"))
+		Expect(out).To(ContainSubstring("  - [node-b](#node-node-b)"))
+		Expect(out).To(ContainSubstring("- [Cluster-wide Issues](#cluster-wide-issues)"))
+		Expect(out).To(ContainSubstring("- [Recommendations](#recommendations)"))
+	})
+
+	It("should include the summary", func() {
+		out := render()
+		Expect(out).To(ContainSubstring("| Total issues | 3 |"))
+		Expect(out).To(ContainSubstring("| high | 2 |"))
+		Expect(out).To(ContainSubstring("| Affected drivers | cinder.csi.openstack.org |"))
+	})
+
+	It("should flag partial results", func() {
+		Expect(render()).NotTo(ContainSubstring("Partial result"))
+
+		result.Partial = true
+		Expect(render()).To(ContainSubstring("| Partial result | yes"))
+	})
+
+	It("should list the methods that failed in best-effort mode", func() {
+		Expect(render()).NotTo(ContainSubstring("Failed method"))
+
+		result.Errors = []types.MethodError{{Method: types.EventsMethod, Error: "events is forbidden"}}
+		Expect(render()).To(ContainSubstring("| Failed method | events: events is forbidden |"))
+	})
+
+	It("should write a section for each affected node in order", func() {
+		out := render()
+		Expect(out).To(ContainSubstring("### Node: node-a"))
+		Expect(out).To(ContainSubstring("### Node: node-b"))
+		Expect(bytes.Index([]byte(out), []byte("### Node: node-a"))).To(BeNumerically("<", bytes.Index([]byte(out), []byte("### Node: node-b"))))
+		Expect(out).To(ContainSubstring("| low | failed-attach-volume | pvc-2 | - | - | attach failed \\| retrying |"))
+	})
+
+	It("should list issues without a node as cluster-wide", func() {
+		out := render()
+		Expect(out).To(ContainSubstring("## Cluster-wide Issues"))
+		Expect(out).To(ContainSubstring("| high | multiple-attachments | pvc-3 |"))
+	})
+
+	It("should nest the recommendations under their own section", func() {
+		out := render()
+		Expect(out).To(ContainSubstring("## Recommendations"))
+		Expect(out).To(ContainSubstring("### Immediate Actions"))
+		Expect(out).To(ContainSubstring("### Affected Nodes"))
+		Expect(out).To(ContainSubstring("kubectl get volumeattachments -o wide"))
+	})
+
+	It("should explain how to get recommendations when there are none", func() {
+		result.Recommendations = nil
+		Expect(render()).To(ContainSubstring("--recommend-cleanup"))
+	})
+})
@@ -0,0 +1,167 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// Draft is the JSON Schema dialect of the generated documents
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// enums lists the values of string types whose set of values is fixed. Issue types and
+// detection methods are left open so that a newer tool version does not fail validation.
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(types.IssueSeverity("")): {
+		string(types.SeverityLow), string(types.SeverityMedium), string(types.SeverityHigh), string(types.SeverityCritical),
+	},
+	reflect.TypeOf(types.HealthStatus("")): {
+		string(types.StatusHealthy), string(types.StatusDegraded), string(types.StatusCritical),
+	},
+}
+
+// DetectionResult returns the JSON Schema of the detect JSON output
+func DetectionResult() map[string]interface{} {
+	doc := Generate(reflect.TypeOf(types.DetectionResult{}))
+	doc["$id"] = fmt.Sprintf("https://github.com/jdambly/kubectl-csi-scan/schema/detection-result/%s.json", types.SchemaVersion)
+	doc["title"] = "DetectionResult"
+	return doc
+}
+
+// Generate builds a JSON Schema document for a struct type from its fields and json tags.
+// Named struct types are placed in $defs and referenced, so each is described once.
+func Generate(t reflect.Type) map[string]interface{} {
+	g := &generator{defs: make(map[string]interface{}), refs: make(map[string]int)}
+	root := g.schemaFor(t)
+
+	doc := map[string]interface{}{"$schema": Draft}
+	// The root type is described inline; its definition is only kept when the type refers
+	// to itself
+	if ref, ok := root["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		root = g.defs[name].(map[string]interface{})
+		if g.refs[name] == 1 {
+			delete(g.defs, name)
+		}
+	}
+	for key, value := range root {
+		doc[key] = value
+	}
+	if len(g.defs) > 0 {
+		doc["$defs"] = g.defs
+	}
+	return doc
+}
+
+// Marshal returns a schema document as indented JSON
+func Marshal(doc map[string]interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	return data, nil
+}
+
+type generator struct {
+	defs map[string]interface{}
+	refs map[string]int // references made to each definition
+}
+
+// schemaFor describes one Go type as encoding/json marshals it
+func (g *generator) schemaFor(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	if values, ok := enums[t]; ok {
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.structRef(t)
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	// Interfaces and anything else can hold any value
+	return map[string]interface{}{}
+}
+
+// structRef adds a struct type to $defs on first use and returns a reference to it
+func (g *generator) structRef(t reflect.Type) map[string]interface{} {
+	ref := map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	g.refs[t.Name()]++
+	if _, ok := g.defs[t.Name()]; ok {
+		return ref
+	}
+	// Reserve the name first so recursive types terminate
+	g.defs[t.Name()] = map[string]interface{}{}
+
+	properties := make(map[string]interface{})
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitEmpty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		property := g.schemaFor(field.Type)
+		// encoding/json writes nil slices and maps as null unless they are omitted
+		if !omitEmpty && (field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Map) {
+			property["type"] = []string{property["type"].(string), "null"}
+		}
+		properties[name] = property
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+
+	// Additional properties stay allowed: fields are added without a new SchemaVersion
+	def := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		def["required"] = required
+	}
+	g.defs[t.Name()] = def
+	return ref
+}
+
+// jsonName returns the JSON name of a struct field, whether it has omitempty, and whether
+// encoding/json skips it
+func jsonName(field reflect.StructField) (string, bool, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(","+options+",", ",omitempty,"), false
+}
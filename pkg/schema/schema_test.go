@@ -0,0 +1,77 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"reflect"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/schema"
+)
+
+var _ = Describe("Schema", func() {
+	Describe("DetectionResult", func() {
+		var doc map[string]interface{}
+
+		BeforeEach(func() {
+			data, err := schema.Marshal(schema.DetectionResult())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Valid(data)).To(BeTrue())
+			Expect(json.Unmarshal(data, &doc)).To(Succeed())
+		})
+
+		It("should describe the top-level issues and summary", func() {
+			Expect(doc["$schema"]).To(Equal(schema.Draft))
+			Expect(doc["type"]).To(Equal("object"))
+
+			properties := doc["properties"].(map[string]interface{})
+			Expect(properties).To(HaveKeyWithValue("summary", map[string]interface{}{"$ref": "#/$defs/DetectionSummary"}))
+			Expect(properties).To(HaveKey("issues"))
+			Expect(properties["issues"]).To(HaveKeyWithValue("items", map[string]interface{}{"$ref": "#/$defs/CSIMountIssue"}))
+			Expect(doc["required"]).To(ContainElements("summary", "issues", "generatedAt"))
+			Expect(doc["required"]).NotTo(ContainElement("recommendations"))
+
+			defs := doc["$defs"].(map[string]interface{})
+			Expect(defs).To(HaveKey("DetectionSummary"))
+			Expect(defs).To(HaveKey("CSIMountIssue"))
+			Expect(defs).To(HaveKey("SourceRef"))
+			Expect(defs).NotTo(HaveKey("DetectionResult"))
+		})
+
+		It("should describe issue fields by their JSON names", func() {
+			issue := doc["$defs"].(map[string]interface{})["CSIMountIssue"].(map[string]interface{})
+			properties := issue["properties"].(map[string]interface{})
+
+			Expect(properties["detectedAt"]).To(Equal(map[string]interface{}{"type": "string", "format": "date-time"}))
+			Expect(properties["severity"]).To(HaveKeyWithValue("enum", []interface{}{"low", "medium", "high", "critical"}))
+			Expect(properties["metadata"]).To(HaveKeyWithValue("additionalProperties", map[string]interface{}{"type": "string"}))
+			Expect(issue["required"]).To(ContainElements("type", "severity", "node", "description"))
+			Expect(issue["required"]).NotTo(ContainElement("volume"))
+		})
+	})
+
+	Describe("Generate", func() {
+		type node struct {
+			Name     string  `json:"name"`
+			Children []*node `json:"children"`
+			Weight   float64 `json:"weight,omitempty"`
+			internal bool
+			Skipped  string `json:"-"`
+		}
+
+		It("should handle recursive types, nullable slices and skipped fields", func() {
+			doc := schema.Generate(reflect.TypeOf(node{}))
+			properties := doc["properties"].(map[string]interface{})
+
+			Expect(properties).To(HaveLen(3))
+			Expect(properties["children"]).To(Equal(map[string]interface{}{
+				"type":  []string{"array", "null"},
+				"items": map[string]interface{}{"$ref": "#/$defs/node"},
+			}))
+			Expect(properties["weight"]).To(Equal(map[string]interface{}{"type": "number"}))
+			Expect(doc["required"]).To(Equal([]string{"name", "children"}))
+			Expect(doc["$defs"]).To(HaveKey("node"))
+		})
+	})
+})
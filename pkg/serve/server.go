@@ -0,0 +1,155 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+// DefaultShutdownGrace is how long in-flight requests get to finish on shutdown
+const DefaultShutdownGrace = 10 * time.Second
+
+// ScanFunc runs one detection pass
+type ScanFunc func(ctx context.Context) (*types.DetectionResult, error)
+
+// Server runs detection on an interval and serves a health endpoint and the latest result,
+// so the tool can run in-cluster as a Deployment
+type Server struct {
+	addr     string
+	interval time.Duration
+	scan     ScanFunc
+	scanned  atomic.Bool
+	latest   atomic.Pointer[types.DetectionResult]
+}
+
+// New creates a server listening on addr that scans every interval
+func New(addr string, interval time.Duration, scan ScanFunc) *Server {
+	return &Server{
+		addr:     addr,
+		interval: interval,
+		scan:     scan,
+	}
+}
+
+// Handler returns the HTTP handler. /healthz answers 503 until the first scan has
+// completed and 200 afterwards. /results serves the result of the latest successful scan
+// as JSON, or 503 until there is one.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if !s.scanned.Load() {
+			http.Error(w, "waiting for first scan", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/results", func(w http.ResponseWriter, r *http.Request) {
+		result := s.latest.Load()
+		if result == nil {
+			http.Error(w, "no scan result yet", http.StatusServiceUnavailable)
+			return
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to marshal result: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(append(data, '\n'))
+	})
+	return mux
+}
+
+// Run serves HTTP and scans until ctx is cancelled, for example on SIGTERM, or the HTTP
+// server fails. On cancellation it stops scanning and gives in-flight requests up to grace
+// to finish before returning.
+func (s *Server) Run(ctx context.Context, grace time.Duration) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+
+	httpServer := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(listener)
+	}()
+	log.Info().Str("addr", listener.Addr().String()).Dur("interval", s.interval).Msg("serving")
+
+	// Scans run under their own context so a failing HTTP server can stop them too
+	scanCtx, stopScans := context.WithCancel(ctx)
+	defer stopScans()
+	scansDone := make(chan struct{})
+	go func() {
+		defer close(scansDone)
+		s.scanLoop(scanCtx)
+	}()
+
+	select {
+	case <-ctx.Done():
+	case err := <-serveErr:
+		stopScans()
+		<-scansDone
+		return fmt.Errorf("HTTP server failed: %w", err)
+	}
+
+	log.Info().Msg("shutting down")
+	// A scan in progress is cancelled through ctx, so this only waits for it to return
+	<-scansDone
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down HTTP server: %w", err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("HTTP server failed: %w", err)
+	}
+	return nil
+}
+
+// scanLoop scans immediately and then on every tick until ctx is cancelled
+func (s *Server) scanLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runScan(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runScan runs one scan, logs its outcome and keeps its result for /results. Failed scans
+// still count as completed for /healthz: the process is alive, and the error is in the
+// log. /results keeps serving the previous result until a scan succeeds again.
+func (s *Server) runScan(ctx context.Context) {
+	result, err := s.scan(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	s.scanned.Store(true)
+
+	if err != nil {
+		log.Error().Err(err).Msg("scan failed")
+		return
+	}
+	s.latest.Store(result)
+	log.Info().Int("issues_found", len(result.Issues)).Msg("scan completed")
+}
@@ -0,0 +1,129 @@
+package serve_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/serve"
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		release chan struct{}
+		scans   chan struct{}
+		server  *serve.Server
+	)
+
+	BeforeEach(func() {
+		release = make(chan struct{})
+		scans = make(chan struct{}, 10)
+		// Each scan blocks until released or cancelled
+		server = serve.New("127.0.0.1:0", time.Hour, func(ctx context.Context) (*types.DetectionResult, error) {
+			scans <- struct{}{}
+			select {
+			case <-release:
+				return &types.DetectionResult{Issues: []types.CSIMountIssue{{Type: types.VolumeAttachmentConflict, Node: "node-1"}}}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		})
+	})
+
+	healthz := func() int {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		return recorder.Code
+	}
+
+	It("should report unhealthy until the first scan completes", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(server.Run(ctx, time.Second)).To(Succeed())
+		}()
+
+		Eventually(scans).Should(Receive())
+		Expect(healthz()).To(Equal(http.StatusServiceUnavailable))
+
+		close(release)
+		Eventually(healthz).Should(Equal(http.StatusOK))
+	})
+
+	It("should serve the latest scan result as JSON", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(server.Run(ctx, time.Second)).To(Succeed())
+		}()
+
+		results := func() *httptest.ResponseRecorder {
+			recorder := httptest.NewRecorder()
+			server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/results", nil))
+			return recorder
+		}
+
+		Eventually(scans).Should(Receive())
+		Expect(results().Code).To(Equal(http.StatusServiceUnavailable))
+
+		close(release)
+		Eventually(func() int { return results().Code }).Should(Equal(http.StatusOK))
+
+		recorder := results()
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+		var result types.DetectionResult
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &result)).To(Succeed())
+		Expect(result.Issues).To(HaveLen(1))
+		Expect(result.Issues[0].Node).To(Equal("node-1"))
+	})
+
+	It("should shut down promptly when cancelled, even during a scan", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- server.Run(ctx, time.Second)
+		}()
+
+		Eventually(scans).Should(Receive())
+		cancel()
+
+		var err error
+		Eventually(done, 2*time.Second).Should(Receive(&err))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(healthz()).To(Equal(http.StatusServiceUnavailable))
+	})
+
+	It("should return an error rather than hang when the port is in use", func() {
+		occupied, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		defer occupied.Close()
+
+		server = serve.New(occupied.Addr().String(), time.Hour, func(ctx context.Context) (*types.DetectionResult, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		done := make(chan error, 1)
+		go func() {
+			done <- server.Run(context.Background(), time.Second)
+		}()
+
+		var runErr error
+		Eventually(done, 2*time.Second).Should(Receive(&runErr))
+		Expect(runErr).To(MatchError(ContainSubstring(occupied.Addr().String())))
+	})
+
+	It("should fail when the address cannot be listened on", func() {
+		server = serve.New("256.0.0.1:0", time.Hour, nil)
+		Expect(server.Run(context.Background(), time.Second)).To(MatchError(ContainSubstring("failed to listen on 256.0.0.1:0")))
+	})
+})
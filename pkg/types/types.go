@@ -1,6 +1,8 @@
 package types
 
 import (
+	"slices"
+	"strings"
 	"time"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -13,6 +15,12 @@ const (
 	CrossNodePVCMethod     DetectionMethod = "cross-node-pvc"
 	EventsMethod          DetectionMethod = "events"
 	MetricsMethod         DetectionMethod = "metrics"
+	StorageClassMethod    DetectionMethod = "storageclass"
+	ProbeMethod           DetectionMethod = "probe" // node plugin health checks run with --probe
+	NodeConditionsMethod  DetectionMethod = "node-conditions"
+	MountProbeMethod      DetectionMethod = "mount-probe" // mount listing jobs run with --probe-mounts
+	DriverTopologyMethod  DetectionMethod = "driver-topology"
+	WorkloadRollupMethod  DetectionMethod = "workload-rollup" // owner lookup run with --recommend-cleanup --with-owners
 )
 
 // CSIMountIssue represents a detected CSI mount problem
@@ -27,7 +35,31 @@ type CSIMountIssue struct {
 	Description   string        `json:"description"`
 	DetectedBy    DetectionMethod `json:"detectedBy"`
 	DetectedAt    time.Time     `json:"detectedAt"`
+	OccurredAt    time.Time     `json:"occurredAt,omitempty,omitzero"` // time of the underlying event or object, not of the scan; left out when unknown
 	Metadata      map[string]string `json:"metadata,omitempty"`
+	Sources       []SourceRef   `json:"sources,omitempty"` // objects the issue was derived from
+}
+
+// ID identifies an issue across scans by its type, node, volume and PVC, which stay the
+// same while the underlying problem persists
+func (i CSIMountIssue) ID() string {
+	return strings.Join([]string{string(i.Type), i.Node, i.Volume, i.PVC}, "|")
+}
+
+// SourceRef identifies a Kubernetes object an issue was derived from
+type SourceRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// String returns the reference as kind/namespace/name, omitting the namespace for cluster-scoped objects
+func (r SourceRef) String() string {
+	if r.Namespace == "" {
+		return r.Kind + "/" + r.Name
+	}
+	return r.Kind + "/" + r.Namespace + "/" + r.Name
 }
 
 // IssueType categorizes the type of mount issue
@@ -42,8 +74,26 @@ const (
 	FailedAttachVolume      IssueType = "failed-attach-volume"
 	StuckMountReference     IssueType = "stuck-mount-reference"
 	CSIOperationFailure     IssueType = "csi-operation-failure"
+	StorageClassMisconfiguration IssueType = "storage-class-misconfiguration"
+	DeviceBusy              IssueType = "device-busy"
+	MissingPVC              IssueType = "missing-pvc"
+	UnhealthyNodePlugin     IssueType = "unhealthy-node-plugin"
+	AttachedWithoutClaim    IssueType = "attached-without-claim"
+	HighNodePVCUsage        IssueType = "high-node-pvc-usage"
+	AttachmentFlapping      IssueType = "attachment-flapping"
+	AttachmentNotReconciled IssueType = "attachment-not-reconciled"
+	UnhealthyNode           IssueType = "unhealthy-node"
+	DriverNotRegistered     IssueType = "driver-not-registered"
 )
 
+// IssueTypes lists every issue type detection can report
+var IssueTypes = []IssueType{
+	VolumeAttachmentConflict, StuckVolumeAttachment, StuckVolumeDetachment, MultipleAttachments,
+	MultiAttachError, FailedAttachVolume, StuckMountReference, CSIOperationFailure,
+	StorageClassMisconfiguration, DeviceBusy, MissingPVC, UnhealthyNodePlugin, AttachedWithoutClaim,
+	HighNodePVCUsage, AttachmentFlapping, AttachmentNotReconciled, UnhealthyNode, DriverNotRegistered,
+}
+
 // IssueSeverity indicates the impact level
 type IssueSeverity string
 
@@ -54,6 +104,33 @@ const (
 	SeverityLow      IssueSeverity = "low"      // 1 conflict or isolated issue
 )
 
+// Severities lists the severities from most to least severe, for summaries that break
+// issues down by severity
+var Severities = []IssueSeverity{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow}
+
+// severityOrder ranks the severities from 1 (low) to 4 (critical)
+var severityOrder = map[IssueSeverity]int{
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// Level returns the rank of the severity for ordering, from 1 (low) to 4 (critical).
+// Unknown severities rank 0.
+func (s IssueSeverity) Level() int {
+	return severityOrder[s]
+}
+
+// HealthStatus is the overall verdict of a detection run
+type HealthStatus string
+
+const (
+	StatusHealthy  HealthStatus = "healthy"
+	StatusDegraded HealthStatus = "degraded"
+	StatusCritical HealthStatus = "critical"
+)
+
 // VolumeAttachmentInfo contains details about volume attachment conflicts
 type VolumeAttachmentInfo struct {
 	Name           string            `json:"name"`
@@ -73,6 +150,13 @@ type NodePVCUsage struct {
 	Total     int               `json:"total"`
 }
 
+// PVCUsageSummary is how often one PVC is referenced by pods across all nodes
+type PVCUsageSummary struct {
+	PVC        string `json:"pvc"`        // namespace/name
+	References int    `json:"references"` // pod references summed over every node
+	NodeCount  int    `json:"nodeCount"`  // distinct nodes with at least one reference
+}
+
 // EventInfo represents relevant Kubernetes events
 type EventInfo struct {
 	Type      string      `json:"type"`
@@ -92,19 +176,94 @@ type MetricQuery struct {
 
 // DetectionOptions configures the detection process
 type DetectionOptions struct {
-	Methods        []DetectionMethod `json:"methods"`
-	TargetDriver   string           `json:"targetDriver,omitempty"`
-	OutputFormat   string           `json:"outputFormat"`  // json, yaml, table, detailed
-	RecommendCleanup bool           `json:"recommendCleanup"`
-	MinSeverity    IssueSeverity    `json:"minSeverity"`
+	Methods               []DetectionMethod        `json:"methods"`
+	TargetDriver          string                   `json:"targetDriver,omitempty"`
+	OutputFormat          string                   `json:"outputFormat"` // json, yaml, table, wide, detailed, report
+	RecommendCleanup      bool                     `json:"recommendCleanup"`
+	MinSeverity           IssueSeverity            `json:"minSeverity"`
+	CSIOnly               bool                     `json:"csiOnly,omitempty"`               // skip PVCs not backed by a CSI PV
+	WithOwners            bool                     `json:"withOwners,omitempty"`            // add affected workloads to recommendations
+	StrictDriverMatch     bool                     `json:"strictDriverMatch,omitempty"`     // exclude items whose driver is uncertain
+	DeviceBusyPatterns    []string                 `json:"deviceBusyPatterns,omitempty"`    // event message substrings classified as device-busy
+	StuckThreshold        time.Duration            `json:"stuckThreshold,omitempty"`        // default age before an unattached VolumeAttachment is stuck
+	DriverStuckThresholds map[string]time.Duration `json:"driverStuckThresholds,omitempty"` // per-driver overrides of StuckThreshold
+	DeletionThreshold     time.Duration            `json:"deletionThreshold,omitempty"`     // how long a deleted VolumeAttachment may keep its finalizers; 0 uses the default
+	Offline               bool                     `json:"offline,omitempty"`               // only recommend steps that need no external connectivity
+	Suppressions          []SuppressionRule        `json:"suppressions,omitempty"`          // known and accepted issues left out of results
+	Probe                 bool                     `json:"probe,omitempty"`                 // check CSI node plugin pods on affected nodes
+	ProbeMounts           bool                     `json:"probeMounts,omitempty"`           // list the CSI mounts on nodes with suspected stuck mount references using read-only jobs
+	NodePluginSelectors   map[string]string        `json:"nodePluginSelectors,omitempty"`   // per-driver label selectors of node plugin pods, overriding the defaults
+	SeverityOverrides     map[IssueType]IssueSeverity `json:"severityOverrides,omitempty"`  // fixed severities for issue types, replacing the detectors' calculation
+	CheckClaims           bool                     `json:"checkClaims,omitempty"`           // look up the claim of each attached PV to find detaches that never happened
+	EnrichmentErrorLimit  int                      `json:"-"`                               // consecutive identical lookup errors that stop enrichment lookups
+	NodePVCWarn           int                      `json:"nodePVCWarn,omitempty"`           // PVC references on one node above which the node is reported; 0 disables
+	FlapThreshold         int                      `json:"flapThreshold,omitempty"`         // attach and detach events of one volume above which it is reported as flapping; 0 uses the default
+	EventsLookback        time.Duration            `json:"eventsLookback,omitempty"`        // how far back the events method looks; 0 uses the default of one hour
+	PVC                   string                   `json:"pvc,omitempty"`                   // namespace/name of the only PVC to report issues about
+	StorageClass          string                   `json:"storageClass,omitempty"`          // only inspect PVCs and PVs of this StorageClass
+	PrometheusURL         string                   `json:"prometheusURL,omitempty"`         // Prometheus the metrics method queries; empty disables it
+	CriticalThresholds    map[IssueSeverity]int    `json:"criticalThresholds,omitempty"`    // issue counts per severity that make the status critical; nil uses the defaults
+	DegradedThresholds    map[IssueSeverity]int    `json:"degradedThresholds,omitempty"`    // issue counts per severity that make the status degraded; nil uses the defaults
+	NoDedup               bool                     `json:"noDedup,omitempty"`               // report each method's issues separately instead of merging those about the same volume
+	TopPVCs               int                      `json:"topPVCs,omitempty"`               // PVCs the detailed analysis ranks by references; 0 uses the default of 10
+	Namespace             string                   `json:"namespace,omitempty"`             // only list pods and events in this namespace; empty lists all namespaces
+	BestEffort            bool                     `json:"bestEffort,omitempty"`            // record methods that fail in the result and keep running the others
+}
+
+// SuppressionRule matches known and accepted issues so they are left out of results.
+// Every field that is set must match. Driver, Node and PVC are glob patterns; PVC is
+// matched against both namespace/name and the bare name.
+type SuppressionRule struct {
+	Type   IssueType `json:"type,omitempty"`
+	Driver string    `json:"driver,omitempty"`
+	Node   string    `json:"node,omitempty"`
+	PVC    string    `json:"pvc,omitempty"`
 }
 
+// SchemaVersion identifies the layout of DetectionResult in JSON output. It changes when
+// fields are removed or change meaning, not when fields are added.
+const SchemaVersion = "v1"
+
 // DetectionResult contains all findings from the detection process
 type DetectionResult struct {
+	SchemaVersion string            `json:"schemaVersion,omitempty"`
 	Summary       DetectionSummary  `json:"summary"`
 	Issues        []CSIMountIssue   `json:"issues"`
 	Recommendations []string        `json:"recommendations,omitempty"`
 	GeneratedAt   time.Time         `json:"generatedAt"`
+	Partial       bool              `json:"partial,omitempty"` // detection was interrupted before every method completed
+	Resolved      []CSIMountIssue   `json:"resolved,omitempty"` // baseline issues no longer detected, set when comparing against a baseline
+	Errors        []MethodError     `json:"errors,omitempty"`   // methods that failed in best-effort mode; their issues are missing
+}
+
+// MethodError records a detection method that failed when detection runs in best-effort
+// mode
+type MethodError struct {
+	Method DetectionMethod `json:"method"`
+	Error  string          `json:"error"`
+}
+
+// ClusterWideNode is the IssuesByNode key of issues that are not tied to one node, such as
+// cross-node PVC issues
+const ClusterWideNode = "<cluster-wide>"
+
+// IssuesByNode groups the issues by node, with issues without a node under ClusterWideNode.
+// Each node's issues are ordered by descending severity, keeping their order otherwise.
+func (r *DetectionResult) IssuesByNode() map[string][]CSIMountIssue {
+	byNode := make(map[string][]CSIMountIssue)
+	for _, issue := range r.Issues {
+		node := issue.Node
+		if node == "" {
+			node = ClusterWideNode
+		}
+		byNode[node] = append(byNode[node], issue)
+	}
+	for _, issues := range byNode {
+		slices.SortStableFunc(issues, func(a, b CSIMountIssue) int {
+			return b.Severity.Level() - a.Severity.Level()
+		})
+	}
+	return byNode
 }
 
 // DetectionSummary provides high-level statistics
@@ -114,5 +273,39 @@ type DetectionSummary struct {
 	IssuesByType     map[IssueType]int          `json:"issuesByType"`
 	AffectedNodes    []string                   `json:"affectedNodes"`
 	AffectedDrivers  []string                   `json:"affectedDrivers"`
+	IssuesByDriver   map[string]DriverIssueCounts `json:"issuesByDriver,omitempty"`
+	Status           HealthStatus               `json:"status,omitempty"` // overall verdict from the issue counts and status thresholds
 	MethodsUsed      []DetectionMethod          `json:"methodsUsed"`
-}
\ No newline at end of file
+	SnapshotTime     time.Time                  `json:"snapshotTime"` // when methods began reading cluster state, on the API server's clock when known; views are approximately consistent as of this time
+	ResourceVersions map[string]string          `json:"resourceVersions,omitempty"` // resourceVersion of the lists the methods read, by resource
+	Suppressed       int                        `json:"suppressed,omitempty"` // issues left out by suppression rules
+}
+// DriverIssueCounts tallies the issues attributed to one CSI driver
+type DriverIssueCounts struct {
+	Total      int                   `json:"total"`
+	BySeverity map[IssueSeverity]int `json:"bySeverity"`
+	ByType     map[IssueType]int     `json:"byType"`
+}
+
+// MethodInfo describes a detection method and the cluster access it requires
+type MethodInfo struct {
+	Method      DetectionMethod `json:"method"`
+	Description string          `json:"description"`
+	Reads       []string        `json:"reads"`
+	Permissions []Permission    `json:"permissions"`
+	Analysis    bool            `json:"analysis,omitempty"` // contributes to the detailed analysis of the analyze command
+}
+
+// Permission is an RBAC rule needed by a detection method
+type Permission struct {
+	Resource string   `json:"resource"`
+	Verbs    []string `json:"verbs"`
+}
+
+// AffectedWorkload is a workload consuming PVCs with detected issues
+type AffectedWorkload struct {
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	IssueCount int    `json:"issueCount"`
+}
@@ -0,0 +1,67 @@
+package types_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jdambly/kubectl-csi-scan/pkg/types"
+)
+
+var _ = Describe("DetectionResult", func() {
+	Describe("IssuesByNode", func() {
+		It("should group issues under their node, most severe first", func() {
+			stuck := types.CSIMountIssue{Type: types.StuckVolumeAttachment, Severity: types.SeverityMedium, Node: "node-1", Volume: "pv-1"}
+			busy := types.CSIMountIssue{Type: types.DeviceBusy, Severity: types.SeverityHigh, Node: "node-1", PVC: "default/data"}
+			failed := types.CSIMountIssue{Type: types.FailedAttachVolume, Severity: types.SeverityMedium, Node: "node-1", Volume: "pv-2"}
+			multi := types.CSIMountIssue{Type: types.MultiAttachError, Severity: types.SeverityCritical, Node: "node-2", Volume: "pv-3"}
+			result := &types.DetectionResult{Issues: []types.CSIMountIssue{stuck, busy, multi, failed}}
+
+			Expect(result.IssuesByNode()).To(Equal(map[string][]types.CSIMountIssue{
+				// Issues of the same severity keep their order
+				"node-1": {busy, stuck, failed},
+				"node-2": {multi},
+			}))
+		})
+
+		It("should put issues without a node under the cluster-wide key", func() {
+			crossNode := types.CSIMountIssue{Type: types.StuckMountReference, Severity: types.SeverityHigh, PVC: "default/data"}
+			missing := types.CSIMountIssue{Type: types.MissingPVC, Severity: types.SeverityLow, PVC: "default/gone"}
+			onNode := types.CSIMountIssue{Type: types.DeviceBusy, Severity: types.SeverityHigh, Node: "node-1"}
+			result := &types.DetectionResult{Issues: []types.CSIMountIssue{missing, onNode, crossNode}}
+
+			byNode := result.IssuesByNode()
+			Expect(byNode).To(HaveLen(2))
+			Expect(byNode[types.ClusterWideNode]).To(Equal([]types.CSIMountIssue{crossNode, missing}))
+			Expect(byNode["node-1"]).To(Equal([]types.CSIMountIssue{onNode}))
+		})
+
+		It("should not reorder the result's issues", func() {
+			low := types.CSIMountIssue{Type: types.MissingPVC, Severity: types.SeverityLow, Node: "node-1"}
+			high := types.CSIMountIssue{Type: types.DeviceBusy, Severity: types.SeverityHigh, Node: "node-1"}
+			result := &types.DetectionResult{Issues: []types.CSIMountIssue{low, high}}
+
+			result.IssuesByNode()
+			Expect(result.Issues).To(Equal([]types.CSIMountIssue{low, high}))
+		})
+
+		It("should return an empty map without issues", func() {
+			Expect((&types.DetectionResult{}).IssuesByNode()).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("CSIMountIssue", func() {
+	It("should leave out an unknown occurredAt", func() {
+		data, err := json.Marshal(types.CSIMountIssue{Node: "node-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring("occurredAt"))
+
+		occurred := time.Date(2025, 9, 8, 14, 5, 12, 0, time.UTC)
+		data, err = json.Marshal(types.CSIMountIssue{Node: "node-1", OccurredAt: occurred})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"occurredAt":"2025-09-08T14:05:12Z"`))
+	})
+})
@@ -11,6 +11,7 @@ SCRIPT_NAME="csi-mount-cleanup"
 LOG_PREFIX="[$SCRIPT_NAME]"
 DRY_RUN="${DRY_RUN:-false}"
 VERBOSE="${VERBOSE:-false}"
+SCAN_ONLY="${SCAN_ONLY:-false}"
 NODE_NAME="${NODE_NAME:-$(hostname)}"
 
 # CSI mount paths to check
@@ -81,7 +82,10 @@ find_stuck_mounts() {
         done < <(find "$CSI_PLUGIN_PATH" -name "globalmount" -type d -print0 2>/dev/null || true)
     fi
     
-    printf '%s\n' "${stuck_mounts[@]}"
+    # printf with no arguments would still print an empty line
+    if [ ${#stuck_mounts[@]} -gt 0 ]; then
+        printf '%s\n' "${stuck_mounts[@]}"
+    fi
 }
 
 check_mount_safety() {
@@ -179,7 +183,34 @@ show_summary() {
     fi
 }
 
+scan_only() {
+    local stuck_mounts
+    mapfile -t stuck_mounts < <(find_stuck_mounts)
+    
+    if [ ${#stuck_mounts[@]} -eq 0 ]; then
+        log "No stuck CSI mounts found on this node"
+        return 0
+    fi
+    
+    for mount_path in "${stuck_mounts[@]}"; do
+        log "STUCK: $mount_path"
+    done
+    
+    log "=== Scan Summary ==="
+    log "Node: $NODE_NAME"
+    log "Stuck mounts found: ${#stuck_mounts[@]}"
+    log "SCAN ONLY MODE: No unmounts attempted"
+}
+
 main() {
+    if [ "$SCAN_ONLY" = "true" ]; then
+        # Reading mount points needs no privileges, so this runs where cleanup is disallowed
+        log "Scanning for stuck CSI mounts on node: $NODE_NAME"
+        check_paths
+        scan_only
+        return 0
+    fi
+
     log "Starting CSI mount cleanup on node: $NODE_NAME"
     
     if [ "$DRY_RUN" = "true" ]; then
@@ -229,6 +260,10 @@ while [[ $# -gt 0 ]]; do
             VERBOSE=true
             shift
             ;;
+        --scan-only)
+            SCAN_ONLY=true
+            shift
+            ;;
         --node-name)
             NODE_NAME="$2"
             shift 2
@@ -242,12 +277,14 @@ Usage: $0 [OPTIONS]
 OPTIONS:
     --dry-run       Show what would be done without making changes
     --verbose       Enable verbose logging
+    --scan-only     Report stuck mounts without unmounting; does not require root
     --node-name     Specify the node name (default: hostname)
     --help          Show this help message
 
 ENVIRONMENT VARIABLES:
     DRY_RUN         Set to 'true' to enable dry run mode
     VERBOSE         Set to 'true' to enable verbose logging
+    SCAN_ONLY       Set to 'true' to enable scan-only mode
     NODE_NAME       Override the node name
 
 EXAMPLES:
@@ -257,6 +294,9 @@ EXAMPLES:
     # Perform actual cleanup
     $0
     
+    # Only report stuck mounts, e.g. from an unprivileged container
+    $0 --scan-only
+    
     # Cleanup with custom node name
     $0 --node-name knode57
 